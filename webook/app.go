@@ -1,11 +1,18 @@
 package main
 
 import (
+	"Webook/webook/internal/job"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/events"
+
 	"github.com/gin-gonic/gin"
-	"github.com/robfig/cron/v3"
 )
 
 type App struct {
-	server *gin.Engine
-	cron   *cron.Cron
+	server    *gin.Engine
+	scheduler *job.Scheduler
+	// 启动时重建本地的搜索索引
+	searchSvc service.SearchService
+	// 事件总线的后台消费者
+	consumers []events.Consumer
 }
@@ -6,5 +6,5 @@ var DevRedisExpire = RedisExpire{
 	ArticleDetail:    time.Minute,
 	ArticleFirstPage: time.Minute * 10,
 	PublicArticle:    time.Minute * 10,
-	Top100:           time.Minute * 3,
+	Top100:           time.Minute * 30, // 榜单，要比最长的刷新周期长
 }
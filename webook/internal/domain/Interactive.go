@@ -9,6 +9,7 @@ type Interactive struct {
 	ReadCnt    int64
 	LikeCnt    int64
 	CollectCnt int64
+	CommentCnt int64
 
 	// 与具体的 userId 有关
 	Liked     bool
@@ -10,6 +10,19 @@ type Article struct {
 	Status  ArticleStatus
 	Ctime   time.Time
 	Utime   time.Time
+
+	// 定时发布的时间，只有 ArticleStatusScheduled 状态下有意义
+	PublishAt time.Time
+
+	// 分类只有一个，标签可以有多个
+	Category string
+	Tags     []string
+}
+
+// ArticleFilter 线上文章列表的过滤条件，为空表示不过滤
+type ArticleFilter struct {
+	Tag      string
+	Category string
 }
 
 type Author struct {
@@ -24,7 +37,8 @@ const (
 	ArticleStatusUnpublished
 	ArticleStatusPublished
 	ArticleStatusPrivate
-	ArticleStatusArchived // 已删除
+	ArticleStatusArchived  // 已删除
+	ArticleStatusScheduled // 定时发布，到点后由定时任务发布
 )
 
 func (s ArticleStatus) ToUint8() uint8 {
@@ -38,3 +52,13 @@ func (a Article) Abstract() string {
 	}
 	return string(content[:100])
 }
+
+// ArticleRevision 文章的历史版本，每次保存都会追加一条，只增不改
+type ArticleRevision struct {
+	Id        int64
+	ArticleId int64
+	Title     string
+	Content   string
+	Author    Author
+	Ctime     time.Time
+}
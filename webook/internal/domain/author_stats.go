@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// AuthorStats 作者在一天内的数据，汇总多天时 Day 为空。
+// 点赞、收藏是当天的净增数，取消点赞、取消收藏会减掉
+type AuthorStats struct {
+	AuthorId   int64
+	Day        time.Time
+	ReadCnt    int64
+	LikeCnt    int64
+	CollectCnt int64
+	// 首次发表的文章数
+	PublishCnt int64
+}
+
+// AuthorScoreWeights 作者榜单按各项数据加权求和打分
+type AuthorScoreWeights struct {
+	Read    float64
+	Like    float64
+	Collect float64
+	Publish float64
+}
+
+// AuthorRankItem 作者榜单中的一项
+type AuthorRankItem struct {
+	AuthorStats
+	Score float64
+}
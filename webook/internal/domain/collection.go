@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Collection 收藏夹
+type Collection struct {
+	Id   int64
+	Uid  int64
+	Name string
+	// 收藏夹中的内容数量
+	Cnt int64
+
+	Ctime time.Time
+	Utime time.Time
+}
+
+// CollectionItem 收藏夹中收藏的一个资源
+type CollectionItem struct {
+	Biz   string
+	BizId int64
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// Comment 评论，按 (Biz, BizId) 挂在具体业务上
+// 楼中楼：RootId 为 0 的是根评论，回复的 RootId 指向所在的根评论，ParentId 指向被回复的评论
+type Comment struct {
+	Id    int64
+	Biz   string
+	BizId int64
+
+	Commentator Commentator
+	Content     string
+
+	RootId   int64
+	ParentId int64
+	// 被回复的用户，根评论为空
+	ReplyTo Commentator
+
+	// 根评论下的回复数
+	ReplyCnt int64
+
+	Ctime time.Time
+	Utime time.Time
+}
+
+// Commentator 评论者
+type Commentator struct {
+	Id   int64
+	Name string
+}
+
+func (c Comment) IsRoot() bool {
+	return c.RootId == 0
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type CronJobStatus uint8
+
+const (
+	CronJobStatusUnknown CronJobStatus = iota
+	CronJobStatusWaiting               // 等待下一次执行
+	CronJobStatusRunning               // 被某个节点抢占，正在执行
+	CronJobStatusPaused                // 暂停，不会被抢占
+)
+
+func (s CronJobStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+// CronJob 保存在数据库中的定时任务，到期后由抢占到它的节点执行
+type CronJob struct {
+	Id   int64
+	Name string
+	// Executor 执行器的名字，Cfg 是交给执行器的配置
+	Executor string
+	Cfg      string
+	// Expression cron 表达式，秒可以省略，也支持 @every 1m 这种写法
+	Expression string
+	Status     CronJobStatus
+	NextTime   time.Time
+	// Owner 抢占到任务的节点
+	Owner string
+	// Version 每次抢占加一，同时作为任务的 fencing token
+	Version   int64
+	Heartbeat time.Time
+	Ctime     time.Time
+	Utime     time.Time
+
+	// Ctx 执行任务用的 context，续约时发现任务已经不归本节点所有就会取消，
+	// 任务应该尽快退出，避免和接手的节点同时执行
+	Ctx context.Context
+	// CancelFunc 执行完之后调用，停止续约并释放任务
+	CancelFunc func()
+}
+
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour |
+	cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Next t 之后的下一次执行时间
+func (j CronJob) Next(t time.Time) (time.Time, error) {
+	s, err := cronParser.Parse(j.Expression)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return s.Next(t), nil
+}
+
+type CronJobExecutionStatus uint8
+
+const (
+	CronJobExecutionStatusUnknown CronJobExecutionStatus = iota
+	CronJobExecutionStatusRunning
+	CronJobExecutionStatusSucceeded
+	CronJobExecutionStatusFailed
+)
+
+func (s CronJobExecutionStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+// CronJobExecution 任务的一次执行记录
+type CronJobExecution struct {
+	Id    int64
+	JobId int64
+	Name  string
+	// Owner 执行的节点
+	Owner string
+	// Version 执行时持有的版本号
+	Version int64
+	Status  CronJobExecutionStatus
+	// Error 失败原因
+	Error     string
+	StartTime time.Time
+	EndTime   time.Time
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// 通过事件总线发送的业务事件，消费方可能重复收到同一个事件，处理时要幂等
+
+type ArticleEventType string
+
+const (
+	ArticleEventPublished ArticleEventType = "published"
+	ArticleEventWithdrawn ArticleEventType = "withdrawn"
+	ArticleEventDeleted   ArticleEventType = "deleted"
+)
+
+// ArticleEvent 文章在线上库的状态变化
+type ArticleEvent struct {
+	Type      ArticleEventType `json:"type"`
+	ArticleId int64            `json:"article_id"`
+	AuthorId  int64            `json:"author_id"`
+	Time      time.Time        `json:"time"`
+}
+
+type InteractiveEventType string
+
+const (
+	InteractiveEventLike      InteractiveEventType = "like"
+	InteractiveEventUnlike    InteractiveEventType = "unlike"
+	InteractiveEventCollect   InteractiveEventType = "collect"
+	InteractiveEventUncollect InteractiveEventType = "uncollect"
+	InteractiveEventComment   InteractiveEventType = "comment"
+	InteractiveEventUncomment InteractiveEventType = "uncomment"
+)
+
+// InteractiveEvent 用户对资源的点赞、收藏、评论
+type InteractiveEvent struct {
+	Type  InteractiveEventType `json:"type"`
+	Uid   int64                `json:"uid"`
+	Biz   string               `json:"biz"`
+	BizId int64                `json:"biz_id"`
+	// Cnt 删除评论时连同回复一起删除的条数，其他事件为 0，表示 1 次
+	Cnt  int64     `json:"cnt,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+// ReadEvent 用户阅读了资源
+type ReadEvent struct {
+	Uid   int64  `json:"uid"`
+	Biz   string `json:"biz"`
+	BizId int64  `json:"biz_id"`
+	// 读者的 IP，用于阅读去重
+	Ip   string    `json:"ip,omitempty"`
+	Time time.Time `json:"time"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// FeedItem 动态流中的一条：作者 AuthorId 在 Time 发表了文章 ArticleId
+type FeedItem struct {
+	ArticleId int64
+	AuthorId  int64
+	Time      time.Time
+}
+
+// FeedCursor 动态流的游标，按 (Time, ArticleId) 倒序翻页，零值表示从最新的开始
+type FeedCursor struct {
+	Time      time.Time
+	ArticleId int64
+}
+
+func (c FeedCursor) IsZero() bool {
+	return c.Time.IsZero() && c.ArticleId == 0
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// FollowRelation 关注关系：Follower 关注了 Followee
+type FollowRelation struct {
+	Follower int64
+	Followee int64
+	Ctime    time.Time
+}
+
+// FollowStatics 用户的粉丝数和关注数
+type FollowStatics struct {
+	Followers int64
+	Followees int64
+}
+
+// FollowInfo 当前用户和另一个用户之间的关注状态
+type FollowInfo struct {
+	// 我关注了对方
+	Followed bool
+	// 对方关注了我
+	FollowedBy bool
+}
+
+// Mutual 互相关注
+func (f FollowInfo) Mutual() bool {
+	return f.Followed && f.FollowedBy
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+type NotificationType uint8
+
+const (
+	NotificationTypeUnknown NotificationType = iota
+	NotificationTypeLike
+	NotificationTypeCollect
+	NotificationTypeFollow
+	NotificationTypeComment // 评论了你的文章
+	NotificationTypeReply   // 回复了你的评论
+)
+
+var notificationTypeNames = map[NotificationType]string{
+	NotificationTypeLike:    "like",
+	NotificationTypeCollect: "collect",
+	NotificationTypeFollow:  "follow",
+	NotificationTypeComment: "comment",
+	NotificationTypeReply:   "reply",
+}
+
+func (t NotificationType) ToUint8() uint8 {
+	return uint8(t)
+}
+
+func (t NotificationType) String() string {
+	return notificationTypeNames[t]
+}
+
+// Aggregatable 点赞、收藏、关注会聚合成一条通知，比如 "12 个人赞了你的文章"
+// 评论和回复带有内容，每条单独通知
+func (t NotificationType) Aggregatable() bool {
+	return t == NotificationTypeLike || t == NotificationTypeCollect || t == NotificationTypeFollow
+}
+
+// NotificationTypeFromString 未知的类型返回 NotificationTypeUnknown
+func NotificationTypeFromString(s string) NotificationType {
+	for t, name := range notificationTypeNames {
+		if name == s {
+			return t
+		}
+	}
+	return NotificationTypeUnknown
+}
+
+// NotificationEvent 触发通知的事件：Actor 对 Biz 上的 BizId 做了 Type 操作
+type NotificationEvent struct {
+	Type  NotificationType `json:"type"`
+	Actor int64            `json:"actor"`
+	// 接收者，为 0 时按 Biz 和 BizId 查询业务的所有者，比如文章的作者
+	Receiver int64  `json:"receiver,omitempty"`
+	Biz      string `json:"biz"`
+	BizId    int64  `json:"biz_id"`
+	BizTitle string `json:"biz_title,omitempty"`
+	// 评论和回复的内容
+	Content string `json:"content,omitempty"`
+}
+
+type Notification struct {
+	Id       int64
+	Receiver int64
+	Type     NotificationType
+	Biz      string
+	BizId    int64
+	BizTitle string
+	// 最近的几个触发者，最新的在前，ActorCnt 是去重后的总人数
+	Actors   []int64
+	ActorCnt int64
+	Content  string
+	Read     bool
+	Ctime    time.Time
+	Utime    time.Time
+}
@@ -0,0 +1,14 @@
+package domain
+
+// RankingAction 会影响增量榜单分数的行为
+type RankingAction uint8
+
+const (
+	RankingActionRead RankingAction = iota + 1
+	RankingActionLike
+	RankingActionUnlike
+	RankingActionCollect
+	RankingActionUncollect
+	RankingActionComment
+	RankingActionUncomment
+)
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ReadHistory 用户对某个资源的阅读记录，同一个资源只有一条
+type ReadHistory struct {
+	Id    int64
+	Uid   int64
+	Biz   string
+	BizId int64
+	// 阅读进度，0 - 100
+	Progress    int
+	FirstReadAt time.Time
+	LastReadAt  time.Time
+
+	// 资源是文章且仍然在线时才有
+	Article Article
+}
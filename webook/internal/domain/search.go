@@ -0,0 +1,9 @@
+package domain
+
+// ArticleSearchHit 搜索命中的文章，高亮的标题和摘要已经做过 HTML 转义
+type ArticleSearchHit struct {
+	Article Article
+
+	TitleHighlight   string
+	ContentHighlight string
+}
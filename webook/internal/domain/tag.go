@@ -0,0 +1,7 @@
+package domain
+
+// Tag 标签及其热度：已发表文章中使用该标签的数量
+type Tag struct {
+	Name string
+	Cnt  int64
+}
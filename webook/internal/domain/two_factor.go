@@ -0,0 +1,25 @@
+package domain
+
+// TwoFactor 用户的 TOTP 二次验证
+type TwoFactor struct {
+	Uid    int64
+	Secret string
+	// 确认过第一个验证码才算开启
+	Enabled bool
+	// 最后一次用过的时间窗口
+	LastCounter int64
+}
+
+// TwoFactorEnrollment 开启二次验证时返回给用户的密钥
+type TwoFactorEnrollment struct {
+	Secret string
+	// 验证器扫码用的 otpauth:// 地址
+	URI string
+}
+
+// TwoFactorStatus 二次验证的开启情况
+type TwoFactorStatus struct {
+	Enabled bool
+	// 剩余可用的恢复码
+	RecoveryCodesLeft int64
+}
@@ -18,3 +18,10 @@ type User struct {
 	// 微信信息
 	WechatInfo WechatInfo
 }
+
+// PasswordReset 忘记密码时发出的重置 token 对应的用户
+type PasswordReset struct {
+	Uid int64
+	// 发 token 时密码哈希的指纹，密码改过之后旧的 token 全部失效
+	Fingerprint string
+}
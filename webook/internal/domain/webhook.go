@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// WebhookEventType 可以订阅的平台事件
+type WebhookEventType string
+
+const (
+	WebhookEventArticlePublished WebhookEventType = "article.published"
+	WebhookEventArticleWithdrawn WebhookEventType = "article.withdrawn"
+	WebhookEventArticleDeleted   WebhookEventType = "article.deleted"
+	WebhookEventInteractiveLiked WebhookEventType = "interactive.liked"
+	WebhookEventUserCreated      WebhookEventType = "user.created"
+)
+
+func (t WebhookEventType) Valid() bool {
+	switch t {
+	case WebhookEventArticlePublished, WebhookEventArticleWithdrawn, WebhookEventArticleDeleted,
+		WebhookEventInteractiveLiked, WebhookEventUserCreated:
+		return true
+	}
+	return false
+}
+
+// Global 和具体用户的资源无关的平台事件，只有管理员可以订阅
+// 其他事件只投递给资源所有者（文章作者等）注册的 webhook
+func (t WebhookEventType) Global() bool {
+	return t == WebhookEventUserCreated
+}
+
+// Webhook 用户注册的回调地址，事件发生时向 Url 发送签过名的 JSON POST 请求
+type Webhook struct {
+	Id  int64
+	Uid int64
+	Url string
+	// 签名用的密钥，只在创建时返回给用户
+	Secret string
+	Events []WebhookEventType
+	Ctime  time.Time
+	Utime  time.Time
+}
+
+// Subscribed 是否订阅了事件
+func (w Webhook) Subscribed(evt WebhookEventType) bool {
+	for _, e := range w.Events {
+		if e == evt {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus uint8
+
+const (
+	WebhookDeliveryStatusUnknown WebhookDeliveryStatus = iota
+	WebhookDeliveryStatusPending                       // 等待发送或重试
+	WebhookDeliveryStatusSucceeded
+	WebhookDeliveryStatusFailed // 超过最大重试次数，不再重试
+)
+
+func (s WebhookDeliveryStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+// WebhookDelivery 一次投递，同时也是投递日志
+type WebhookDelivery struct {
+	Id        int64
+	WebhookId int64
+	Event     WebhookEventType
+	// 请求体，重新投递时原样发送
+	Payload  string
+	Status   WebhookDeliveryStatus
+	Attempts int
+	// 下一次发送的时间
+	NextRetryAt time.Time
+	// 最近一次发送的响应码，请求失败时为 0，Error 为错误信息
+	// 不保存响应内容，避免把接收方返回的内容展示出来
+	StatusCode int
+	Error      string
+	Ctime      time.Time
+	Utime      time.Time
+}
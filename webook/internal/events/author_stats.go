@@ -0,0 +1,146 @@
+package events
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+	"sync"
+	"time"
+)
+
+// NewAuthorStatsConsumers 点赞、收藏和首次发表计入作者每天的汇总。
+// 阅读数由 ReadCntConsumer 去重合并后计入
+func NewAuthorStatsConsumers(bus events.Bus, svc service.AuthorStatsService, l logger.Logger) []events.Consumer {
+	return []events.Consumer{
+		NewAuthorStatsConsumer(bus, svc, l),
+		events.NewJSONConsumer[domain.ArticleEvent](bus, TopicArticleEvents, "author_stats",
+			svc.RecordPublish, l),
+	}
+}
+
+// AuthorStatsConsumer 消费点赞、收藏事件，攒够 batchSize 个，或者距离上次写入超过 interval 时
+// 批量计入作者的数据汇总，一批事件只查询一次文章的作者。
+// 按消息 id 去重，重新投递的事件不会重复计数。
+//
+// 和 ReadCntConsumer 一样，事件放进缓冲就确认了，进程崩溃时缓冲中的事件会丢失；
+// 正常退出时 Stop 会把缓冲写完
+type AuthorStatsConsumer struct {
+	svc      service.AuthorStatsService
+	logger   logger.Logger
+	consumer events.Consumer
+
+	batchSize int
+	interval  time.Duration
+	timeout   time.Duration
+
+	mu     sync.Mutex
+	buffer []domain.InteractiveEvent
+
+	cancel context.CancelFunc
+	// 定时写入的协程退出后关闭
+	done chan struct{}
+}
+
+func NewAuthorStatsConsumer(bus events.Bus, svc service.AuthorStatsService, l logger.Logger) *AuthorStatsConsumer {
+	c := &AuthorStatsConsumer{
+		svc:       svc,
+		logger:    l,
+		batchSize: 100,
+		interval:  time.Second,
+		timeout:   time.Second * 3,
+	}
+	c.consumer = events.NewJSONConsumer[domain.InteractiveEvent](bus, TopicInteractiveEvents, "author_stats", c.add, l)
+	return c
+}
+
+func (c *AuthorStatsConsumer) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	c.consumer.Start(ctx)
+	go c.flushLoop(ctx)
+}
+
+// Stop 先停止消费，再把缓冲中剩下的事件写完
+func (c *AuthorStatsConsumer) Stop(ctx context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+	err := c.consumer.Stop(ctx)
+	c.cancel()
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if er := c.flush(ctx); er != nil {
+		return er
+	}
+	return err
+}
+
+func (c *AuthorStatsConsumer) add(ctx context.Context, evt domain.InteractiveEvent) error {
+	if id, ok := events.MessageIdFromContext(ctx); ok {
+		first, err := c.svc.ShouldRecord(ctx, id)
+		if err != nil {
+			// 去重失败时让事件重试，不能多算
+			return err
+		}
+		if !first {
+			return nil
+		}
+	}
+
+	c.mu.Lock()
+	c.buffer = append(c.buffer, evt)
+	full := len(c.buffer) >= c.batchSize
+	c.mu.Unlock()
+
+	if full {
+		c.flushAndLog()
+	}
+	// 写入失败的事件还在缓冲里，下次再写
+	return nil
+}
+
+func (c *AuthorStatsConsumer) flushLoop(ctx context.Context) {
+	defer close(c.done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.flushAndLog()
+		}
+	}
+}
+
+func (c *AuthorStatsConsumer) flushAndLog() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.flush(ctx); err != nil {
+		c.logger.Error("批量计入作者汇总失败", logger.Error(err))
+	}
+}
+
+// flush 取出缓冲写入，失败时把事件放回缓冲
+func (c *AuthorStatsConsumer) flush(ctx context.Context) error {
+	c.mu.Lock()
+	buffer := c.buffer
+	c.buffer = nil
+	c.mu.Unlock()
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	err := c.svc.RecordInteractive(ctx, buffer)
+	if err != nil {
+		c.mu.Lock()
+		c.buffer = append(buffer, c.buffer...)
+		c.mu.Unlock()
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	svcmocks "Webook/webook/internal/service/mocks"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestAuthorStatsConsumer(t *testing.T) {
+	evts := []domain.InteractiveEvent{
+		{Type: domain.InteractiveEventLike, Uid: 1, Biz: "article", BizId: 1},
+		{Type: domain.InteractiveEventCollect, Uid: 2, Biz: "article", BizId: 2},
+		{Type: domain.InteractiveEventLike, Uid: 3, Biz: "article", BizId: 1},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.AuthorStatsService
+
+		batchSize int
+	}{
+		{
+			name: "合并后在退出时写入",
+			mock: func(ctrl *gomock.Controller) service.AuthorStatsService {
+				svc := svcmocks.NewMockAuthorStatsService(ctrl)
+				svc.EXPECT().ShouldRecord(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+				svc.EXPECT().RecordInteractive(gomock.Any(), evts).Return(nil)
+				return svc
+			},
+			batchSize: 100,
+		},
+		{
+			name: "重新投递的事件不计入",
+			mock: func(ctrl *gomock.Controller) service.AuthorStatsService {
+				svc := svcmocks.NewMockAuthorStatsService(ctrl)
+				svc.EXPECT().ShouldRecord(gomock.Any(), "1").Return(true, nil)
+				svc.EXPECT().ShouldRecord(gomock.Any(), "2").Return(false, nil)
+				svc.EXPECT().ShouldRecord(gomock.Any(), "3").Return(true, nil)
+				svc.EXPECT().RecordInteractive(gomock.Any(),
+					[]domain.InteractiveEvent{evts[0], evts[2]}).Return(nil)
+				return svc
+			},
+			batchSize: 100,
+		},
+		{
+			name: "写入失败，事件放回缓冲，下次一起写入",
+			mock: func(ctrl *gomock.Controller) service.AuthorStatsService {
+				svc := svcmocks.NewMockAuthorStatsService(ctrl)
+				svc.EXPECT().ShouldRecord(gomock.Any(), gomock.Any()).Return(true, nil).Times(3)
+				svc.EXPECT().RecordInteractive(gomock.Any(), evts[:2]).Return(errors.New("db error"))
+				svc.EXPECT().RecordInteractive(gomock.Any(), evts).Return(nil)
+				return svc
+			},
+			batchSize: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			bus := events.NewMemoryBus(events.Config{})
+			c := NewAuthorStatsConsumer(bus, tc.mock(ctrl), logger.NewZapLogger(zap.NewNop()))
+			c.batchSize = tc.batchSize
+			// 不让定时写入干扰
+			c.interval = time.Hour
+
+			producer := events.NewJSONProducer[domain.InteractiveEvent](bus, TopicInteractiveEvents)
+			for _, evt := range evts {
+				require.NoError(t, producer.Produce(context.Background(), evt))
+			}
+			c.Start(context.Background())
+			// 等待消费完
+			time.Sleep(time.Millisecond * 100)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			assert.NoError(t, c.Stop(ctx))
+		})
+	}
+}
@@ -0,0 +1,14 @@
+// Package events 业务事件的发送方和消费方实现
+package events
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+)
+
+// NewNotificationConsumer 消费通知事件，生成或者聚合站内通知。处理失败的事件会重新投递
+func NewNotificationConsumer(bus events.Bus, svc service.NotificationService, l logger.Logger) events.Consumer {
+	return events.NewJSONConsumer[domain.NotificationEvent](bus, TopicNotificationEvents, "notification", svc.Notify, l)
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+)
+
+var rankingActions = map[domain.InteractiveEventType]domain.RankingAction{
+	domain.InteractiveEventLike:      domain.RankingActionLike,
+	domain.InteractiveEventUnlike:    domain.RankingActionUnlike,
+	domain.InteractiveEventCollect:   domain.RankingActionCollect,
+	domain.InteractiveEventUncollect: domain.RankingActionUncollect,
+	domain.InteractiveEventComment:   domain.RankingActionComment,
+	domain.InteractiveEventUncomment: domain.RankingActionUncomment,
+}
+
+// NewRankingConsumers 点赞、收藏、评论实时更新增量榜单，文章撤回、删除后移出增量榜单。
+// 阅读数由 ReadCntConsumer 去重合并后计入
+func NewRankingConsumers(bus events.Bus, svc service.RankingService, l logger.Logger) []events.Consumer {
+	return []events.Consumer{
+		events.NewJSONConsumer[domain.InteractiveEvent](bus, TopicInteractiveEvents, "ranking",
+			func(ctx context.Context, evt domain.InteractiveEvent) error {
+				action, ok := rankingActions[evt.Type]
+				if !ok || evt.Biz != "article" {
+					return nil
+				}
+				cnt := evt.Cnt
+				if cnt <= 0 {
+					cnt = 1
+				}
+				return svc.IncrScores(ctx, action, []int64{evt.BizId}, []int64{cnt})
+			}, l),
+		events.NewJSONConsumer[domain.ArticleEvent](bus, TopicArticleEvents, "ranking",
+			func(ctx context.Context, evt domain.ArticleEvent) error {
+				if evt.Type == domain.ArticleEventPublished {
+					return nil
+				}
+				return svc.Remove(ctx, evt.ArticleId)
+			}, l),
+	}
+}
@@ -0,0 +1,241 @@
+package events
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+type readKey struct {
+	biz   string
+	bizId int64
+}
+
+// ReadCntConsumer 消费阅读事件，按 (biz, bizId) 合并后批量增加阅读计数：
+// 缓冲中的资源数达到 batchSize，或者距离上次写入超过 interval 时写入一次。
+// 去重窗口内的重复阅读不计数。写入成功后，合并的阅读数同时计入增量榜单和作者的数据汇总。
+//
+// 事件放进缓冲就确认了，进程崩溃时缓冲中的计数会丢失，阅读数对此不敏感；
+// 正常退出时 Stop 会把缓冲写完。
+// 数据库一直写入失败时，缓冲中的资源数最多到 maxBufferSize，之后不再确认新的事件，
+// 等写入成功腾出空间再继续消费，不会在内存中无限堆积
+type ReadCntConsumer struct {
+	svc        service.InteractiveService
+	historySvc service.ReadHistoryService
+	rankSvc    service.RankingService
+	statsSvc   service.AuthorStatsService
+	logger     logger.Logger
+	consumer   events.Consumer
+
+	batchSize int
+	// 缓冲中最多的资源数，正常情况下不会超过 batchSize，只有持续写入失败才会攒到这么多
+	maxBufferSize int
+	interval      time.Duration
+	timeout       time.Duration
+	// 缓冲满了并且写入失败时，等待多久再重试
+	retryBackoff time.Duration
+
+	mu     sync.Mutex
+	buffer map[readKey]int64
+
+	cancel context.CancelFunc
+	// 定时写入的协程退出后关闭
+	done chan struct{}
+}
+
+func NewReadCntConsumer(bus events.Bus, svc service.InteractiveService,
+	historySvc service.ReadHistoryService, rankSvc service.RankingService,
+	statsSvc service.AuthorStatsService, l logger.Logger) *ReadCntConsumer {
+	c := &ReadCntConsumer{
+		svc:           svc,
+		historySvc:    historySvc,
+		rankSvc:       rankSvc,
+		statsSvc:      statsSvc,
+		logger:        l,
+		batchSize:     100,
+		maxBufferSize: 10000,
+		interval:      time.Second,
+		timeout:       time.Second * 3,
+		retryBackoff:  time.Second,
+		buffer:        make(map[readKey]int64),
+	}
+	c.consumer = events.NewJSONConsumer[domain.ReadEvent](bus, TopicReadEvents, "read_cnt", c.add, l)
+	return c
+}
+
+func (c *ReadCntConsumer) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	c.consumer.Start(ctx)
+	go c.flushLoop(ctx)
+}
+
+// Stop 先停止消费，再把缓冲中剩下的计数写完
+func (c *ReadCntConsumer) Stop(ctx context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+	err := c.consumer.Stop(ctx)
+	c.cancel()
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if er := c.flush(ctx); er != nil {
+		return er
+	}
+	return err
+}
+
+func (c *ReadCntConsumer) add(ctx context.Context, evt domain.ReadEvent) error {
+	key := readKey{biz: evt.Biz, bizId: evt.BizId}
+	// 先等缓冲有空间再去重，否则去重记录写了，事件重新投递时就不会再计数了
+	if err := c.waitRoom(ctx, key); err != nil {
+		return err
+	}
+
+	ok, err := c.historySvc.ShouldCount(ctx, evt)
+	if err != nil {
+		// 去重失败时宁可多算
+		c.logger.Error("阅读去重失败",
+			logger.String("biz", evt.Biz),
+			logger.Int64("bizId", evt.BizId),
+			logger.Error(err),
+		)
+	} else if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	c.buffer[key]++
+	full := len(c.buffer) >= c.batchSize
+	c.mu.Unlock()
+
+	if full {
+		c.flushAndLog()
+	}
+	// 写入失败的计数还在缓冲里，下次再写，不能让事件重试，否则会重复计数
+	return nil
+}
+
+// waitRoom 缓冲中的资源数达到 maxBufferSize 并且 key 不在缓冲中时，同步写入腾出空间，
+// 写入失败就退避后重试。等待期间事件不确认，消费者也不会读取新的事件；
+// ctx 取消时返回错误，事件之后重新投递
+func (c *ReadCntConsumer) waitRoom(ctx context.Context, key readKey) error {
+	for first := true; !c.hasRoom(key); first = false {
+		if !first {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.retryBackoff):
+			}
+		}
+		c.flushAndLog()
+	}
+	return nil
+}
+
+func (c *ReadCntConsumer) hasRoom(key readKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.buffer[key]
+	return ok || len(c.buffer) < c.maxBufferSize
+}
+
+func (c *ReadCntConsumer) flushLoop(ctx context.Context) {
+	defer close(c.done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.flushAndLog()
+		}
+	}
+}
+
+func (c *ReadCntConsumer) flushAndLog() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.flush(ctx); err != nil {
+		c.logger.Error("批量增加阅读计数失败", logger.Error(err))
+	}
+}
+
+// flush 取出缓冲写入，写数据库失败时把计数放回缓冲
+func (c *ReadCntConsumer) flush(ctx context.Context) error {
+	c.mu.Lock()
+	buffer := c.buffer
+	c.buffer = make(map[readKey]int64, len(buffer))
+	c.mu.Unlock()
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	keys := make([]readKey, 0, len(buffer))
+	for k := range buffer {
+		keys = append(keys, k)
+	}
+	// 固定顺序，避免多个实例同时写入时死锁
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].biz != keys[j].biz {
+			return keys[i].biz < keys[j].biz
+		}
+		return keys[i].bizId < keys[j].bizId
+	})
+	bizs := make([]string, 0, len(keys))
+	bizIds := make([]int64, 0, len(keys))
+	cnts := make([]int64, 0, len(keys))
+	for _, k := range keys {
+		bizs = append(bizs, k.biz)
+		bizIds = append(bizIds, k.bizId)
+		cnts = append(cnts, buffer[k])
+	}
+
+	err := c.svc.BatchIncreaseReadCnt(ctx, bizs, bizIds, cnts)
+	// 只是缓存没更新，数据库已经写入了
+	if err != nil && !errors.Is(err, service.ErrInteractiveCacheNotUpdated) {
+		c.mu.Lock()
+		for k, cnt := range buffer {
+			c.buffer[k] += cnt
+		}
+		c.mu.Unlock()
+		return err
+	}
+	c.incrArticleReads(ctx, bizs, bizIds, cnts)
+	return err
+}
+
+// incrArticleReads 榜单和作者汇总对阅读数不敏感，失败只记录日志
+func (c *ReadCntConsumer) incrArticleReads(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) {
+	var artIds, artCnts []int64
+	for i := range bizs {
+		if bizs[i] == "article" {
+			artIds = append(artIds, bizIds[i])
+			artCnts = append(artCnts, cnts[i])
+		}
+	}
+	if len(artIds) == 0 {
+		return
+	}
+	if err := c.rankSvc.IncrScores(ctx, domain.RankingActionRead, artIds, artCnts); err != nil {
+		c.logger.Error("阅读数计入榜单失败", logger.Error(err))
+	}
+	if err := c.statsSvc.IncrReadCnt(ctx, artIds, artCnts); err != nil {
+		c.logger.Error("阅读数计入作者汇总失败", logger.Error(err))
+	}
+}
+
+// NewReadHistoryConsumer 消费阅读事件，记录用户的阅读历史。重复消费只会更新最近阅读时间
+func NewReadHistoryConsumer(bus events.Bus, svc service.ReadHistoryService, l logger.Logger) events.Consumer {
+	return events.NewJSONConsumer[domain.ReadEvent](bus, TopicReadEvents, "read_history", svc.Record, l)
+}
@@ -0,0 +1,205 @@
+package events
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	svcmocks "Webook/webook/internal/service/mocks"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestReadCntConsumer(t *testing.T) {
+	reads := []domain.ReadEvent{
+		{Uid: 1, Biz: "article", BizId: 2},
+		{Uid: 2, Biz: "article", BizId: 1},
+		{Uid: 3, Biz: "article", BizId: 2},
+		{Uid: 1, Biz: "article", BizId: 2},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.InteractiveService, service.ReadHistoryService)
+		// 不设置时不检查计入榜单的阅读数
+		rankMock func(ctrl *gomock.Controller) service.RankingService
+		// 不设置时不检查计入作者汇总的阅读数
+		statsMock func(ctrl *gomock.Controller) service.AuthorStatsService
+
+		batchSize int
+		// 为 0 时使用默认值
+		maxBufferSize int
+	}{
+		{
+			name: "合并后在退出时写入",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.ReadHistoryService) {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				historySvc := countAll(ctrl)
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article", "article"}, []int64{1, 2}, []int64{1, 3}).Return(nil)
+				return svc, historySvc
+			},
+			rankMock: func(ctrl *gomock.Controller) service.RankingService {
+				rankSvc := svcmocks.NewMockRankingService(ctrl)
+				rankSvc.EXPECT().IncrScores(gomock.Any(), domain.RankingActionRead,
+					[]int64{1, 2}, []int64{1, 3}).Return(nil)
+				return rankSvc
+			},
+			statsMock: func(ctrl *gomock.Controller) service.AuthorStatsService {
+				statsSvc := svcmocks.NewMockAuthorStatsService(ctrl)
+				statsSvc.EXPECT().IncrReadCnt(gomock.Any(), []int64{1, 2}, []int64{1, 3}).Return(nil)
+				return statsSvc
+			},
+			batchSize: 100,
+		},
+		{
+			name: "达到批量大小时写入",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.ReadHistoryService) {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				historySvc := countAll(ctrl)
+				// 第二个资源出现时缓冲满了
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article", "article"}, []int64{1, 2}, []int64{1, 1}).Return(nil)
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article"}, []int64{2}, []int64{2}).Return(nil)
+				return svc, historySvc
+			},
+			batchSize: 2,
+		},
+		{
+			name: "写入失败，计数放回缓冲，下次一起写入",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.ReadHistoryService) {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				historySvc := countAll(ctrl)
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article", "article"}, []int64{1, 2}, []int64{1, 1}).Return(errors.New("db error"))
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article", "article"}, []int64{1, 2}, []int64{1, 2}).Return(nil)
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article"}, []int64{2}, []int64{1}).Return(nil)
+				return svc, historySvc
+			},
+			rankMock: func(ctrl *gomock.Controller) service.RankingService {
+				// 写入失败的那次不计入榜单，榜单失败不影响阅读计数
+				rankSvc := svcmocks.NewMockRankingService(ctrl)
+				rankSvc.EXPECT().IncrScores(gomock.Any(), domain.RankingActionRead,
+					[]int64{1, 2}, []int64{1, 2}).Return(errors.New("redis error"))
+				rankSvc.EXPECT().IncrScores(gomock.Any(), domain.RankingActionRead,
+					[]int64{2}, []int64{1}).Return(nil)
+				return rankSvc
+			},
+			batchSize: 2,
+		},
+		{
+			name: "只是缓存没更新，不重复写入",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.ReadHistoryService) {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				historySvc := countAll(ctrl)
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article", "article"}, []int64{1, 2}, []int64{1, 1}).
+					Return(service.ErrInteractiveCacheNotUpdated)
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article"}, []int64{2}, []int64{2}).Return(nil)
+				return svc, historySvc
+			},
+			batchSize: 2,
+		},
+		{
+			name: "去重窗口内的重复阅读不计数，去重失败时计数",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.ReadHistoryService) {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				historySvc := svcmocks.NewMockReadHistoryService(ctrl)
+				historySvc.EXPECT().ShouldCount(gomock.Any(), reads[0]).Return(true, nil)
+				historySvc.EXPECT().ShouldCount(gomock.Any(), reads[1]).Return(true, nil)
+				historySvc.EXPECT().ShouldCount(gomock.Any(), reads[2]).Return(false, errors.New("redis error"))
+				historySvc.EXPECT().ShouldCount(gomock.Any(), reads[3]).Return(false, nil)
+				svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+					[]string{"article", "article"}, []int64{1, 2}, []int64{1, 2}).Return(nil)
+				return svc, historySvc
+			},
+			batchSize: 100,
+		},
+		{
+			name: "缓冲满了并且写入失败，等写入成功后再接收新的资源",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.ReadHistoryService) {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				historySvc := countAll(ctrl)
+				gomock.InOrder(
+					svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+						[]string{"article"}, []int64{2}, []int64{1}).Return(errors.New("db error")),
+					svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+						[]string{"article"}, []int64{2}, []int64{1}).Return(nil),
+					svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+						[]string{"article"}, []int64{1}, []int64{1}).Return(nil),
+					// 缓冲中已有的资源直接累加
+					svc.EXPECT().BatchIncreaseReadCnt(gomock.Any(),
+						[]string{"article"}, []int64{2}, []int64{2}).Return(nil),
+				)
+				return svc, historySvc
+			},
+			batchSize:     100,
+			maxBufferSize: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			bus := events.NewMemoryBus(events.Config{})
+			svc, historySvc := tc.mock(ctrl)
+			var rankSvc service.RankingService
+			if tc.rankMock != nil {
+				rankSvc = tc.rankMock(ctrl)
+			} else {
+				mockRankSvc := svcmocks.NewMockRankingService(ctrl)
+				mockRankSvc.EXPECT().IncrScores(gomock.Any(), domain.RankingActionRead, gomock.Any(), gomock.Any()).
+					Return(nil).AnyTimes()
+				rankSvc = mockRankSvc
+			}
+			var statsSvc service.AuthorStatsService
+			if tc.statsMock != nil {
+				statsSvc = tc.statsMock(ctrl)
+			} else {
+				mockStatsSvc := svcmocks.NewMockAuthorStatsService(ctrl)
+				mockStatsSvc.EXPECT().IncrReadCnt(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil).AnyTimes()
+				statsSvc = mockStatsSvc
+			}
+			c := NewReadCntConsumer(bus, svc, historySvc, rankSvc, statsSvc, logger.NewZapLogger(zap.NewNop()))
+			c.batchSize = tc.batchSize
+			if tc.maxBufferSize > 0 {
+				c.maxBufferSize = tc.maxBufferSize
+			}
+			c.retryBackoff = time.Millisecond * 10
+			// 不让定时写入干扰
+			c.interval = time.Hour
+
+			producer := events.NewJSONProducer[domain.ReadEvent](bus, TopicReadEvents)
+			for _, evt := range reads {
+				require.NoError(t, producer.Produce(context.Background(), evt))
+			}
+			c.Start(context.Background())
+			// 等待消费完
+			time.Sleep(time.Millisecond * 100)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			assert.NoError(t, c.Stop(ctx))
+		})
+	}
+}
+
+// countAll 不去重
+func countAll(ctrl *gomock.Controller) service.ReadHistoryService {
+	historySvc := svcmocks.NewMockReadHistoryService(ctrl)
+	historySvc.EXPECT().ShouldCount(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	return historySvc
+}
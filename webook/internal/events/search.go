@@ -0,0 +1,18 @@
+package events
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+)
+
+// NewSearchIndexConsumer 文章发表、撤回、删除后更新本实例的搜索索引。
+// 索引在每个实例本地，所以每个实例要用自己的消费者组 group，各自消费全部的文章事件
+func NewSearchIndexConsumer(bus events.Bus, svc service.SearchService, group string, l logger.Logger) events.Consumer {
+	return events.NewJSONConsumer[domain.ArticleEvent](bus, TopicArticleEvents, group,
+		func(ctx context.Context, evt domain.ArticleEvent) error {
+			return svc.SyncArticle(ctx, evt.ArticleId)
+		}, l)
+}
@@ -0,0 +1,13 @@
+package events
+
+// 事件总线上的 topic
+const (
+	// TopicArticleEvents 文章发表、撤回、删除，domain.ArticleEvent
+	TopicArticleEvents = "article_events"
+	// TopicInteractiveEvents 点赞、收藏，domain.InteractiveEvent
+	TopicInteractiveEvents = "interactive_events"
+	// TopicReadEvents 阅读，domain.ReadEvent
+	TopicReadEvents = "read_events"
+	// TopicNotificationEvents 点赞、收藏、关注、评论、回复的通知，domain.NotificationEvent
+	TopicNotificationEvents = "notification_events"
+)
@@ -2,17 +2,33 @@ package job
 
 import (
 	"Webook/webook/pkg/logger"
-	"github.com/robfig/cron/v3"
+	"Webook/webook/pkg/redislock"
+	"context"
+	"errors"
+	"sync"
 	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
+// CronJonBuilder 多个实例同时调度同一个任务时，只有拿到分布式锁的实例执行。
+// 拿到锁之后一直持有并自动续约，持有者宕机后锁过期，其他实例在下一次调度时接手。
+// 续约失败或者超时时取消正在执行的任务，锁的 token 作为 fencing token 交给 FencedJob
 type CronJonBuilder struct {
-	logger logger.Logger
+	logger     logger.Logger
+	lockClient *redislock.Client
+
+	// 锁的过期时间，每 1/3 过期时间续约一次
+	expiration time.Duration
+	timeout    time.Duration
 }
 
-func NewCronJobBuilder(logger logger.Logger) *CronJonBuilder {
+func NewCronJobBuilder(logger logger.Logger, lockClient *redislock.Client) *CronJonBuilder {
 	return &CronJonBuilder{
-		logger: logger,
+		logger:     logger,
+		lockClient: lockClient,
+		expiration: time.Second * 30,
+		timeout:    time.Second,
 	}
 }
 
@@ -22,12 +38,31 @@ func (c cronJobAdapterFunc) Run() {
 	c()
 }
 
+// heldLock 本实例持有的锁，ctx 在失去锁时取消
+type heldLock struct {
+	lock *redislock.Lock
+	ctx  context.Context
+}
+
+// jobLock 一个任务在本实例持有的锁，没有持有时为 nil
+type jobLock struct {
+	mu   sync.Mutex
+	held *heldLock
+}
+
 func (c *CronJonBuilder) Build(job Job) cron.Job {
 	name := job.Name()
+	jl := &jobLock{}
 	return cronJobAdapterFunc(func() {
+		held, ok := c.hold(name, jl)
+		if !ok {
+			c.logger.Debug("其他实例正在执行任务", logger.String("name", name))
+			return
+		}
+
 		start := time.Now()
 		c.logger.Debug("start job", logger.String("name", name))
-		err := job.Run()
+		err := runJob(held.ctx, job, held.lock.Token())
 		if err != nil {
 			c.logger.Error("job failed",
 				logger.String("name", name),
@@ -41,3 +76,44 @@ func (c *CronJonBuilder) Build(job Job) cron.Job {
 		)
 	})
 }
+
+// hold 已经持有锁时直接返回，否则尝试抢锁，抢到后开始自动续约
+func (c *CronJonBuilder) hold(name string, jl *jobLock) (*heldLock, bool) {
+	jl.mu.Lock()
+	defer jl.mu.Unlock()
+	if jl.held != nil {
+		return jl.held, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	lock, err := c.lockClient.TryLock(ctx, "job:"+name, c.expiration)
+	if err != nil {
+		if !errors.Is(err, redislock.ErrFailedToPreemptLock) {
+			c.logger.Error("抢任务锁失败",
+				logger.String("name", name),
+				logger.Error(err),
+			)
+		}
+		return nil, false
+	}
+	jobCtx, jobCancel := context.WithCancel(context.Background())
+	held := &heldLock{lock: lock, ctx: jobCtx}
+	jl.held = held
+
+	go func() {
+		err := lock.AutoRefresh(c.expiration/3, c.timeout)
+		// 续约失败，锁可能已经被其他实例拿走，取消正在执行的任务，下次调度时重新抢
+		jobCancel()
+		c.logger.Error("任务锁续约失败",
+			logger.String("name", name),
+			logger.Error(err),
+		)
+		jl.mu.Lock()
+		if jl.held == held {
+			jl.held = nil
+		}
+		jl.mu.Unlock()
+	}()
+	return held, true
+}
@@ -0,0 +1,118 @@
+package job
+
+import (
+	"Webook/webook/internal/repository/cache/redismocks"
+	"Webook/webook/pkg/logger"
+	"Webook/webook/pkg/redislock"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestCronJonBuilder_Build(t *testing.T) {
+	t.Run("抢到锁后一直持有", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := redismocks.NewMockCmdable(ctrl)
+		res := redis.NewCmd(context.Background())
+		res.SetVal(int64(7))
+		// 只抢一次锁
+		client.EXPECT().Eval(gomock.Any(), gomock.Any(),
+			[]string{"job:mock", "job:mock:fencing_token"}, gomock.Any()).Return(res)
+
+		builder := NewCronJobBuilder(logger.NewZapLogger(zap.NewNop()), redislock.NewClient(client))
+		j := &mockJob{}
+		cronJob := builder.Build(j)
+		cronJob.Run()
+		cronJob.Run()
+		assert.Equal(t, []int64{7, 7}, j.tokens)
+		assert.Equal(t, 0, j.runs)
+	})
+
+	t.Run("锁被其他实例持有时不执行", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := redismocks.NewMockCmdable(ctrl)
+		res := redis.NewCmd(context.Background())
+		res.SetVal(int64(0))
+		client.EXPECT().Eval(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(res).Times(2)
+
+		builder := NewCronJobBuilder(logger.NewZapLogger(zap.NewNop()), redislock.NewClient(client))
+		j := &mockJob{}
+		cronJob := builder.Build(j)
+		cronJob.Run()
+		cronJob.Run()
+		assert.Empty(t, j.tokens)
+	})
+}
+
+// blockingJob 一直执行到 ctx 被取消
+type blockingJob struct {
+	tokens []int64
+}
+
+func (b *blockingJob) Name() string {
+	return "blocking"
+}
+
+func (b *blockingJob) Run() error {
+	return errors.New("不应该调用 Run")
+}
+
+func (b *blockingJob) RunWithToken(ctx context.Context, token int64) error {
+	b.tokens = append(b.tokens, token)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCronJonBuilder_LoseLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := redismocks.NewMockCmdable(ctrl)
+	locked := redis.NewCmd(context.Background())
+	locked.SetVal(int64(7))
+	timeout := redis.NewCmd(context.Background())
+	timeout.SetErr(context.DeadlineExceeded)
+	relocked := redis.NewCmd(context.Background())
+	relocked.SetVal(int64(8))
+	lockKeys := []string{"job:blocking", "job:blocking:fencing_token"}
+	gomock.InOrder(
+		client.EXPECT().Eval(gomock.Any(), gomock.Any(), lockKeys, gomock.Any()).Return(locked),
+		// 续约超时，锁可能已经丢了
+		client.EXPECT().Eval(gomock.Any(), gomock.Any(), []string{"job:blocking"}, gomock.Any()).Return(timeout),
+		// 下次调度重新抢锁
+		client.EXPECT().Eval(gomock.Any(), gomock.Any(), lockKeys, gomock.Any()).Return(relocked),
+		client.EXPECT().Eval(gomock.Any(), gomock.Any(), []string{"job:blocking"}, gomock.Any()).
+			Return(timeout).AnyTimes(),
+	)
+
+	builder := NewCronJobBuilder(logger.NewZapLogger(zap.NewNop()), redislock.NewClient(client))
+	builder.expiration = time.Millisecond * 30
+	j := &blockingJob{}
+	cronJob := builder.Build(j)
+
+	done := make(chan struct{})
+	go func() {
+		// 续约失败后任务被取消才会返回
+		cronJob.Run()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("失去锁后任务没有被取消")
+	}
+	// 等续约协程清掉本地持有的锁
+	time.Sleep(time.Millisecond * 10)
+	cronJob.Run()
+	assert.Equal(t, []int64{7, 8}, j.tokens)
+}
@@ -0,0 +1,39 @@
+package job
+
+import (
+	"Webook/webook/internal/service"
+	"context"
+	"time"
+)
+
+// CronJobExecutionCleanJob 清理过期的定时任务执行记录
+type CronJobExecutionCleanJob struct {
+	svc service.CronJobService
+	// 执行记录保留多久
+	retention time.Duration
+	timeout   time.Duration
+}
+
+func NewCronJobExecutionCleanJob(svc service.CronJobService, retention time.Duration, timeout time.Duration) *CronJobExecutionCleanJob {
+	return &CronJobExecutionCleanJob{
+		svc:       svc,
+		retention: retention,
+		timeout:   timeout,
+	}
+}
+
+func (c *CronJobExecutionCleanJob) Name() string {
+	return "cron_job_execution_clean"
+}
+
+func (c *CronJobExecutionCleanJob) Run() error {
+	return c.RunContext(context.Background())
+}
+
+func (c *CronJobExecutionCleanJob) RunContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, err := c.svc.CleanExecutions(ctx, time.Now().Add(-c.retention))
+	return err
+}
@@ -1,6 +1,32 @@
 package job
 
+import "context"
+
 type Job interface {
 	Name() string
 	Run() error
 }
+
+// ContextJob 可以取消的任务，任务被其他节点接手后调度器会取消 ctx
+type ContextJob interface {
+	Job
+	RunContext(ctx context.Context) error
+}
+
+// FencedJob 需要 fencing token 的任务，把 token 带到写入的地方，
+// 避免任务被其他节点接手后，还在执行的旧持有者覆盖新持有者的结果
+type FencedJob interface {
+	Job
+	RunWithToken(ctx context.Context, token int64) error
+}
+
+// runJob 按任务支持的方式执行：需要 token 的带上 token，可以取消的带上 ctx
+func runJob(ctx context.Context, job Job, token int64) error {
+	if fenced, ok := job.(FencedJob); ok {
+		return fenced.RunWithToken(ctx, token)
+	}
+	if cj, ok := job.(ContextJob); ok {
+		return cj.RunContext(ctx)
+	}
+	return job.Run()
+}
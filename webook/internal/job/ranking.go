@@ -2,28 +2,101 @@ package job
 
 import (
 	"Webook/webook/internal/service"
+	"Webook/webook/pkg/fencing"
 	"context"
 	"time"
 )
 
+// RankingJob 计算一个榜单
 type RankingJob struct {
 	svc     service.RankingService
+	board   string
 	timeout time.Duration
 }
 
-func NewRankingJob(svc service.RankingService, timeout time.Duration) *RankingJob {
+func NewRankingJob(svc service.RankingService, board string, timeout time.Duration) *RankingJob {
 	return &RankingJob{
 		svc:     svc,
+		board:   board,
 		timeout: timeout,
 	}
 }
 func (r *RankingJob) Name() string {
-	return "ranking"
+	return "ranking:" + r.board
 }
 
 func (r *RankingJob) Run() error {
-	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	return r.RunContext(context.Background())
+}
+
+func (r *RankingJob) RunContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.svc.SetTopN(ctx, r.board)
+}
+
+// RunWithToken 写入榜单缓存时检查 fencing token
+func (r *RankingJob) RunWithToken(ctx context.Context, token int64) error {
+	return r.RunContext(fencing.WithToken(ctx, token))
+}
+
+// RankingDecayJob 增量榜单的时间衰减
+type RankingDecayJob struct {
+	svc     service.RankingService
+	board   string
+	timeout time.Duration
+}
+
+func NewRankingDecayJob(svc service.RankingService, board string, timeout time.Duration) *RankingDecayJob {
+	return &RankingDecayJob{
+		svc:     svc,
+		board:   board,
+		timeout: timeout,
+	}
+}
+
+func (r *RankingDecayJob) Name() string {
+	return "ranking_decay:" + r.board
+}
+
+func (r *RankingDecayJob) Run() error {
+	return r.RunContext(context.Background())
+}
+
+func (r *RankingDecayJob) RunContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.svc.Decay(ctx, r.board)
+}
+
+// RankingRebuildJob 用全量计算重建增量榜单
+type RankingRebuildJob struct {
+	svc     service.RankingService
+	board   string
+	timeout time.Duration
+}
+
+func NewRankingRebuildJob(svc service.RankingService, board string, timeout time.Duration) *RankingRebuildJob {
+	return &RankingRebuildJob{
+		svc:     svc,
+		board:   board,
+		timeout: timeout,
+	}
+}
+
+func (r *RankingRebuildJob) Name() string {
+	return "ranking_rebuild:" + r.board
+}
+
+func (r *RankingRebuildJob) Run() error {
+	return r.RunContext(context.Background())
+}
+
+func (r *RankingRebuildJob) RunContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	return r.svc.SetTop100(ctx)
+	return r.svc.Rebuild(ctx, r.board)
 }
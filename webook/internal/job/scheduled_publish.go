@@ -0,0 +1,38 @@
+package job
+
+import (
+	"Webook/webook/internal/service"
+	"context"
+	"time"
+)
+
+// ScheduledPublishJob 定时发布：把到期的定时文章发布到线上库
+type ScheduledPublishJob struct {
+	svc       service.ArticleService
+	timeout   time.Duration
+	batchSize int
+}
+
+func NewScheduledPublishJob(svc service.ArticleService, timeout time.Duration) *ScheduledPublishJob {
+	return &ScheduledPublishJob{
+		svc:       svc,
+		timeout:   timeout,
+		batchSize: 100,
+	}
+}
+
+func (s *ScheduledPublishJob) Name() string {
+	return "scheduled_publish"
+}
+
+func (s *ScheduledPublishJob) Run() error {
+	return s.RunContext(context.Background())
+}
+
+func (s *ScheduledPublishJob) RunContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := s.svc.PublishDue(ctx, time.Now(), s.batchSize)
+	return err
+}
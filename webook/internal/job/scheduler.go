@@ -0,0 +1,224 @@
+package job
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Executor 执行抢占到的任务，任务的 Executor 字段决定交给哪个执行器
+type Executor interface {
+	Name() string
+	Exec(ctx context.Context, j domain.CronJob) error
+}
+
+// LocalExecutor 执行本进程中注册的 Job，按任务名字找到对应的 Job
+type LocalExecutor struct {
+	mu   sync.RWMutex
+	jobs map[string]Job
+}
+
+func NewLocalExecutor() *LocalExecutor {
+	return &LocalExecutor{
+		jobs: make(map[string]Job),
+	}
+}
+
+func (e *LocalExecutor) Name() string {
+	return "local"
+}
+
+func (e *LocalExecutor) Register(job Job) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.jobs[job.Name()] = job
+}
+
+func (e *LocalExecutor) Exec(ctx context.Context, j domain.CronJob) error {
+	e.mu.RLock()
+	job, ok := e.jobs[j.Name]
+	e.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("未注册的本地任务 %s", j.Name)
+	}
+	// 每次抢占版本号加一，正好作为 fencing token
+	return runJob(ctx, job, j.Version)
+}
+
+// Scheduler 任务保存在数据库中，各个节点抢占到期的任务执行。
+// 执行期间定时续约，执行完释放并计算下一次执行时间；
+// 持有者宕机后心跳超时，任务会被其他节点接手
+type Scheduler struct {
+	svc    service.CronJobService
+	logger logger.Logger
+
+	executors map[string]Executor
+	local     *LocalExecutor
+
+	// 同时执行的任务数
+	limiter chan struct{}
+	// 没有可以执行的任务时，隔多久再抢
+	interval time.Duration
+	timeout  time.Duration
+
+	cancel context.CancelFunc
+	// 抢占的协程退出后关闭
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewScheduler(svc service.CronJobService, l logger.Logger) *Scheduler {
+	local := NewLocalExecutor()
+	return &Scheduler{
+		svc:       svc,
+		logger:    l,
+		executors: map[string]Executor{local.Name(): local},
+		local:     local,
+		limiter:   make(chan struct{}, 10),
+		interval:  time.Second,
+		timeout:   time.Second,
+	}
+}
+
+// RegisterExecutor 需要在 Start 之前调用
+func (s *Scheduler) RegisterExecutor(e Executor) {
+	s.executors[e.Name()] = e
+}
+
+// RegisterJob 注册本地任务，expression 是 cron 表达式
+func (s *Scheduler) RegisterJob(ctx context.Context, job Job, expression string) error {
+	s.local.Register(job)
+	return s.svc.Register(ctx, domain.CronJob{
+		Name:       job.Name(),
+		Executor:   s.local.Name(),
+		Expression: expression,
+	})
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go s.loop(ctx)
+}
+
+// Stop 停止抢占，等待正在执行的任务结束
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	finished := make(chan struct{})
+	go func() {
+		<-s.done
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+	for {
+		select {
+		case s.limiter <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		pctx, cancel := context.WithTimeout(ctx, s.timeout)
+		j, err := s.svc.Preempt(pctx)
+		cancel()
+		if err != nil {
+			<-s.limiter
+			if !errors.Is(err, service.ErrNoCronJob) && ctx.Err() == nil {
+				s.logger.Error("抢占任务失败", logger.Error(err))
+			}
+			select {
+			case <-time.After(s.interval):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer func() {
+				j.CancelFunc()
+				<-s.limiter
+				s.wg.Done()
+			}()
+			s.exec(j)
+		}()
+	}
+}
+
+func (s *Scheduler) exec(j domain.CronJob) {
+	eid := s.startExecution(j)
+	start := time.Now()
+	s.logger.Debug("start job",
+		logger.String("name", j.Name),
+		logger.Int64("version", j.Version),
+	)
+	err := s.run(j)
+	if err != nil {
+		s.logger.Error("job failed",
+			logger.String("name", j.Name),
+			logger.Error(err),
+		)
+	}
+	s.logger.Debug("finish job",
+		logger.String("name", j.Name),
+		logger.String("duration", time.Since(start).String()),
+	)
+	s.finishExecution(j, eid, err)
+}
+
+func (s *Scheduler) run(j domain.CronJob) error {
+	executor, ok := s.executors[j.Executor]
+	if !ok {
+		return fmt.Errorf("找不到任务的执行器 %s", j.Executor)
+	}
+	// 退出时等任务执行完，不随 Stop 取消，只在任务被其他节点接手时取消
+	return executor.Exec(j.Ctx, j)
+}
+
+// startExecution 执行记录写入失败不影响任务执行，返回 0
+func (s *Scheduler) startExecution(j domain.CronJob) int64 {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	eid, err := s.svc.StartExecution(ctx, j)
+	if err != nil {
+		s.logger.Error("记录任务执行失败",
+			logger.String("name", j.Name),
+			logger.Error(err),
+		)
+		return 0
+	}
+	return eid
+}
+
+func (s *Scheduler) finishExecution(j domain.CronJob, eid int64, execErr error) {
+	if eid == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := s.svc.FinishExecution(ctx, eid, execErr); err != nil {
+		s.logger.Error("记录任务执行结果失败",
+			logger.String("name", j.Name),
+			logger.Int64("executionId", eid),
+			logger.Error(err),
+		)
+	}
+}
@@ -0,0 +1,118 @@
+package job
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	svcmocks "Webook/webook/internal/service/mocks"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+type mockJob struct {
+	runs   int
+	tokens []int64
+}
+
+func (m *mockJob) Name() string {
+	return "mock"
+}
+
+func (m *mockJob) Run() error {
+	m.runs++
+	return nil
+}
+
+func (m *mockJob) RunWithToken(ctx context.Context, token int64) error {
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func TestScheduler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := svcmocks.NewMockCronJobService(ctrl)
+	svc.EXPECT().Register(gomock.Any(), domain.CronJob{
+		Name:       "mock",
+		Executor:   "local",
+		Expression: "@every 1m",
+	}).Return(nil)
+
+	released := make(chan struct{})
+	gomock.InOrder(
+		svc.EXPECT().Preempt(gomock.Any()).Return(domain.CronJob{
+			Name:       "mock",
+			Executor:   "local",
+			Version:    3,
+			Ctx:        context.Background(),
+			CancelFunc: func() { close(released) },
+		}, nil),
+		// 抢占失败时等一会儿再抢
+		svc.EXPECT().Preempt(gomock.Any()).Return(domain.CronJob{}, errors.New("db error")),
+		svc.EXPECT().Preempt(gomock.Any()).Return(domain.CronJob{}, service.ErrNoCronJob).AnyTimes(),
+	)
+
+	// 记录执行
+	svc.EXPECT().StartExecution(gomock.Any(), gomock.Any()).Return(int64(11), nil)
+	svc.EXPECT().FinishExecution(gomock.Any(), int64(11), nil).Return(nil)
+
+	s := NewScheduler(svc, logger.NewZapLogger(zap.NewNop()))
+	s.interval = time.Millisecond * 10
+	j := &mockJob{}
+	require.NoError(t, s.RegisterJob(context.Background(), j, "@every 1m"))
+	s.Start(context.Background())
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("任务没有执行")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, s.Stop(ctx))
+	// 版本号作为 fencing token
+	assert.Equal(t, []int64{3}, j.tokens)
+}
+
+func TestScheduler_exec(t *testing.T) {
+	t.Run("找不到执行器时记录失败", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := svcmocks.NewMockCronJobService(ctrl)
+		svc.EXPECT().StartExecution(gomock.Any(), gomock.Any()).Return(int64(11), nil)
+		svc.EXPECT().FinishExecution(gomock.Any(), int64(11), gomock.Not(nil)).Return(nil)
+
+		s := NewScheduler(svc, logger.NewZapLogger(zap.NewNop()))
+		s.exec(domain.CronJob{Name: "mock", Executor: "remote", Ctx: context.Background()})
+	})
+
+	t.Run("执行记录写入失败时照常执行", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := svcmocks.NewMockCronJobService(ctrl)
+		svc.EXPECT().Register(gomock.Any(), gomock.Any()).Return(nil)
+		svc.EXPECT().StartExecution(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("db error"))
+
+		s := NewScheduler(svc, logger.NewZapLogger(zap.NewNop()))
+		j := &mockJob{}
+		require.NoError(t, s.RegisterJob(context.Background(), j, "@every 1m"))
+		s.exec(domain.CronJob{Name: "mock", Executor: "local", Version: 5, Ctx: context.Background()})
+		assert.Equal(t, []int64{5}, j.tokens)
+	})
+}
+
+func TestLocalExecutor_Exec(t *testing.T) {
+	e := NewLocalExecutor()
+	err := e.Exec(context.Background(), domain.CronJob{Name: "mock"})
+	assert.Error(t, err)
+}
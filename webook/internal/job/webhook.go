@@ -0,0 +1,38 @@
+package job
+
+import (
+	"Webook/webook/internal/service"
+	"context"
+	"time"
+)
+
+// WebhookRetryJob 发送到期的 webhook 投递，包括新产生的和等待重试的
+type WebhookRetryJob struct {
+	svc       service.WebhookService
+	timeout   time.Duration
+	batchSize int
+}
+
+func NewWebhookRetryJob(svc service.WebhookService, timeout time.Duration) *WebhookRetryJob {
+	return &WebhookRetryJob{
+		svc:       svc,
+		timeout:   timeout,
+		batchSize: 20,
+	}
+}
+
+func (w *WebhookRetryJob) Name() string {
+	return "webhook_retry"
+}
+
+func (w *WebhookRetryJob) Run() error {
+	return w.RunContext(context.Background())
+}
+
+func (w *WebhookRetryJob) RunContext(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	_, err := w.svc.DeliverDue(ctx, time.Now(), w.batchSize)
+	return err
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ecodeclub/ekit/slice"
+	"go.mongodb.org/mongo-driver/mongo"
 	"gorm.io/gorm"
 )
 
@@ -22,9 +23,35 @@ type ArticleRepository interface {
 	List(ctx context.Context, userId int64, limit int, offset int) ([]domain.Article, error)
 	FindById(ctx context.Context, id int64) (domain.Article, error)
 	FindPublishedArticleById(ctx context.Context, id int64) (domain.Article, error)
-	FindPublishedArticleList(ctx context.Context, end time.Time, offset int, limit int) ([]domain.Article, error)
+	// FindPublishedArticlesByIds 批量获取已发表的文章，按 ids 的顺序返回，未发表的文章会被跳过
+	FindPublishedArticlesByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
+	FindPublishedArticleList(ctx context.Context, end time.Time, offset int, limit int, filter domain.ArticleFilter) ([]domain.Article, error)
+	// FindFeedOutbox 作者们已发表文章中排在游标之后的 limit 条，用于拉模式的动态流
+	FindFeedOutbox(ctx context.Context, authorIds []int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error)
+	// FindPublishedAfter 按 id 升序遍历已发表的文章，不带标签
+	FindPublishedAfter(ctx context.Context, id int64, limit int) ([]domain.Article, error)
+
+	// 标签：按使用该标签的已发表文章数降序，prefix 为空时返回热门标签
+	FindTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error)
+
+	// 历史版本
+	GetRevisions(ctx context.Context, artId int64, authorId int64, limit int, offset int) ([]domain.ArticleRevision, error)
+	FindRevisionById(ctx context.Context, id int64) (domain.ArticleRevision, error)
+
+	// 定时发布
+	// Schedule 保存定时发布的文章，只写写者库，到点后由 PublishScheduled 写入线上库
+	Schedule(ctx context.Context, art domain.Article) (int64, error)
+	CancelSchedule(ctx context.Context, art domain.Article) (int64, error)
+	FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error)
+	PublishScheduled(ctx context.Context, art domain.Article) error
 }
 
+var (
+	ErrRevisionNotFound = errors.New("历史版本不存在")
+	ErrArticleNotFound  = errors.New("文章不存在")
+	ErrNotScheduled     = article.ErrNotScheduled
+)
+
 type CachedArticleRepository struct {
 	dao   article.ArticleDAO
 	cache cache.ArticleCache
@@ -33,7 +60,8 @@ type CachedArticleRepository struct {
 	logger   logger.Logger
 }
 
-func NewArticleRepository(dao article.ArticleDAO, cache cache.ArticleCache, userRepo repository.UserRepository, logger logger.Logger) ArticleRepository {
+func NewArticleRepository(dao article.ArticleDAO, cache cache.ArticleCache, userRepo repository.UserRepository,
+	logger logger.Logger) ArticleRepository {
 	return &CachedArticleRepository{
 		dao:      dao,
 		cache:    cache,
@@ -225,6 +253,13 @@ func (c *CachedArticleRepository) FindById(ctx context.Context, id int64) (domai
 
 	// 缓存未命中，从数据库中获取
 	art, err := c.dao.FindById(ctx, id)
+	if err == gorm.ErrRecordNotFound || err == mongo.ErrNoDocuments {
+		return domain.Article{}, ErrArticleNotFound
+	}
+	if err != nil {
+		return domain.Article{}, err
+	}
+	art.Tags, err = c.dao.GetTags(ctx, id)
 	if err != nil {
 		return domain.Article{}, err
 	}
@@ -259,6 +294,13 @@ func (c *CachedArticleRepository) FindPublishedArticleById(ctx context.Context,
 		return domain.Article{}, err
 	}
 
+	// 获取标签
+	tags, err := c.dao.GetPublishedTagsByIds(ctx, []int64{id})
+	if err != nil {
+		return domain.Article{}, err
+	}
+	artPublic_published.Tags = tags[id]
+
 	// 获取作者信息
 	artPublic := ToArticleDomain(artPublic_published.Article)
 	author, err := c.userRepo.FindById(ctx, artPublic.Author.Id)
@@ -280,38 +322,223 @@ func (c *CachedArticleRepository) FindPublishedArticleById(ctx context.Context,
 	return artPublic, nil
 }
 
-func (c *CachedArticleRepository) FindPublishedArticleList(ctx context.Context, end time.Time, offset int, limit int) ([]domain.Article, error) {
-	arts, err := c.dao.FindPublishedArticleList(ctx, end, offset, limit)
+func (c *CachedArticleRepository) FindPublishedAfter(ctx context.Context, id int64, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.FindPublishedAfter(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[article.PublishedArticle, domain.Article](arts,
+		func(idx int, src article.PublishedArticle) domain.Article {
+			return ToArticleDomain(src.Article)
+		}), nil
+}
+
+func (c *CachedArticleRepository) FindPublishedArticleList(ctx context.Context, end time.Time, offset int, limit int, filter domain.ArticleFilter) ([]domain.Article, error) {
+	arts, err := c.dao.FindPublishedArticleList(ctx, end, offset, limit, article.ArticleFilter{
+		Tag:      filter.Tag,
+		Category: filter.Category,
+	})
+	if err != nil || len(arts) == 0 {
+		return nil, err
+	}
+
+	// 批量获取标签
+	ids := slice.Map[article.PublishedArticle, int64](arts,
+		func(idx int, src article.PublishedArticle) int64 {
+			return src.Id
+		})
+	tags, err := c.dao.GetPublishedTagsByIds(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 	return slice.Map[article.PublishedArticle, domain.Article](arts,
 		func(idx int, src article.PublishedArticle) domain.Article {
+			src.Tags = tags[src.Id]
 			return ToArticleDomain(src.Article)
 		}), nil
 }
+
+func (c *CachedArticleRepository) FindPublishedArticlesByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+	arts, err := c.dao.FindPublicByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := c.dao.GetPublishedTagsByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	artMap := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		art.Tags = tags[art.Id]
+		artMap[art.Id] = ToArticleDomain(art.Article)
+	}
+	res := make([]domain.Article, 0, len(arts))
+	for _, id := range ids {
+		if art, ok := artMap[id]; ok {
+			res = append(res, art)
+		}
+	}
+	return res, nil
+}
+
+func (c *CachedArticleRepository) FindFeedOutbox(ctx context.Context, authorIds []int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error) {
+	if len(authorIds) == 0 {
+		return []domain.FeedItem{}, nil
+	}
+	var utime int64
+	if !cursor.Time.IsZero() {
+		utime = cursor.Time.UnixMilli()
+	}
+	arts, err := c.dao.FindPublishedByAuthors(ctx, authorIds, utime, cursor.ArticleId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src article.PublishedArticle) domain.FeedItem {
+		return domain.FeedItem{
+			ArticleId: src.Id,
+			AuthorId:  src.AuthorId,
+			Time:      time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
+
+func (c *CachedArticleRepository) FindTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	cnts, err := c.dao.FindTagCnts(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[article.TagCnt, domain.Tag](cnts,
+		func(idx int, src article.TagCnt) domain.Tag {
+			return domain.Tag{
+				Name: src.Tag,
+				Cnt:  src.Cnt,
+			}
+		}), nil
+}
+func (c *CachedArticleRepository) GetRevisions(ctx context.Context, artId int64, authorId int64, limit int, offset int) ([]domain.ArticleRevision, error) {
+	revs, err := c.dao.GetRevisions(ctx, artId, authorId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[article.ArticleRevision, domain.ArticleRevision](revs,
+		func(idx int, src article.ArticleRevision) domain.ArticleRevision {
+			return ToRevisionDomain(src)
+		}), nil
+}
+
+func (c *CachedArticleRepository) FindRevisionById(ctx context.Context, id int64) (domain.ArticleRevision, error) {
+	rev, err := c.dao.FindRevisionById(ctx, id)
+	if err == gorm.ErrRecordNotFound || err == mongo.ErrNoDocuments {
+		return domain.ArticleRevision{}, ErrRevisionNotFound
+	}
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	return ToRevisionDomain(rev), nil
+}
+
+func (c *CachedArticleRepository) Schedule(ctx context.Context, art domain.Article) (int64, error) {
+	defer c.delCache(ctx, "Schedule", art)
+	return c.dao.Schedule(ctx, ToArticleEntity(art))
+}
+
+func (c *CachedArticleRepository) CancelSchedule(ctx context.Context, art domain.Article) (int64, error) {
+	defer c.delCache(ctx, "CancelSchedule", art)
+	return c.dao.CancelSchedule(ctx, ToArticleEntity(art))
+}
+
+func (c *CachedArticleRepository) FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.FindDueScheduled(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[article.Article, domain.Article](arts,
+		func(idx int, src article.Article) domain.Article {
+			return ToArticleDomain(src)
+		}), nil
+}
+
+func (c *CachedArticleRepository) PublishScheduled(ctx context.Context, art domain.Article) error {
+	defer c.delCache(ctx, "PublishScheduled", art)
+	return c.dao.PublishScheduled(ctx, ToArticleEntity(art))
+}
+
+// delCache 数据修改后删除作者第一页、线上文章和文章详情的缓存
+func (c *CachedArticleRepository) delCache(ctx context.Context, op string, art domain.Article) {
+	if err := c.cache.DelFirstPage(ctx, art.Author.Id); err != nil {
+		c.logger.Error(op+" Article 后删除缓存 FirstPage 失败",
+			logger.Int64("userId", art.Author.Id),
+			logger.Error(err),
+		)
+	}
+
+	if err := c.cache.DelPublic(ctx, art.Id); err != nil {
+		c.logger.Error(op+" Article 后删除缓存 Public Article 失败",
+			logger.Int64("articleId", art.Id),
+			logger.Error(err),
+		)
+	}
+
+	if err := c.cache.Del(ctx, art.Id); err != nil {
+		c.logger.Error(op+" Article 后删除缓存 article 失败",
+			logger.Int64("articleId", art.Id),
+			logger.Error(err),
+		)
+	}
+}
+
 func ToArticleEntity(art domain.Article) article.Article {
-	return article.Article{
+	res := article.Article{
 		Id:       art.Id,
 		Title:    art.Title,
 		Content:  art.Content,
 		AuthorId: art.Author.Id,
 		Status:   art.Status.ToUint8(),
+		Category: art.Category,
+		Tags:     art.Tags,
 		Ctime:    art.Ctime.UnixMilli(),
 		Utime:    art.Utime.UnixMilli(),
 	}
+	// 零值时间的 UnixMilli 是负数，未设置定时发布时存 0
+	if !art.PublishAt.IsZero() {
+		res.PublishAt = art.PublishAt.UnixMilli()
+	}
+	return res
 }
 
 func ToArticleDomain(art article.Article) domain.Article {
-	return domain.Article{
+	res := domain.Article{
 		Id:      art.Id,
 		Title:   art.Title,
 		Content: art.Content,
 		Author: domain.Author{
 			Id: art.AuthorId,
 		},
-		Status: domain.ArticleStatus(art.Status),
-		Ctime:  time.UnixMilli(art.Ctime),
-		Utime:  time.UnixMilli(art.Utime),
+		Status:   domain.ArticleStatus(art.Status),
+		Category: art.Category,
+		Tags:     art.Tags,
+		Ctime:    time.UnixMilli(art.Ctime),
+		Utime:    time.UnixMilli(art.Utime),
+	}
+	if art.PublishAt > 0 {
+		res.PublishAt = time.UnixMilli(art.PublishAt)
+	}
+	return res
+}
+
+func ToRevisionDomain(rev article.ArticleRevision) domain.ArticleRevision {
+	return domain.ArticleRevision{
+		Id:        rev.Id,
+		ArticleId: rev.ArticleId,
+		Title:     rev.Title,
+		Content:   rev.Content,
+		Author: domain.Author{
+			Id: rev.AuthorId,
+		},
+		Ctime: time.UnixMilli(rev.Ctime),
 	}
 }
@@ -42,6 +42,21 @@ func (m *MockArticleRepository) EXPECT() *MockArticleRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CancelSchedule mocks base method.
+func (m *MockArticleRepository) CancelSchedule(ctx context.Context, art domain.Article) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, art)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockArticleRepositoryMockRecorder) CancelSchedule(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockArticleRepository)(nil).CancelSchedule), ctx, art)
+}
+
 // Create mocks base method.
 func (m *MockArticleRepository) Create(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +87,51 @@ func (mr *MockArticleRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockArticleRepository)(nil).FindById), ctx, id)
 }
 
+// FindDueScheduled mocks base method.
+func (m *MockArticleRepository) FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDueScheduled", ctx, now, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDueScheduled indicates an expected call of FindDueScheduled.
+func (mr *MockArticleRepositoryMockRecorder) FindDueScheduled(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDueScheduled", reflect.TypeOf((*MockArticleRepository)(nil).FindDueScheduled), ctx, now, limit)
+}
+
+// FindFeedOutbox mocks base method.
+func (m *MockArticleRepository) FindFeedOutbox(ctx context.Context, authorIds []int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFeedOutbox", ctx, authorIds, cursor, limit)
+	ret0, _ := ret[0].([]domain.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFeedOutbox indicates an expected call of FindFeedOutbox.
+func (mr *MockArticleRepositoryMockRecorder) FindFeedOutbox(ctx, authorIds, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFeedOutbox", reflect.TypeOf((*MockArticleRepository)(nil).FindFeedOutbox), ctx, authorIds, cursor, limit)
+}
+
+// FindPublishedAfter mocks base method.
+func (m *MockArticleRepository) FindPublishedAfter(ctx context.Context, id int64, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPublishedAfter", ctx, id, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPublishedAfter indicates an expected call of FindPublishedAfter.
+func (mr *MockArticleRepositoryMockRecorder) FindPublishedAfter(ctx, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPublishedAfter", reflect.TypeOf((*MockArticleRepository)(nil).FindPublishedAfter), ctx, id, limit)
+}
+
 // FindPublishedArticleById mocks base method.
 func (m *MockArticleRepository) FindPublishedArticleById(ctx context.Context, id int64) (domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -88,18 +148,78 @@ func (mr *MockArticleRepositoryMockRecorder) FindPublishedArticleById(ctx, id an
 }
 
 // FindPublishedArticleList mocks base method.
-func (m *MockArticleRepository) FindPublishedArticleList(ctx context.Context, end time.Time, offset, limit int) ([]domain.Article, error) {
+func (m *MockArticleRepository) FindPublishedArticleList(ctx context.Context, end time.Time, offset, limit int, filter domain.ArticleFilter) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindPublishedArticleList", ctx, end, offset, limit)
+	ret := m.ctrl.Call(m, "FindPublishedArticleList", ctx, end, offset, limit, filter)
 	ret0, _ := ret[0].([]domain.Article)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindPublishedArticleList indicates an expected call of FindPublishedArticleList.
-func (mr *MockArticleRepositoryMockRecorder) FindPublishedArticleList(ctx, end, offset, limit any) *gomock.Call {
+func (mr *MockArticleRepositoryMockRecorder) FindPublishedArticleList(ctx, end, offset, limit, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPublishedArticleList", reflect.TypeOf((*MockArticleRepository)(nil).FindPublishedArticleList), ctx, end, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPublishedArticleList", reflect.TypeOf((*MockArticleRepository)(nil).FindPublishedArticleList), ctx, end, offset, limit, filter)
+}
+
+// FindPublishedArticlesByIds mocks base method.
+func (m *MockArticleRepository) FindPublishedArticlesByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPublishedArticlesByIds", ctx, ids)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPublishedArticlesByIds indicates an expected call of FindPublishedArticlesByIds.
+func (mr *MockArticleRepositoryMockRecorder) FindPublishedArticlesByIds(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPublishedArticlesByIds", reflect.TypeOf((*MockArticleRepository)(nil).FindPublishedArticlesByIds), ctx, ids)
+}
+
+// FindRevisionById mocks base method.
+func (m *MockArticleRepository) FindRevisionById(ctx context.Context, id int64) (domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevisionById", ctx, id)
+	ret0, _ := ret[0].(domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevisionById indicates an expected call of FindRevisionById.
+func (mr *MockArticleRepositoryMockRecorder) FindRevisionById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevisionById", reflect.TypeOf((*MockArticleRepository)(nil).FindRevisionById), ctx, id)
+}
+
+// FindTags mocks base method.
+func (m *MockArticleRepository) FindTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTags", ctx, prefix, limit)
+	ret0, _ := ret[0].([]domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTags indicates an expected call of FindTags.
+func (mr *MockArticleRepositoryMockRecorder) FindTags(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTags", reflect.TypeOf((*MockArticleRepository)(nil).FindTags), ctx, prefix, limit)
+}
+
+// GetRevisions mocks base method.
+func (m *MockArticleRepository) GetRevisions(ctx context.Context, artId, authorId int64, limit, offset int) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, artId, authorId, limit, offset)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockArticleRepositoryMockRecorder) GetRevisions(ctx, artId, authorId, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockArticleRepository)(nil).GetRevisions), ctx, artId, authorId, limit, offset)
 }
 
 // List mocks base method.
@@ -117,6 +237,35 @@ func (mr *MockArticleRepositoryMockRecorder) List(ctx, userId, limit, offset any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArticleRepository)(nil).List), ctx, userId, limit, offset)
 }
 
+// PublishScheduled mocks base method.
+func (m *MockArticleRepository) PublishScheduled(ctx context.Context, art domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduled", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishScheduled indicates an expected call of PublishScheduled.
+func (mr *MockArticleRepositoryMockRecorder) PublishScheduled(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduled", reflect.TypeOf((*MockArticleRepository)(nil).PublishScheduled), ctx, art)
+}
+
+// Schedule mocks base method.
+func (m *MockArticleRepository) Schedule(ctx context.Context, art domain.Article) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", ctx, art)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockArticleRepositoryMockRecorder) Schedule(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockArticleRepository)(nil).Schedule), ctx, art)
+}
+
 // Sync mocks base method.
 func (m *MockArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
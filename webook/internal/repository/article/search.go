@@ -0,0 +1,91 @@
+package article
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/pkg/search"
+	"context"
+	"strconv"
+	"time"
+)
+
+// ArticleSearchRepository 线上文章的全文检索
+type ArticleSearchRepository interface {
+	// InputArticle 写入或覆盖索引中的文章
+	InputArticle(ctx context.Context, art domain.Article) error
+	DeleteArticle(ctx context.Context, id int64) error
+	// SearchArticle 返回当前页的结果和命中总数
+	SearchArticle(ctx context.Context, keyword string, offset int, limit int) ([]domain.ArticleSearchHit, int, error)
+	// Rebuild 用 arts 替换索引中的所有文章
+	Rebuild(ctx context.Context, arts []domain.Article) error
+}
+
+const (
+	fieldTitle   = "title"
+	fieldContent = "content"
+
+	extraAuthorId = "author_id"
+	extraUtime    = "utime"
+)
+
+// LocalArticleSearchRepository 基于嵌入式倒排索引的实现
+type LocalArticleSearchRepository struct {
+	index *search.Index
+}
+
+func NewLocalArticleSearchRepository(index *search.Index) ArticleSearchRepository {
+	return &LocalArticleSearchRepository{
+		index: index,
+	}
+}
+
+func (r *LocalArticleSearchRepository) InputArticle(ctx context.Context, art domain.Article) error {
+	return r.index.Put(r.toDocument(art))
+}
+
+func (r *LocalArticleSearchRepository) Rebuild(ctx context.Context, arts []domain.Article) error {
+	docs := make([]search.Document, 0, len(arts))
+	for _, art := range arts {
+		docs = append(docs, r.toDocument(art))
+	}
+	return r.index.Replace(docs)
+}
+
+func (r *LocalArticleSearchRepository) toDocument(art domain.Article) search.Document {
+	return search.Document{
+		Id: art.Id,
+		Fields: map[string]string{
+			fieldTitle:   art.Title,
+			fieldContent: art.Content,
+		},
+		Extra: map[string]string{
+			extraAuthorId: strconv.FormatInt(art.Author.Id, 10),
+			extraUtime:    strconv.FormatInt(art.Utime.UnixMilli(), 10),
+		},
+	}
+}
+
+func (r *LocalArticleSearchRepository) DeleteArticle(ctx context.Context, id int64) error {
+	return r.index.Delete(id)
+}
+
+func (r *LocalArticleSearchRepository) SearchArticle(ctx context.Context, keyword string, offset int, limit int) ([]domain.ArticleSearchHit, int, error) {
+	hits, total := r.index.Search(keyword, offset, limit)
+	res := make([]domain.ArticleSearchHit, 0, len(hits))
+	for _, hit := range hits {
+		authorId, _ := strconv.ParseInt(hit.Extra[extraAuthorId], 10, 64)
+		utime, _ := strconv.ParseInt(hit.Extra[extraUtime], 10, 64)
+		res = append(res, domain.ArticleSearchHit{
+			Article: domain.Article{
+				Id:      hit.Id,
+				Title:   hit.Fields[fieldTitle],
+				Content: hit.Fields[fieldContent],
+				Author:  domain.Author{Id: authorId},
+				Status:  domain.ArticleStatusPublished,
+				Utime:   time.UnixMilli(utime),
+			},
+			TitleHighlight:   hit.Highlights[fieldTitle],
+			ContentHighlight: hit.Highlights[fieldContent],
+		})
+	}
+	return res, total, nil
+}
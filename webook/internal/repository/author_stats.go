@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/cache"
+	"Webook/webook/internal/repository/dao"
+	"Webook/webook/pkg/logger"
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+const authorStatsDayLayout = time.DateOnly
+
+type AuthorStatsRepository interface {
+	// IncrStats 按作者和日期累加阅读、点赞、收藏数
+	IncrStats(ctx context.Context, stats []domain.AuthorStats) error
+	// IncrPublishCnt 每篇文章只计一次
+	IncrPublishCnt(ctx context.Context, authorId int64, artId int64, day time.Time) error
+	// GetDaily [start, end] 之间有数据的日期，按日期升序
+	GetDaily(ctx context.Context, authorId int64, start time.Time, end time.Time) ([]domain.AuthorStats, error)
+	// GetTopAuthors 最近 days 天的作者榜单
+	GetTopAuthors(ctx context.Context, days int, weights domain.AuthorScoreWeights, limit int) ([]domain.AuthorRankItem, error)
+	// MarkEvent 标记事件已经计入汇总，重新投递的事件返回 false
+	MarkEvent(ctx context.Context, eventId string) (bool, error)
+}
+
+type authorStatsRepository struct {
+	dao    dao.AuthorStatsDAO
+	cache  cache.AuthorStatsCache
+	logger logger.Logger
+}
+
+func NewAuthorStatsRepository(dao dao.AuthorStatsDAO, cache cache.AuthorStatsCache, l logger.Logger) AuthorStatsRepository {
+	return &authorStatsRepository{
+		dao:    dao,
+		cache:  cache,
+		logger: l,
+	}
+}
+
+func (r *authorStatsRepository) IncrStats(ctx context.Context, stats []domain.AuthorStats) error {
+	return r.dao.BatchIncr(ctx, slice.Map(stats, func(idx int, src domain.AuthorStats) dao.AuthorDailyStats {
+		return dao.AuthorDailyStats{
+			AuthorId:   src.AuthorId,
+			Day:        src.Day.Format(authorStatsDayLayout),
+			ReadCnt:    src.ReadCnt,
+			LikeCnt:    src.LikeCnt,
+			CollectCnt: src.CollectCnt,
+		}
+	}))
+}
+
+func (r *authorStatsRepository) IncrPublishCnt(ctx context.Context, authorId int64, artId int64, day time.Time) error {
+	return r.dao.IncrPublishCnt(ctx, authorId, artId, day.Format(authorStatsDayLayout))
+}
+
+func (r *authorStatsRepository) GetDaily(ctx context.Context, authorId int64, start time.Time, end time.Time) ([]domain.AuthorStats, error) {
+	stats, err := r.dao.FindByAuthor(ctx, authorId, start.Format(authorStatsDayLayout), end.Format(authorStatsDayLayout))
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(stats, func(idx int, src dao.AuthorDailyStats) domain.AuthorStats {
+		day, _ := time.ParseInLocation(authorStatsDayLayout, src.Day, time.Local)
+		return domain.AuthorStats{
+			AuthorId:   src.AuthorId,
+			Day:        day,
+			ReadCnt:    src.ReadCnt,
+			LikeCnt:    src.LikeCnt,
+			CollectCnt: src.CollectCnt,
+			PublishCnt: src.PublishCnt,
+		}
+	}), nil
+}
+
+// GetTopAuthors 榜单对实时性不敏感，缓存一段时间
+func (r *authorStatsRepository) GetTopAuthors(ctx context.Context, days int, weights domain.AuthorScoreWeights, limit int) ([]domain.AuthorRankItem, error) {
+	items, err := r.cache.GetTopAuthors(ctx, days)
+	if err == nil {
+		return items, nil
+	}
+
+	// 包括今天在内的最近 days 天
+	start := time.Now().AddDate(0, 0, 1-days).Format(authorStatsDayLayout)
+	sums, err := r.dao.TopAuthors(ctx, start, dao.AuthorScoreWeights(weights), limit)
+	if err != nil {
+		return nil, err
+	}
+	items = slice.Map(sums, func(idx int, src dao.AuthorStatsSum) domain.AuthorRankItem {
+		return domain.AuthorRankItem{
+			AuthorStats: domain.AuthorStats{
+				AuthorId:   src.AuthorId,
+				ReadCnt:    src.ReadCnt,
+				LikeCnt:    src.LikeCnt,
+				CollectCnt: src.CollectCnt,
+				PublishCnt: src.PublishCnt,
+			},
+			Score: src.Score,
+		}
+	})
+	if er := r.cache.SetTopAuthors(ctx, days, items); er != nil {
+		r.logger.Error("回写作者榜单缓存失败",
+			logger.Int64("days", int64(days)),
+			logger.Error(er),
+		)
+	}
+	return items, nil
+}
+
+func (r *authorStatsRepository) MarkEvent(ctx context.Context, eventId string) (bool, error) {
+	return r.cache.MarkEvent(ctx, eventId)
+}
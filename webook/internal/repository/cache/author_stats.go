@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"Webook/webook/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AuthorStatsCache 作者榜单，按统计的天数分别缓存
+type AuthorStatsCache interface {
+	// GetTopAuthors 缓存中没有时返回 ErrKeyNotFound
+	GetTopAuthors(ctx context.Context, days int) ([]domain.AuthorRankItem, error)
+	SetTopAuthors(ctx context.Context, days int, items []domain.AuthorRankItem) error
+	// MarkEvent 标记事件已经计入汇总，第一次标记时返回 true
+	MarkEvent(ctx context.Context, eventId string) (bool, error)
+}
+
+type RedisAuthorStatsCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+	// 事件标记的过期时间，要远大于消息被重新投递的间隔
+	eventExpiration time.Duration
+}
+
+func NewAuthorStatsCache(client redis.Cmdable) AuthorStatsCache {
+	return &RedisAuthorStatsCache{
+		client:          client,
+		expiration:      time.Minute * 10,
+		eventExpiration: time.Hour * 24,
+	}
+}
+
+func (r *RedisAuthorStatsCache) key(days int) string {
+	return fmt.Sprintf("author_ranking:%d", days)
+}
+
+func (r *RedisAuthorStatsCache) GetTopAuthors(ctx context.Context, days int) ([]domain.AuthorRankItem, error) {
+	val, err := r.client.Get(ctx, r.key(days)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var items []domain.AuthorRankItem
+	err = json.Unmarshal(val, &items)
+	return items, err
+}
+
+func (r *RedisAuthorStatsCache) SetTopAuthors(ctx context.Context, days int, items []domain.AuthorRankItem) error {
+	val, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, r.key(days), val, r.expiration).Err()
+}
+
+func (r *RedisAuthorStatsCache) MarkEvent(ctx context.Context, eventId string) (bool, error) {
+	return r.client.SetNX(ctx, fmt.Sprintf("author_stats:event:%s", eventId), 1, r.eventExpiration).Result()
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"Webook/webook/internal/domain"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	fieldFollowerCnt = "follower_cnt"
+	fieldFolloweeCnt = "followee_cnt"
+)
+
+// FollowCache 粉丝数和关注数，和 RedisInteractiveCache 一样用 hash 保存，只在 key 存在时自增
+type FollowCache interface {
+	GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+	SetStatics(ctx context.Context, uid int64, statics domain.FollowStatics) error
+	// Follow 维护 follower 的关注数和 followee 的粉丝数，delta 为 1 或 -1
+	Follow(ctx context.Context, follower int64, followee int64, delta int64) error
+}
+
+type RedisFollowCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+}
+
+func NewFollowCache(client redis.Cmdable) FollowCache {
+	return &RedisFollowCache{
+		client:     client,
+		expiration: time.Minute * 15,
+	}
+}
+
+func (r *RedisFollowCache) key(uid int64) string {
+	return fmt.Sprintf("follow:statics:%d", uid)
+}
+
+func (r *RedisFollowCache) GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	data, err := r.client.HGetAll(ctx, r.key(uid)).Result()
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	if len(data) == 0 {
+		return domain.FollowStatics{}, ErrKeyNotFound
+	}
+
+	var res domain.FollowStatics
+	res.Followers, _ = strconv.ParseInt(data[fieldFollowerCnt], 10, 64)
+	res.Followees, _ = strconv.ParseInt(data[fieldFolloweeCnt], 10, 64)
+	return res, nil
+}
+
+func (r *RedisFollowCache) SetStatics(ctx context.Context, uid int64, statics domain.FollowStatics) error {
+	key := r.key(uid)
+	err := r.client.HSet(ctx, key,
+		fieldFollowerCnt, statics.Followers,
+		fieldFolloweeCnt, statics.Followees,
+	).Err()
+	if err != nil {
+		return err
+	}
+	return r.client.Expire(ctx, key, r.expiration).Err()
+}
+
+func (r *RedisFollowCache) Follow(ctx context.Context, follower int64, followee int64, delta int64) error {
+	err := r.client.Eval(ctx, luaIncrCnt,
+		[]string{r.key(follower)},
+		fieldFolloweeCnt,
+		delta,
+	).Err()
+	if err != nil {
+		return err
+	}
+	return r.client.Eval(ctx, luaIncrCnt,
+		[]string{r.key(followee)},
+		fieldFollowerCnt,
+		delta,
+	).Err()
+}
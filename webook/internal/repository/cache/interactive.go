@@ -1,9 +1,11 @@
 package cache
 
 import (
+	"Webook/webook/internal/domain"
 	"context"
 	_ "embed"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,11 +14,48 @@ import (
 //go:embed lua/interactive_incr_cnt.lua
 var luaIncrCnt string
 
+const (
+	fieldReadCnt    = "read_cnt"
+	fieldLikeCnt    = "like_cnt"
+	fieldCollectCnt = "collect_cnt"
+	fieldCommentCnt = "comment_cnt"
+)
+
+// 用户状态按位存储，0 表示既没有点赞也没有收藏
+const (
+	userStateLiked = 1 << iota
+	userStateCollected
+)
+
+// InteractiveUserState 某个用户对资源是否点赞、收藏
+type InteractiveUserState struct {
+	Liked     bool
+	Collected bool
+}
+
 type InteractiveCache interface {
+	// Get 只有计数，缓存中没有时返回 ErrKeyNotFound
+	Get(ctx context.Context, biz string, bizId int64) (domain.Interactive, error)
+	Set(ctx context.Context, biz string, bizId int64, inter domain.Interactive) error
+	// MGet 只返回缓存中有的资源
+	MGet(ctx context.Context, biz string, bizIds []int64) (map[int64]domain.Interactive, error)
+	MSet(ctx context.Context, biz string, inters []domain.Interactive) error
+
+	// GetUserStates 只返回缓存中有的资源
+	GetUserStates(ctx context.Context, biz string, uid int64, bizIds []int64) (map[int64]InteractiveUserState, error)
+	SetUserStates(ctx context.Context, biz string, uid int64, states map[int64]InteractiveUserState) error
+	// DelUserState 用户点赞、收藏状态变化后删掉，下次读取时从数据库加载
+	DelUserState(ctx context.Context, biz string, uid int64, bizId int64) error
+
 	IncreaseReadCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	// BatchIncreaseReadCntIfPresent 批量增加阅读计数，bizs、bizIds、cnts 一一对应
+	BatchIncreaseReadCntIfPresent(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error
 	IncreaseLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error
 	DecreaseLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error
 	IncreaseCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	DecreaseCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	// 评论数：新增为正数，删除时可能连带删除回复，所以是负数的删除条数
+	IncreaseCommentCntIfPresent(ctx context.Context, biz string, bizId int64, delta int64) error
 }
 
 type RedisInteractiveCache struct {
@@ -35,18 +74,176 @@ func (r *RedisInteractiveCache) key(biz string, bizId int64) string {
 	return fmt.Sprintf("interactive:%s:%d", biz, bizId)
 }
 
+// userKey 一个用户在一个业务下的所有状态放在一个 hash 里，field 是 bizId
+func (r *RedisInteractiveCache) userKey(biz string, uid int64) string {
+	return fmt.Sprintf("interactive_user:%s:%d", biz, uid)
+}
+
+func (r *RedisInteractiveCache) Get(ctx context.Context, biz string, bizId int64) (domain.Interactive, error) {
+	data, err := r.client.HGetAll(ctx, r.key(biz, bizId)).Result()
+	if err != nil {
+		return domain.Interactive{}, err
+	}
+	if len(data) == 0 {
+		return domain.Interactive{}, ErrKeyNotFound
+	}
+	return r.toDomain(biz, bizId, data), nil
+}
+
+func (r *RedisInteractiveCache) Set(ctx context.Context, biz string, bizId int64, inter domain.Interactive) error {
+	key := r.key(biz, bizId)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key,
+		fieldReadCnt, inter.ReadCnt,
+		fieldLikeCnt, inter.LikeCnt,
+		fieldCollectCnt, inter.CollectCnt,
+		fieldCommentCnt, inter.CommentCnt,
+	)
+	pipe.Expire(ctx, key, r.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisInteractiveCache) MGet(ctx context.Context, biz string, bizIds []int64) (map[int64]domain.Interactive, error) {
+	if len(bizIds) == 0 {
+		return map[int64]domain.Interactive{}, nil
+	}
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(bizIds))
+	for _, id := range bizIds {
+		cmds = append(cmds, pipe.HGetAll(ctx, r.key(biz, id)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	res := make(map[int64]domain.Interactive, len(bizIds))
+	for i, cmd := range cmds {
+		data := cmd.Val()
+		if len(data) == 0 {
+			continue
+		}
+		res[bizIds[i]] = r.toDomain(biz, bizIds[i], data)
+	}
+	return res, nil
+}
+
+func (r *RedisInteractiveCache) MSet(ctx context.Context, biz string, inters []domain.Interactive) error {
+	if len(inters) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for _, inter := range inters {
+		key := r.key(biz, inter.BizId)
+		pipe.HSet(ctx, key,
+			fieldReadCnt, inter.ReadCnt,
+			fieldLikeCnt, inter.LikeCnt,
+			fieldCollectCnt, inter.CollectCnt,
+			fieldCommentCnt, inter.CommentCnt,
+		)
+		pipe.Expire(ctx, key, r.expiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisInteractiveCache) toDomain(biz string, bizId int64, data map[string]string) domain.Interactive {
+	res := domain.Interactive{
+		Biz:   biz,
+		BizId: bizId,
+	}
+	res.ReadCnt, _ = strconv.ParseInt(data[fieldReadCnt], 10, 64)
+	res.LikeCnt, _ = strconv.ParseInt(data[fieldLikeCnt], 10, 64)
+	res.CollectCnt, _ = strconv.ParseInt(data[fieldCollectCnt], 10, 64)
+	res.CommentCnt, _ = strconv.ParseInt(data[fieldCommentCnt], 10, 64)
+	return res
+}
+
+func (r *RedisInteractiveCache) GetUserStates(ctx context.Context, biz string, uid int64, bizIds []int64) (map[int64]InteractiveUserState, error) {
+	if len(bizIds) == 0 {
+		return map[int64]InteractiveUserState{}, nil
+	}
+	fields := make([]string, 0, len(bizIds))
+	for _, id := range bizIds {
+		fields = append(fields, strconv.FormatInt(id, 10))
+	}
+	vals, err := r.client.HMGet(ctx, r.userKey(biz, uid), fields...).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]InteractiveUserState, len(bizIds))
+	for i, val := range vals {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		bits, err := strconv.Atoi(str)
+		if err != nil {
+			continue
+		}
+		res[bizIds[i]] = InteractiveUserState{
+			Liked:     bits&userStateLiked != 0,
+			Collected: bits&userStateCollected != 0,
+		}
+	}
+	return res, nil
+}
+
+func (r *RedisInteractiveCache) SetUserStates(ctx context.Context, biz string, uid int64, states map[int64]InteractiveUserState) error {
+	if len(states) == 0 {
+		return nil
+	}
+	values := make([]any, 0, len(states)*2)
+	for id, state := range states {
+		bits := 0
+		if state.Liked {
+			bits |= userStateLiked
+		}
+		if state.Collected {
+			bits |= userStateCollected
+		}
+		values = append(values, strconv.FormatInt(id, 10), bits)
+	}
+	key := r.userKey(biz, uid)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, values...)
+	pipe.Expire(ctx, key, r.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisInteractiveCache) DelUserState(ctx context.Context, biz string, uid int64, bizId int64) error {
+	return r.client.HDel(ctx, r.userKey(biz, uid), strconv.FormatInt(bizId, 10)).Err()
+}
+
 func (r *RedisInteractiveCache) IncreaseReadCntIfPresent(ctx context.Context, biz string, bizId int64) error {
 	return r.client.Eval(ctx, luaIncrCnt,
 		[]string{r.key(biz, bizId)},
-		"read_cnt",
+		fieldReadCnt,
 		1,
 	).Err()
 }
 
+// BatchIncreaseReadCntIfPresent 用 pipeline 一次发送所有的脚本
+func (r *RedisInteractiveCache) BatchIncreaseReadCntIfPresent(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error {
+	if len(bizs) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for i := range bizs {
+		pipe.Eval(ctx, luaIncrCnt,
+			[]string{r.key(bizs[i], bizIds[i])},
+			fieldReadCnt,
+			cnts[i],
+		)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
 func (r *RedisInteractiveCache) IncreaseLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error {
 	return r.client.Eval(ctx, luaIncrCnt,
 		[]string{r.key(biz, bizId)},
-		"like_cnt",
+		fieldLikeCnt,
 		1,
 	).Err()
 }
@@ -54,15 +251,31 @@ func (r *RedisInteractiveCache) IncreaseLikeCntIfPresent(ctx context.Context, bi
 func (r *RedisInteractiveCache) DecreaseLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error {
 	return r.client.Eval(ctx, luaIncrCnt,
 		[]string{r.key(biz, bizId)},
-		"like_cnt",
-		1,
+		fieldLikeCnt,
+		-1,
 	).Err()
 }
 
 func (r *RedisInteractiveCache) IncreaseCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error {
 	return r.client.Eval(ctx, luaIncrCnt,
 		[]string{r.key(biz, bizId)},
-		"collect_cnt",
+		fieldCollectCnt,
 		1,
 	).Err()
 }
+
+func (r *RedisInteractiveCache) DecreaseCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error {
+	return r.client.Eval(ctx, luaIncrCnt,
+		[]string{r.key(biz, bizId)},
+		fieldCollectCnt,
+		-1,
+	).Err()
+}
+
+func (r *RedisInteractiveCache) IncreaseCommentCntIfPresent(ctx context.Context, biz string, bizId int64, delta int64) error {
+	return r.client.Eval(ctx, luaIncrCnt,
+		[]string{r.key(biz, bizId)},
+		fieldCommentCnt,
+		delta,
+	).Err()
+}
@@ -0,0 +1,21 @@
+-- 阅读去重：KEYS 是读者的各个标识（用户、IP）在窗口内的 key
+-- ARGV[1]：窗口长度，毫秒
+local window = tonumber(ARGV[1])
+
+-- 任意一个标识在窗口内读过，都算重复阅读
+local seen = 0
+for i, key in ipairs(KEYS) do
+    if redis.call("EXISTS", key) == 1 then
+        seen = 1
+    end
+end
+
+-- 窗口从第一次阅读开始算，已经存在的 key 不续期
+for i, key in ipairs(KEYS) do
+    redis.call("SET", key, 1, "PX", window, "NX")
+end
+
+if seen == 1 then
+    return 0
+end
+return 1
@@ -0,0 +1,10 @@
+-- 二次验证失败次数，窗口从第一次失败开始算
+local key = KEYS[1]
+-- 窗口长度，毫秒
+local window = ARGV[1]
+
+local cnt = redis.call("INCR", key)
+if cnt == 1 then
+    redis.call("PEXPIRE", key, window)
+end
+return cnt
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"Webook/webook/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrPasswordResetTooFrequent = errors.New("发送重置邮件太频繁")
+	ErrPasswordResetNotFound    = errors.New("重置链接无效或已过期")
+)
+
+// PasswordResetCache 忘记密码的重置 token，key 是 token 的哈希，用一次就删除
+type PasswordResetCache interface {
+	// Limit 同一个邮箱发送太频繁时返回 ErrPasswordResetTooFrequent，不管邮箱有没有注册
+	Limit(ctx context.Context, email string) error
+	// Set 保存 token
+	Set(ctx context.Context, tokenHash string, reset domain.PasswordReset) error
+	// Take 取出并删除 token，不存在时返回 ErrPasswordResetNotFound
+	Take(ctx context.Context, tokenHash string) (domain.PasswordReset, error)
+}
+
+type RedisPasswordResetCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+	interval   time.Duration
+}
+
+func NewPasswordResetCache(client redis.Cmdable) PasswordResetCache {
+	return &RedisPasswordResetCache{
+		client:     client,
+		expiration: time.Minute * 15,
+		interval:   time.Minute,
+	}
+}
+
+func (c *RedisPasswordResetCache) Limit(ctx context.Context, email string) error {
+	ok, err := c.client.SetNX(ctx, c.limitKey(email), 1, c.interval).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPasswordResetTooFrequent
+	}
+	return nil
+}
+
+func (c *RedisPasswordResetCache) Set(ctx context.Context, tokenHash string, reset domain.PasswordReset) error {
+	key := c.key(tokenHash)
+	pipe := c.client.TxPipeline()
+	pipe.HSet(ctx, key, "uid", reset.Uid, "fingerprint", reset.Fingerprint)
+	pipe.Expire(ctx, key, c.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisPasswordResetCache) Take(ctx context.Context, tokenHash string) (domain.PasswordReset, error) {
+	key := c.key(tokenHash)
+	pipe := c.client.TxPipeline()
+	get := pipe.HGetAll(ctx, key)
+	del := pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return domain.PasswordReset{}, err
+	}
+	// 并发使用同一个 token 时只有删除成功的那个有效
+	data := get.Val()
+	if del.Val() == 0 || len(data) == 0 {
+		return domain.PasswordReset{}, ErrPasswordResetNotFound
+	}
+	uid, err := strconv.ParseInt(data["uid"], 10, 64)
+	if err != nil {
+		return domain.PasswordReset{}, ErrPasswordResetNotFound
+	}
+	return domain.PasswordReset{
+		Uid:         uid,
+		Fingerprint: data["fingerprint"],
+	}, nil
+}
+
+func (c *RedisPasswordResetCache) key(tokenHash string) string {
+	return fmt.Sprintf("password_reset:%s", tokenHash)
+}
+
+func (c *RedisPasswordResetCache) limitKey(email string) string {
+	return fmt.Sprintf("password_reset_limit:%s", email)
+}
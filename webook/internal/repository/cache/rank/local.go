@@ -8,36 +8,44 @@ import (
 	"time"
 )
 
+type rankingLocalItem struct {
+	arts []domain.Article
+	ddl  time.Time
+}
+
 type RankingLocalCache struct {
 	sync.RWMutex
-	top100     []domain.Article
-	ddl        time.Time
+	boards     map[string]rankingLocalItem
 	expiration time.Duration
 }
 
 func NewRankingLocalCache() *RankingLocalCache {
 	return &RankingLocalCache{
+		boards:     make(map[string]rankingLocalItem),
 		expiration: time.Minute * 30, // 设置默认过期时间为30分钟
 	}
 }
 
-func (l *RankingLocalCache) Set(ctx context.Context, arts []domain.Article) error {
+func (l *RankingLocalCache) Set(ctx context.Context, board string, arts []domain.Article) error {
 	l.Lock()
 	defer l.Unlock()
 	// 处理文章内容，保持与 Redis 实现一致
 	for i := range arts {
 		arts[i].Content = arts[i].Abstract()
 	}
-	l.top100 = arts
-	l.ddl = time.Now().Add(l.expiration)
+	l.boards[board] = rankingLocalItem{
+		arts: arts,
+		ddl:  time.Now().Add(l.expiration),
+	}
 	return nil
 }
 
-func (l *RankingLocalCache) Get(ctx context.Context) ([]domain.Article, error) {
+func (l *RankingLocalCache) Get(ctx context.Context, board string) ([]domain.Article, error) {
 	l.RLock()
 	defer l.RUnlock()
-	if time.Now().After(l.ddl) {
+	item, ok := l.boards[board]
+	if !ok || time.Now().After(item.ddl) {
 		return nil, errors.New("cache expired")
 	}
-	return l.top100, nil
+	return item.arts, nil
 }
@@ -0,0 +1,29 @@
+-- 榜单的有序集合
+local key = KEYS[1]
+-- 上次衰减的时间，毫秒
+local tsKey = KEYS[2]
+local now = tonumber(ARGV[1])
+-- 半衰期，毫秒
+local halfLife = tonumber(ARGV[2])
+-- 衰减后只保留分数最高的 keep 个
+local keep = tonumber(ARGV[3])
+
+local last = tonumber(redis.call("GET", tsKey))
+-- 还没有重建过
+if last == nil then
+    return -1
+end
+-- 其他实例刚衰减过，不能重复衰减
+if now <= last then
+    return 0
+end
+redis.call("SET", tsKey, now)
+
+local factor = math.pow(0.5, (now - last) / halfLife)
+local members = redis.call("ZRANGE", key, 0, -1, "WITHSCORES")
+for i = 1, #members, 2 do
+    redis.call("ZADD", key, tonumber(members[i + 1]) * factor, members[i])
+end
+
+redis.call("ZREMRANGEBYRANK", key, 0, -(keep + 1))
+return 1
@@ -0,0 +1,16 @@
+-- 榜单
+local key = KEYS[1]
+-- 写入过的最大 fencing token
+local tokenKey = KEYS[2]
+local val = ARGV[1]
+local expiration = ARGV[2]
+local token = tonumber(ARGV[3])
+
+local last = tonumber(redis.call("GET", tokenKey) or "0")
+-- 任务已经被其他节点接手过，旧持有者的结果不能覆盖
+if token < last then
+    return 0
+end
+redis.call("SET", tokenKey, token)
+redis.call("SET", key, val, "PX", expiration)
+return 1
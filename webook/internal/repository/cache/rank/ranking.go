@@ -5,9 +5,10 @@ import (
 	"context"
 )
 
+// RankingCache 每个榜单单独缓存
 type RankingCache interface {
-	GetTop100(ctx context.Context) ([]domain.Article, error)
-	SetTop100(ctx context.Context, articles []domain.Article) error
+	GetTopN(ctx context.Context, board string) ([]domain.Article, error)
+	SetTopN(ctx context.Context, board string, articles []domain.Article) error
 }
 
 type CompositeRankingCache struct {
@@ -22,30 +23,30 @@ func NewCompositeRankingCache(local *RankingLocalCache, redis *RankingRedisCache
 	}
 }
 
-func (c *CompositeRankingCache) GetTop100(ctx context.Context) ([]domain.Article, error) {
+func (c *CompositeRankingCache) GetTopN(ctx context.Context, board string) ([]domain.Article, error) {
 	// 先尝试从本地缓存获取
-	arts, err := c.local.Get(ctx)
+	arts, err := c.local.Get(ctx, board)
 	if err == nil {
 		return arts, nil
 	}
 
 	// 本地缓存失效，从Redis获取
-	arts, err = c.redis.Get(ctx)
+	arts, err = c.redis.Get(ctx, board)
 	if err != nil {
 		return nil, err
 	}
 
 	// 设置到本地缓存
-	_ = c.local.Set(ctx, arts)
+	_ = c.local.Set(ctx, board, arts)
 	return arts, nil
 }
 
-func (c *CompositeRankingCache) SetTop100(ctx context.Context, arts []domain.Article) error {
+func (c *CompositeRankingCache) SetTopN(ctx context.Context, board string, arts []domain.Article) error {
 	// 先设置本地缓存
-	if err := c.local.Set(ctx, arts); err != nil {
+	if err := c.local.Set(ctx, board, arts); err != nil {
 		return err
 	}
 
 	// 再设置Redis缓存
-	return c.redis.Set(ctx, arts)
+	return c.redis.Set(ctx, board, arts)
 }
@@ -3,29 +3,37 @@ package cache
 import (
 	"Webook/webook/config"
 	"Webook/webook/internal/domain"
+	"Webook/webook/pkg/fencing"
 	"context"
+	_ "embed"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+//go:embed lua/fenced_set.lua
+var luaFencedSet string
+
 type RankingRedisCache struct {
 	client     redis.Cmdable
-	key        string
 	expiration time.Duration
 }
 
 func NewRankingRedisCache(client redis.Cmdable) *RankingRedisCache {
 	return &RankingRedisCache{
 		client:     client,
-		key:        "ranking:top_100",
 		expiration: config.DevRedisExpire.Top100,
 	}
 }
 
-func (r *RankingRedisCache) Get(ctx context.Context) ([]domain.Article, error) {
-	val, err := r.client.Get(ctx, r.key).Bytes()
+func (r *RankingRedisCache) key(board string) string {
+	return fmt.Sprintf("ranking:%s", board)
+}
+
+func (r *RankingRedisCache) Get(ctx context.Context, board string) ([]domain.Article, error) {
+	val, err := r.client.Get(ctx, r.key(board)).Bytes()
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +42,7 @@ func (r *RankingRedisCache) Get(ctx context.Context) ([]domain.Article, error) {
 	return res, err
 }
 
-func (r *RankingRedisCache) Set(ctx context.Context, arts []domain.Article) error {
+func (r *RankingRedisCache) Set(ctx context.Context, board string, arts []domain.Article) error {
 	for i := range arts {
 		arts[i].Content = arts[i].Abstract()
 	}
@@ -42,5 +50,19 @@ func (r *RankingRedisCache) Set(ctx context.Context, arts []domain.Article) erro
 	if err != nil {
 		return err
 	}
-	return r.client.Set(ctx, r.key, val, r.expiration).Err()
+	token, ok := fencing.TokenFromContext(ctx)
+	if !ok {
+		return r.client.Set(ctx, r.key(board), val, r.expiration).Err()
+	}
+	// 由抢占到的任务写入，检查 fencing token
+	key := r.key(board)
+	res, err := r.client.Eval(ctx, luaFencedSet, []string{key, key + ":fencing_token"},
+		val, r.expiration.Milliseconds(), token).Int()
+	if err != nil {
+		return err
+	}
+	if res == 0 {
+		return fencing.ErrStaleToken
+	}
+	return nil
 }
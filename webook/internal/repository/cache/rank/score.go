@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/decay.lua
+var luaDecay string
+
+// ErrScoresNotBuilt 增量榜单还没有全量重建过，不知道从什么时候开始衰减
+var ErrScoresNotBuilt = errors.New("榜单分数还没有重建")
+
+// RankingScoreCache 增量模式下榜单的分数，每个榜单一个有序集合，member 是文章 id
+type RankingScoreCache interface {
+	// IncrBy 批量加分，delta 可以是负数
+	IncrBy(ctx context.Context, board string, deltas map[int64]float64) error
+	// Remove 从每个榜单中移除这些文章
+	Remove(ctx context.Context, boards []string, artIds ...int64) error
+	// TopIds 分数从高到低
+	TopIds(ctx context.Context, board string, n int) ([]int64, error)
+	// Decay 按距离上次衰减经过的时间衰减分数，只保留分数最高的 keep 个。还没有 Replace 过时返回 ErrScoresNotBuilt
+	Decay(ctx context.Context, board string, now time.Time, halfLife time.Duration, keep int) error
+	// Replace 用重新计算的分数覆盖整个榜单
+	Replace(ctx context.Context, board string, scores map[int64]float64, now time.Time) error
+}
+
+type RedisRankingScoreCache struct {
+	client redis.Cmdable
+}
+
+func NewRankingScoreCache(client redis.Cmdable) RankingScoreCache {
+	return &RedisRankingScoreCache{
+		client: client,
+	}
+}
+
+func (r *RedisRankingScoreCache) key(board string) string {
+	return fmt.Sprintf("ranking:zset:%s", board)
+}
+
+func (r *RedisRankingScoreCache) decayedAtKey(board string) string {
+	return fmt.Sprintf("ranking:zset:%s:decayed_at", board)
+}
+
+func (r *RedisRankingScoreCache) IncrBy(ctx context.Context, board string, deltas map[int64]float64) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	key := r.key(board)
+	pipe := r.client.Pipeline()
+	for id, delta := range deltas {
+		pipe.ZIncrBy(ctx, key, delta, strconv.FormatInt(id, 10))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisRankingScoreCache) Remove(ctx context.Context, boards []string, artIds ...int64) error {
+	if len(boards) == 0 || len(artIds) == 0 {
+		return nil
+	}
+	members := make([]any, 0, len(artIds))
+	for _, id := range artIds {
+		members = append(members, strconv.FormatInt(id, 10))
+	}
+	pipe := r.client.Pipeline()
+	for _, board := range boards {
+		pipe.ZRem(ctx, r.key(board), members...)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisRankingScoreCache) TopIds(ctx context.Context, board string, n int) ([]int64, error) {
+	members, err := r.client.ZRevRange(ctx, r.key(board), 0, int64(n-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseInt(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *RedisRankingScoreCache) Decay(ctx context.Context, board string, now time.Time, halfLife time.Duration, keep int) error {
+	res, err := r.client.Eval(ctx, luaDecay,
+		[]string{r.key(board), r.decayedAtKey(board)},
+		now.UnixMilli(),
+		halfLife.Milliseconds(),
+		keep,
+	).Int()
+	if err != nil {
+		return err
+	}
+	if res == -1 {
+		return ErrScoresNotBuilt
+	}
+	return nil
+}
+
+func (r *RedisRankingScoreCache) Replace(ctx context.Context, board string, scores map[int64]float64, now time.Time) error {
+	key := r.key(board)
+	members := make([]redis.Z, 0, len(scores))
+	for id, score := range scores {
+		members = append(members, redis.Z{
+			Score:  score,
+			Member: strconv.FormatInt(id, 10),
+		})
+	}
+	// 事务中先删后加，读的人不会看到空榜单
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(members) > 0 {
+		pipe.ZAdd(ctx, key, members...)
+	}
+	// 重建的分数已经是当前时间的，从现在开始衰减
+	pipe.Set(ctx, r.decayedAtKey(board), now.UnixMilli(), 0)
+	_, err := pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/read_dedup.lua
+var luaReadDedup string
+
+// ReadDedupCache 阅读去重：同一个用户或同一个 IP 在窗口内的重复阅读只算一次
+type ReadDedupCache interface {
+	// FirstInWindow 标记读者读过资源，返回窗口内是否是第一次阅读，ip 为空时只按用户去重
+	FirstInWindow(ctx context.Context, biz string, bizId int64, uid int64, ip string, window time.Duration) (bool, error)
+}
+
+type RedisReadDedupCache struct {
+	client redis.Cmdable
+}
+
+func NewReadDedupCache(client redis.Cmdable) ReadDedupCache {
+	return &RedisReadDedupCache{
+		client: client,
+	}
+}
+
+func (r *RedisReadDedupCache) FirstInWindow(ctx context.Context, biz string, bizId int64, uid int64, ip string, window time.Duration) (bool, error) {
+	keys := []string{fmt.Sprintf("read_dedup:%s:%d:uid:%d", biz, bizId, uid)}
+	if ip != "" {
+		keys = append(keys, fmt.Sprintf("read_dedup:%s:%d:ip:%s", biz, bizId, ip))
+	}
+	res, err := r.client.Eval(ctx, luaReadDedup, keys, window.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/two_factor_failed.lua
+var luaTwoFactorFailed string
+
+// TwoFactorCache 按用户记录二次验证失败的次数，不区分登录、开启和关闭
+type TwoFactorCache interface {
+	// Failures 窗口内失败的次数
+	Failures(ctx context.Context, uid int64) (int64, error)
+	// IncrFailures 记录一次失败，返回窗口内失败的次数
+	IncrFailures(ctx context.Context, uid int64) (int64, error)
+	ResetFailures(ctx context.Context, uid int64) error
+}
+
+type RedisTwoFactorCache struct {
+	client redis.Cmdable
+	// 失败次数的统计窗口，也是锁定的时长
+	window time.Duration
+}
+
+func NewTwoFactorCache(client redis.Cmdable) TwoFactorCache {
+	return &RedisTwoFactorCache{
+		client: client,
+		window: time.Minute * 15,
+	}
+}
+
+func (r *RedisTwoFactorCache) key(uid int64) string {
+	return fmt.Sprintf("users:2fa_failures:%d", uid)
+}
+
+func (r *RedisTwoFactorCache) Failures(ctx context.Context, uid int64) (int64, error) {
+	cnt, err := r.client.Get(ctx, r.key(uid)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return cnt, err
+}
+
+func (r *RedisTwoFactorCache) IncrFailures(ctx context.Context, uid int64) (int64, error) {
+	return r.client.Eval(ctx, luaTwoFactorFailed, []string{r.key(uid)}, r.window.Milliseconds()).Int64()
+}
+
+func (r *RedisTwoFactorCache) ResetFailures(ctx context.Context, uid int64) error {
+	return r.client.Del(ctx, r.key(uid)).Err()
+}
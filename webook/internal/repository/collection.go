@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/cache"
+	"Webook/webook/internal/repository/dao"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var (
+	ErrCollectionDuplicate = dao.ErrCollectionDuplicate
+	ErrCollectionNotFound  = dao.ErrCollectionNotFound
+)
+
+type CollectionRepository interface {
+	Create(ctx context.Context, c domain.Collection) (int64, error)
+	Rename(ctx context.Context, c domain.Collection) error
+	// Delete 删除收藏夹，返回被取消的收藏。缓存更新失败时也会返回被取消的收藏
+	Delete(ctx context.Context, id int64, uid int64) ([]domain.CollectionItem, error)
+	FindById(ctx context.Context, id int64) (domain.Collection, error)
+	// FindByUid 用户的所有收藏夹，带上收藏数量
+	FindByUid(ctx context.Context, uid int64) ([]domain.Collection, error)
+	// FindItemIds 收藏夹中收藏的业务 id，最近收藏的在前
+	FindItemIds(ctx context.Context, cid int64, offset int, limit int) ([]int64, error)
+}
+
+type collectionRepository struct {
+	dao dao.CollectionDAO
+	// 删除收藏夹时同步收藏数和用户的收藏状态
+	interCache cache.InteractiveCache
+}
+
+func NewCollectionRepository(dao dao.CollectionDAO, interCache cache.InteractiveCache) CollectionRepository {
+	return &collectionRepository{
+		dao:        dao,
+		interCache: interCache,
+	}
+}
+
+func (r *collectionRepository) Create(ctx context.Context, c domain.Collection) (int64, error) {
+	return r.dao.Insert(ctx, r.toEntity(c))
+}
+
+func (r *collectionRepository) Rename(ctx context.Context, c domain.Collection) error {
+	return r.dao.UpdateName(ctx, r.toEntity(c))
+}
+
+func (r *collectionRepository) Delete(ctx context.Context, id int64, uid int64) ([]domain.CollectionItem, error) {
+	items, err := r.dao.Delete(ctx, id, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// 和取消收藏一样维护缓存，一个失败了也继续处理剩下的
+	res := make([]domain.CollectionItem, 0, len(items))
+	var errs []error
+	for _, item := range items {
+		res = append(res, domain.CollectionItem{Biz: item.Biz, BizId: item.BizId})
+		if err = r.interCache.DecreaseCollectCntIfPresent(ctx, item.Biz, item.BizId); err != nil {
+			errs = append(errs, err)
+		}
+		if err = r.interCache.DelUserState(ctx, item.Biz, uid, item.BizId); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+func (r *collectionRepository) FindById(ctx context.Context, id int64) (domain.Collection, error) {
+	c, err := r.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Collection{}, err
+	}
+	return r.toDomain(c), nil
+}
+
+func (r *collectionRepository) FindByUid(ctx context.Context, uid int64) ([]domain.Collection, error) {
+	cs, err := r.dao.FindByUid(ctx, uid)
+	if err != nil || len(cs) == 0 {
+		return []domain.Collection{}, err
+	}
+
+	cids := slice.Map(cs, func(idx int, src dao.Collection) int64 {
+		return src.Id
+	})
+	cnts, err := r.dao.CountItems(ctx, cids)
+	if err != nil {
+		return nil, err
+	}
+	cntMap := make(map[int64]int64, len(cnts))
+	for _, cnt := range cnts {
+		cntMap[cnt.Cid] = cnt.Cnt
+	}
+
+	return slice.Map(cs, func(idx int, src dao.Collection) domain.Collection {
+		c := r.toDomain(src)
+		c.Cnt = cntMap[src.Id]
+		return c
+	}), nil
+}
+
+func (r *collectionRepository) FindItemIds(ctx context.Context, cid int64, offset int, limit int) ([]int64, error) {
+	items, err := r.dao.FindItems(ctx, cid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(items, func(idx int, src dao.UserCollectBiz) int64 {
+		return src.BizId
+	}), nil
+}
+
+func (r *collectionRepository) toEntity(c domain.Collection) dao.Collection {
+	return dao.Collection{
+		Id:   c.Id,
+		Uid:  c.Uid,
+		Name: c.Name,
+	}
+}
+
+func (r *collectionRepository) toDomain(c dao.Collection) domain.Collection {
+	return domain.Collection{
+		Id:    c.Id,
+		Uid:   c.Uid,
+		Name:  c.Name,
+		Ctime: time.UnixMilli(c.Ctime),
+		Utime: time.UnixMilli(c.Utime),
+	}
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/cache"
+	"Webook/webook/internal/repository/dao"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"gorm.io/gorm"
+)
+
+var ErrCommentNotFound = errors.New("评论不存在")
+
+type CommentRepository interface {
+	Create(ctx context.Context, c domain.Comment) (int64, error)
+	// Delete 删除评论及其下的所有回复，返回删除的条数
+	Delete(ctx context.Context, c domain.Comment) (int64, error)
+	FindById(ctx context.Context, id int64) (domain.Comment, error)
+	// FindRoots 根评论，带上回复数
+	FindRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]domain.Comment, error)
+	// FindReplies 根评论下的回复，带上被回复的用户
+	FindReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]domain.Comment, error)
+}
+
+type commentRepository struct {
+	dao dao.CommentDAO
+	// 评论数保存在交互的缓存中
+	interCache cache.InteractiveCache
+}
+
+func NewCommentRepository(dao dao.CommentDAO, interCache cache.InteractiveCache) CommentRepository {
+	return &commentRepository{
+		dao:        dao,
+		interCache: interCache,
+	}
+}
+
+func (r *commentRepository) Create(ctx context.Context, c domain.Comment) (int64, error) {
+	id, err := r.dao.Insert(ctx, r.toEntity(c))
+	if err != nil {
+		return 0, err
+	}
+	return id, r.interCache.IncreaseCommentCntIfPresent(ctx, c.Biz, c.BizId, 1)
+}
+
+func (r *commentRepository) Delete(ctx context.Context, c domain.Comment) (int64, error) {
+	cnt, err := r.dao.Delete(ctx, r.toEntity(c))
+	if err != nil || cnt == 0 {
+		return cnt, err
+	}
+	return cnt, r.interCache.IncreaseCommentCntIfPresent(ctx, c.Biz, c.BizId, -cnt)
+}
+
+func (r *commentRepository) FindById(ctx context.Context, id int64) (domain.Comment, error) {
+	c, err := r.dao.FindById(ctx, id)
+	if err == gorm.ErrRecordNotFound {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return r.toDomain(c), nil
+}
+
+func (r *commentRepository) FindRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]domain.Comment, error) {
+	roots, err := r.dao.FindRoots(ctx, biz, bizId, maxId, limit)
+	if err != nil || len(roots) == 0 {
+		return []domain.Comment{}, err
+	}
+
+	ids := slice.Map(roots, func(idx int, src dao.Comment) int64 {
+		return src.Id
+	})
+	cnts, err := r.dao.CountReplies(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	cntMap := make(map[int64]int64, len(cnts))
+	for _, cnt := range cnts {
+		cntMap[cnt.RootId] = cnt.Cnt
+	}
+
+	return slice.Map(roots, func(idx int, src dao.Comment) domain.Comment {
+		c := r.toDomain(src)
+		c.ReplyCnt = cntMap[src.Id]
+		return c
+	}), nil
+}
+
+func (r *commentRepository) FindReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]domain.Comment, error) {
+	replies, err := r.dao.FindReplies(ctx, rootId, minId, limit)
+	if err != nil || len(replies) == 0 {
+		return []domain.Comment{}, err
+	}
+
+	// 查询被回复的评论，得到被回复的用户
+	pids := slice.Map(replies, func(idx int, src dao.Comment) int64 {
+		return src.Pid
+	})
+	parents, err := r.dao.FindByIds(ctx, pids)
+	if err != nil {
+		return nil, err
+	}
+	uidMap := make(map[int64]int64, len(parents))
+	for _, p := range parents {
+		uidMap[p.Id] = p.Uid
+	}
+
+	return slice.Map(replies, func(idx int, src dao.Comment) domain.Comment {
+		c := r.toDomain(src)
+		c.ReplyTo = domain.Commentator{Id: uidMap[src.Pid]}
+		return c
+	}), nil
+}
+
+func (r *commentRepository) toEntity(c domain.Comment) dao.Comment {
+	return dao.Comment{
+		Id:      c.Id,
+		Uid:     c.Commentator.Id,
+		Biz:     c.Biz,
+		BizId:   c.BizId,
+		RootId:  c.RootId,
+		Pid:     c.ParentId,
+		Content: c.Content,
+	}
+}
+
+func (r *commentRepository) toDomain(c dao.Comment) domain.Comment {
+	return domain.Comment{
+		Id:    c.Id,
+		Biz:   c.Biz,
+		BizId: c.BizId,
+		Commentator: domain.Commentator{
+			Id: c.Uid,
+		},
+		Content:  c.Content,
+		RootId:   c.RootId,
+		ParentId: c.Pid,
+		Ctime:    time.UnixMilli(c.Ctime),
+		Utime:    time.UnixMilli(c.Utime),
+	}
+}
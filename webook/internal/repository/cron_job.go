@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/dao"
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var (
+	ErrNoCronJob             = dao.ErrNoCronJob
+	ErrCronJobNotHeld        = dao.ErrCronJobNotHeld
+	ErrCronJobNotFound       = dao.ErrCronJobNotFound
+	ErrCronJobStatusConflict = dao.ErrCronJobStatusConflict
+)
+
+type CronJobRepository interface {
+	Upsert(ctx context.Context, j domain.CronJob) error
+	// Preempt 抢占到期的任务，或者持有者心跳超时的任务，没有时返回 ErrNoCronJob
+	Preempt(ctx context.Context, owner string, heartbeatTimeout time.Duration) (domain.CronJob, error)
+	// Heartbeat 续约，任务已经被其他节点接手时返回 ErrCronJobNotHeld
+	Heartbeat(ctx context.Context, id int64, version int64) error
+	// Release 释放任务，nextTime 按 expression 计算，表达式已经被修改时返回 ErrCronJobNotHeld
+	Release(ctx context.Context, id int64, version int64, expression string, nextTime time.Time) error
+
+	List(ctx context.Context) ([]domain.CronJob, error)
+	FindById(ctx context.Context, id int64) (domain.CronJob, error)
+	// Pause 只能暂停等待中的任务
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64, nextTime time.Time) error
+	// Trigger 把等待中的任务的下一次执行时间提前到现在
+	Trigger(ctx context.Context, id int64) error
+	UpdateExpression(ctx context.Context, id int64, expression string, nextTime time.Time) error
+
+	AddExecution(ctx context.Context, e domain.CronJobExecution) (int64, error)
+	FinishExecution(ctx context.Context, id int64, status domain.CronJobExecutionStatus, errMsg string) error
+	ListExecutions(ctx context.Context, jobId int64, offset int, limit int) ([]domain.CronJobExecution, error)
+	// DeleteExecutionsBefore 删除一批开始时间早于 before 的执行记录，返回删除的条数
+	DeleteExecutionsBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type cronJobRepository struct {
+	dao dao.CronJobDAO
+}
+
+func NewCronJobRepository(dao dao.CronJobDAO) CronJobRepository {
+	return &cronJobRepository{
+		dao: dao,
+	}
+}
+
+func (r *cronJobRepository) Upsert(ctx context.Context, j domain.CronJob) error {
+	return r.dao.Upsert(ctx, r.toEntity(j))
+}
+
+func (r *cronJobRepository) Preempt(ctx context.Context, owner string, heartbeatTimeout time.Duration) (domain.CronJob, error) {
+	now := time.Now()
+	j, err := r.dao.Preempt(ctx,
+		domain.CronJobStatusWaiting.ToUint8(),
+		domain.CronJobStatusRunning.ToUint8(),
+		owner, now.UnixMilli(), now.Add(-heartbeatTimeout).UnixMilli())
+	if err != nil {
+		return domain.CronJob{}, err
+	}
+	return r.toDomain(j), nil
+}
+
+func (r *cronJobRepository) Heartbeat(ctx context.Context, id int64, version int64) error {
+	return r.dao.Heartbeat(ctx, id, version, domain.CronJobStatusRunning.ToUint8())
+}
+
+func (r *cronJobRepository) Release(ctx context.Context, id int64, version int64, expression string, nextTime time.Time) error {
+	return r.dao.Release(ctx, id, version, expression,
+		domain.CronJobStatusRunning.ToUint8(),
+		domain.CronJobStatusWaiting.ToUint8(),
+		nextTime.UnixMilli())
+}
+
+func (r *cronJobRepository) List(ctx context.Context) ([]domain.CronJob, error) {
+	js, err := r.dao.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(js, func(idx int, src dao.CronJob) domain.CronJob {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *cronJobRepository) FindById(ctx context.Context, id int64) (domain.CronJob, error) {
+	j, err := r.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.CronJob{}, err
+	}
+	return r.toDomain(j), nil
+}
+
+func (r *cronJobRepository) Pause(ctx context.Context, id int64) error {
+	return r.dao.Transit(ctx, id, domain.CronJobStatusWaiting.ToUint8(), map[string]any{
+		"status": domain.CronJobStatusPaused.ToUint8(),
+	})
+}
+
+func (r *cronJobRepository) Resume(ctx context.Context, id int64, nextTime time.Time) error {
+	return r.dao.Transit(ctx, id, domain.CronJobStatusPaused.ToUint8(), map[string]any{
+		"status":    domain.CronJobStatusWaiting.ToUint8(),
+		"next_time": nextTime.UnixMilli(),
+	})
+}
+
+func (r *cronJobRepository) Trigger(ctx context.Context, id int64) error {
+	return r.dao.Transit(ctx, id, domain.CronJobStatusWaiting.ToUint8(), map[string]any{
+		"next_time": time.Now().UnixMilli(),
+	})
+}
+
+func (r *cronJobRepository) UpdateExpression(ctx context.Context, id int64, expression string, nextTime time.Time) error {
+	return r.dao.UpdateExpression(ctx, id, expression, domain.CronJobStatusWaiting.ToUint8(), nextTime.UnixMilli())
+}
+
+func (r *cronJobRepository) AddExecution(ctx context.Context, e domain.CronJobExecution) (int64, error) {
+	return r.dao.InsertExecution(ctx, dao.CronJobExecution{
+		JobId:     e.JobId,
+		Name:      e.Name,
+		Owner:     e.Owner,
+		Version:   e.Version,
+		Status:    e.Status.ToUint8(),
+		StartTime: e.StartTime.UnixMilli(),
+	})
+}
+
+func (r *cronJobRepository) FinishExecution(ctx context.Context, id int64, status domain.CronJobExecutionStatus, errMsg string) error {
+	return r.dao.FinishExecution(ctx, id, status.ToUint8(), errMsg, time.Now().UnixMilli())
+}
+
+func (r *cronJobRepository) ListExecutions(ctx context.Context, jobId int64, offset int, limit int) ([]domain.CronJobExecution, error) {
+	es, err := r.dao.FindExecutions(ctx, jobId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(es, func(idx int, src dao.CronJobExecution) domain.CronJobExecution {
+		e := domain.CronJobExecution{
+			Id:        src.Id,
+			JobId:     src.JobId,
+			Name:      src.Name,
+			Owner:     src.Owner,
+			Version:   src.Version,
+			Status:    domain.CronJobExecutionStatus(src.Status),
+			Error:     src.Error,
+			StartTime: time.UnixMilli(src.StartTime),
+		}
+		if src.EndTime > 0 {
+			e.EndTime = time.UnixMilli(src.EndTime)
+		}
+		return e
+	}), nil
+}
+
+func (r *cronJobRepository) toEntity(j domain.CronJob) dao.CronJob {
+	return dao.CronJob{
+		Id:         j.Id,
+		Name:       j.Name,
+		Executor:   j.Executor,
+		Cfg:        j.Cfg,
+		Expression: j.Expression,
+		Status:     j.Status.ToUint8(),
+		NextTime:   j.NextTime.UnixMilli(),
+		Owner:      j.Owner,
+		Version:    j.Version,
+	}
+}
+
+func (r *cronJobRepository) DeleteExecutionsBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return r.dao.DeleteExecutionsBefore(ctx, before.UnixMilli(), limit)
+}
+
+func (r *cronJobRepository) toDomain(j dao.CronJob) domain.CronJob {
+	return domain.CronJob{
+		Id:         j.Id,
+		Name:       j.Name,
+		Executor:   j.Executor,
+		Cfg:        j.Cfg,
+		Expression: j.Expression,
+		Status:     domain.CronJobStatus(j.Status),
+		NextTime:   time.UnixMilli(j.NextTime),
+		Owner:      j.Owner,
+		Version:    j.Version,
+		Heartbeat:  time.UnixMilli(j.Heartbeat),
+		Ctime:      time.UnixMilli(j.Ctime),
+		Utime:      time.UnixMilli(j.Utime),
+	}
+}
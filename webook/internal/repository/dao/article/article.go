@@ -24,6 +24,13 @@ type Article struct {
 	// 创建和修改时间，毫秒时间戳
 	Ctime int64 `bson:"ctime,omitempty"`
 	Utime int64 `bson:"utime,omitempty"`
+
+	// 定时发布时间，毫秒时间戳，定时任务按 status + publish_at 扫描
+	PublishAt int64 `gorm:"index" bson:"publish_at,omitempty"`
+
+	// 分类是一对一的，直接作为列；标签是一对多的，存在 ArticleTag 表中
+	Category string   `gorm:"type:varchar(64);index" bson:"category,omitempty"`
+	Tags     []string `gorm:"-" bson:"tags,omitempty"`
 }
 
 // 线上库：reader 进行被动更新
@@ -31,6 +38,16 @@ type PublishedArticle struct {
 	Article
 }
 
+// 历史版本：每次保存写者库时追加一条，只增不改
+type ArticleRevision struct {
+	Id        int64  `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
+	ArticleId int64  `gorm:"index" bson:"article_id,omitempty"`
+	AuthorId  int64  `bson:"author_id,omitempty"`
+	Title     string `gorm:"type=varchar(1024)" bson:"title,omitempty"`
+	Content   string `gorm:"type=BLOB" bson:"content,omitempty"`
+	Ctime     int64  `bson:"ctime,omitempty"`
+}
+
 type ArticleDAO interface {
 	Insert(ctx context.Context, art Article) (int64, error)
 	UpdateById(ctx context.Context, art Article) (int64, error)
@@ -39,9 +56,45 @@ type ArticleDAO interface {
 	GetByAuthorId(ctx context.Context, userId int64, limit int, offset int) ([]Article, error)
 	FindById(ctx context.Context, id int64) (Article, error)
 	FindPublicById(ctx context.Context, id int64) (PublishedArticle, error)
-	FindPublishedArticleList(ctx context.Context, end time.Time, offset int, limit int) ([]PublishedArticle, error)
+	FindPublicByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error)
+	FindPublishedArticleList(ctx context.Context, end time.Time, offset int, limit int, filter ArticleFilter) ([]PublishedArticle, error)
+	// FindPublishedByAuthors 作者们的发件箱：按 (utime, id) 倒序，只查询 id, author_id, utime
+	FindPublishedByAuthors(ctx context.Context, authorIds []int64, utime int64, id int64, limit int) ([]PublishedArticle, error)
+	// FindPublishedAfter 按 id 升序查询 id 之后的线上文章，用于全量遍历
+	FindPublishedAfter(ctx context.Context, id int64, limit int) ([]PublishedArticle, error)
+
+	// 历史版本
+	GetRevisions(ctx context.Context, artId int64, authorId int64, limit int, offset int) ([]ArticleRevision, error)
+	FindRevisionById(ctx context.Context, id int64) (ArticleRevision, error)
+
+	// 定时发布
+	// Schedule 只写写者库，线上库保持原样，到点后由 PublishScheduled 写入
+	Schedule(ctx context.Context, art Article) (int64, error)
+	CancelSchedule(ctx context.Context, art Article) (int64, error)
+	FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]Article, error)
+	PublishScheduled(ctx context.Context, art Article) error
+
+	// 标签
+	GetTags(ctx context.Context, artId int64) ([]string, error)
+	GetPublishedTagsByIds(ctx context.Context, artIds []int64) (map[int64][]string, error)
+	FindTagCnts(ctx context.Context, prefix string, limit int) ([]TagCnt, error)
 }
 
+var ErrNotScheduled = errors.New("文章不存在或不是定时发布状态")
+
+// ArticleFilter 线上文章列表的过滤条件，为空表示不过滤
+type ArticleFilter struct {
+	Tag      string
+	Category string
+}
+
+// 和 domain.ArticleStatus 保持一致
+const (
+	statusUnpublished uint8 = 1
+	statusPublished   uint8 = 2
+	statusScheduled   uint8 = 5
+)
+
 type GormArticleDAO struct {
 	db *gorm.DB
 }
@@ -56,29 +109,45 @@ func (dao *GormArticleDAO) Insert(ctx context.Context, art Article) (int64, erro
 	now := time.Now().UnixMilli()
 	art.Ctime = now
 	art.Utime = now
-	err := dao.db.WithContext(ctx).Create(&art).Error
+	// 文章和标签在同一个事务中写入
+	err := dao.db.WithContext(ctx).Transaction(func(txDb *gorm.DB) error {
+		err := txDb.Create(&art).Error
+		if err != nil {
+			return err
+		}
+		return replaceTags(txDb, tagTable, art.Id, art.Tags, now)
+	})
 	return art.Id, err
 }
 
-// Update 更新文章的标题和内容
 func (dao *GormArticleDAO) UpdateById(ctx context.Context, art Article) (int64, error) {
 	now := time.Now().UnixMilli()
 	art.Utime = now
-	res := dao.db.WithContext(ctx).Model(&art).
-		Where("id = ?", art.Id).
-		Where("author_id = ?", art.AuthorId).
-		Updates(map[string]any{
-			"title":   art.Title,
-			"content": art.Content,
-			"status":  art.Status,
-			"utime":   now,
-		})
+	err := dao.db.WithContext(ctx).Transaction(func(txDb *gorm.DB) error {
+		res := txDb.Model(&art).
+			Where("id = ?", art.Id).
+			Where("author_id = ?", art.AuthorId).
+			Updates(map[string]any{
+				"title":      art.Title,
+				"content":    art.Content,
+				"status":     art.Status,
+				"publish_at": art.PublishAt,
+				"category":   art.Category,
+				"utime":      now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
 
-	// 至少会有更新时间 Utime 会被更新，所以可以判断是否更新成功
-	if res.RowsAffected == 0 {
-		return art.Id, errors.New("可能是别人写的文章，或者已经删除了")
-	}
-	return art.Id, res.Error
+		// 至少会有更新时间 Utime 会被更新，所以可以判断是否更新成功
+		if res.RowsAffected == 0 {
+			return errors.New("可能是别人写的文章，或者已经删除了")
+		}
+
+		// 标签：覆盖写者库的标签
+		return replaceTags(txDb, tagTable, art.Id, art.Tags, now)
+	})
+	return art.Id, err
 }
 
 func (dao *GormArticleDAO) Upsert(ctx context.Context, art Article) (int64, error) {
@@ -88,41 +157,71 @@ func (dao *GormArticleDAO) Upsert(ctx context.Context, art Article) (int64, erro
 	err := dao.db.WithContext(ctx).Transaction(func(txDb *gorm.DB) error {
 		var err error
 		now := time.Now().UnixMilli()
-		// 写者库,
-		dao := NewArticleDAO(txDb)
-		if id > 0 {
-			id, err = dao.UpdateById(ctx, art)
-		} else {
-			id, err = dao.Insert(ctx, art)
-		}
-
+		id, err = saveAuthor(ctx, txDb, art, now)
 		if err != nil {
 			return err
 		}
 
 		// 读者库：Upsert 即 update or insert
 		art.Id = id
-		pubArt := PublishedArticle{
-			Article: art,
-		}
-		pubArt.Ctime = now
-		pubArt.Utime = now
-		err = txDb.Clauses(clause.OnConflict{
-			// id 冲突的时候执行 update，否则执行 insert
-			Columns: []clause.Column{{Name: "id"}},
-			// update 的时候，只更新 title 和 content, utime
-			DoUpdates: clause.Assignments(map[string]interface{}{
-				"title":   art.Title,
-				"content": art.Content,
-				"status":  art.Status,
-				"utime":   now,
-			}),
-		}).Create(&pubArt).Error
-		return err
+		return upsertPublished(txDb, art, now)
 	})
 	return id, err
 }
 
+// saveAuthor 写入写者库，并保存一份历史版本
+func saveAuthor(ctx context.Context, txDb *gorm.DB, art Article, now int64) (int64, error) {
+	var (
+		id  = art.Id
+		err error
+	)
+	dao := NewArticleDAO(txDb)
+	if id > 0 {
+		id, err = dao.UpdateById(ctx, art)
+	} else {
+		id, err = dao.Insert(ctx, art)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// 历史版本：保存一份快照
+	err = txDb.Create(&ArticleRevision{
+		ArticleId: id,
+		AuthorId:  art.AuthorId,
+		Title:     art.Title,
+		Content:   art.Content,
+		Ctime:     now,
+	}).Error
+	return id, err
+}
+
+// upsertPublished 写入读者库，id 冲突的时候执行 update，否则执行 insert
+// 标签以写者库为准，同步到线上库
+func upsertPublished(txDb *gorm.DB, art Article, now int64) error {
+	pubArt := PublishedArticle{
+		Article: art,
+	}
+	pubArt.Ctime = now
+	pubArt.Utime = now
+	err := txDb.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		// update 的时候，只更新 title 和 content, status, utime
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"title":      art.Title,
+			"content":    art.Content,
+			"status":     art.Status,
+			"publish_at": art.PublishAt,
+			"category":   art.Category,
+			"utime":      now,
+		}),
+	}).Create(&pubArt).Error
+	if err != nil {
+		return err
+	}
+	return syncPublishedTags(txDb, art.Id)
+}
+
 func (dao *GormArticleDAO) UpdateStatus(ctx context.Context, art Article) (int64, error) {
 	now := time.Now().UnixMilli()
 
@@ -178,11 +277,129 @@ func (dao *GormArticleDAO) FindPublicById(ctx context.Context, id int64) (Publis
 	return art, err
 }
 
-// FindPublishedArticleList 获取线上库文章列表
-func (dao *GormArticleDAO) FindPublishedArticleList(ctx context.Context, end time.Time, offset int, limit int) ([]PublishedArticle, error) {
+// FindPublicByIds 批量获取线上库文章，只返回状态为 ArticleStatusPublished：2 的文章
+func (dao *GormArticleDAO) FindPublicByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
 	var arts []PublishedArticle
-	err := dao.db.WithContext(ctx).Where("status = ? and utime < ?", 2, end.UnixMilli()).
-		Order("utime desc").
+	err := dao.db.WithContext(ctx).Where("id IN ? and status = ?", ids, 2).Find(&arts).Error
+	return arts, err
+}
+
+// FindPublishedByAuthors 按 (utime, id) 倒序查询作者们排在游标之后的线上文章，utime 为 0 表示从最新的开始
+func (dao *GormArticleDAO) FindPublishedByAuthors(ctx context.Context, authorIds []int64, utime int64, id int64, limit int) ([]PublishedArticle, error) {
+	var arts []PublishedArticle
+	query := dao.db.WithContext(ctx).Select("id", "author_id", "utime").
+		Where("author_id IN ? and status = ?", authorIds, statusPublished)
+	if utime > 0 {
+		query = query.Where("utime < ? OR (utime = ? AND id < ?)", utime, utime, id)
+	}
+	err := query.Order("utime desc, id desc").
+		Limit(limit).
+		Find(&arts).Error
+	return arts, err
+}
+
+func (dao *GormArticleDAO) FindPublishedAfter(ctx context.Context, id int64, limit int) ([]PublishedArticle, error) {
+	var arts []PublishedArticle
+	err := dao.db.WithContext(ctx).Where("id > ? and status = ?", id, statusPublished).
+		Order("id asc").
+		Limit(limit).
+		Find(&arts).Error
+	return arts, err
+}
+
+// FindPublishedArticleList 获取线上库文章列表，可以按标签和分类过滤
+func (dao *GormArticleDAO) FindPublishedArticleList(ctx context.Context, end time.Time, offset int, limit int, filter ArticleFilter) ([]PublishedArticle, error) {
+	var arts []PublishedArticle
+	query := dao.db.WithContext(ctx).Where("status = ? and utime < ?", 2, end.UnixMilli())
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Tag != "" {
+		query = query.Where("id IN (?)", dao.db.Model(&PublishedArticleTag{}).
+			Select("article_id").
+			Where("tag = ?", filter.Tag))
+	}
+	err := query.Order("utime desc").
 		Limit(limit).Offset(offset).Find(&arts).Error
 	return arts, err
 }
+
+// GetRevisions 获取文章的历史版本，按创建时间倒序，只有作者本人可以查看
+func (dao *GormArticleDAO) GetRevisions(ctx context.Context, artId int64, authorId int64, limit int, offset int) ([]ArticleRevision, error) {
+	var revs []ArticleRevision
+	err := dao.db.WithContext(ctx).
+		Where("article_id = ? and author_id = ?", artId, authorId).
+		Order("id desc").
+		Limit(limit).Offset(offset).Find(&revs).Error
+	return revs, err
+}
+
+func (dao *GormArticleDAO) FindRevisionById(ctx context.Context, id int64) (ArticleRevision, error) {
+	var rev ArticleRevision
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&rev).Error
+	return rev, err
+}
+
+// Schedule 保存定时发布的文章。已经发表的文章在到点之前仍然以线上库的版本对外可见，
+// 所以只写写者库和历史版本，不动线上库
+func (dao *GormArticleDAO) Schedule(ctx context.Context, art Article) (int64, error) {
+	var id int64
+	err := dao.db.WithContext(ctx).Transaction(func(txDb *gorm.DB) error {
+		var err error
+		id, err = saveAuthor(ctx, txDb, art, time.Now().UnixMilli())
+		return err
+	})
+	return id, err
+}
+
+// CancelSchedule 取消定时发布，写者库回到未发表状态，只对定时发布状态的文章生效。
+// 定时发布没有写线上库，所以线上库不用改，已经发表的文章保持发表
+func (dao *GormArticleDAO) CancelSchedule(ctx context.Context, art Article) (int64, error) {
+	res := dao.db.WithContext(ctx).Model(&Article{}).
+		Where("id = ? and author_id = ? and status = ?", art.Id, art.AuthorId, statusScheduled).
+		Updates(map[string]any{
+			"status":     statusUnpublished,
+			"publish_at": 0,
+			"utime":      time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return art.Id, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return art.Id, ErrNotScheduled
+	}
+	return art.Id, nil
+}
+
+// FindDueScheduled 查询到期的定时发布文章，按发布时间升序
+func (dao *GormArticleDAO) FindDueScheduled(ctx context.Context, now time.Time, limit int) ([]Article, error) {
+	var arts []Article
+	err := dao.db.WithContext(ctx).
+		Where("status = ? and publish_at <= ?", statusScheduled, now.UnixMilli()).
+		Order("publish_at asc").
+		Limit(limit).Find(&arts).Error
+	return arts, err
+}
+
+// PublishScheduled 发布到期的定时文章：写者库改状态，读者库 Upsert
+// 只有仍处于定时发布状态的文章才会发布，避免和作者的取消、编辑操作冲突
+func (dao *GormArticleDAO) PublishScheduled(ctx context.Context, art Article) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(txDb *gorm.DB) error {
+		res := txDb.Model(&Article{}).
+			Where("id = ? and status = ?", art.Id, statusScheduled).
+			Updates(map[string]any{
+				"status": statusPublished,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotScheduled
+		}
+
+		art.Status = statusPublished
+		return upsertPublished(txDb, art, now)
+	})
+}
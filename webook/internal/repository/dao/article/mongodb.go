@@ -14,6 +14,7 @@ import (
 type MongoDBArticleDAO struct {
 	col     *mongo.Collection
 	liveCol *mongo.Collection
+	revCol  *mongo.Collection
 	node    *snowflake.Node
 }
 
@@ -21,6 +22,7 @@ func NewMongoDBArticleDAO(mongoDB *mongo.Database, node *snowflake.Node) *MongoD
 	return &MongoDBArticleDAO{
 		col:     mongoDB.Collection("article"),
 		liveCol: mongoDB.Collection("published_article"),
+		revCol:  mongoDB.Collection("article_revision"),
 		node:    node,
 	}
 }
@@ -48,10 +50,12 @@ func (dao *MongoDBArticleDAO) UpdateById(ctx context.Context, art Article) (int6
 		bson.E{
 			Key: "$set",
 			Value: bson.M{
-				"title":   art.Title,
-				"content": art.Content,
-				"status":  art.Status,
-				"utime":   now,
+				"title":    art.Title,
+				"content":  art.Content,
+				"status":   art.Status,
+				"category": art.Category,
+				"tags":     art.Tags,
+				"utime":    now,
 			},
 		},
 	}
@@ -85,9 +89,22 @@ func (dao *MongoDBArticleDAO) Upsert(ctx context.Context, art Article) (int64, e
 		return 0, err
 	}
 
-	// 线上库
+	// 历史版本：保存一份快照
 	art.Id = id
 	now := time.Now().UnixMilli()
+	_, err = dao.revCol.InsertOne(ctx, ArticleRevision{
+		Id:        dao.node.Generate().Int64(),
+		ArticleId: id,
+		AuthorId:  art.AuthorId,
+		Title:     art.Title,
+		Content:   art.Content,
+		Ctime:     now,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// 线上库
 	art.Utime = now
 	filter := bson.D{
 		bson.E{
@@ -153,3 +170,29 @@ func (dao *MongoDBArticleDAO) UpdateStatus(ctx context.Context, art Article) (in
 	_, err = dao.liveCol.UpdateOne(ctx, filter, set)
 	return art.Id, err
 }
+
+func (dao *MongoDBArticleDAO) GetRevisions(ctx context.Context, artId int64, authorId int64, limit int, offset int) ([]ArticleRevision, error) {
+	filter := bson.D{
+		bson.E{Key: "article_id", Value: artId},
+		bson.E{Key: "author_id", Value: authorId},
+	}
+	// snowflake id 是递增的，按 id 倒序即按创建时间倒序
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "id", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+
+	cursor, err := dao.revCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var revs []ArticleRevision
+	err = cursor.All(ctx, &revs)
+	return revs, err
+}
+
+func (dao *MongoDBArticleDAO) FindRevisionById(ctx context.Context, id int64) (ArticleRevision, error) {
+	var rev ArticleRevision
+	err := dao.revCol.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&rev)
+	return rev, err
+}
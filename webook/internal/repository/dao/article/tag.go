@@ -0,0 +1,113 @@
+package article
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 写者库的文章标签
+type ArticleTag struct {
+	Id        int64  `gorm:"primaryKey,autoIncrement"`
+	ArticleId int64  `gorm:"uniqueIndex:article_tag"`
+	Tag       string `gorm:"type:varchar(64);uniqueIndex:article_tag;index"`
+	Ctime     int64
+}
+
+// 线上库的文章标签：随读者库被动更新，用于标签过滤和热度统计
+type PublishedArticleTag struct {
+	ArticleTag
+}
+
+const (
+	tagTable          = "article_tags"
+	publishedTagTable = "published_article_tags"
+)
+
+// TagCnt 标签和使用该标签的已发表文章数
+type TagCnt struct {
+	Tag string
+	Cnt int64
+}
+
+// replaceTags 先删后插，覆盖文章的全部标签，table 决定写入写者库还是线上库
+func replaceTags(txDb *gorm.DB, table string, artId int64, tags []string, now int64) error {
+	err := txDb.Table(table).Where("article_id = ?", artId).Delete(&ArticleTag{}).Error
+	if err != nil || len(tags) == 0 {
+		return err
+	}
+
+	rows := make([]ArticleTag, 0, len(tags))
+	for _, tag := range tags {
+		rows = append(rows, ArticleTag{
+			ArticleId: artId,
+			Tag:       tag,
+			Ctime:     now,
+		})
+	}
+	return txDb.Table(table).Create(&rows).Error
+}
+
+func (dao *GormArticleDAO) GetTags(ctx context.Context, artId int64) ([]string, error) {
+	var tags []string
+	err := dao.db.WithContext(ctx).Model(&ArticleTag{}).
+		Where("article_id = ?", artId).
+		Order("id asc").
+		Pluck("tag", &tags).Error
+	return tags, err
+}
+
+// GetPublishedTagsByIds 批量获取线上文章的标签
+func (dao *GormArticleDAO) GetPublishedTagsByIds(ctx context.Context, artIds []int64) (map[int64][]string, error) {
+	var rows []PublishedArticleTag
+	err := dao.db.WithContext(ctx).
+		Where("article_id IN ?", artIds).
+		Order("id asc").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64][]string, len(artIds))
+	for _, row := range rows {
+		res[row.ArticleId] = append(res[row.ArticleId], row.Tag)
+	}
+	return res, nil
+}
+
+// FindTagCnts 统计已发表文章的标签热度，prefix 不为空时只统计以 prefix 开头的标签
+func (dao *GormArticleDAO) FindTagCnts(ctx context.Context, prefix string, limit int) ([]TagCnt, error) {
+	query := dao.db.WithContext(ctx).Model(&PublishedArticleTag{}).
+		Select("published_article_tags.tag AS tag, COUNT(*) AS cnt").
+		Joins("JOIN published_articles ON published_articles.id = published_article_tags.article_id").
+		Where("published_articles.status = ?", statusPublished)
+	if prefix != "" {
+		query = query.Where("published_article_tags.tag LIKE ?", escapeLike(prefix)+"%")
+	}
+
+	var res []TagCnt
+	err := query.Group("published_article_tags.tag").
+		Order("cnt desc").
+		Limit(limit).
+		Scan(&res).Error
+	return res, err
+}
+
+// escapeLike 转义 LIKE 中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// syncPublishedTags 把写者库的标签同步到线上库
+func syncPublishedTags(txDb *gorm.DB, artId int64) error {
+	var tags []string
+	err := txDb.Model(&ArticleTag{}).
+		Where("article_id = ?", artId).
+		Order("id asc").
+		Pluck("tag", &tags).Error
+	if err != nil {
+		return err
+	}
+	return replaceTags(txDb, publishedTagTable, artId, tags, time.Now().UnixMilli())
+}
@@ -0,0 +1,148 @@
+package dao
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 作者每天的数据汇总，Day 是 2006-01-02 格式的日期
+type AuthorDailyStats struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	AuthorId   int64  `gorm:"uniqueIndex:author_day"`
+	Day        string `gorm:"type:varchar(10);uniqueIndex:author_day;index"`
+	ReadCnt    int64
+	LikeCnt    int64
+	CollectCnt int64
+	PublishCnt int64
+	Ctime      int64
+	Utime      int64
+}
+
+// 已经计入发表数的文章，重新发表或者重复消费事件时不重复计数
+type AuthorPublishedArticle struct {
+	ArticleId int64 `gorm:"primaryKey,autoIncrement:false"`
+	AuthorId  int64
+	Ctime     int64
+}
+
+// AuthorStatsSum 一段时间内的汇总，Score 按权重计算
+type AuthorStatsSum struct {
+	AuthorId   int64
+	ReadCnt    int64
+	LikeCnt    int64
+	CollectCnt int64
+	PublishCnt int64
+	Score      float64
+}
+
+type AuthorScoreWeights struct {
+	Read    float64
+	Like    float64
+	Collect float64
+	Publish float64
+}
+
+type AuthorStatsDAO interface {
+	// BatchIncr 阅读、点赞、收藏数是增量，按 (author_id, day) 累加。发表数用 IncrPublishCnt
+	BatchIncr(ctx context.Context, stats []AuthorDailyStats) error
+	// IncrPublishCnt 文章第一次计入时发表数加一
+	IncrPublishCnt(ctx context.Context, authorId int64, artId int64, day string) error
+	// FindByAuthor [start, end] 之间的每日数据
+	FindByAuthor(ctx context.Context, authorId int64, start string, end string) ([]AuthorDailyStats, error)
+	// TopAuthors 从 start 开始按得分取前 limit 个作者
+	TopAuthors(ctx context.Context, start string, weights AuthorScoreWeights, limit int) ([]AuthorStatsSum, error)
+}
+
+type GormAuthorStatsDAO struct {
+	db *gorm.DB
+}
+
+func NewAuthorStatsDAO(db *gorm.DB) AuthorStatsDAO {
+	return &GormAuthorStatsDAO{
+		db: db,
+	}
+}
+
+func (dao *GormAuthorStatsDAO) BatchIncr(ctx context.Context, stats []AuthorDailyStats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	// 固定顺序，避免多个实例同时写入时死锁
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].AuthorId != stats[j].AuthorId {
+			return stats[i].AuthorId < stats[j].AuthorId
+		}
+		return stats[i].Day < stats[j].Day
+	})
+	now := time.Now().UnixMilli()
+	for i := range stats {
+		stats[i].Ctime = now
+		stats[i].Utime = now
+	}
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"read_cnt":    gorm.Expr("`read_cnt` + VALUES(`read_cnt`)"),
+			"like_cnt":    gorm.Expr("`like_cnt` + VALUES(`like_cnt`)"),
+			"collect_cnt": gorm.Expr("`collect_cnt` + VALUES(`collect_cnt`)"),
+			"utime":       now,
+		}),
+	}).Create(&stats).Error
+}
+
+func (dao *GormAuthorStatsDAO) IncrPublishCnt(ctx context.Context, authorId int64, artId int64, day string) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&AuthorPublishedArticle{
+			ArticleId: artId,
+			AuthorId:  authorId,
+			Ctime:     now,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// 已经计过了
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{
+				"publish_cnt": gorm.Expr("`publish_cnt` + 1"),
+				"utime":       now,
+			}),
+		}).Create(&AuthorDailyStats{
+			AuthorId:   authorId,
+			Day:        day,
+			PublishCnt: 1,
+			Ctime:      now,
+			Utime:      now,
+		}).Error
+	})
+}
+
+func (dao *GormAuthorStatsDAO) FindByAuthor(ctx context.Context, authorId int64, start string, end string) ([]AuthorDailyStats, error) {
+	var res []AuthorDailyStats
+	err := dao.db.WithContext(ctx).
+		Where("author_id = ? AND day >= ? AND day <= ?", authorId, start, end).
+		Order("day").
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormAuthorStatsDAO) TopAuthors(ctx context.Context, start string, weights AuthorScoreWeights, limit int) ([]AuthorStatsSum, error) {
+	var res []AuthorStatsSum
+	err := dao.db.WithContext(ctx).Model(&AuthorDailyStats{}).
+		Select("author_id, SUM(read_cnt) AS read_cnt, SUM(like_cnt) AS like_cnt, "+
+			"SUM(collect_cnt) AS collect_cnt, SUM(publish_cnt) AS publish_cnt, "+
+			"SUM(read_cnt) * ? + SUM(like_cnt) * ? + SUM(collect_cnt) * ? + SUM(publish_cnt) * ? AS score",
+			weights.Read, weights.Like, weights.Collect, weights.Publish).
+		Where("day >= ?", start).
+		Group("author_id").
+		Order("score DESC").
+		Limit(limit).
+		Scan(&res).Error
+	return res, err
+}
@@ -0,0 +1,163 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCollectionDuplicate = errors.New("收藏夹名称重复")
+	ErrCollectionNotFound  = errors.New("收藏夹不存在")
+)
+
+// 收藏夹表：同一个用户下名称唯一
+type Collection struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"uniqueIndex:uid_name"`
+	Name  string `gorm:"type:varchar(64);uniqueIndex:uid_name"`
+	Ctime int64
+	Utime int64
+}
+
+// CollectionCnt 收藏夹中的内容数量
+type CollectionCnt struct {
+	Cid int64
+	Cnt int64
+}
+
+type CollectionDAO interface {
+	Insert(ctx context.Context, c Collection) (int64, error)
+	UpdateName(ctx context.Context, c Collection) error
+	// Delete 删除收藏夹，并取消收藏夹中的所有收藏，返回被取消的收藏
+	Delete(ctx context.Context, id int64, uid int64) ([]UserCollectBiz, error)
+	FindById(ctx context.Context, id int64) (Collection, error)
+	FindByUid(ctx context.Context, uid int64) ([]Collection, error)
+	CountItems(ctx context.Context, cids []int64) ([]CollectionCnt, error)
+	// FindItems 收藏夹中的收藏，最近收藏的在前
+	FindItems(ctx context.Context, cid int64, offset int, limit int) ([]UserCollectBiz, error)
+}
+
+type GormCollectionDAO struct {
+	db *gorm.DB
+}
+
+func NewCollectionDAO(db *gorm.DB) CollectionDAO {
+	return &GormCollectionDAO{
+		db: db,
+	}
+}
+
+func (dao *GormCollectionDAO) Insert(ctx context.Context, c Collection) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := dao.db.WithContext(ctx).Create(&c).Error
+	if isDuplicateErr(err) {
+		return 0, ErrCollectionDuplicate
+	}
+	return c.Id, err
+}
+
+func (dao *GormCollectionDAO) UpdateName(ctx context.Context, c Collection) error {
+	res := dao.db.WithContext(ctx).Model(&Collection{}).
+		Where("id = ? AND uid = ?", c.Id, c.Uid).
+		Updates(map[string]any{
+			"name":  c.Name,
+			"utime": time.Now().UnixMilli(),
+		})
+	if isDuplicateErr(res.Error) {
+		return ErrCollectionDuplicate
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+func (dao *GormCollectionDAO) Delete(ctx context.Context, id int64, uid int64) ([]UserCollectBiz, error) {
+	now := time.Now().UnixMilli()
+	var items []UserCollectBiz
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND uid = ?", id, uid).Delete(&Collection{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrCollectionNotFound
+		}
+
+		// 收藏夹中的收藏全部取消，并维护收藏数
+		err := tx.Where("cid = ?", id).Find(&items).Error
+		if err != nil || len(items) == 0 {
+			return err
+		}
+		err = tx.Where("cid = ?", id).Delete(&UserCollectBiz{}).Error
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			err = tx.Model(&Interactive{}).
+				Where("biz = ? AND biz_id = ?", item.Biz, item.BizId).
+				Updates(map[string]any{
+					"collect_cnt": gorm.Expr("`collect_cnt` - 1"),
+					"utime":       now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (dao *GormCollectionDAO) FindById(ctx context.Context, id int64) (Collection, error) {
+	var c Collection
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&c).Error
+	if err == gorm.ErrRecordNotFound {
+		return Collection{}, ErrCollectionNotFound
+	}
+	return c, err
+}
+
+func (dao *GormCollectionDAO) FindByUid(ctx context.Context, uid int64) ([]Collection, error) {
+	var res []Collection
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).Order("id asc").Find(&res).Error
+	return res, err
+}
+
+func (dao *GormCollectionDAO) CountItems(ctx context.Context, cids []int64) ([]CollectionCnt, error) {
+	var res []CollectionCnt
+	err := dao.db.WithContext(ctx).Model(&UserCollectBiz{}).
+		Select("cid, COUNT(*) AS cnt").
+		Where("cid IN ?", cids).
+		Group("cid").
+		Scan(&res).Error
+	return res, err
+}
+
+func (dao *GormCollectionDAO) FindItems(ctx context.Context, cid int64, offset int, limit int) ([]UserCollectBiz, error) {
+	var res []UserCollectBiz
+	err := dao.db.WithContext(ctx).Where("cid = ?", cid).
+		Order("utime desc").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// isDuplicateErr 唯一索引冲突
+func isDuplicateErr(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	const uniqueConflictsErrNo uint16 = 1062
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == uniqueConflictsErrNo
+}
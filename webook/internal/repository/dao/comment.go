@@ -0,0 +1,170 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 评论表：和交互表一样按 (biz, bizid) 区分业务
+type Comment struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 评论者
+	Uid int64 `gorm:"index"`
+
+	Biz   string `gorm:"type:varchar(128);index:biz_type_id"`
+	BizId int64  `gorm:"index:biz_type_id"`
+
+	// 根评论为 0，回复指向所在的根评论，用于分页查询某个根评论下的回复
+	RootId int64 `gorm:"index"`
+	// 被回复的评论，根评论为 0
+	Pid int64 `gorm:"index"`
+
+	Content string `gorm:"type:text"`
+	Ctime   int64
+	Utime   int64
+}
+
+// ReplyCnt 根评论下的回复数
+type ReplyCnt struct {
+	RootId int64
+	Cnt    int64
+}
+
+type CommentDAO interface {
+	Insert(ctx context.Context, c Comment) (int64, error)
+	// Delete 删除评论及其下的所有回复，返回删除的条数
+	Delete(ctx context.Context, c Comment) (int64, error)
+	FindById(ctx context.Context, id int64) (Comment, error)
+	FindByIds(ctx context.Context, ids []int64) ([]Comment, error)
+	// FindRoots 游标分页：id 小于 maxId 的根评论，按 id 倒序，maxId 为 0 表示从头开始
+	FindRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]Comment, error)
+	// FindReplies 游标分页：id 大于 minId 的回复，按 id 正序
+	FindReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]Comment, error)
+	CountReplies(ctx context.Context, rootIds []int64) ([]ReplyCnt, error)
+}
+
+type GormCommentDAO struct {
+	db *gorm.DB
+}
+
+func NewCommentDAO(db *gorm.DB) CommentDAO {
+	return &GormCommentDAO{
+		db: db,
+	}
+}
+
+func (dao *GormCommentDAO) Insert(ctx context.Context, c Comment) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&c).Error
+		if err != nil {
+			return err
+		}
+
+		// 维护交互表中的评论数
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{
+				"comment_cnt": gorm.Expr("`comment_cnt` + 1"),
+				"utime":       now,
+			}),
+		}).Create(&Interactive{
+			Biz:        c.Biz,
+			BizId:      c.BizId,
+			CommentCnt: 1,
+			Ctime:      now,
+			Utime:      now,
+		}).Error
+	})
+	return c.Id, err
+}
+
+func (dao *GormCommentDAO) Delete(ctx context.Context, c Comment) (int64, error) {
+	var cnt int64
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []int64{c.Id}
+		if c.RootId == 0 {
+			// 根评论：连带删除所有回复
+			var replyIds []int64
+			err := tx.Model(&Comment{}).Where("root_id = ?", c.Id).Pluck("id", &replyIds).Error
+			if err != nil {
+				return err
+			}
+			ids = append(ids, replyIds...)
+		} else {
+			// 回复：逐层找出回复它的评论
+			parents := []int64{c.Id}
+			for len(parents) > 0 {
+				var children []int64
+				err := tx.Model(&Comment{}).Where("pid IN ?", parents).Pluck("id", &children).Error
+				if err != nil {
+					return err
+				}
+				ids = append(ids, children...)
+				parents = children
+			}
+		}
+
+		res := tx.Where("id IN ?", ids).Delete(&Comment{})
+		if res.Error != nil {
+			return res.Error
+		}
+		cnt = res.RowsAffected
+		if cnt == 0 {
+			return nil
+		}
+
+		return tx.Model(&Interactive{}).
+			Where("biz = ? AND biz_id = ?", c.Biz, c.BizId).
+			Updates(map[string]any{
+				"comment_cnt": gorm.Expr("`comment_cnt` - ?", cnt),
+				"utime":       time.Now().UnixMilli(),
+			}).Error
+	})
+	return cnt, err
+}
+
+func (dao *GormCommentDAO) FindById(ctx context.Context, id int64) (Comment, error) {
+	var c Comment
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&c).Error
+	return c, err
+}
+
+func (dao *GormCommentDAO) FindByIds(ctx context.Context, ids []int64) ([]Comment, error) {
+	var res []Comment
+	err := dao.db.WithContext(ctx).Where("id IN ?", ids).Find(&res).Error
+	return res, err
+}
+
+func (dao *GormCommentDAO) FindRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]Comment, error) {
+	var res []Comment
+	query := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND root_id = ?", biz, bizId, 0)
+	if maxId > 0 {
+		query = query.Where("id < ?", maxId)
+	}
+	err := query.Order("id desc").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GormCommentDAO) FindReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]Comment, error) {
+	var res []Comment
+	err := dao.db.WithContext(ctx).
+		Where("root_id = ? AND id > ?", rootId, minId).
+		Order("id asc").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GormCommentDAO) CountReplies(ctx context.Context, rootIds []int64) ([]ReplyCnt, error) {
+	var res []ReplyCnt
+	err := dao.db.WithContext(ctx).Model(&Comment{}).
+		Select("root_id, COUNT(*) AS cnt").
+		Where("root_id IN ?", rootIds).
+		Group("root_id").
+		Scan(&res).Error
+	return res, err
+}
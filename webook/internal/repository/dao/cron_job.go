@@ -0,0 +1,258 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNoCronJob = errors.New("没有可以执行的任务")
+	// ErrCronJobNotHeld 任务已经被其他节点接手
+	ErrCronJobNotHeld  = errors.New("没有持有任务")
+	ErrCronJobNotFound = errors.New("任务不存在")
+	// ErrCronJobStatusConflict 例如暂停正在执行的任务
+	ErrCronJobStatusConflict = errors.New("任务当前的状态不允许这个操作")
+)
+
+// 定时任务：等待中的任务按 status + next_time 扫描，运行中的任务按 heartbeat 判断持有者是否还活着
+type CronJob struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	Name       string `gorm:"type:varchar(128);uniqueIndex"`
+	Executor   string `gorm:"type:varchar(64)"`
+	Cfg        string `gorm:"type:text"`
+	Expression string `gorm:"type:varchar(64)"`
+	Status     uint8  `gorm:"index:status_next_time,priority:1"`
+	NextTime   int64  `gorm:"index:status_next_time,priority:2"`
+	Owner      string `gorm:"type:varchar(128)"`
+	// 乐观锁，每次抢占加一
+	Version   int64
+	Heartbeat int64
+	Ctime     int64
+	Utime     int64
+}
+
+// 任务的执行记录，按任务倒序查看最近的执行，过期的按 start_time 清理
+type CronJobExecution struct {
+	Id        int64  `gorm:"primaryKey,autoIncrement"`
+	JobId     int64  `gorm:"index"`
+	Name      string `gorm:"type:varchar(128)"`
+	Owner     string `gorm:"type:varchar(128)"`
+	Version   int64
+	Status    uint8
+	Error     string `gorm:"type:varchar(1024)"`
+	StartTime int64  `gorm:"index"`
+	EndTime   int64
+}
+
+type CronJobDAO interface {
+	// Upsert 按名字插入，已经存在时只更新执行器和配置，
+	// cron 表达式可能在运行时修改过，以数据库为准
+	Upsert(ctx context.Context, j CronJob) error
+	FindAll(ctx context.Context) ([]CronJob, error)
+	FindById(ctx context.Context, id int64) (CronJob, error)
+	// Transit 任务处于 from 状态时更新，不存在时返回 ErrCronJobNotFound，状态不对时返回 ErrCronJobStatusConflict
+	Transit(ctx context.Context, id int64, from uint8, updates map[string]any) error
+	// UpdateExpression 修改 cron 表达式，任务处于 waiting 状态时同时更新下一次执行时间，
+	// 其他状态在释放或者恢复时按新的表达式计算
+	UpdateExpression(ctx context.Context, id int64, expression string, waiting uint8, nextTime int64) error
+	// Preempt 抢占一个到期的等待中任务，或者心跳早于 heartbeatBefore 的运行中任务
+	Preempt(ctx context.Context, waiting uint8, running uint8, owner string, now int64, heartbeatBefore int64) (CronJob, error)
+	Heartbeat(ctx context.Context, id int64, version int64, running uint8) error
+	// Release 执行完释放任务，等待下一次执行。
+	// nextTime 是按 expression 计算的，执行期间表达式被修改过时同样返回 ErrCronJobNotHeld，由调用方重新计算
+	Release(ctx context.Context, id int64, version int64, expression string, running uint8, waiting uint8, nextTime int64) error
+
+	InsertExecution(ctx context.Context, e CronJobExecution) (int64, error)
+	FinishExecution(ctx context.Context, id int64, status uint8, errMsg string, endTime int64) error
+	FindExecutions(ctx context.Context, jobId int64, offset int, limit int) ([]CronJobExecution, error)
+	// DeleteExecutionsBefore 删除开始时间早于 before 的执行记录，最多删除 limit 条，返回删除的条数
+	DeleteExecutionsBefore(ctx context.Context, before int64, limit int) (int64, error)
+}
+
+type GormCronJobDAO struct {
+	db *gorm.DB
+}
+
+func NewCronJobDAO(db *gorm.DB) CronJobDAO {
+	return &GormCronJobDAO{
+		db: db,
+	}
+}
+
+func (dao *GormCronJobDAO) Upsert(ctx context.Context, j CronJob) error {
+	now := time.Now().UnixMilli()
+	j.Ctime = now
+	j.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"executor": j.Executor,
+			"cfg":      j.Cfg,
+			"utime":    now,
+		}),
+	}).Create(&j).Error
+}
+
+func (dao *GormCronJobDAO) Preempt(ctx context.Context, waiting uint8, running uint8, owner string, now int64, heartbeatBefore int64) (CronJob, error) {
+	db := dao.db.WithContext(ctx)
+	for {
+		if err := ctx.Err(); err != nil {
+			return CronJob{}, err
+		}
+		var j CronJob
+		err := db.Where("(status = ? AND next_time <= ?) OR (status = ? AND heartbeat < ?)",
+			waiting, now, running, heartbeatBefore).
+			First(&j).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return CronJob{}, ErrNoCronJob
+		}
+		if err != nil {
+			return CronJob{}, err
+		}
+
+		// 乐观锁，版本号变了说明被其他节点抢走了，换一个继续抢
+		res := db.Model(&CronJob{}).
+			Where("id = ? AND version = ?", j.Id, j.Version).
+			Updates(map[string]any{
+				"status":    running,
+				"owner":     owner,
+				"version":   j.Version + 1,
+				"heartbeat": now,
+				"utime":     now,
+			})
+		if res.Error != nil {
+			return CronJob{}, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		j.Status = running
+		j.Owner = owner
+		j.Version++
+		j.Heartbeat = now
+		j.Utime = now
+		return j, nil
+	}
+}
+
+func (dao *GormCronJobDAO) Heartbeat(ctx context.Context, id int64, version int64, running uint8) error {
+	now := time.Now().UnixMilli()
+	res := dao.db.WithContext(ctx).Model(&CronJob{}).
+		Where("id = ? AND version = ? AND status = ?", id, version, running).
+		Updates(map[string]any{
+			"heartbeat": now,
+			"utime":     now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrCronJobNotHeld
+	}
+	return nil
+}
+
+func (dao *GormCronJobDAO) Release(ctx context.Context, id int64, version int64, expression string, running uint8, waiting uint8, nextTime int64) error {
+	res := dao.db.WithContext(ctx).Model(&CronJob{}).
+		Where("id = ? AND version = ? AND status = ? AND expression = ?", id, version, running, expression).
+		Updates(map[string]any{
+			"status":    waiting,
+			"owner":     "",
+			"next_time": nextTime,
+			"utime":     time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrCronJobNotHeld
+	}
+	return nil
+}
+
+func (dao *GormCronJobDAO) FindAll(ctx context.Context) ([]CronJob, error) {
+	var js []CronJob
+	err := dao.db.WithContext(ctx).Order("id").Find(&js).Error
+	return js, err
+}
+
+func (dao *GormCronJobDAO) FindById(ctx context.Context, id int64) (CronJob, error) {
+	var j CronJob
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&j).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return CronJob{}, ErrCronJobNotFound
+	}
+	return j, err
+}
+
+func (dao *GormCronJobDAO) Transit(ctx context.Context, id int64, from uint8, updates map[string]any) error {
+	updates["utime"] = time.Now().UnixMilli()
+	res := dao.db.WithContext(ctx).Model(&CronJob{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+	// 区分任务不存在和状态不对
+	if _, err := dao.FindById(ctx, id); err != nil {
+		return err
+	}
+	return ErrCronJobStatusConflict
+}
+
+func (dao *GormCronJobDAO) UpdateExpression(ctx context.Context, id int64, expression string, waiting uint8, nextTime int64) error {
+	res := dao.db.WithContext(ctx).Model(&CronJob{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"expression": expression,
+			"next_time":  gorm.Expr("CASE WHEN status = ? THEN ? ELSE next_time END", waiting, nextTime),
+			"utime":      time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrCronJobNotFound
+	}
+	return nil
+}
+
+func (dao *GormCronJobDAO) InsertExecution(ctx context.Context, e CronJobExecution) (int64, error) {
+	err := dao.db.WithContext(ctx).Create(&e).Error
+	return e.Id, err
+}
+
+func (dao *GormCronJobDAO) FinishExecution(ctx context.Context, id int64, status uint8, errMsg string, endTime int64) error {
+	return dao.db.WithContext(ctx).Model(&CronJobExecution{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":   status,
+			"error":    errMsg,
+			"end_time": endTime,
+		}).Error
+}
+
+func (dao *GormCronJobDAO) FindExecutions(ctx context.Context, jobId int64, offset int, limit int) ([]CronJobExecution, error) {
+	var es []CronJobExecution
+	err := dao.db.WithContext(ctx).
+		Where("job_id = ?", jobId).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&es).Error
+	return es, err
+}
+
+func (dao *GormCronJobDAO) DeleteExecutionsBefore(ctx context.Context, before int64, limit int) (int64, error) {
+	res := dao.db.WithContext(ctx).
+		Where("start_time < ?", before).
+		Limit(limit).
+		Delete(&CronJobExecution{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,81 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 收件箱（推模式）：作者发表文章时写入每个粉丝的收件箱
+type FeedInbox struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 同一篇文章重新发表时只更新时间
+	Uid       int64 `gorm:"uniqueIndex:uid_article;index:uid_ctime,priority:1"`
+	ArticleId int64 `gorm:"uniqueIndex:uid_article"`
+	AuthorId  int64
+	// 发表时间，毫秒时间戳
+	Ctime int64 `gorm:"index:uid_ctime,priority:2"`
+}
+
+// 拉模式的作者：粉丝太多，发表时不写收件箱，读的时候从作者的发件箱（线上库）拉取
+type FeedPullAuthor struct {
+	AuthorId int64 `gorm:"primaryKey,autoIncrement:false"`
+	Ctime    int64
+}
+
+type FeedDAO interface {
+	// InsertInbox 批量写入收件箱，已存在的条目更新发表时间
+	InsertInbox(ctx context.Context, items []FeedInbox) error
+	// FindInbox 按 (ctime, article_id) 倒序查询收件箱中排在游标之后的条目，ctime 为 0 表示从最新的开始
+	FindInbox(ctx context.Context, uid int64, ctime int64, articleId int64, limit int) ([]FeedInbox, error)
+	InsertPullAuthor(ctx context.Context, authorId int64) error
+	FindPullAuthors(ctx context.Context) ([]int64, error)
+}
+
+type GormFeedDAO struct {
+	db *gorm.DB
+}
+
+func NewFeedDAO(db *gorm.DB) FeedDAO {
+	return &GormFeedDAO{
+		db: db,
+	}
+}
+
+func (dao *GormFeedDAO) InsertInbox(ctx context.Context, items []FeedInbox) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"ctime"}),
+	}).Create(&items).Error
+}
+
+func (dao *GormFeedDAO) FindInbox(ctx context.Context, uid int64, ctime int64, articleId int64, limit int) ([]FeedInbox, error) {
+	var res []FeedInbox
+	query := dao.db.WithContext(ctx).Where("uid = ?", uid)
+	if ctime > 0 {
+		query = query.Where("ctime < ? OR (ctime = ? AND article_id < ?)", ctime, ctime, articleId)
+	}
+	err := query.Order("ctime desc, article_id desc").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormFeedDAO) InsertPullAuthor(ctx context.Context, authorId int64) error {
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&FeedPullAuthor{
+			AuthorId: authorId,
+			Ctime:    time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GormFeedDAO) FindPullAuthors(ctx context.Context) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&FeedPullAuthor{}).
+		Pluck("author_id", &res).Error
+	return res, err
+}
@@ -0,0 +1,137 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	followStatusActive   uint8 = 1
+	followStatusInactive uint8 = 0
+)
+
+// 关注关系表：取消关注时软删除，再次关注时恢复
+type FollowRelation struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 按 follower 查询关注列表，按 followee 查询粉丝列表
+	Follower int64 `gorm:"uniqueIndex:follower_followee"`
+	Followee int64 `gorm:"uniqueIndex:follower_followee;index"`
+	Status   uint8
+	Ctime    int64
+	Utime    int64
+}
+
+type FollowDAO interface {
+	// Follow 关注，返回关系是否发生了变化，已经关注过则为 false
+	Follow(ctx context.Context, follower int64, followee int64) (bool, error)
+	// Unfollow 取消关注，返回关系是否发生了变化，没有关注过则为 false
+	Unfollow(ctx context.Context, follower int64, followee int64) (bool, error)
+	// FindFollowers 粉丝列表，最近关注的在前
+	FindFollowers(ctx context.Context, followee int64, offset int, limit int) ([]FollowRelation, error)
+	// FindFollowees 关注列表，最近关注的在前
+	FindFollowees(ctx context.Context, follower int64, offset int, limit int) ([]FollowRelation, error)
+	// FindFollowed 返回 followees 中被 follower 关注了的用户
+	FindFollowed(ctx context.Context, follower int64, followees []int64) ([]int64, error)
+	CntFollowers(ctx context.Context, uid int64) (int64, error)
+	CntFollowees(ctx context.Context, uid int64) (int64, error)
+}
+
+type GormFollowDAO struct {
+	db *gorm.DB
+}
+
+func NewFollowDAO(db *gorm.DB) FollowDAO {
+	return &GormFollowDAO{
+		db: db,
+	}
+}
+
+func (dao *GormFollowDAO) Follow(ctx context.Context, follower int64, followee int64) (bool, error) {
+	now := time.Now().UnixMilli()
+	changed := false
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 恢复取消过的关注
+		res := tx.Model(&FollowRelation{}).
+			Where("follower = ? AND followee = ? AND status = ?", follower, followee, followStatusInactive).
+			Updates(map[string]any{
+				"status": followStatusActive,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			changed = true
+			return nil
+		}
+
+		// 第一次关注，已经关注过则什么都不做
+		res = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&FollowRelation{
+			Follower: follower,
+			Followee: followee,
+			Status:   followStatusActive,
+			Ctime:    now,
+			Utime:    now,
+		})
+		changed = res.RowsAffected > 0
+		return res.Error
+	})
+	return changed, err
+}
+
+func (dao *GormFollowDAO) Unfollow(ctx context.Context, follower int64, followee int64) (bool, error) {
+	res := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("follower = ? AND followee = ? AND status = ?", follower, followee, followStatusActive).
+		Updates(map[string]any{
+			"status": followStatusInactive,
+			"utime":  time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (dao *GormFollowDAO) FindFollowers(ctx context.Context, followee int64, offset int, limit int) ([]FollowRelation, error) {
+	var res []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("followee = ? AND status = ?", followee, followStatusActive).
+		Order("utime desc").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormFollowDAO) FindFollowees(ctx context.Context, follower int64, offset int, limit int) ([]FollowRelation, error) {
+	var res []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("follower = ? AND status = ?", follower, followStatusActive).
+		Order("utime desc").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormFollowDAO) FindFollowed(ctx context.Context, follower int64, followees []int64) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("follower = ? AND followee IN ? AND status = ?", follower, followees, followStatusActive).
+		Pluck("followee", &res).Error
+	return res, err
+}
+
+func (dao *GormFollowDAO) CntFollowers(ctx context.Context, uid int64) (int64, error) {
+	var cnt int64
+	err := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("followee = ? AND status = ?", uid, followStatusActive).
+		Count(&cnt).Error
+	return cnt, err
+}
+
+func (dao *GormFollowDAO) CntFollowees(ctx context.Context, uid int64) (int64, error) {
+	var cnt int64
+	err := dao.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("follower = ? AND status = ?", uid, followStatusActive).
+		Count(&cnt).Error
+	return cnt, err
+}
@@ -7,7 +7,7 @@ import (
 )
 
 func InitTable(db *gorm.DB) error {
-	return db.AutoMigrate(&User{}, &article.Article{}, &article.PublishedArticle{}, &Interactive{}, &UserLikeBiz{}, &UserCollectBiz{})
+	return db.AutoMigrate(&User{}, &article.Article{}, &article.PublishedArticle{}, &article.ArticleRevision{}, &article.ArticleTag{}, &article.PublishedArticleTag{}, &Interactive{}, &UserLikeBiz{}, &UserCollectBiz{}, &Comment{}, &Collection{}, &FollowRelation{}, &FeedInbox{}, &FeedPullAuthor{}, &Notification{}, &NotificationActor{}, &Webhook{}, &WebhookSubscription{}, &WebhookDelivery{}, &ReadHistory{}, &CronJob{}, &CronJobExecution{}, &AuthorDailyStats{}, &AuthorPublishedArticle{}, &UserTwoFactor{}, &UserRecoveryCode{})
 }
 
 func TruncateTable(db *gorm.DB, tableName string) error {
@@ -19,6 +19,7 @@ type Interactive struct {
 	ReadCnt    int64
 	LikeCnt    int64
 	CollectCnt int64
+	CommentCnt int64
 	Ctime      int64
 	Utime      int64
 }
@@ -47,9 +48,13 @@ type UserCollectBiz struct {
 
 type InteractiveDAO interface {
 	IncreaseReadCnt(ctx context.Context, biz string, bizId int64) error
+	// BatchIncreaseReadCnt 批量增加阅读计数，bizs、bizIds、cnts 一一对应
+	BatchIncreaseReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error
 	InsertLikeInfo(ctx context.Context, biz string, bizId int64, userId int64) error
 	DeleteLikeInfo(ctx context.Context, biz string, bizId int64, userId int64) error
-	InsertCollection(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) error
+	// InsertCollection 返回是否新增了收藏记录，移动收藏夹时为 false
+	InsertCollection(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) (bool, error)
+	DeleteCollection(ctx context.Context, biz string, bizId int64, userId int64) (bool, error)
 	GetInteractive(ctx context.Context, biz string, bizId int64) (Interactive, error)
 	GetLiked(ctx context.Context, biz string, bizId int64, userId int64) (bool, error)
 	GetCollected(ctx context.Context, biz string, bizId int64, userId int64) (bool, error)
@@ -85,6 +90,30 @@ func (dao *GormInteractiveDAO) IncreaseReadCnt(ctx context.Context, biz string,
 	}).Error
 }
 
+// BatchIncreaseReadCnt 一条多行的 Upsert 语句
+func (dao *GormInteractiveDAO) BatchIncreaseReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error {
+	if len(bizs) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	inters := make([]Interactive, 0, len(bizs))
+	for i := range bizs {
+		inters = append(inters, Interactive{
+			Biz:     bizs[i],
+			BizId:   bizIds[i],
+			ReadCnt: cnts[i],
+			Ctime:   now,
+			Utime:   now,
+		})
+	}
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"read_cnt": gorm.Expr("`read_cnt` + VALUES(`read_cnt`)"),
+			"utime":    now,
+		}),
+	}).Create(&inters).Error
+}
+
 func (dao *GormInteractiveDAO) InsertLikeInfo(ctx context.Context, biz string, bizId int64, userId int64) error {
 	now := time.Now().UnixMilli()
 	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -142,9 +171,24 @@ func (dao *GormInteractiveDAO) DeleteLikeInfo(ctx context.Context, biz string, b
 	})
 }
 
-func (dao *GormInteractiveDAO) InsertCollection(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) error {
+// InsertCollection 收藏，已经收藏过的则移动到新的收藏夹，不重复计数
+func (dao *GormInteractiveDAO) InsertCollection(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) (bool, error) {
 	now := time.Now().UnixMilli()
-	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	created := false
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&UserCollectBiz{}).
+			Where("uid = ? AND biz = ? AND biz_id = ?", userId, biz, bizId).
+			Updates(map[string]any{
+				"cid":   collectionId,
+				"utime": now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			return nil
+		}
+
 		err := tx.Create(&UserCollectBiz{
 			Uid:   userId,
 			Biz:   biz,
@@ -156,6 +200,7 @@ func (dao *GormInteractiveDAO) InsertCollection(ctx context.Context, biz string,
 		if err != nil {
 			return err
 		}
+		created = true
 
 		// 维护 redis 中的 map 结构
 		return tx.WithContext(ctx).Clauses(clause.OnConflict{
@@ -171,12 +216,42 @@ func (dao *GormInteractiveDAO) InsertCollection(ctx context.Context, biz string,
 			Utime:      now,
 		}).Error
 	})
+	return created, err
+}
+
+// DeleteCollection 取消收藏，返回是否真的删除了收藏记录
+func (dao *GormInteractiveDAO) DeleteCollection(ctx context.Context, biz string, bizId int64, userId int64) (bool, error) {
+	now := time.Now().UnixMilli()
+	deleted := false
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("uid = ? AND biz = ? AND biz_id = ?", userId, biz, bizId).
+			Delete(&UserCollectBiz{})
+		if res.Error != nil {
+			return res.Error
+		}
+		// 没有收藏过，不需要减少收藏数
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		deleted = true
+		return tx.Model(&Interactive{}).
+			Where("biz = ? AND biz_id = ?", biz, bizId).
+			Updates(map[string]any{
+				"collect_cnt": gorm.Expr("`collect_cnt` - 1"),
+				"utime":       now,
+			}).Error
+	})
+	return deleted, err
 }
 
 func (dao *GormInteractiveDAO) GetInteractive(ctx context.Context, biz string, bizId int64) (Interactive, error) {
 	var res Interactive
 	err := dao.db.WithContext(ctx).
 		Where("biz = ? AND biz_id = ?", biz, bizId).First(&res).Error
+	// 阅读计数是异步增加的，第一次阅读时可能还没有记录，当作计数都是 0
+	if err == gorm.ErrRecordNotFound {
+		return Interactive{Biz: biz, BizId: bizId}, nil
+	}
 	return res, err
 }
 
@@ -0,0 +1,217 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	NotificationStatusUnread uint8 = 0
+	NotificationStatusRead   uint8 = 1
+
+	// 通知上保留的最近触发者数量
+	maxLatestActors = 3
+)
+
+// 通知表：可聚合的通知在未读期间复用同一行，已读后再触发会新建一行
+type Notification struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	Receiver int64 `gorm:"index:receiver_utime,priority:1"`
+	Type     uint8
+	Biz      string `gorm:"type:varchar(64)"`
+	BizId    int64
+	// 聚合键：接收者 + 类型 + 业务，只有未读的可聚合通知才有，已读后置为 NULL
+	// 唯一索引保证同一时刻只有一条未读的聚合通知，NULL 不参与唯一性检查
+	AggKey   sql.NullString `gorm:"type:varchar(128);unique"`
+	BizTitle string         `gorm:"type:varchar(1024)"`
+	// 最近的触发者 id，JSON 数组，最新的在前
+	Actors   string `gorm:"type:varchar(256)"`
+	ActorCnt int64
+	Content  string `gorm:"type:varchar(1024)"`
+	Status   uint8
+	Ctime    int64
+	Utime    int64 `gorm:"index:receiver_utime,priority:2"`
+}
+
+// 通知的触发者，用于聚合时去重：同一个人反复点赞只算一次
+type NotificationActor struct {
+	Id    int64 `gorm:"primaryKey,autoIncrement"`
+	Nid   int64 `gorm:"uniqueIndex:nid_actor"`
+	Actor int64 `gorm:"uniqueIndex:nid_actor"`
+	Ctime int64
+}
+
+type NotificationDAO interface {
+	// Insert 新建一条不聚合的通知
+	Insert(ctx context.Context, n Notification) (int64, error)
+	// Aggregate 聚合到接收者未读的同类通知上，没有则新建
+	Aggregate(ctx context.Context, n Notification, actor int64) error
+	// FindByReceiver 最近更新的在前，typ 为 0 表示所有类型
+	FindByReceiver(ctx context.Context, receiver int64, typ uint8, offset int, limit int) ([]Notification, error)
+	// CntUnread 各类型的未读数
+	CntUnread(ctx context.Context, receiver int64) ([]NotificationCnt, error)
+	MarkRead(ctx context.Context, receiver int64, ids []int64) error
+	// MarkAllRead typ 为 0 表示所有类型
+	MarkAllRead(ctx context.Context, receiver int64, typ uint8) error
+}
+
+type NotificationCnt struct {
+	Type uint8
+	Cnt  int64
+}
+
+type GormNotificationDAO struct {
+	db *gorm.DB
+}
+
+func NewNotificationDAO(db *gorm.DB) NotificationDAO {
+	return &GormNotificationDAO{
+		db: db,
+	}
+}
+
+func (dao *GormNotificationDAO) Insert(ctx context.Context, n Notification) (int64, error) {
+	now := time.Now().UnixMilli()
+	n.Status = NotificationStatusUnread
+	n.Ctime = now
+	n.Utime = now
+	err := dao.db.WithContext(ctx).Create(&n).Error
+	return n.Id, err
+}
+
+// aggregateRetries 插入时冲突、锁住之前又被标记为已读的话重试
+const aggregateRetries = 3
+
+func (dao *GormNotificationDAO) Aggregate(ctx context.Context, n Notification, actor int64) error {
+	n.AggKey = sql.NullString{
+		String: fmt.Sprintf("%d:%d:%s:%d", n.Receiver, n.Type, n.Biz, n.BizId),
+		Valid:  true,
+	}
+	for i := 0; i < aggregateRetries; i++ {
+		done, err := dao.aggregate(ctx, n, actor)
+		if err != nil || done {
+			return err
+		}
+	}
+	return errors.New("聚合通知冲突次数过多")
+}
+
+// aggregate 返回 false 表示未读通知在插入和锁定之间被标记为已读了，需要重试
+func (dao *GormNotificationDAO) aggregate(ctx context.Context, n Notification, actor int64) (bool, error) {
+	now := time.Now().UnixMilli()
+	done := true
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		actors, err := json.Marshal([]int64{actor})
+		if err != nil {
+			return err
+		}
+		n.Actors = string(actors)
+		n.ActorCnt = 1
+		n.Status = NotificationStatusUnread
+		n.Ctime = now
+		n.Utime = now
+		// INSERT ... ON DUPLICATE KEY UPDATE，已经有未读的同类通知时什么也不做
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&n)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			return tx.Create(&NotificationActor{Nid: n.Id, Actor: actor, Ctime: now}).Error
+		}
+
+		// 锁住已经存在的未读通知
+		var existing Notification
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("agg_key = ?", n.AggKey.String).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			done = false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		res = tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&NotificationActor{Nid: existing.Id, Actor: actor, Ctime: now})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		var latest []int64
+		if err = json.Unmarshal([]byte(existing.Actors), &latest); err != nil {
+			return err
+		}
+		latest = append([]int64{actor}, latest...)
+		if len(latest) > maxLatestActors {
+			latest = latest[:maxLatestActors]
+		}
+		data, err := json.Marshal(latest)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&Notification{}).Where("id = ?", existing.Id).
+			Updates(map[string]any{
+				"actors":    string(data),
+				"actor_cnt": gorm.Expr("actor_cnt + 1"),
+				"biz_title": n.BizTitle,
+				"utime":     now,
+			}).Error
+	})
+	return done, err
+}
+
+func (dao *GormNotificationDAO) FindByReceiver(ctx context.Context, receiver int64, typ uint8, offset int, limit int) ([]Notification, error) {
+	var res []Notification
+	query := dao.db.WithContext(ctx).Where("receiver = ?", receiver)
+	if typ > 0 {
+		query = query.Where("type = ?", typ)
+	}
+	err := query.Order("utime desc, id desc").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormNotificationDAO) CntUnread(ctx context.Context, receiver int64) ([]NotificationCnt, error) {
+	var res []NotificationCnt
+	err := dao.db.WithContext(ctx).Model(&Notification{}).
+		Select("type, COUNT(*) AS cnt").
+		Where("receiver = ? AND status = ?", receiver, NotificationStatusUnread).
+		Group("type").
+		Scan(&res).Error
+	return res, err
+}
+
+func (dao *GormNotificationDAO) MarkRead(ctx context.Context, receiver int64, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return dao.db.WithContext(ctx).Model(&Notification{}).
+		Where("receiver = ? AND id IN ? AND status = ?", receiver, ids, NotificationStatusUnread).
+		Updates(dao.readUpdates()).Error
+}
+
+func (dao *GormNotificationDAO) MarkAllRead(ctx context.Context, receiver int64, typ uint8) error {
+	query := dao.db.WithContext(ctx).Model(&Notification{}).
+		Where("receiver = ? AND status = ?", receiver, NotificationStatusUnread)
+	if typ > 0 {
+		query = query.Where("type = ?", typ)
+	}
+	return query.Updates(dao.readUpdates()).Error
+}
+
+// readUpdates 已读之后不再聚合，再触发时新建一条
+func (dao *GormNotificationDAO) readUpdates() map[string]any {
+	return map[string]any{
+		"status":  NotificationStatusRead,
+		"agg_key": nil,
+	}
+}
@@ -0,0 +1,90 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 阅读记录：同一个用户对同一个资源只有一条，按最近阅读时间倒序列出
+type ReadHistory struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	Uid         int64  `gorm:"uniqueIndex:uid_biz_id;index:uid_last_read,priority:1"`
+	Biz         string `gorm:"type:varchar(128);uniqueIndex:uid_biz_id"`
+	BizId       int64  `gorm:"uniqueIndex:uid_biz_id"`
+	Progress    int
+	FirstReadAt int64
+	LastReadAt  int64 `gorm:"index:uid_last_read,priority:2"`
+	Ctime       int64
+	Utime       int64
+}
+
+type ReadHistoryDAO interface {
+	// Upsert 记录一次阅读：第一次阅读时创建，之后只更新最近阅读时间
+	Upsert(ctx context.Context, h ReadHistory) error
+	// UpdateProgress 更新阅读进度，没有阅读记录时创建
+	UpdateProgress(ctx context.Context, h ReadHistory) error
+	// FindByUid 最近阅读的在前
+	FindByUid(ctx context.Context, uid int64, offset int, limit int) ([]ReadHistory, error)
+	Delete(ctx context.Context, uid int64, biz string, bizIds []int64) error
+	DeleteAll(ctx context.Context, uid int64) error
+}
+
+type GormReadHistoryDAO struct {
+	db *gorm.DB
+}
+
+func NewReadHistoryDAO(db *gorm.DB) ReadHistoryDAO {
+	return &GormReadHistoryDAO{
+		db: db,
+	}
+}
+
+func (dao *GormReadHistoryDAO) Upsert(ctx context.Context, h ReadHistory) error {
+	now := time.Now().UnixMilli()
+	h.Ctime = now
+	h.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			// 事件可能乱序到达
+			"last_read_at": gorm.Expr("GREATEST(`last_read_at`, VALUES(`last_read_at`))"),
+			"utime":        now,
+		}),
+	}).Create(&h).Error
+}
+
+func (dao *GormReadHistoryDAO) UpdateProgress(ctx context.Context, h ReadHistory) error {
+	now := time.Now().UnixMilli()
+	h.FirstReadAt = now
+	h.LastReadAt = now
+	h.Ctime = now
+	h.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"progress":     h.Progress,
+			"last_read_at": now,
+			"utime":        now,
+		}),
+	}).Create(&h).Error
+}
+
+func (dao *GormReadHistoryDAO) FindByUid(ctx context.Context, uid int64, offset int, limit int) ([]ReadHistory, error) {
+	var res []ReadHistory
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("last_read_at desc").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormReadHistoryDAO) Delete(ctx context.Context, uid int64, biz string, bizIds []int64) error {
+	return dao.db.WithContext(ctx).
+		Where("uid = ? AND biz = ? AND biz_id IN ?", uid, biz, bizIds).
+		Delete(&ReadHistory{}).Error
+}
+
+func (dao *GormReadHistoryDAO) DeleteAll(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Where("uid = ?", uid).Delete(&ReadHistory{}).Error
+}
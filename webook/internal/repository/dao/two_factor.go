@@ -0,0 +1,175 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTwoFactorNotFound = gorm.ErrRecordNotFound
+	// ErrTwoFactorConflict 状态已经被别人改过了，比如重复确认、用过的验证码
+	ErrTwoFactorConflict = errors.New("二次验证状态冲突")
+)
+
+// UserTwoFactor 用户的 TOTP 密钥，一个用户一条
+type UserTwoFactor struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	Uid    int64  `gorm:"unique"`
+	Secret string `gorm:"type:varchar(64)"`
+	// 确认第一个验证码之后才开启
+	Enabled bool
+	// 最后一次用过的时间窗口，同一个验证码不能用两次
+	LastCounter int64
+	Ctime       int64
+	Utime       int64
+}
+
+// UserRecoveryCode 恢复码，只保存哈希，用过之后记录使用时间
+type UserRecoveryCode struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Uid      int64  `gorm:"index"`
+	CodeHash string `gorm:"type:varchar(64)"`
+	UsedAt   int64
+	Ctime    int64
+}
+
+type TwoFactorDAO interface {
+	FindByUid(ctx context.Context, uid int64) (UserTwoFactor, error)
+	// UpsertPending 保存一个还没确认的密钥，已经开启时返回 ErrTwoFactorConflict
+	UpsertPending(ctx context.Context, uid int64, secret string) error
+	// Enable 开启二次验证并替换掉所有恢复码
+	Enable(ctx context.Context, uid int64, counter int64, codeHashes []string) error
+	// UseCounter 记录用过的时间窗口，不大于上一次时返回 ErrTwoFactorConflict
+	UseCounter(ctx context.Context, uid int64, counter int64) error
+	// UseRecoveryCode 恢复码不存在或者已经用过时返回 ErrTwoFactorConflict
+	UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error
+	CountRecoveryCodes(ctx context.Context, uid int64) (int64, error)
+	// Delete 删除密钥和恢复码
+	Delete(ctx context.Context, uid int64) error
+}
+
+type GormTwoFactorDAO struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorDAO(db *gorm.DB) TwoFactorDAO {
+	return &GormTwoFactorDAO{
+		db: db,
+	}
+}
+
+func (dao *GormTwoFactorDAO) FindByUid(ctx context.Context, uid int64) (UserTwoFactor, error) {
+	var tf UserTwoFactor
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&tf).Error
+	return tf, err
+}
+
+func (dao *GormTwoFactorDAO) UpsertPending(ctx context.Context, uid int64, secret string) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&UserTwoFactor{
+			Uid:    uid,
+			Secret: secret,
+			Ctime:  now,
+			Utime:  now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		// 没有开启时可以重新生成密钥
+		res := tx.Model(&UserTwoFactor{}).
+			Where("uid = ? AND enabled = ?", uid, false).
+			Updates(map[string]any{
+				"secret":       secret,
+				"last_counter": 0,
+				"utime":        now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrTwoFactorConflict
+		}
+		return nil
+	})
+}
+
+func (dao *GormTwoFactorDAO) Enable(ctx context.Context, uid int64, counter int64, codeHashes []string) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&UserTwoFactor{}).
+			Where("uid = ? AND enabled = ?", uid, false).
+			Updates(map[string]any{
+				"enabled":      true,
+				"last_counter": counter,
+				"utime":        now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrTwoFactorConflict
+		}
+		if err := tx.Where("uid = ?", uid).Delete(&UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]UserRecoveryCode, 0, len(codeHashes))
+		for _, h := range codeHashes {
+			codes = append(codes, UserRecoveryCode{
+				Uid:      uid,
+				CodeHash: h,
+				Ctime:    now,
+			})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (dao *GormTwoFactorDAO) UseCounter(ctx context.Context, uid int64, counter int64) error {
+	res := dao.db.WithContext(ctx).Model(&UserTwoFactor{}).
+		Where("uid = ? AND last_counter < ?", uid, counter).
+		Updates(map[string]any{
+			"last_counter": counter,
+			"utime":        time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTwoFactorConflict
+	}
+	return nil
+}
+
+func (dao *GormTwoFactorDAO) UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error {
+	res := dao.db.WithContext(ctx).Model(&UserRecoveryCode{}).
+		Where("uid = ? AND code_hash = ? AND used_at = ?", uid, codeHash, 0).
+		Update("used_at", time.Now().UnixMilli())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrTwoFactorConflict
+	}
+	return nil
+}
+
+func (dao *GormTwoFactorDAO) CountRecoveryCodes(ctx context.Context, uid int64) (int64, error) {
+	var cnt int64
+	err := dao.db.WithContext(ctx).Model(&UserRecoveryCode{}).
+		Where("uid = ? AND used_at = ?", uid, 0).Count(&cnt).Error
+	return cnt, err
+}
+
+func (dao *GormTwoFactorDAO) Delete(ctx context.Context, uid int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("uid = ?", uid).Delete(&UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("uid = ?", uid).Delete(&UserTwoFactor{}).Error
+	})
+}
@@ -23,6 +23,7 @@ type UserDAO interface {
 	FindByWechat(ctx context.Context, openId string) (User, error)
 	UpdateById(ctx context.Context, user User) error
 	FindByIds(ctx context.Context, ids []int64) ([]User, error)
+	UpdatePassword(ctx context.Context, id int64, password string) error
 }
 
 type GormUserDAO struct {
@@ -113,3 +114,10 @@ func (dao *GormUserDAO) FindByIds(ctx context.Context, ids []int64) ([]User, err
 	err := dao.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error
 	return users, err
 }
+
+func (dao *GormUserDAO) UpdatePassword(ctx context.Context, id int64, password string) error {
+	return dao.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).Updates(map[string]any{
+		"password": password,
+		"utime":    time.Now().UnixMilli(),
+	}).Error
+}
@@ -0,0 +1,228 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook 不存在")
+	ErrWebhookDeliveryNotFound = errors.New("投递记录不存在")
+)
+
+// 注册的 webhook
+type Webhook struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	Uid    int64  `gorm:"index"`
+	Url    string `gorm:"type:varchar(1024)"`
+	Secret string `gorm:"type:varchar(128)"`
+	// 订阅的事件，用逗号分隔
+	Events string `gorm:"type:varchar(512)"`
+	Ctime  int64
+	Utime  int64
+}
+
+// 订阅关系：按事件和 webhook 所有者查找订阅者，和 Webhook.Events 一起写入
+type WebhookSubscription struct {
+	Id        int64  `gorm:"primaryKey,autoIncrement"`
+	WebhookId int64  `gorm:"index"`
+	Event     string `gorm:"type:varchar(64);index:event_uid,priority:1"`
+	Uid       int64  `gorm:"index:event_uid,priority:2"`
+	Ctime     int64
+}
+
+// 投递记录：待发送的投递按 status + next_retry_at 扫描
+type WebhookDelivery struct {
+	Id          int64  `gorm:"primaryKey,autoIncrement"`
+	WebhookId   int64  `gorm:"index"`
+	Event       string `gorm:"type:varchar(64)"`
+	Payload     string `gorm:"type:text"`
+	Status      uint8  `gorm:"index:status_next_retry,priority:1"`
+	Attempts    int
+	NextRetryAt int64 `gorm:"index:status_next_retry,priority:2"`
+	StatusCode  int
+	Error       string `gorm:"type:varchar(1024)"` // 请求失败时的错误信息，不保存响应内容
+	Ctime       int64
+	Utime       int64
+}
+
+type WebhookDAO interface {
+	// Insert 保存 webhook 和它订阅的每个事件，events 为空时不订阅
+	Insert(ctx context.Context, w Webhook, events []string) (int64, error)
+	// Delete 删除 webhook、订阅关系和它的投递记录
+	Delete(ctx context.Context, id int64, uid int64) error
+	FindById(ctx context.Context, id int64) (Webhook, error)
+	FindByUid(ctx context.Context, uid int64) ([]Webhook, error)
+	// FindSubscribers uids 中的用户注册的、订阅了 event 的 webhook
+	FindSubscribers(ctx context.Context, event string, uids []int64) ([]Webhook, error)
+
+	InsertDeliveries(ctx context.Context, ds []WebhookDelivery) ([]int64, error)
+	FindDeliveryById(ctx context.Context, id int64) (WebhookDelivery, error)
+	// FindDeliveries 投递日志，最新的在前
+	FindDeliveries(ctx context.Context, webhookId int64, offset int, limit int) ([]WebhookDelivery, error)
+	// FindDueDeliveries 状态为 status 且 next_retry_at 不晚于 now 的投递
+	FindDueDeliveries(ctx context.Context, status uint8, now int64, limit int) ([]WebhookDelivery, error)
+	// ClaimDelivery 抢占投递：next_retry_at 没有被别人改过时推迟到 leaseUntil，返回是否抢到
+	// 多个实例同时扫描时，只有抢到的实例发送，租约过期前没有更新结果的投递会被重新扫描到
+	ClaimDelivery(ctx context.Context, d WebhookDelivery, leaseUntil int64) (bool, error)
+	// UpdateDeliveryResult 更新发送结果
+	UpdateDeliveryResult(ctx context.Context, d WebhookDelivery) error
+}
+
+type GormWebhookDAO struct {
+	db *gorm.DB
+}
+
+func NewWebhookDAO(db *gorm.DB) WebhookDAO {
+	return &GormWebhookDAO{
+		db: db,
+	}
+}
+
+func (dao *GormWebhookDAO) Insert(ctx context.Context, w Webhook, events []string) (int64, error) {
+	now := time.Now().UnixMilli()
+	w.Ctime = now
+	w.Utime = now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&w).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		subs := make([]WebhookSubscription, 0, len(events))
+		for _, evt := range events {
+			subs = append(subs, WebhookSubscription{
+				WebhookId: w.Id,
+				Event:     evt,
+				Uid:       w.Uid,
+				Ctime:     now,
+			})
+		}
+		return tx.Create(&subs).Error
+	})
+	return w.Id, err
+}
+
+func (dao *GormWebhookDAO) Delete(ctx context.Context, id int64, uid int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND uid = ?", id, uid).Delete(&Webhook{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrWebhookNotFound
+		}
+		if err := tx.Where("webhook_id = ?", id).Delete(&WebhookSubscription{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("webhook_id = ?", id).Delete(&WebhookDelivery{}).Error
+	})
+}
+
+func (dao *GormWebhookDAO) FindById(ctx context.Context, id int64) (Webhook, error) {
+	var w Webhook
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&w).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Webhook{}, ErrWebhookNotFound
+	}
+	return w, err
+}
+
+func (dao *GormWebhookDAO) FindByUid(ctx context.Context, uid int64) ([]Webhook, error) {
+	var res []Webhook
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("id desc").
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormWebhookDAO) FindSubscribers(ctx context.Context, event string, uids []int64) ([]Webhook, error) {
+	if len(uids) == 0 {
+		return []Webhook{}, nil
+	}
+	var ids []int64
+	err := dao.db.WithContext(ctx).Model(&WebhookSubscription{}).
+		Where("event = ? AND uid IN ?", event, uids).
+		Pluck("webhook_id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return []Webhook{}, err
+	}
+	var res []Webhook
+	err = dao.db.WithContext(ctx).Where("id IN ?", ids).Find(&res).Error
+	return res, err
+}
+
+func (dao *GormWebhookDAO) InsertDeliveries(ctx context.Context, ds []WebhookDelivery) ([]int64, error) {
+	if len(ds) == 0 {
+		return []int64{}, nil
+	}
+	now := time.Now().UnixMilli()
+	for i := range ds {
+		ds[i].Ctime = now
+		ds[i].Utime = now
+	}
+	err := dao.db.WithContext(ctx).Create(&ds).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(ds))
+	for _, d := range ds {
+		ids = append(ids, d.Id)
+	}
+	return ids, nil
+}
+
+func (dao *GormWebhookDAO) FindDeliveryById(ctx context.Context, id int64) (WebhookDelivery, error) {
+	var d WebhookDelivery
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&d).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return WebhookDelivery{}, ErrWebhookDeliveryNotFound
+	}
+	return d, err
+}
+
+func (dao *GormWebhookDAO) FindDeliveries(ctx context.Context, webhookId int64, offset int, limit int) ([]WebhookDelivery, error) {
+	var res []WebhookDelivery
+	err := dao.db.WithContext(ctx).Where("webhook_id = ?", webhookId).
+		Order("id desc").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormWebhookDAO) FindDueDeliveries(ctx context.Context, status uint8, now int64, limit int) ([]WebhookDelivery, error) {
+	var res []WebhookDelivery
+	err := dao.db.WithContext(ctx).
+		Where("status = ? AND next_retry_at <= ?", status, now).
+		Order("next_retry_at").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GormWebhookDAO) ClaimDelivery(ctx context.Context, d WebhookDelivery, leaseUntil int64) (bool, error) {
+	res := dao.db.WithContext(ctx).Model(&WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_retry_at = ?", d.Id, d.Status, d.NextRetryAt).
+		Updates(map[string]any{
+			"next_retry_at": leaseUntil,
+			"utime":         time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (dao *GormWebhookDAO) UpdateDeliveryResult(ctx context.Context, d WebhookDelivery) error {
+	return dao.db.WithContext(ctx).Model(&WebhookDelivery{}).
+		Where("id = ?", d.Id).
+		Updates(map[string]any{
+			"status":        d.Status,
+			"attempts":      d.Attempts,
+			"next_retry_at": d.NextRetryAt,
+			"status_code":   d.StatusCode,
+			"error":         d.Error,
+			"utime":         time.Now().UnixMilli(),
+		}).Error
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/dao"
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type FeedRepository interface {
+	// Push 把作者发表的文章写入粉丝们的收件箱
+	Push(ctx context.Context, artId int64, authorId int64, uids []int64, t time.Time) error
+	// FindInbox 收件箱中排在游标之后的 limit 条
+	FindInbox(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error)
+	// AddPullAuthor 把作者标记为拉模式
+	AddPullAuthor(ctx context.Context, authorId int64) error
+	FindPullAuthors(ctx context.Context) ([]int64, error)
+}
+
+type feedRepository struct {
+	dao dao.FeedDAO
+}
+
+func NewFeedRepository(dao dao.FeedDAO) FeedRepository {
+	return &feedRepository{
+		dao: dao,
+	}
+}
+
+func (r *feedRepository) Push(ctx context.Context, artId int64, authorId int64, uids []int64, t time.Time) error {
+	items := slice.Map(uids, func(idx int, uid int64) dao.FeedInbox {
+		return dao.FeedInbox{
+			Uid:       uid,
+			ArticleId: artId,
+			AuthorId:  authorId,
+			Ctime:     t.UnixMilli(),
+		}
+	})
+	return r.dao.InsertInbox(ctx, items)
+}
+
+func (r *feedRepository) FindInbox(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.FeedItem, error) {
+	var ctime int64
+	if !cursor.Time.IsZero() {
+		ctime = cursor.Time.UnixMilli()
+	}
+	items, err := r.dao.FindInbox(ctx, uid, ctime, cursor.ArticleId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(items, func(idx int, src dao.FeedInbox) domain.FeedItem {
+		return domain.FeedItem{
+			ArticleId: src.ArticleId,
+			AuthorId:  src.AuthorId,
+			Time:      time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (r *feedRepository) AddPullAuthor(ctx context.Context, authorId int64) error {
+	return r.dao.InsertPullAuthor(ctx, authorId)
+}
+
+func (r *feedRepository) FindPullAuthors(ctx context.Context) ([]int64, error) {
+	return r.dao.FindPullAuthors(ctx)
+}
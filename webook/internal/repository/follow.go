@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/cache"
+	"Webook/webook/internal/repository/dao"
+	"Webook/webook/pkg/logger"
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type FollowRepository interface {
+	// Follow 返回是否新增了关注，已经关注过时返回 false
+	Follow(ctx context.Context, follower int64, followee int64) (bool, error)
+	Unfollow(ctx context.Context, follower int64, followee int64) error
+	FindFollowers(ctx context.Context, followee int64, offset int, limit int) ([]domain.FollowRelation, error)
+	FindFollowees(ctx context.Context, follower int64, offset int, limit int) ([]domain.FollowRelation, error)
+	// FollowedMap followees 中每个用户是否被 follower 关注
+	FollowedMap(ctx context.Context, follower int64, followees []int64) (map[int64]bool, error)
+	GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type CachedFollowRepository struct {
+	dao    dao.FollowDAO
+	cache  cache.FollowCache
+	logger logger.Logger
+}
+
+func NewFollowRepository(dao dao.FollowDAO, cache cache.FollowCache, l logger.Logger) FollowRepository {
+	return &CachedFollowRepository{
+		dao:    dao,
+		cache:  cache,
+		logger: l,
+	}
+}
+
+func (r *CachedFollowRepository) Follow(ctx context.Context, follower int64, followee int64) (bool, error) {
+	changed, err := r.dao.Follow(ctx, follower, followee)
+	if err != nil || !changed {
+		return false, err
+	}
+	return true, r.cache.Follow(ctx, follower, followee, 1)
+}
+
+func (r *CachedFollowRepository) Unfollow(ctx context.Context, follower int64, followee int64) error {
+	changed, err := r.dao.Unfollow(ctx, follower, followee)
+	if err != nil || !changed {
+		return err
+	}
+	return r.cache.Follow(ctx, follower, followee, -1)
+}
+
+func (r *CachedFollowRepository) FindFollowers(ctx context.Context, followee int64, offset int, limit int) ([]domain.FollowRelation, error) {
+	rs, err := r.dao.FindFollowers(ctx, followee, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(rs, func(idx int, src dao.FollowRelation) domain.FollowRelation {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *CachedFollowRepository) FindFollowees(ctx context.Context, follower int64, offset int, limit int) ([]domain.FollowRelation, error) {
+	rs, err := r.dao.FindFollowees(ctx, follower, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(rs, func(idx int, src dao.FollowRelation) domain.FollowRelation {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *CachedFollowRepository) FollowedMap(ctx context.Context, follower int64, followees []int64) (map[int64]bool, error) {
+	res := make(map[int64]bool, len(followees))
+	if len(followees) == 0 {
+		return res, nil
+	}
+	followed, err := r.dao.FindFollowed(ctx, follower, followees)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range followed {
+		res[id] = true
+	}
+	return res, nil
+}
+
+func (r *CachedFollowRepository) GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	res, err := r.cache.GetStatics(ctx, uid)
+	if err == nil {
+		return res, nil
+	}
+
+	// 缓存未命中，从数据库中统计
+	res.Followers, err = r.dao.CntFollowers(ctx, uid)
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	res.Followees, err = r.dao.CntFollowees(ctx, uid)
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+
+	if err = r.cache.SetStatics(ctx, uid, res); err != nil {
+		r.logger.Error("缓存关注数失败",
+			logger.Int64("uid", uid),
+			logger.Error(err),
+		)
+	}
+	return res, nil
+}
+
+func (r *CachedFollowRepository) toDomain(f dao.FollowRelation) domain.FollowRelation {
+	return domain.FollowRelation{
+		Follower: f.Follower,
+		Followee: f.Followee,
+		Ctime:    time.UnixMilli(f.Utime),
+	}
+}
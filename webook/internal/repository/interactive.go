@@ -4,27 +4,40 @@ import (
 	"Webook/webook/internal/domain"
 	"Webook/webook/internal/repository/cache"
 	"Webook/webook/internal/repository/dao"
+	"Webook/webook/pkg/logger"
 	"context"
+	"errors"
+	"fmt"
 )
 
+// ErrInteractiveCacheNotUpdated 数据库已经更新，只是缓存没有更新，调用方不能重试，否则会重复计数
+var ErrInteractiveCacheNotUpdated = errors.New("互动数据的缓存更新失败")
+
 type InteractiveRepository interface {
 	IncreaseReadCnt(ctx context.Context, biz string, bizId int64) error
+	// BatchIncreaseReadCnt 批量增加阅读计数，bizs、bizIds、cnts 一一对应
+	BatchIncreaseReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error
 	IncreaseLikeCnt(ctx context.Context, biz string, bizId int64, userId int64) error
 	DecreaseLikeCnt(ctx context.Context, biz string, bizId int64, userId int64) error
-	InsertCollection(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) error
+	// InsertCollection 返回是否新增了收藏，已经收藏过的只是移动收藏夹，返回 false
+	InsertCollection(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) (bool, error)
+	// DeleteCollection 返回是否真的取消了收藏，没有收藏过时返回 false
+	DeleteCollection(ctx context.Context, biz string, bizId int64, userId int64) (bool, error)
 	GetInteractive(ctx context.Context, biz string, bizId int64, userId int64) (domain.Interactive, error)
 	GetInterMapByBizIds(ctx context.Context, biz string, BizIds []int64, userId int64) (map[int64]domain.Interactive, error)
 }
 
 type interactiveRepository struct {
-	dao   dao.InteractiveDAO
-	cache cache.InteractiveCache
+	dao    dao.InteractiveDAO
+	cache  cache.InteractiveCache
+	logger logger.Logger
 }
 
-func NewInteractiveRepository(dao dao.InteractiveDAO, cache cache.InteractiveCache) InteractiveRepository {
+func NewInteractiveRepository(dao dao.InteractiveDAO, cache cache.InteractiveCache, l logger.Logger) InteractiveRepository {
 	return &interactiveRepository{
-		dao:   dao,
-		cache: cache,
+		dao:    dao,
+		cache:  cache,
+		logger: l,
 	}
 }
 
@@ -43,6 +56,17 @@ func (r *interactiveRepository) IncreaseReadCnt(ctx context.Context, biz string,
 	return r.cache.IncreaseReadCntIfPresent(ctx, biz, bizId)
 }
 
+func (r *interactiveRepository) BatchIncreaseReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error {
+	if err := r.dao.BatchIncreaseReadCnt(ctx, bizs, bizIds, cnts); err != nil {
+		return err
+	}
+	// 和 IncreaseReadCnt 一样，缓存更新失败时容忍不一致
+	if err := r.cache.BatchIncreaseReadCntIfPresent(ctx, bizs, bizIds, cnts); err != nil {
+		return fmt.Errorf("%w: %w", ErrInteractiveCacheNotUpdated, err)
+	}
+	return nil
+}
+
 func (r *interactiveRepository) IncreaseLikeCnt(ctx context.Context, biz string, bizId int64, userId int64) error {
 	err := r.dao.InsertLikeInfo(ctx, biz, bizId, userId)
 	if err != nil {
@@ -50,7 +74,10 @@ func (r *interactiveRepository) IncreaseLikeCnt(ctx context.Context, biz string,
 	}
 
 	// 缓存中增加点赞信息
-	return r.cache.IncreaseLikeCntIfPresent(ctx, biz, bizId)
+	if err = r.cache.IncreaseLikeCntIfPresent(ctx, biz, bizId); err != nil {
+		return err
+	}
+	return r.cache.DelUserState(ctx, biz, userId, bizId)
 }
 
 func (r *interactiveRepository) DecreaseLikeCnt(ctx context.Context, biz string, bizId int64, userId int64) error {
@@ -60,76 +87,185 @@ func (r *interactiveRepository) DecreaseLikeCnt(ctx context.Context, biz string,
 	}
 
 	// 缓存中减少点赞信息
-	return r.cache.DecreaseLikeCntIfPresent(ctx, biz, bizId)
+	if err = r.cache.DecreaseLikeCntIfPresent(ctx, biz, bizId); err != nil {
+		return err
+	}
+	return r.cache.DelUserState(ctx, biz, userId, bizId)
 }
 
-func (r *interactiveRepository) InsertCollection(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) error {
-	err := r.dao.InsertCollection(ctx, biz, bizId, collectionId, userId)
-	if err != nil {
-		return err
+func (r *interactiveRepository) InsertCollection(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) (bool, error) {
+	created, err := r.dao.InsertCollection(ctx, biz, bizId, collectionId, userId)
+	if err != nil || !created {
+		return false, err
 	}
 
-	return r.cache.IncreaseCollectCntIfPresent(ctx, biz, bizId)
+	if err = r.cache.IncreaseCollectCntIfPresent(ctx, biz, bizId); err != nil {
+		return true, err
+	}
+	return true, r.cache.DelUserState(ctx, biz, userId, bizId)
+}
+
+func (r *interactiveRepository) DeleteCollection(ctx context.Context, biz string, bizId int64, userId int64) (bool, error) {
+	deleted, err := r.dao.DeleteCollection(ctx, biz, bizId, userId)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	if err = r.cache.DecreaseCollectCntIfPresent(ctx, biz, bizId); err != nil {
+		return true, err
+	}
+	return true, r.cache.DelUserState(ctx, biz, userId, bizId)
 }
 
 func (r *interactiveRepository) GetInteractive(ctx context.Context, biz string, bizId int64, userId int64) (domain.Interactive, error) {
-	interactive, err := r.dao.GetInteractive(ctx, biz, bizId)
+	res, err := r.cache.Get(ctx, biz, bizId)
 	if err != nil {
-		return domain.Interactive{}, err
+		// 缓存未命中或者 redis 出错，都从数据库加载
+		inter, err := r.dao.GetInteractive(ctx, biz, bizId)
+		if err != nil {
+			return domain.Interactive{}, err
+		}
+		res = r.toDomain(inter)
+		res.Biz, res.BizId = biz, bizId
+		if err = r.cache.Set(ctx, biz, bizId, res); err != nil {
+			r.logger.Error("缓存互动数据失败",
+				logger.String("biz", biz),
+				logger.Int64("bizId", bizId),
+				logger.Error(err),
+			)
+		}
 	}
-	liked, err := r.dao.GetLiked(ctx, biz, bizId, userId)
+
+	states, err := r.getUserStates(ctx, biz, []int64{bizId}, userId)
 	if err != nil {
 		return domain.Interactive{}, err
 	}
-	collected, err := r.dao.GetCollected(ctx, biz, bizId, userId)
-
-	return domain.Interactive{
-		ReadCnt:    interactive.ReadCnt,
-		LikeCnt:    interactive.LikeCnt,
-		CollectCnt: interactive.CollectCnt,
-		Liked:      liked,
-		Collected:  collected,
-	}, nil
+	res.Liked = states[bizId].Liked
+	res.Collected = states[bizId].Collected
+	return res, nil
 }
 
 func (r *interactiveRepository) GetInterMapByBizIds(ctx context.Context, biz string, BizIds []int64, userId int64) (map[int64]domain.Interactive, error) {
-	inters, err := r.dao.GetByBizIds(ctx, biz, BizIds)
+	res, err := r.cache.MGet(ctx, biz, BizIds)
+	if err != nil {
+		r.logger.Error("批量读取互动数据缓存失败",
+			logger.String("biz", biz),
+			logger.Error(err),
+		)
+		res = make(map[int64]domain.Interactive, len(BizIds))
+	}
+
+	var missed []int64
+	for _, id := range BizIds {
+		if _, ok := res[id]; !ok {
+			missed = append(missed, id)
+		}
+	}
+	if len(missed) > 0 {
+		inters, err := r.dao.GetByBizIds(ctx, biz, missed)
+		if err != nil {
+			return nil, err
+		}
+		loaded := make([]domain.Interactive, 0, len(inters))
+		for _, inter := range inters {
+			res[inter.BizId] = r.toDomain(inter)
+			loaded = append(loaded, res[inter.BizId])
+		}
+		if err = r.cache.MSet(ctx, biz, loaded); err != nil {
+			r.logger.Error("批量缓存互动数据失败",
+				logger.String("biz", biz),
+				logger.Error(err),
+			)
+		}
+	}
+
+	// 榜单计算等内部调用没有用户
+	if userId <= 0 {
+		return res, nil
+	}
+	states, err := r.getUserStates(ctx, biz, BizIds, userId)
 	if err != nil {
 		return nil, err
 	}
-	likes, err := r.dao.GetLikedByBizIds(ctx, biz, BizIds, userId)
+	for id, inter := range res {
+		inter.Liked = states[id].Liked
+		inter.Collected = states[id].Collected
+		res[id] = inter
+	}
+	return res, nil
+}
+
+// getUserStates 先读缓存，缓存中没有的从数据库加载后回写
+func (r *interactiveRepository) getUserStates(ctx context.Context, biz string, bizIds []int64, userId int64) (map[int64]cache.InteractiveUserState, error) {
+	res, err := r.cache.GetUserStates(ctx, biz, userId, bizIds)
+	if err != nil {
+		r.logger.Error("读取用户点赞收藏状态缓存失败",
+			logger.String("biz", biz),
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		res = make(map[int64]cache.InteractiveUserState, len(bizIds))
+	}
+
+	var missed []int64
+	for _, id := range bizIds {
+		if _, ok := res[id]; !ok {
+			missed = append(missed, id)
+		}
+	}
+	if len(missed) == 0 {
+		return res, nil
+	}
+
+	likes, err := r.dao.GetLikedByBizIds(ctx, biz, missed, userId)
 	if err != nil {
 		return nil, err
 	}
-	collects, err := r.dao.GetCollectedByBizIds(ctx, biz, BizIds, userId)
+	collects, err := r.dao.GetCollectedByBizIds(ctx, biz, missed, userId)
 	if err != nil {
 		return nil, err
 	}
-	res := make(map[int64]domain.Interactive, len(inters))
-	for _, inter := range inters {
-		res[inter.BizId] = domain.Interactive{
-			ReadCnt:    inter.ReadCnt,
-			LikeCnt:    inter.LikeCnt,
-			CollectCnt: inter.CollectCnt,
-		}
+	// 没有点赞、收藏的也要缓存，否则每次都会查数据库
+	loaded := make(map[int64]cache.InteractiveUserState, len(missed))
+	for _, id := range missed {
+		loaded[id] = cache.InteractiveUserState{}
 	}
 	const likeValid = 1
 	const unCollected = 0
 	for _, like := range likes {
 		if like.Status == likeValid {
-			if inter, ok := res[like.BizId]; ok {
-				inter.Liked = true
-				res[like.BizId] = inter
-			}
+			state := loaded[like.BizId]
+			state.Liked = true
+			loaded[like.BizId] = state
 		}
 	}
 	for _, collect := range collects {
 		if collect.Cid != unCollected {
-			if inter, ok := res[collect.BizId]; ok {
-				inter.Collected = true
-				res[collect.BizId] = inter
-			}
+			state := loaded[collect.BizId]
+			state.Collected = true
+			loaded[collect.BizId] = state
 		}
 	}
+	if err = r.cache.SetUserStates(ctx, biz, userId, loaded); err != nil {
+		r.logger.Error("缓存用户点赞收藏状态失败",
+			logger.String("biz", biz),
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+	}
+	for id, state := range loaded {
+		res[id] = state
+	}
 	return res, nil
 }
+
+func (r *interactiveRepository) toDomain(inter dao.Interactive) domain.Interactive {
+	return domain.Interactive{
+		Biz:        inter.Biz,
+		BizId:      inter.BizId,
+		ReadCnt:    inter.ReadCnt,
+		LikeCnt:    inter.LikeCnt,
+		CollectCnt: inter.CollectCnt,
+		CommentCnt: inter.CommentCnt,
+	}
+}
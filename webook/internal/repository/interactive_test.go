@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/cache"
+	cachemocks "Webook/webook/internal/repository/cache/mocks"
+	"Webook/webook/internal/repository/dao"
+	daomocks "Webook/webook/internal/repository/dao/mocks"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestInteractiveRepository_GetInteractive(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache)
+
+		wantInter domain.Interactive
+		wantErr   error
+	}{
+		{
+			name: "全部命中缓存",
+			mock: func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache) {
+				d := daomocks.NewMockInteractiveDAO(ctrl)
+				c := cachemocks.NewMockInteractiveCache(ctrl)
+				c.EXPECT().Get(gomock.Any(), "article", int64(1)).
+					Return(domain.Interactive{Biz: "article", BizId: 1, ReadCnt: 10, LikeCnt: 2}, nil)
+				c.EXPECT().GetUserStates(gomock.Any(), "article", int64(123), []int64{1}).
+					Return(map[int64]cache.InteractiveUserState{1: {Liked: true}}, nil)
+				return d, c
+			},
+			wantInter: domain.Interactive{Biz: "article", BizId: 1, ReadCnt: 10, LikeCnt: 2, Liked: true},
+		},
+		{
+			name: "缓存未命中，从数据库加载并回写",
+			mock: func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache) {
+				d := daomocks.NewMockInteractiveDAO(ctrl)
+				c := cachemocks.NewMockInteractiveCache(ctrl)
+				c.EXPECT().Get(gomock.Any(), "article", int64(1)).
+					Return(domain.Interactive{}, cache.ErrKeyNotFound)
+				d.EXPECT().GetInteractive(gomock.Any(), "article", int64(1)).
+					Return(dao.Interactive{Biz: "article", BizId: 1, ReadCnt: 10, CollectCnt: 3}, nil)
+				c.EXPECT().Set(gomock.Any(), "article", int64(1),
+					domain.Interactive{Biz: "article", BizId: 1, ReadCnt: 10, CollectCnt: 3}).Return(nil)
+
+				c.EXPECT().GetUserStates(gomock.Any(), "article", int64(123), []int64{1}).
+					Return(map[int64]cache.InteractiveUserState{}, nil)
+				d.EXPECT().GetLikedByBizIds(gomock.Any(), "article", []int64{1}, int64(123)).
+					Return(nil, nil)
+				d.EXPECT().GetCollectedByBizIds(gomock.Any(), "article", []int64{1}, int64(123)).
+					Return([]dao.UserCollectBiz{{BizId: 1, Cid: 5}}, nil)
+				c.EXPECT().SetUserStates(gomock.Any(), "article", int64(123),
+					map[int64]cache.InteractiveUserState{1: {Collected: true}}).Return(nil)
+				return d, c
+			},
+			wantInter: domain.Interactive{Biz: "article", BizId: 1, ReadCnt: 10, CollectCnt: 3, Collected: true},
+		},
+		{
+			name: "回写缓存失败不影响返回",
+			mock: func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache) {
+				d := daomocks.NewMockInteractiveDAO(ctrl)
+				c := cachemocks.NewMockInteractiveCache(ctrl)
+				c.EXPECT().Get(gomock.Any(), "article", int64(1)).
+					Return(domain.Interactive{}, errors.New("redis error"))
+				d.EXPECT().GetInteractive(gomock.Any(), "article", int64(1)).
+					Return(dao.Interactive{Biz: "article", BizId: 1, LikeCnt: 1}, nil)
+				c.EXPECT().Set(gomock.Any(), "article", int64(1), gomock.Any()).
+					Return(errors.New("redis error"))
+
+				c.EXPECT().GetUserStates(gomock.Any(), "article", int64(123), []int64{1}).
+					Return(nil, errors.New("redis error"))
+				d.EXPECT().GetLikedByBizIds(gomock.Any(), "article", []int64{1}, int64(123)).
+					Return([]dao.UserLikeBiz{{BizId: 1, Status: 1}}, nil)
+				d.EXPECT().GetCollectedByBizIds(gomock.Any(), "article", []int64{1}, int64(123)).
+					Return(nil, nil)
+				c.EXPECT().SetUserStates(gomock.Any(), "article", int64(123), gomock.Any()).
+					Return(errors.New("redis error"))
+				return d, c
+			},
+			wantInter: domain.Interactive{Biz: "article", BizId: 1, LikeCnt: 1, Liked: true},
+		},
+		{
+			name: "数据库查询失败",
+			mock: func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache) {
+				d := daomocks.NewMockInteractiveDAO(ctrl)
+				c := cachemocks.NewMockInteractiveCache(ctrl)
+				c.EXPECT().Get(gomock.Any(), "article", int64(1)).
+					Return(domain.Interactive{}, cache.ErrKeyNotFound)
+				d.EXPECT().GetInteractive(gomock.Any(), "article", int64(1)).
+					Return(dao.Interactive{}, errors.New("db error"))
+				return d, c
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			d, c := tc.mock(ctrl)
+			repo := NewInteractiveRepository(d, c, logger.NewZapLogger(zap.NewNop()))
+			inter, err := repo.GetInteractive(context.Background(), "article", 1, 123)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantInter, inter)
+		})
+	}
+}
+
+func TestInteractiveRepository_GetInterMapByBizIds(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache)
+
+		wantRes map[int64]domain.Interactive
+		wantErr error
+	}{
+		{
+			name: "部分命中缓存，其余从数据库加载",
+			mock: func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache) {
+				d := daomocks.NewMockInteractiveDAO(ctrl)
+				c := cachemocks.NewMockInteractiveCache(ctrl)
+				c.EXPECT().MGet(gomock.Any(), "article", []int64{1, 2, 3}).
+					Return(map[int64]domain.Interactive{
+						1: {Biz: "article", BizId: 1, ReadCnt: 1},
+					}, nil)
+				// 3 在数据库中也没有
+				d.EXPECT().GetByBizIds(gomock.Any(), "article", []int64{2, 3}).
+					Return([]dao.Interactive{{Biz: "article", BizId: 2, ReadCnt: 2}}, nil)
+				c.EXPECT().MSet(gomock.Any(), "article",
+					[]domain.Interactive{{Biz: "article", BizId: 2, ReadCnt: 2}}).Return(nil)
+
+				c.EXPECT().GetUserStates(gomock.Any(), "article", int64(123), []int64{1, 2, 3}).
+					Return(map[int64]cache.InteractiveUserState{
+						1: {Liked: true},
+						2: {},
+					}, nil)
+				d.EXPECT().GetLikedByBizIds(gomock.Any(), "article", []int64{3}, int64(123)).
+					Return(nil, nil)
+				d.EXPECT().GetCollectedByBizIds(gomock.Any(), "article", []int64{3}, int64(123)).
+					Return(nil, nil)
+				c.EXPECT().SetUserStates(gomock.Any(), "article", int64(123),
+					map[int64]cache.InteractiveUserState{3: {}}).Return(nil)
+				return d, c
+			},
+			wantRes: map[int64]domain.Interactive{
+				1: {Biz: "article", BizId: 1, ReadCnt: 1, Liked: true},
+				2: {Biz: "article", BizId: 2, ReadCnt: 2},
+			},
+		},
+		{
+			name: "全部命中缓存",
+			mock: func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache) {
+				d := daomocks.NewMockInteractiveDAO(ctrl)
+				c := cachemocks.NewMockInteractiveCache(ctrl)
+				c.EXPECT().MGet(gomock.Any(), "article", []int64{1, 2, 3}).
+					Return(map[int64]domain.Interactive{
+						1: {Biz: "article", BizId: 1},
+						2: {Biz: "article", BizId: 2},
+						3: {Biz: "article", BizId: 3},
+					}, nil)
+				c.EXPECT().GetUserStates(gomock.Any(), "article", int64(123), []int64{1, 2, 3}).
+					Return(map[int64]cache.InteractiveUserState{
+						1: {},
+						2: {Collected: true},
+						3: {Liked: true, Collected: true},
+					}, nil)
+				return d, c
+			},
+			wantRes: map[int64]domain.Interactive{
+				1: {Biz: "article", BizId: 1},
+				2: {Biz: "article", BizId: 2, Collected: true},
+				3: {Biz: "article", BizId: 3, Liked: true, Collected: true},
+			},
+		},
+		{
+			name: "数据库查询失败",
+			mock: func(ctrl *gomock.Controller) (dao.InteractiveDAO, cache.InteractiveCache) {
+				d := daomocks.NewMockInteractiveDAO(ctrl)
+				c := cachemocks.NewMockInteractiveCache(ctrl)
+				c.EXPECT().MGet(gomock.Any(), "article", []int64{1, 2, 3}).
+					Return(nil, errors.New("redis error"))
+				d.EXPECT().GetByBizIds(gomock.Any(), "article", []int64{1, 2, 3}).
+					Return(nil, errors.New("db error"))
+				return d, c
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			d, c := tc.mock(ctrl)
+			repo := NewInteractiveRepository(d, c, logger.NewZapLogger(zap.NewNop()))
+			res, err := repo.GetInterMapByBizIds(context.Background(), "article", []int64{1, 2, 3}, 123)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestInteractiveRepository_IncreaseLikeCnt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	d := daomocks.NewMockInteractiveDAO(ctrl)
+	c := cachemocks.NewMockInteractiveCache(ctrl)
+	gomock.InOrder(
+		d.EXPECT().InsertLikeInfo(gomock.Any(), "article", int64(1), int64(123)).Return(nil),
+		c.EXPECT().IncreaseLikeCntIfPresent(gomock.Any(), "article", int64(1)).Return(nil),
+		// 点赞状态变了，删掉缓存
+		c.EXPECT().DelUserState(gomock.Any(), "article", int64(123), int64(1)).Return(nil),
+	)
+	repo := NewInteractiveRepository(d, c, logger.NewZapLogger(zap.NewNop()))
+	assert.NoError(t, repo.IncreaseLikeCnt(context.Background(), "article", 1, 123))
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./webook/internal/repository/password_reset.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./webook/internal/repository/password_reset.go -package=repomocks -destination=./webook/internal/repository/mocks/password_reset.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	domain "Webook/webook/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPasswordResetRepository is a mock of PasswordResetRepository interface.
+type MockPasswordResetRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPasswordResetRepositoryMockRecorder is the mock recorder for MockPasswordResetRepository.
+type MockPasswordResetRepositoryMockRecorder struct {
+	mock *MockPasswordResetRepository
+}
+
+// NewMockPasswordResetRepository creates a new mock instance.
+func NewMockPasswordResetRepository(ctrl *gomock.Controller) *MockPasswordResetRepository {
+	mock := &MockPasswordResetRepository{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetRepository) EXPECT() *MockPasswordResetRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Limit mocks base method.
+func (m *MockPasswordResetRepository) Limit(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limit", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Limit indicates an expected call of Limit.
+func (mr *MockPasswordResetRepositoryMockRecorder) Limit(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockPasswordResetRepository)(nil).Limit), ctx, email)
+}
+
+// Store mocks base method.
+func (m *MockPasswordResetRepository) Store(ctx context.Context, tokenHash string, reset domain.PasswordReset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Store", ctx, tokenHash, reset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Store indicates an expected call of Store.
+func (mr *MockPasswordResetRepositoryMockRecorder) Store(ctx, tokenHash, reset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockPasswordResetRepository)(nil).Store), ctx, tokenHash, reset)
+}
+
+// Take mocks base method.
+func (m *MockPasswordResetRepository) Take(ctx context.Context, tokenHash string) (domain.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, tokenHash)
+	ret0, _ := ret[0].(domain.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockPasswordResetRepositoryMockRecorder) Take(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockPasswordResetRepository)(nil).Take), ctx, tokenHash)
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/dao"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n domain.Notification) (int64, error)
+	// Aggregate 聚合到接收者未读的同类通知上，没有则新建，actor 重复时不计数
+	Aggregate(ctx context.Context, n domain.Notification, actor int64) error
+	FindByReceiver(ctx context.Context, receiver int64, typ domain.NotificationType, offset int, limit int) ([]domain.Notification, error)
+	CntUnread(ctx context.Context, receiver int64) (map[domain.NotificationType]int64, error)
+	MarkRead(ctx context.Context, receiver int64, ids []int64) error
+	MarkAllRead(ctx context.Context, receiver int64, typ domain.NotificationType) error
+}
+
+type notificationRepository struct {
+	dao dao.NotificationDAO
+}
+
+func NewNotificationRepository(dao dao.NotificationDAO) NotificationRepository {
+	return &notificationRepository{
+		dao: dao,
+	}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, n domain.Notification) (int64, error) {
+	return r.dao.Insert(ctx, r.toEntity(n))
+}
+
+func (r *notificationRepository) Aggregate(ctx context.Context, n domain.Notification, actor int64) error {
+	return r.dao.Aggregate(ctx, r.toEntity(n), actor)
+}
+
+func (r *notificationRepository) FindByReceiver(ctx context.Context, receiver int64, typ domain.NotificationType,
+	offset int, limit int) ([]domain.Notification, error) {
+	ns, err := r.dao.FindByReceiver(ctx, receiver, typ.ToUint8(), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ns, func(idx int, src dao.Notification) domain.Notification {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *notificationRepository) CntUnread(ctx context.Context, receiver int64) (map[domain.NotificationType]int64, error) {
+	cnts, err := r.dao.CntUnread(ctx, receiver)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[domain.NotificationType]int64, len(cnts))
+	for _, c := range cnts {
+		res[domain.NotificationType(c.Type)] = c.Cnt
+	}
+	return res, nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, receiver int64, ids []int64) error {
+	return r.dao.MarkRead(ctx, receiver, ids)
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, receiver int64, typ domain.NotificationType) error {
+	return r.dao.MarkAllRead(ctx, receiver, typ.ToUint8())
+}
+
+func (r *notificationRepository) toEntity(n domain.Notification) dao.Notification {
+	actors, _ := json.Marshal(n.Actors)
+	return dao.Notification{
+		Id:       n.Id,
+		Receiver: n.Receiver,
+		Type:     n.Type.ToUint8(),
+		Biz:      n.Biz,
+		BizId:    n.BizId,
+		BizTitle: n.BizTitle,
+		Actors:   string(actors),
+		ActorCnt: n.ActorCnt,
+		Content:  n.Content,
+	}
+}
+
+func (r *notificationRepository) toDomain(n dao.Notification) domain.Notification {
+	var actors []int64
+	// 解析失败只是少了触发者，不影响通知本身
+	_ = json.Unmarshal([]byte(n.Actors), &actors)
+	return domain.Notification{
+		Id:       n.Id,
+		Receiver: n.Receiver,
+		Type:     domain.NotificationType(n.Type),
+		Biz:      n.Biz,
+		BizId:    n.BizId,
+		BizTitle: n.BizTitle,
+		Actors:   actors,
+		ActorCnt: n.ActorCnt,
+		Content:  n.Content,
+		Read:     n.Status == dao.NotificationStatusRead,
+		Ctime:    time.UnixMilli(n.Ctime),
+		Utime:    time.UnixMilli(n.Utime),
+	}
+}
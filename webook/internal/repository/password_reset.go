@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/cache"
+	"context"
+)
+
+var (
+	ErrPasswordResetTooFrequent = cache.ErrPasswordResetTooFrequent
+	ErrPasswordResetNotFound    = cache.ErrPasswordResetNotFound
+)
+
+type PasswordResetRepository interface {
+	// Limit 同一个邮箱发送太频繁时返回 ErrPasswordResetTooFrequent
+	Limit(ctx context.Context, email string) error
+	Store(ctx context.Context, tokenHash string, reset domain.PasswordReset) error
+	Take(ctx context.Context, tokenHash string) (domain.PasswordReset, error)
+}
+
+type CachedPasswordResetRepository struct {
+	cache cache.PasswordResetCache
+}
+
+func NewPasswordResetRepository(c cache.PasswordResetCache) PasswordResetRepository {
+	return &CachedPasswordResetRepository{
+		cache: c,
+	}
+}
+
+func (repo *CachedPasswordResetRepository) Limit(ctx context.Context, email string) error {
+	return repo.cache.Limit(ctx, email)
+}
+
+func (repo *CachedPasswordResetRepository) Store(ctx context.Context, tokenHash string, reset domain.PasswordReset) error {
+	return repo.cache.Set(ctx, tokenHash, reset)
+}
+
+func (repo *CachedPasswordResetRepository) Take(ctx context.Context, tokenHash string) (domain.PasswordReset, error) {
+	return repo.cache.Take(ctx, tokenHash)
+}
@@ -4,26 +4,65 @@ import (
 	"Webook/webook/internal/domain"
 	cache "Webook/webook/internal/repository/cache/rank"
 	"context"
+	"time"
 )
 
+// ErrRankingScoresNotBuilt 增量榜单还没有全量重建过
+var ErrRankingScoresNotBuilt = cache.ErrScoresNotBuilt
+
 type RankingRepository interface {
-	GetTop100(ctx context.Context) ([]domain.Article, error)
-	ReplaceTop100(ctx context.Context, articles []domain.Article) error
+	GetTopN(ctx context.Context, board string) ([]domain.Article, error)
+	ReplaceTopN(ctx context.Context, board string, articles []domain.Article) error
+
+	// 增量模式：分数保存在有序集合中，随互动实时更新
+	IncrScores(ctx context.Context, board string, deltas map[int64]float64) error
+	RemoveArticle(ctx context.Context, boards []string, artId int64) error
+	// RemoveArticles 从一个榜单中移除多篇文章
+	RemoveArticles(ctx context.Context, board string, artIds []int64) error
+	GetTopIds(ctx context.Context, board string, n int) ([]int64, error)
+	DecayScores(ctx context.Context, board string, halfLife time.Duration, keep int) error
+	ReplaceScores(ctx context.Context, board string, scores map[int64]float64) error
 }
 
 type CachedRankingRepository struct {
-	cache cache.RankingCache
+	cache      cache.RankingCache
+	scoreCache cache.RankingScoreCache
 }
 
-func NewRankingRepository(cache cache.RankingCache) RankingRepository {
+func NewRankingRepository(cache cache.RankingCache, scoreCache cache.RankingScoreCache) RankingRepository {
 	return &CachedRankingRepository{
-		cache: cache,
+		cache:      cache,
+		scoreCache: scoreCache,
 	}
 }
-func (repo *CachedRankingRepository) GetTop100(ctx context.Context) ([]domain.Article, error) {
-	return repo.cache.GetTop100(ctx)
+func (repo *CachedRankingRepository) GetTopN(ctx context.Context, board string) ([]domain.Article, error) {
+	return repo.cache.GetTopN(ctx, board)
+}
+
+func (repo *CachedRankingRepository) ReplaceTopN(ctx context.Context, board string, articles []domain.Article) error {
+	return repo.cache.SetTopN(ctx, board, articles)
+}
+
+func (repo *CachedRankingRepository) IncrScores(ctx context.Context, board string, deltas map[int64]float64) error {
+	return repo.scoreCache.IncrBy(ctx, board, deltas)
+}
+
+func (repo *CachedRankingRepository) RemoveArticle(ctx context.Context, boards []string, artId int64) error {
+	return repo.scoreCache.Remove(ctx, boards, artId)
+}
+
+func (repo *CachedRankingRepository) RemoveArticles(ctx context.Context, board string, artIds []int64) error {
+	return repo.scoreCache.Remove(ctx, []string{board}, artIds...)
+}
+
+func (repo *CachedRankingRepository) GetTopIds(ctx context.Context, board string, n int) ([]int64, error) {
+	return repo.scoreCache.TopIds(ctx, board, n)
+}
+
+func (repo *CachedRankingRepository) DecayScores(ctx context.Context, board string, halfLife time.Duration, keep int) error {
+	return repo.scoreCache.Decay(ctx, board, time.Now(), halfLife, keep)
 }
 
-func (repo *CachedRankingRepository) ReplaceTop100(ctx context.Context, articles []domain.Article) error {
-	return repo.cache.SetTop100(ctx, articles)
+func (repo *CachedRankingRepository) ReplaceScores(ctx context.Context, board string, scores map[int64]float64) error {
+	return repo.scoreCache.Replace(ctx, board, scores, time.Now())
 }
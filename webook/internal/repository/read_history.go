@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/cache"
+	"Webook/webook/internal/repository/dao"
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type ReadHistoryRepository interface {
+	// Record 记录一次阅读
+	Record(ctx context.Context, evt domain.ReadEvent) error
+	UpdateProgress(ctx context.Context, uid int64, biz string, bizId int64, progress int) error
+	FindByUid(ctx context.Context, uid int64, offset int, limit int) ([]domain.ReadHistory, error)
+	Delete(ctx context.Context, uid int64, biz string, bizIds []int64) error
+	DeleteAll(ctx context.Context, uid int64) error
+	// FirstReadInWindow 窗口内是否是同一个用户或 IP 第一次阅读
+	FirstReadInWindow(ctx context.Context, evt domain.ReadEvent, window time.Duration) (bool, error)
+}
+
+type readHistoryRepository struct {
+	dao        dao.ReadHistoryDAO
+	dedupCache cache.ReadDedupCache
+}
+
+func NewReadHistoryRepository(dao dao.ReadHistoryDAO, dedupCache cache.ReadDedupCache) ReadHistoryRepository {
+	return &readHistoryRepository{
+		dao:        dao,
+		dedupCache: dedupCache,
+	}
+}
+
+func (r *readHistoryRepository) Record(ctx context.Context, evt domain.ReadEvent) error {
+	return r.dao.Upsert(ctx, dao.ReadHistory{
+		Uid:         evt.Uid,
+		Biz:         evt.Biz,
+		BizId:       evt.BizId,
+		FirstReadAt: evt.Time.UnixMilli(),
+		LastReadAt:  evt.Time.UnixMilli(),
+	})
+}
+
+func (r *readHistoryRepository) UpdateProgress(ctx context.Context, uid int64, biz string, bizId int64, progress int) error {
+	return r.dao.UpdateProgress(ctx, dao.ReadHistory{
+		Uid:      uid,
+		Biz:      biz,
+		BizId:    bizId,
+		Progress: progress,
+	})
+}
+
+func (r *readHistoryRepository) FindByUid(ctx context.Context, uid int64, offset int, limit int) ([]domain.ReadHistory, error) {
+	hs, err := r.dao.FindByUid(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(hs, func(idx int, src dao.ReadHistory) domain.ReadHistory {
+		return domain.ReadHistory{
+			Id:          src.Id,
+			Uid:         src.Uid,
+			Biz:         src.Biz,
+			BizId:       src.BizId,
+			Progress:    src.Progress,
+			FirstReadAt: time.UnixMilli(src.FirstReadAt),
+			LastReadAt:  time.UnixMilli(src.LastReadAt),
+		}
+	}), nil
+}
+
+func (r *readHistoryRepository) Delete(ctx context.Context, uid int64, biz string, bizIds []int64) error {
+	return r.dao.Delete(ctx, uid, biz, bizIds)
+}
+
+func (r *readHistoryRepository) DeleteAll(ctx context.Context, uid int64) error {
+	return r.dao.DeleteAll(ctx, uid)
+}
+
+func (r *readHistoryRepository) FirstReadInWindow(ctx context.Context, evt domain.ReadEvent, window time.Duration) (bool, error) {
+	return r.dedupCache.FirstInWindow(ctx, evt.Biz, evt.BizId, evt.Uid, evt.Ip, window)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/cache"
+	"Webook/webook/internal/repository/dao"
+	"context"
+)
+
+var (
+	ErrTwoFactorNotFound = dao.ErrTwoFactorNotFound
+	ErrTwoFactorConflict = dao.ErrTwoFactorConflict
+)
+
+type TwoFactorRepository interface {
+	FindByUid(ctx context.Context, uid int64) (domain.TwoFactor, error)
+	// SavePending 保存还没确认的密钥，已经开启时返回 ErrTwoFactorConflict
+	SavePending(ctx context.Context, uid int64, secret string) error
+	// Enable 开启二次验证，codeHashes 是新的恢复码哈希
+	Enable(ctx context.Context, uid int64, counter int64, codeHashes []string) error
+	// UseCounter 时间窗口不能重复使用，重复时返回 ErrTwoFactorConflict
+	UseCounter(ctx context.Context, uid int64, counter int64) error
+	// UseRecoveryCode 恢复码只能用一次，无效时返回 ErrTwoFactorConflict
+	UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error
+	CountRecoveryCodes(ctx context.Context, uid int64) (int64, error)
+	Delete(ctx context.Context, uid int64) error
+
+	// Failures 最近一段时间内验证码错误的次数
+	Failures(ctx context.Context, uid int64) (int64, error)
+	// IncrFailures 记录一次验证码错误，返回最近一段时间内错误的次数
+	IncrFailures(ctx context.Context, uid int64) (int64, error)
+	ResetFailures(ctx context.Context, uid int64) error
+}
+
+type twoFactorRepository struct {
+	dao   dao.TwoFactorDAO
+	cache cache.TwoFactorCache
+}
+
+func NewTwoFactorRepository(dao dao.TwoFactorDAO, cache cache.TwoFactorCache) TwoFactorRepository {
+	return &twoFactorRepository{
+		dao:   dao,
+		cache: cache,
+	}
+}
+
+func (r *twoFactorRepository) FindByUid(ctx context.Context, uid int64) (domain.TwoFactor, error) {
+	tf, err := r.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return domain.TwoFactor{}, err
+	}
+	return domain.TwoFactor{
+		Uid:         tf.Uid,
+		Secret:      tf.Secret,
+		Enabled:     tf.Enabled,
+		LastCounter: tf.LastCounter,
+	}, nil
+}
+
+func (r *twoFactorRepository) SavePending(ctx context.Context, uid int64, secret string) error {
+	return r.dao.UpsertPending(ctx, uid, secret)
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, uid int64, counter int64, codeHashes []string) error {
+	return r.dao.Enable(ctx, uid, counter, codeHashes)
+}
+
+func (r *twoFactorRepository) UseCounter(ctx context.Context, uid int64, counter int64) error {
+	return r.dao.UseCounter(ctx, uid, counter)
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, uid int64, codeHash string) error {
+	return r.dao.UseRecoveryCode(ctx, uid, codeHash)
+}
+
+func (r *twoFactorRepository) CountRecoveryCodes(ctx context.Context, uid int64) (int64, error) {
+	return r.dao.CountRecoveryCodes(ctx, uid)
+}
+
+func (r *twoFactorRepository) Delete(ctx context.Context, uid int64) error {
+	return r.dao.Delete(ctx, uid)
+}
+
+func (r *twoFactorRepository) Failures(ctx context.Context, uid int64) (int64, error) {
+	return r.cache.Failures(ctx, uid)
+}
+
+func (r *twoFactorRepository) IncrFailures(ctx context.Context, uid int64) (int64, error) {
+	return r.cache.IncrFailures(ctx, uid)
+}
+
+func (r *twoFactorRepository) ResetFailures(ctx context.Context, uid int64) error {
+	return r.cache.ResetFailures(ctx, uid)
+}
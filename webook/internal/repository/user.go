@@ -17,6 +17,8 @@ type UserRepository interface {
 	FindByWechat(ctx context.Context, openId string) (domain.User, error)
 	UpdateById(ctx context.Context, user domain.User) error
 	GetNameMapByIds(ctx context.Context, ids []int64) (map[int64]string, error)
+	// UpdatePassword password 是加密之后的密码
+	UpdatePassword(ctx context.Context, id int64, password string) error
 }
 type CachedUserRepository struct {
 	dao   dao.UserDAO
@@ -144,3 +146,11 @@ func (repo *CachedUserRepository) GetNameMapByIds(ctx context.Context, ids []int
 	}
 	return res, nil
 }
+
+func (repo *CachedUserRepository) UpdatePassword(ctx context.Context, id int64, password string) error {
+	if err := repo.dao.UpdatePassword(ctx, id, password); err != nil {
+		return err
+	}
+	// 缓存里有旧的密码，直接删掉
+	return repo.cache.Del(ctx, id)
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/dao"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var (
+	ErrWebhookNotFound         = dao.ErrWebhookNotFound
+	ErrWebhookDeliveryNotFound = dao.ErrWebhookDeliveryNotFound
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, w domain.Webhook) (int64, error)
+	Delete(ctx context.Context, id int64, uid int64) error
+	FindById(ctx context.Context, id int64) (domain.Webhook, error)
+	FindByUid(ctx context.Context, uid int64) ([]domain.Webhook, error)
+	// FindSubscribers uids 中的用户注册的、订阅了事件的 webhook
+	FindSubscribers(ctx context.Context, evt domain.WebhookEventType, uids []int64) ([]domain.Webhook, error)
+
+	CreateDeliveries(ctx context.Context, ds []domain.WebhookDelivery) ([]int64, error)
+	FindDeliveryById(ctx context.Context, id int64) (domain.WebhookDelivery, error)
+	FindDeliveries(ctx context.Context, webhookId int64, offset int, limit int) ([]domain.WebhookDelivery, error)
+	// FindDueDeliveries 到了发送时间的待发送投递
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	// ClaimDelivery 抢占投递，抢到后在 leaseUntil 之前其他实例不会再发送
+	ClaimDelivery(ctx context.Context, d domain.WebhookDelivery, leaseUntil time.Time) (bool, error)
+	UpdateDeliveryResult(ctx context.Context, d domain.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	dao dao.WebhookDAO
+}
+
+func NewWebhookRepository(dao dao.WebhookDAO) WebhookRepository {
+	return &webhookRepository{
+		dao: dao,
+	}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, w domain.Webhook) (int64, error) {
+	events := slice.Map(w.Events, func(idx int, src domain.WebhookEventType) string {
+		return string(src)
+	})
+	return r.dao.Insert(ctx, r.toEntity(w), events)
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id int64, uid int64) error {
+	return r.dao.Delete(ctx, id, uid)
+}
+
+func (r *webhookRepository) FindById(ctx context.Context, id int64) (domain.Webhook, error) {
+	w, err := r.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	return r.toDomain(w), nil
+}
+
+func (r *webhookRepository) FindByUid(ctx context.Context, uid int64) ([]domain.Webhook, error) {
+	ws, err := r.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ws, func(idx int, src dao.Webhook) domain.Webhook {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *webhookRepository) FindSubscribers(ctx context.Context, evt domain.WebhookEventType, uids []int64) ([]domain.Webhook, error) {
+	ws, err := r.dao.FindSubscribers(ctx, string(evt), uids)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ws, func(idx int, src dao.Webhook) domain.Webhook {
+		return r.toDomain(src)
+	}), nil
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, ds []domain.WebhookDelivery) ([]int64, error) {
+	return r.dao.InsertDeliveries(ctx, slice.Map(ds, func(idx int, src domain.WebhookDelivery) dao.WebhookDelivery {
+		return r.toDeliveryEntity(src)
+	}))
+}
+
+func (r *webhookRepository) FindDeliveryById(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	d, err := r.dao.FindDeliveryById(ctx, id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return r.toDeliveryDomain(d), nil
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, webhookId int64, offset int, limit int) ([]domain.WebhookDelivery, error) {
+	ds, err := r.dao.FindDeliveries(ctx, webhookId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ds, func(idx int, src dao.WebhookDelivery) domain.WebhookDelivery {
+		return r.toDeliveryDomain(src)
+	}), nil
+}
+
+func (r *webhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	ds, err := r.dao.FindDueDeliveries(ctx, domain.WebhookDeliveryStatusPending.ToUint8(), now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(ds, func(idx int, src dao.WebhookDelivery) domain.WebhookDelivery {
+		return r.toDeliveryDomain(src)
+	}), nil
+}
+
+func (r *webhookRepository) ClaimDelivery(ctx context.Context, d domain.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	return r.dao.ClaimDelivery(ctx, r.toDeliveryEntity(d), leaseUntil.UnixMilli())
+}
+
+func (r *webhookRepository) UpdateDeliveryResult(ctx context.Context, d domain.WebhookDelivery) error {
+	return r.dao.UpdateDeliveryResult(ctx, r.toDeliveryEntity(d))
+}
+
+func (r *webhookRepository) toEntity(w domain.Webhook) dao.Webhook {
+	events := slice.Map(w.Events, func(idx int, src domain.WebhookEventType) string {
+		return string(src)
+	})
+	return dao.Webhook{
+		Id:     w.Id,
+		Uid:    w.Uid,
+		Url:    w.Url,
+		Secret: w.Secret,
+		Events: strings.Join(events, ","),
+	}
+}
+
+func (r *webhookRepository) toDomain(w dao.Webhook) domain.Webhook {
+	var events []domain.WebhookEventType
+	if w.Events != "" {
+		events = slice.Map(strings.Split(w.Events, ","), func(idx int, src string) domain.WebhookEventType {
+			return domain.WebhookEventType(src)
+		})
+	}
+	return domain.Webhook{
+		Id:     w.Id,
+		Uid:    w.Uid,
+		Url:    w.Url,
+		Secret: w.Secret,
+		Events: events,
+		Ctime:  time.UnixMilli(w.Ctime),
+		Utime:  time.UnixMilli(w.Utime),
+	}
+}
+
+func (r *webhookRepository) toDeliveryEntity(d domain.WebhookDelivery) dao.WebhookDelivery {
+	return dao.WebhookDelivery{
+		Id:          d.Id,
+		WebhookId:   d.WebhookId,
+		Event:       string(d.Event),
+		Payload:     d.Payload,
+		Status:      d.Status.ToUint8(),
+		Attempts:    d.Attempts,
+		NextRetryAt: d.NextRetryAt.UnixMilli(),
+		StatusCode:  d.StatusCode,
+		Error:       d.Error,
+	}
+}
+
+func (r *webhookRepository) toDeliveryDomain(d dao.WebhookDelivery) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		Id:          d.Id,
+		WebhookId:   d.WebhookId,
+		Event:       domain.WebhookEventType(d.Event),
+		Payload:     d.Payload,
+		Status:      domain.WebhookDeliveryStatus(d.Status),
+		Attempts:    d.Attempts,
+		NextRetryAt: time.UnixMilli(d.NextRetryAt),
+		StatusCode:  d.StatusCode,
+		Error:       d.Error,
+		Ctime:       time.UnixMilli(d.Ctime),
+		Utime:       time.UnixMilli(d.Utime),
+	}
+}
@@ -3,10 +3,14 @@ package service
 import (
 	"Webook/webook/internal/domain"
 	"Webook/webook/internal/repository/article"
+	"Webook/webook/pkg/diff"
+	"Webook/webook/pkg/events"
 	"Webook/webook/pkg/logger"
 	"context"
 	"errors"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type ArticleService interface {
@@ -20,14 +24,52 @@ type ArticleService interface {
 	PublicDetail(ctx context.Context, id int64) (domain.Article, error)
 	PublicList(ctx context.Context, end time.Time, offset int, limit int) ([]domain.Article, error)
 
+	// 标签和分类：按标签、分类过滤线上文章，标签联想和热门标签
+	PublicListByFilter(ctx context.Context, end time.Time, offset int, limit int, filter domain.ArticleFilter) ([]domain.Article, error)
+	SuggestTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error)
+	HotTags(ctx context.Context, limit int) ([]domain.Tag, error)
+
+	// 历史版本：列表，任意两个版本的行级 diff，恢复为当前草稿
+	ListRevisions(ctx context.Context, artId int64, authorId int64, limit int, offset int) ([]domain.ArticleRevision, error)
+	DiffRevisions(ctx context.Context, authorId int64, fromId int64, toId int64) ([]diff.Line, error)
+	RestoreRevision(ctx context.Context, authorId int64, revisionId int64) (int64, error)
+
+	// 定时发布：设置、取消，以及由定时任务调用的到期发布
+	Schedule(ctx context.Context, art domain.Article) (int64, error)
+	CancelSchedule(ctx context.Context, art domain.Article) (int64, error)
+	PublishDue(ctx context.Context, now time.Time, batchSize int) (int, error)
+
 	// 两个 Repo 的实现: 读者库和写者库，无事务，有重试机制
 	SaveWithTwoRepo(ctx context.Context, art domain.Article) (int64, error)
 	PublishWithTwoRepo(ctx context.Context, art domain.Article) (int64, error)
 }
 
+var (
+	ErrRevisionNotFound   = article.ErrRevisionNotFound
+	ErrRevisionNotSameArt = errors.New("两个版本不属于同一篇文章")
+	ErrNotArticleAuthor   = errors.New("您无权限操作其他用户的文章")
+	ErrPublishAtInvalid   = errors.New("定时发布时间必须晚于当前时间")
+	ErrNotScheduled       = article.ErrNotScheduled
+	ErrTooManyTags        = errors.New("标签数量超过限制")
+	ErrTagTooLong         = errors.New("标签长度超过限制")
+	ErrCategoryTooLong    = errors.New("分类长度超过限制")
+)
+
+const (
+	maxTagCnt         = 5
+	maxTagLength      = 20
+	maxCategoryLength = 20
+)
+
 type articleService struct {
 	// 一个 Service 操作一个 Repo：读者写者共用一个库
 	repo article.ArticleRepository
+	// 发表后分发到粉丝的动态流
+	feedSvc FeedService
+	// 发表、撤回、删除时通知订阅了事件的 webhook
+	webhookSvc WebhookService
+	// 发表、撤回、删除的事件
+	producer events.Producer[domain.ArticleEvent]
 
 	// 一个 Service 操作两个 Repo：读者库，写者库
 	authorRepo article.ArticleAuthorRepository
@@ -37,14 +79,23 @@ type articleService struct {
 	logger logger.Logger
 }
 
-func NewArticleService(repo article.ArticleRepository) ArticleService {
+func NewArticleService(repo article.ArticleRepository, feedSvc FeedService,
+	webhookSvc WebhookService, producer events.Producer[domain.ArticleEvent], l logger.Logger) ArticleService {
 	return &articleService{
-		repo: repo,
+		repo:       repo,
+		feedSvc:    feedSvc,
+		webhookSvc: webhookSvc,
+		producer:   producer,
+		logger:     l,
 	}
 }
 
 // Save 保存到线上库： 返回文章 id
 func (a *articleService) Save(ctx context.Context, article domain.Article) (int64, error) {
+	article, err := normalizeArticle(article)
+	if err != nil {
+		return 0, err
+	}
 	// 从 ArticleStatusUnknown 到 ArticleStatusUnpublished
 	article.Status = domain.ArticleStatusUnpublished
 	return a.repo.Sync(ctx, article)
@@ -54,25 +105,88 @@ func (a *articleService) Save(ctx context.Context, article domain.Article) (int6
 
 // Publish 发布文章
 func (a *articleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
+	art, err := normalizeArticle(art)
+	if err != nil {
+		return 0, err
+	}
 	// 从 ArticleStatusUnpublished 到 ArticleStatusPublished
 	art.Status = domain.ArticleStatusPublished
-	return a.repo.Sync(ctx, art)
+	id, err := a.repo.Sync(ctx, art)
+	if err != nil {
+		return id, err
+	}
+	art.Id = id
+	a.afterPublish(ctx, art)
+	return id, nil
 
 	// return a.PublishWithTwoRepo(ctx, art)
 }
 
+// afterPublish 分发到粉丝的动态流、发送事件并通知 webhook，失败不影响发表，只记录日志
+func (a *articleService) afterPublish(ctx context.Context, art domain.Article) {
+	a.produceEvent(ctx, domain.ArticleEventPublished, art)
+	if err := a.feedSvc.Push(ctx, art); err != nil {
+		a.logger.Error("分发动态流失败",
+			logger.Int64("artId", art.Id),
+			logger.Int64("authorId", art.Author.Id),
+			logger.Error(err),
+		)
+	}
+	dispatchWebhook(ctx, a.webhookSvc, a.logger, domain.WebhookEventArticlePublished, art.Author.Id, webhookArticleData{
+		Id:       art.Id,
+		AuthorId: art.Author.Id,
+		Title:    art.Title,
+		Abstract: art.Abstract(),
+	})
+}
+
+// produceEvent 发送文章状态变化的事件，失败不影响业务，只记录日志
+func (a *articleService) produceEvent(ctx context.Context, typ domain.ArticleEventType, art domain.Article) {
+	err := a.producer.Produce(ctx, domain.ArticleEvent{
+		Type:      typ,
+		ArticleId: art.Id,
+		AuthorId:  art.Author.Id,
+		Time:      time.Now(),
+	})
+	if err != nil {
+		a.logger.Error("发送文章事件失败",
+			logger.String("type", string(typ)),
+			logger.Int64("artId", art.Id),
+			logger.Error(err),
+		)
+	}
+}
+
 // Withdraw 撤回文章，只有作者本人可以操作，撤回后仅自己可见
 func (a *articleService) Withdraw(ctx context.Context, art domain.Article) (int64, error) {
 	// 从 ArticleStatusPublished 到 ArticleStatusPrivate
 	art.Status = domain.ArticleStatusPrivate
-	return a.repo.SyncStatus(ctx, art)
+	id, err := a.repo.SyncStatus(ctx, art)
+	if err != nil {
+		return id, err
+	}
+	a.produceEvent(ctx, domain.ArticleEventWithdrawn, art)
+	dispatchWebhook(ctx, a.webhookSvc, a.logger, domain.WebhookEventArticleWithdrawn, art.Author.Id, webhookArticleData{
+		Id:       art.Id,
+		AuthorId: art.Author.Id,
+	})
+	return id, nil
 }
 
 // Delete 删除文章，只有作者本人可以操作
 func (a *articleService) Delete(ctx context.Context, art domain.Article) (int64, error) {
 	// 从 ArticleStatusPublished 到 ArticleStatusArchived
 	art.Status = domain.ArticleStatusArchived
-	return a.repo.SyncStatus(ctx, art)
+	id, err := a.repo.SyncStatus(ctx, art)
+	if err != nil {
+		return id, err
+	}
+	a.produceEvent(ctx, domain.ArticleEventDeleted, art)
+	dispatchWebhook(ctx, a.webhookSvc, a.logger, domain.WebhookEventArticleDeleted, art.Author.Id, webhookArticleData{
+		Id:       art.Id,
+		AuthorId: art.Author.Id,
+	})
+	return id, nil
 }
 
 func (a *articleService) List(ctx context.Context, userId int64, limit int, offset int) ([]domain.Article, error) {
@@ -91,7 +205,164 @@ func (a *articleService) PublicDetail(ctx context.Context, id int64) (domain.Art
 
 // PublicList 获取线上库文章列表
 func (a *articleService) PublicList(ctx context.Context, end time.Time, offset int, limit int) ([]domain.Article, error) {
-	return a.repo.FindPublishedArticleList(ctx, end, offset, limit)
+	return a.repo.FindPublishedArticleList(ctx, end, offset, limit, domain.ArticleFilter{})
+}
+
+// PublicListByFilter 按标签、分类获取线上库文章列表
+func (a *articleService) PublicListByFilter(ctx context.Context, end time.Time, offset int, limit int, filter domain.ArticleFilter) ([]domain.Article, error) {
+	filter.Tag = strings.TrimSpace(filter.Tag)
+	filter.Category = strings.TrimSpace(filter.Category)
+	return a.repo.FindPublishedArticleList(ctx, end, offset, limit, filter)
+}
+
+// SuggestTags 标签联想：返回以 prefix 开头的标签，热门的排在前面
+func (a *articleService) SuggestTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []domain.Tag{}, nil
+	}
+	return a.repo.FindTags(ctx, prefix, limit)
+}
+
+// HotTags 热门标签：按使用该标签的已发表文章数排序
+func (a *articleService) HotTags(ctx context.Context, limit int) ([]domain.Tag, error) {
+	return a.repo.FindTags(ctx, "", limit)
+}
+
+// normalizeArticle 整理标签和分类：去掉首尾空白，标签去空去重，并校验数量和长度
+func normalizeArticle(art domain.Article) (domain.Article, error) {
+	art.Category = strings.TrimSpace(art.Category)
+	if utf8.RuneCountInString(art.Category) > maxCategoryLength {
+		return art, ErrCategoryTooLong
+	}
+
+	var tags []string
+	seen := make(map[string]struct{}, len(art.Tags))
+	for _, tag := range art.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return art, ErrTagTooLong
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxTagCnt {
+		return art, ErrTooManyTags
+	}
+	art.Tags = tags
+	return art, nil
+}
+
+// ListRevisions 获取文章的历史版本，只有作者本人可以查看
+func (a *articleService) ListRevisions(ctx context.Context, artId int64, authorId int64, limit int, offset int) ([]domain.ArticleRevision, error) {
+	return a.repo.GetRevisions(ctx, artId, authorId, limit, offset)
+}
+
+// DiffRevisions 比较同一篇文章的两个历史版本，from 为旧版本，to 为新版本
+func (a *articleService) DiffRevisions(ctx context.Context, authorId int64, fromId int64, toId int64) ([]diff.Line, error) {
+	from, err := a.findRevision(ctx, authorId, fromId)
+	if err != nil {
+		return nil, err
+	}
+	to, err := a.findRevision(ctx, authorId, toId)
+	if err != nil {
+		return nil, err
+	}
+	if from.ArticleId != to.ArticleId {
+		return nil, ErrRevisionNotSameArt
+	}
+
+	// 标题也参与比较，放在第一行
+	return diff.Lines(from.Title+"\n"+from.Content, to.Title+"\n"+to.Content), nil
+}
+
+// RestoreRevision 把历史版本恢复为当前草稿，恢复本身也会产生一个新的版本
+func (a *articleService) RestoreRevision(ctx context.Context, authorId int64, revisionId int64) (int64, error) {
+	rev, err := a.findRevision(ctx, authorId, revisionId)
+	if err != nil {
+		return 0, err
+	}
+	// 历史版本只保存标题和内容，标签和分类沿用当前草稿
+	cur, err := a.repo.FindById(ctx, rev.ArticleId)
+	if err != nil {
+		return 0, err
+	}
+	return a.Save(ctx, domain.Article{
+		Id:       rev.ArticleId,
+		Title:    rev.Title,
+		Content:  rev.Content,
+		Category: cur.Category,
+		Tags:     cur.Tags,
+		Author:   domain.Author{Id: authorId},
+	})
+}
+
+// findRevision 查询历史版本，并校验是否是作者本人
+func (a *articleService) findRevision(ctx context.Context, authorId int64, id int64) (domain.ArticleRevision, error) {
+	rev, err := a.repo.FindRevisionById(ctx, id)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	if rev.Author.Id != authorId {
+		return domain.ArticleRevision{}, ErrNotArticleAuthor
+	}
+	return rev, nil
+}
+
+// Schedule 定时发布，art.PublishAt 为发布时间，到点后由定时任务发布。
+// 已经发表的文章在到点之前，读者看到的仍然是当前线上的版本
+func (a *articleService) Schedule(ctx context.Context, art domain.Article) (int64, error) {
+	if !art.PublishAt.After(time.Now()) {
+		return 0, ErrPublishAtInvalid
+	}
+	art, err := normalizeArticle(art)
+	if err != nil {
+		return 0, err
+	}
+	// 从 ArticleStatusUnpublished 到 ArticleStatusScheduled
+	art.Status = domain.ArticleStatusScheduled
+	return a.repo.Schedule(ctx, art)
+}
+
+// CancelSchedule 取消定时发布，作者这边的文章回到未发表状态，已经发表的线上版本不受影响
+func (a *articleService) CancelSchedule(ctx context.Context, art domain.Article) (int64, error) {
+	return a.repo.CancelSchedule(ctx, art)
+}
+
+// PublishDue 发布所有到期的定时文章，返回发布成功的数量
+func (a *articleService) PublishDue(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	cnt := 0
+	for {
+		arts, err := a.repo.FindDueScheduled(ctx, now, batchSize)
+		if err != nil {
+			return cnt, err
+		}
+
+		for _, art := range arts {
+			err = a.repo.PublishScheduled(ctx, art)
+			// 作者在扫描之后取消或修改了定时发布，跳过即可
+			if err == ErrNotScheduled {
+				continue
+			}
+			if err != nil {
+				// 发布失败的文章仍然是定时状态，下一轮任务会重试
+				return cnt, err
+			}
+			a.afterPublish(ctx, art)
+			cnt++
+		}
+
+		// 发布后的文章不再是定时状态，所以不需要 offset
+		if len(arts) < batchSize {
+			return cnt, nil
+		}
+	}
 }
 
 //
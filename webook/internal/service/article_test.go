@@ -4,10 +4,15 @@ import (
 	"Webook/webook/internal/domain"
 	"Webook/webook/internal/repository/article"
 	repomocks "Webook/webook/internal/repository/article/mocks"
+	svcmocks "Webook/webook/internal/service/mocks"
+	"Webook/webook/pkg/diff"
+	"Webook/webook/pkg/events"
+	eventmocks "Webook/webook/pkg/events/mocks"
 	"Webook/webook/pkg/logger"
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -229,3 +234,277 @@ func TestArticleService_Publish(t *testing.T) {
 		})
 	}
 }
+
+func TestArticleService_DiffRevisions(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) article.ArticleRepository
+
+		authorId int64
+		fromId   int64
+		toId     int64
+
+		wantLines []diff.Line
+		wantErr   error
+	}{
+		{
+			name: "比较成功",
+			mock: func(ctrl *gomock.Controller) article.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindRevisionById(gomock.Any(), int64(1)).Return(domain.ArticleRevision{
+					Id: 1, ArticleId: 10, Title: "标题", Content: "第一行\n第二行",
+					Author: domain.Author{Id: 666},
+				}, nil)
+				repo.EXPECT().FindRevisionById(gomock.Any(), int64(2)).Return(domain.ArticleRevision{
+					Id: 2, ArticleId: 10, Title: "标题", Content: "第一行\n修改后的第二行",
+					Author: domain.Author{Id: 666},
+				}, nil)
+				return repo
+			},
+			authorId: 666,
+			fromId:   1,
+			toId:     2,
+			wantLines: []diff.Line{
+				{Op: diff.OpEqual, Text: "标题"},
+				{Op: diff.OpEqual, Text: "第一行"},
+				{Op: diff.OpDelete, Text: "第二行"},
+				{Op: diff.OpInsert, Text: "修改后的第二行"},
+			},
+		},
+		{
+			name: "不是作者本人",
+			mock: func(ctrl *gomock.Controller) article.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindRevisionById(gomock.Any(), int64(1)).Return(domain.ArticleRevision{
+					Id: 1, ArticleId: 10, Author: domain.Author{Id: 777},
+				}, nil)
+				return repo
+			},
+			authorId: 666,
+			fromId:   1,
+			toId:     2,
+			wantErr:  ErrNotArticleAuthor,
+		},
+		{
+			name: "两个版本不属于同一篇文章",
+			mock: func(ctrl *gomock.Controller) article.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindRevisionById(gomock.Any(), int64(1)).Return(domain.ArticleRevision{
+					Id: 1, ArticleId: 10, Author: domain.Author{Id: 666},
+				}, nil)
+				repo.EXPECT().FindRevisionById(gomock.Any(), int64(2)).Return(domain.ArticleRevision{
+					Id: 2, ArticleId: 11, Author: domain.Author{Id: 666},
+				}, nil)
+				return repo
+			},
+			authorId: 666,
+			fromId:   1,
+			toId:     2,
+			wantErr:  ErrRevisionNotSameArt,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil, nil)
+			lines, err := svc.DiffRevisions(context.Background(), tc.authorId, tc.fromId, tc.toId)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantLines, lines)
+		})
+	}
+}
+
+func TestArticleService_PublishDue(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (article.ArticleRepository, FeedService, WebhookService, events.Producer[domain.ArticleEvent])
+
+		batchSize int
+		wantCnt   int
+		wantErr   error
+	}{
+		{
+			name: "分批发布，跳过已取消的文章",
+			mock: func(ctrl *gomock.Controller) (article.ArticleRepository, FeedService, WebhookService, events.Producer[domain.ArticleEvent]) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				feedSvc := svcmocks.NewMockFeedService(ctrl)
+				webhookSvc := svcmocks.NewMockWebhookService(ctrl)
+				producer := eventmocks.NewMockProducer[domain.ArticleEvent](ctrl)
+				repo.EXPECT().FindDueScheduled(gomock.Any(), now, 2).Return([]domain.Article{
+					{Id: 1}, {Id: 2},
+				}, nil)
+				repo.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 1}).Return(nil)
+				producer.EXPECT().Produce(gomock.Any(), gomock.Cond(func(evt domain.ArticleEvent) bool {
+					return evt.Type == domain.ArticleEventPublished && evt.ArticleId == 1
+				})).Return(nil)
+				feedSvc.EXPECT().Push(gomock.Any(), domain.Article{Id: 1}).Return(nil)
+				webhookSvc.EXPECT().Dispatch(gomock.Any(), domain.WebhookEventArticlePublished, int64(0),
+					webhookArticleData{Id: 1}).Return(nil)
+				repo.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 2}).Return(ErrNotScheduled)
+				repo.EXPECT().FindDueScheduled(gomock.Any(), now, 2).Return([]domain.Article{
+					{Id: 3},
+				}, nil)
+				repo.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 3}).Return(nil)
+				// 发送事件、分发动态流失败都不影响发布
+				producer.EXPECT().Produce(gomock.Any(), gomock.Cond(func(evt domain.ArticleEvent) bool {
+					return evt.Type == domain.ArticleEventPublished && evt.ArticleId == 3
+				})).Return(errors.New("mock produce error"))
+				feedSvc.EXPECT().Push(gomock.Any(), domain.Article{Id: 3}).Return(errors.New("mock push error"))
+				// 通知 webhook 失败同样不影响发布
+				webhookSvc.EXPECT().Dispatch(gomock.Any(), domain.WebhookEventArticlePublished, int64(0),
+					webhookArticleData{Id: 3}).Return(errors.New("mock dispatch error"))
+				return repo, feedSvc, webhookSvc, producer
+			},
+			batchSize: 2,
+			wantCnt:   2,
+		},
+		{
+			name: "发布失败，等待下一轮任务重试",
+			mock: func(ctrl *gomock.Controller) (article.ArticleRepository, FeedService, WebhookService, events.Producer[domain.ArticleEvent]) {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				feedSvc := svcmocks.NewMockFeedService(ctrl)
+				webhookSvc := svcmocks.NewMockWebhookService(ctrl)
+				producer := eventmocks.NewMockProducer[domain.ArticleEvent](ctrl)
+				repo.EXPECT().FindDueScheduled(gomock.Any(), now, 2).Return([]domain.Article{
+					{Id: 1}, {Id: 2},
+				}, nil)
+				repo.EXPECT().PublishScheduled(gomock.Any(), domain.Article{Id: 1}).Return(errors.New("db error"))
+				return repo, feedSvc, webhookSvc, producer
+			},
+			batchSize: 2,
+			wantCnt:   0,
+			wantErr:   errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, feedSvc, webhookSvc, producer := tc.mock(ctrl)
+			svc := NewArticleService(repo, feedSvc, webhookSvc, producer, logger.NewZapLogger(zap.NewNop()))
+			cnt, err := svc.PublishDue(context.Background(), now, tc.batchSize)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+		})
+	}
+}
+
+func TestArticleService_Save(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) article.ArticleRepository
+
+		art     domain.Article
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "整理标签和分类",
+			mock: func(ctrl *gomock.Controller) article.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().Sync(gomock.Any(), domain.Article{
+					Title:    "标题",
+					Category: "后端",
+					Tags:     []string{"Go", "MySQL"},
+					Status:   domain.ArticleStatusUnpublished,
+				}).Return(int64(1), nil)
+				return repo
+			},
+			art: domain.Article{
+				Title:    "标题",
+				Category: " 后端 ",
+				Tags:     []string{" Go", "MySQL", "", "Go "},
+			},
+			wantId: 1,
+		},
+		{
+			name: "标签数量超过限制",
+			mock: func(ctrl *gomock.Controller) article.ArticleRepository {
+				return repomocks.NewMockArticleRepository(ctrl)
+			},
+			art: domain.Article{
+				Tags: []string{"a", "b", "c", "d", "e", "f"},
+			},
+			wantErr: ErrTooManyTags,
+		},
+		{
+			name: "标签长度超过限制",
+			mock: func(ctrl *gomock.Controller) article.ArticleRepository {
+				return repomocks.NewMockArticleRepository(ctrl)
+			},
+			art: domain.Article{
+				Tags: []string{"一二三四五六七八九十一二三四五六七八九十一"},
+			},
+			wantErr: ErrTagTooLong,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil, nil)
+			id, err := svc.Save(context.Background(), tc.art)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func TestArticleService_Schedule(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) article.ArticleRepository
+
+		art     domain.Article
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "只保存到写者库，不同步线上库",
+			mock: func(ctrl *gomock.Controller) article.ArticleRepository {
+				repo := repomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().Schedule(gomock.Any(), domain.Article{
+					Id:        1,
+					Title:     "新标题",
+					Status:    domain.ArticleStatusScheduled,
+					PublishAt: publishAt,
+				}).Return(int64(1), nil)
+				return repo
+			},
+			art: domain.Article{
+				Id:        1,
+				Title:     "新标题",
+				PublishAt: publishAt,
+			},
+			wantId: 1,
+		},
+		{
+			name: "发布时间已经过去",
+			mock: func(ctrl *gomock.Controller) article.ArticleRepository {
+				return repomocks.NewMockArticleRepository(ctrl)
+			},
+			art: domain.Article{
+				Id:        1,
+				PublishAt: time.Now().Add(-time.Minute),
+			},
+			wantErr: ErrPublishAtInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleService(tc.mock(ctrl), nil, nil, nil, nil)
+			id, err := svc.Schedule(context.Background(), tc.art)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrAuthorStatsDaysInvalid = errors.New("不支持的统计天数")
+
+// AuthorStatsService 按作者按天汇总阅读、点赞、收藏和发表数，
+// 作者看板和作者榜单都从汇总表中读取
+type AuthorStatsService interface {
+	// IncrReadCnt 计入去重合并后的阅读数，artIds 和 cnts 一一对应
+	IncrReadCnt(ctx context.Context, artIds []int64, cnts []int64) error
+	// ShouldRecord 事件是否还没有计入过汇总，重新投递的事件返回 false
+	ShouldRecord(ctx context.Context, eventId string) (bool, error)
+	// RecordInteractive 批量计入文章的点赞、收藏和取消
+	RecordInteractive(ctx context.Context, evts []domain.InteractiveEvent) error
+	// RecordPublish 计入文章的首次发表
+	RecordPublish(ctx context.Context, evt domain.ArticleEvent) error
+
+	// Dashboard 作者最近 days 天每天的数据，包括今天，按日期升序，没有数据的日期补零
+	Dashboard(ctx context.Context, authorId int64, days int) ([]domain.AuthorStats, error)
+	// TopAuthors 最近 days 天的作者榜单
+	TopAuthors(ctx context.Context, days int) ([]domain.AuthorRankItem, error)
+}
+
+type authorStatsService struct {
+	repo    repository.AuthorStatsRepository
+	artRepo article.ArticleRepository
+
+	weights domain.AuthorScoreWeights
+	// 看板最多查询的天数
+	maxDays int
+	// 榜单支持的天数，每种天数单独缓存
+	boardDays map[int]struct{}
+	topN      int
+}
+
+func NewAuthorStatsService(repo repository.AuthorStatsRepository, artRepo article.ArticleRepository) AuthorStatsService {
+	return &authorStatsService{
+		repo:    repo,
+		artRepo: artRepo,
+		// 和文章榜单一样，收藏比点赞更能说明质量，阅读数最容易刷
+		weights: domain.AuthorScoreWeights{
+			Read:    0.1,
+			Like:    1,
+			Collect: 2,
+			Publish: 5,
+		},
+		maxDays:   90,
+		boardDays: map[int]struct{}{1: {}, 7: {}, 30: {}},
+		topN:      100,
+	}
+}
+
+func (s *authorStatsService) IncrReadCnt(ctx context.Context, artIds []int64, cnts []int64) error {
+	arts, err := s.artRepo.FindPublishedArticlesByIds(ctx, artIds)
+	if err != nil {
+		return err
+	}
+	authors := make(map[int64]int64, len(arts))
+	for _, art := range arts {
+		authors[art.Id] = art.Author.Id
+	}
+
+	reads := make(map[int64]int64)
+	for i, artId := range artIds {
+		// 已经撤回或删除的文章不计
+		authorId, ok := authors[artId]
+		if !ok {
+			continue
+		}
+		reads[authorId] += cnts[i]
+	}
+	today := time.Now()
+	stats := make([]domain.AuthorStats, 0, len(reads))
+	for authorId, cnt := range reads {
+		stats = append(stats, domain.AuthorStats{
+			AuthorId: authorId,
+			Day:      today,
+			ReadCnt:  cnt,
+		})
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+	return s.repo.IncrStats(ctx, stats)
+}
+
+func (s *authorStatsService) ShouldRecord(ctx context.Context, eventId string) (bool, error) {
+	return s.repo.MarkEvent(ctx, eventId)
+}
+
+type authorDayKey struct {
+	authorId int64
+	day      string
+}
+
+func (s *authorStatsService) RecordInteractive(ctx context.Context, evts []domain.InteractiveEvent) error {
+	artIds := make([]int64, 0, len(evts))
+	seen := make(map[int64]struct{}, len(evts))
+	for _, evt := range evts {
+		if _, _, ok := interactiveDelta(evt); !ok {
+			continue
+		}
+		if _, ok := seen[evt.BizId]; !ok {
+			seen[evt.BizId] = struct{}{}
+			artIds = append(artIds, evt.BizId)
+		}
+	}
+	if len(artIds) == 0 {
+		return nil
+	}
+	arts, err := s.artRepo.FindPublishedArticlesByIds(ctx, artIds)
+	if err != nil {
+		return err
+	}
+	authors := make(map[int64]int64, len(arts))
+	for _, art := range arts {
+		authors[art.Id] = art.Author.Id
+	}
+
+	// 同一个作者同一天的合并成一条
+	merged := make(map[authorDayKey]*domain.AuthorStats)
+	var stats []*domain.AuthorStats
+	for _, evt := range evts {
+		like, collect, ok := interactiveDelta(evt)
+		if !ok {
+			continue
+		}
+		// 已经撤回或删除的文章不计
+		authorId, ok := authors[evt.BizId]
+		if !ok {
+			continue
+		}
+		key := authorDayKey{authorId: authorId, day: evt.Time.Format(time.DateOnly)}
+		st, ok := merged[key]
+		if !ok {
+			st = &domain.AuthorStats{AuthorId: authorId, Day: evt.Time}
+			merged[key] = st
+			stats = append(stats, st)
+		}
+		st.LikeCnt += like
+		st.CollectCnt += collect
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+	res := make([]domain.AuthorStats, 0, len(stats))
+	for _, st := range stats {
+		res = append(res, *st)
+	}
+	return s.repo.IncrStats(ctx, res)
+}
+
+// interactiveDelta 事件对点赞数和收藏数的影响，不计入汇总的事件返回 false
+func interactiveDelta(evt domain.InteractiveEvent) (like int64, collect int64, ok bool) {
+	if evt.Biz != "article" {
+		return 0, 0, false
+	}
+	switch evt.Type {
+	case domain.InteractiveEventLike:
+		return 1, 0, true
+	case domain.InteractiveEventUnlike:
+		return -1, 0, true
+	case domain.InteractiveEventCollect:
+		return 0, 1, true
+	case domain.InteractiveEventUncollect:
+		return 0, -1, true
+	}
+	return 0, 0, false
+}
+
+func (s *authorStatsService) RecordPublish(ctx context.Context, evt domain.ArticleEvent) error {
+	if evt.Type != domain.ArticleEventPublished {
+		return nil
+	}
+	return s.repo.IncrPublishCnt(ctx, evt.AuthorId, evt.ArticleId, evt.Time)
+}
+
+func (s *authorStatsService) Dashboard(ctx context.Context, authorId int64, days int) ([]domain.AuthorStats, error) {
+	if days <= 0 || days > s.maxDays {
+		return nil, ErrAuthorStatsDaysInvalid
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	start := today.AddDate(0, 0, 1-days)
+	stats, err := s.repo.GetDaily(ctx, authorId, start, today)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]domain.AuthorStats, 0, days)
+	i := 0
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		if i < len(stats) && stats[i].Day.Equal(day) {
+			res = append(res, stats[i])
+			i++
+			continue
+		}
+		res = append(res, domain.AuthorStats{AuthorId: authorId, Day: day})
+	}
+	return res, nil
+}
+
+func (s *authorStatsService) TopAuthors(ctx context.Context, days int) ([]domain.AuthorRankItem, error) {
+	if _, ok := s.boardDays[days]; !ok {
+		return nil, ErrAuthorStatsDaysInvalid
+	}
+	return s.repo.GetTopAuthors(ctx, days, s.weights, s.topN)
+}
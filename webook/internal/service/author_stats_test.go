@@ -0,0 +1,186 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	artrepomocks "Webook/webook/internal/repository/article/mocks"
+	repomocks "Webook/webook/internal/repository/mocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthorStatsService_IncrReadCnt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repomocks.NewMockAuthorStatsRepository(ctrl)
+	artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+	// 3 已经撤回
+	artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1, 2, 3}).
+		Return([]domain.Article{
+			{Id: 1, Author: domain.Author{Id: 10}},
+			{Id: 2, Author: domain.Author{Id: 10}},
+		}, nil)
+	repo.EXPECT().IncrStats(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, stats []domain.AuthorStats) error {
+			require.Len(t, stats, 1)
+			assert.Equal(t, int64(10), stats[0].AuthorId)
+			assert.Equal(t, int64(5), stats[0].ReadCnt)
+			return nil
+		})
+
+	svc := NewAuthorStatsService(repo, artRepo)
+	assert.NoError(t, svc.IncrReadCnt(context.Background(), []int64{1, 2, 3}, []int64{2, 3, 4}))
+}
+
+func TestAuthorStatsService_RecordInteractive(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.AuthorStatsRepository, article.ArticleRepository)
+
+		evts    []domain.InteractiveEvent
+		wantErr error
+	}{
+		{
+			name: "取消点赞减一",
+			mock: func(ctrl *gomock.Controller) (repository.AuthorStatsRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockAuthorStatsRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1}).
+					Return([]domain.Article{{Id: 1, Author: domain.Author{Id: 10}}}, nil)
+				repo.EXPECT().IncrStats(gomock.Any(), []domain.AuthorStats{
+					{AuthorId: 10, Day: now, LikeCnt: -1},
+				}).Return(nil)
+				return repo, artRepo
+			},
+			evts: []domain.InteractiveEvent{
+				{Type: domain.InteractiveEventUnlike, Biz: "article", BizId: 1, Time: now},
+			},
+		},
+		{
+			name: "一批事件只查询一次文章，同一个作者同一天的合并",
+			mock: func(ctrl *gomock.Controller) (repository.AuthorStatsRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockAuthorStatsRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				// 3 已经撤回
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1, 2, 3, 4}).
+					Return([]domain.Article{
+						{Id: 1, Author: domain.Author{Id: 10}},
+						{Id: 2, Author: domain.Author{Id: 10}},
+						{Id: 4, Author: domain.Author{Id: 20}},
+					}, nil)
+				repo.EXPECT().IncrStats(gomock.Any(), []domain.AuthorStats{
+					{AuthorId: 10, Day: now, LikeCnt: 2, CollectCnt: 1},
+					{AuthorId: 20, Day: now, CollectCnt: -1},
+					{AuthorId: 10, Day: now.AddDate(0, 0, -1), LikeCnt: 1},
+				}).Return(nil)
+				return repo, artRepo
+			},
+			evts: []domain.InteractiveEvent{
+				{Type: domain.InteractiveEventLike, Biz: "article", BizId: 1, Time: now},
+				{Type: domain.InteractiveEventLike, Biz: "article", BizId: 2, Time: now},
+				{Type: domain.InteractiveEventCollect, Biz: "article", BizId: 1, Time: now},
+				{Type: domain.InteractiveEventLike, Biz: "article", BizId: 3, Time: now},
+				{Type: domain.InteractiveEventUncollect, Biz: "article", BizId: 4, Time: now},
+				{Type: domain.InteractiveEventLike, Biz: "article", BizId: 1, Time: now.AddDate(0, 0, -1)},
+			},
+		},
+		{
+			name: "文章已经撤回",
+			mock: func(ctrl *gomock.Controller) (repository.AuthorStatsRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockAuthorStatsRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1}).Return(nil, nil)
+				return repo, artRepo
+			},
+			evts: []domain.InteractiveEvent{
+				{Type: domain.InteractiveEventCollect, Biz: "article", BizId: 1, Time: now},
+			},
+		},
+		{
+			name: "查询文章失败，事件留到下次再写",
+			mock: func(ctrl *gomock.Controller) (repository.AuthorStatsRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockAuthorStatsRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1}).
+					Return(nil, errors.New("db error"))
+				return repo, artRepo
+			},
+			evts: []domain.InteractiveEvent{
+				{Type: domain.InteractiveEventLike, Biz: "article", BizId: 1, Time: now},
+			},
+			wantErr: errors.New("db error"),
+		},
+		{
+			name: "其他业务不计",
+			mock: func(ctrl *gomock.Controller) (repository.AuthorStatsRepository, article.ArticleRepository) {
+				return repomocks.NewMockAuthorStatsRepository(ctrl), artrepomocks.NewMockArticleRepository(ctrl)
+			},
+			evts: []domain.InteractiveEvent{
+				{Type: domain.InteractiveEventLike, Biz: "comment", BizId: 1, Time: now},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo, artRepo := tc.mock(ctrl)
+			svc := NewAuthorStatsService(repo, artRepo)
+			err := svc.RecordInteractive(context.Background(), tc.evts)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestAuthorStatsService_Dashboard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	yesterday := today.AddDate(0, 0, -1)
+	repo := repomocks.NewMockAuthorStatsRepository(ctrl)
+	repo.EXPECT().GetDaily(gomock.Any(), int64(10), today.AddDate(0, 0, -2), today).
+		Return([]domain.AuthorStats{{AuthorId: 10, Day: yesterday, ReadCnt: 3}}, nil)
+
+	svc := NewAuthorStatsService(repo, artrepomocks.NewMockArticleRepository(ctrl))
+	stats, err := svc.Dashboard(context.Background(), 10, 3)
+	require.NoError(t, err)
+	// 没有数据的日期补零
+	assert.Equal(t, []domain.AuthorStats{
+		{AuthorId: 10, Day: today.AddDate(0, 0, -2)},
+		{AuthorId: 10, Day: yesterday, ReadCnt: 3},
+		{AuthorId: 10, Day: today},
+	}, stats)
+
+	_, err = svc.Dashboard(context.Background(), 10, 365)
+	assert.Equal(t, ErrAuthorStatsDaysInvalid, err)
+}
+
+func TestAuthorStatsService_TopAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repomocks.NewMockAuthorStatsRepository(ctrl)
+	repo.EXPECT().GetTopAuthors(gomock.Any(), 7, gomock.Any(), 100).
+		Return([]domain.AuthorRankItem{{AuthorStats: domain.AuthorStats{AuthorId: 10}, Score: 12}}, nil)
+
+	svc := NewAuthorStatsService(repo, artrepomocks.NewMockArticleRepository(ctrl))
+	items, err := svc.TopAuthors(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	_, err = svc.TopAuthors(context.Background(), 3)
+	assert.Equal(t, ErrAuthorStatsDaysInvalid, err)
+}
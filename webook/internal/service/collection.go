@@ -0,0 +1,125 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type CollectionService interface {
+	Create(ctx context.Context, c domain.Collection) (int64, error)
+	Rename(ctx context.Context, c domain.Collection) error
+	// Delete 删除收藏夹，收藏夹中的收藏会被全部取消
+	Delete(ctx context.Context, id int64, uid int64) error
+	List(ctx context.Context, uid int64) ([]domain.Collection, error)
+	// ListArticles 收藏夹中的文章，只有收藏夹的主人可以查看
+	ListArticles(ctx context.Context, id int64, uid int64, offset int, limit int) ([]domain.Article, error)
+}
+
+var (
+	ErrCollectionDuplicate = repository.ErrCollectionDuplicate
+	ErrCollectionNotFound  = repository.ErrCollectionNotFound
+	ErrCollectionNameEmpty = errors.New("收藏夹名称不能为空")
+	ErrCollectionNameLong  = errors.New("收藏夹名称超过长度限制")
+)
+
+const maxCollectionNameLength = 32
+
+type collectionService struct {
+	repo    repository.CollectionRepository
+	artRepo article.ArticleRepository
+	// 删除收藏夹时，被取消的收藏和取消收藏一样发送事件
+	eventProducer events.Producer[domain.InteractiveEvent]
+	logger        logger.Logger
+}
+
+func NewCollectionService(repo repository.CollectionRepository, artRepo article.ArticleRepository,
+	eventProducer events.Producer[domain.InteractiveEvent], l logger.Logger) CollectionService {
+	return &collectionService{
+		repo:          repo,
+		artRepo:       artRepo,
+		eventProducer: eventProducer,
+		logger:        l,
+	}
+}
+
+func (s *collectionService) Create(ctx context.Context, c domain.Collection) (int64, error) {
+	name, err := checkCollectionName(c.Name)
+	if err != nil {
+		return 0, err
+	}
+	c.Name = name
+	return s.repo.Create(ctx, c)
+}
+
+func (s *collectionService) Rename(ctx context.Context, c domain.Collection) error {
+	name, err := checkCollectionName(c.Name)
+	if err != nil {
+		return err
+	}
+	c.Name = name
+	return s.repo.Rename(ctx, c)
+}
+
+func (s *collectionService) Delete(ctx context.Context, id int64, uid int64) error {
+	items, err := s.repo.Delete(ctx, id, uid)
+	// 缓存更新失败时收藏已经取消了，事件照样要发
+	now := time.Now()
+	for _, item := range items {
+		evtErr := s.eventProducer.Produce(ctx, domain.InteractiveEvent{
+			Type:  domain.InteractiveEventUncollect,
+			Uid:   uid,
+			Biz:   item.Biz,
+			BizId: item.BizId,
+			Time:  now,
+		})
+		if evtErr != nil {
+			s.logger.Error("发送取消收藏事件失败",
+				logger.String("biz", item.Biz),
+				logger.Int64("bizId", item.BizId),
+				logger.Error(evtErr),
+			)
+		}
+	}
+	return err
+}
+
+func (s *collectionService) List(ctx context.Context, uid int64) ([]domain.Collection, error) {
+	return s.repo.FindByUid(ctx, uid)
+}
+
+func (s *collectionService) ListArticles(ctx context.Context, id int64, uid int64, offset int, limit int) ([]domain.Article, error) {
+	c, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// 不暴露别人的收藏夹是否存在
+	if c.Uid != uid {
+		return nil, ErrCollectionNotFound
+	}
+
+	ids, err := s.repo.FindItemIds(ctx, id, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	// 已经撤回或删除的文章不再展示
+	return s.artRepo.FindPublishedArticlesByIds(ctx, ids)
+}
+
+func checkCollectionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrCollectionNameEmpty
+	}
+	if utf8.RuneCountInString(name) > maxCollectionNameLength {
+		return "", ErrCollectionNameLong
+	}
+	return name, nil
+}
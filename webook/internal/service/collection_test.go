@@ -0,0 +1,202 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	artrepomocks "Webook/webook/internal/repository/article/mocks"
+	repomocks "Webook/webook/internal/repository/mocks"
+	eventmocks "Webook/webook/pkg/events/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInteractiveService_Collect(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.CollectionRepository)
+
+		cid     int64
+		userId  int64
+		wantErr error
+		// 是否发送通知和收藏事件
+		wantEvent bool
+	}{
+		{
+			name: "收藏到自己的收藏夹",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.CollectionRepository) {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				cRepo := repomocks.NewMockCollectionRepository(ctrl)
+				cRepo.EXPECT().FindById(gomock.Any(), int64(3)).Return(domain.Collection{Id: 3, Uid: 1}, nil)
+				repo.EXPECT().InsertCollection(gomock.Any(), "article", int64(2), int64(3), int64(1)).Return(true, nil)
+				return repo, cRepo
+			},
+			cid:       3,
+			userId:    1,
+			wantEvent: true,
+		},
+		{
+			name: "已经收藏过，只是移动收藏夹",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.CollectionRepository) {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				cRepo := repomocks.NewMockCollectionRepository(ctrl)
+				cRepo.EXPECT().FindById(gomock.Any(), int64(3)).Return(domain.Collection{Id: 3, Uid: 1}, nil)
+				repo.EXPECT().InsertCollection(gomock.Any(), "article", int64(2), int64(3), int64(1)).Return(false, nil)
+				return repo, cRepo
+			},
+			cid:    3,
+			userId: 1,
+		},
+		{
+			name: "收藏夹属于其他用户",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.CollectionRepository) {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				cRepo := repomocks.NewMockCollectionRepository(ctrl)
+				cRepo.EXPECT().FindById(gomock.Any(), int64(3)).Return(domain.Collection{Id: 3, Uid: 4}, nil)
+				return repo, cRepo
+			},
+			cid:     3,
+			userId:  1,
+			wantErr: ErrCollectionNotFound,
+		},
+		{
+			name: "收藏夹不存在",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.CollectionRepository) {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				cRepo := repomocks.NewMockCollectionRepository(ctrl)
+				cRepo.EXPECT().FindById(gomock.Any(), int64(3)).Return(domain.Collection{}, ErrCollectionNotFound)
+				return repo, cRepo
+			},
+			cid:     3,
+			userId:  1,
+			wantErr: ErrCollectionNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, cRepo := tc.mock(ctrl)
+			producer := eventmocks.NewMockProducer[domain.NotificationEvent](ctrl)
+			eventProducer := eventmocks.NewMockProducer[domain.InteractiveEvent](ctrl)
+			if tc.wantEvent {
+				producer.EXPECT().Produce(gomock.Any(), domain.NotificationEvent{
+					Type:  domain.NotificationTypeCollect,
+					Actor: tc.userId,
+					Biz:   "article",
+					BizId: 2,
+				}).Return(nil)
+				eventProducer.EXPECT().Produce(gomock.Any(), gomock.Cond(func(evt domain.InteractiveEvent) bool {
+					return evt.Type == domain.InteractiveEventCollect && evt.Uid == tc.userId &&
+						evt.Biz == "article" && evt.BizId == 2
+				})).Return(nil)
+			}
+			svc := NewInteractiveService(repo, cRepo, nil, producer, nil, nil, eventProducer, nil)
+			err := svc.Collect(context.Background(), "article", 2, tc.cid, tc.userId)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestInteractiveService_Uncollect(t *testing.T) {
+	testCases := []struct {
+		name    string
+		deleted bool
+	}{
+		{
+			name:    "取消收藏",
+			deleted: true,
+		},
+		{
+			name: "没有收藏过",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomocks.NewMockInteractiveRepository(ctrl)
+			repo.EXPECT().DeleteCollection(gomock.Any(), "article", int64(2), int64(1)).Return(tc.deleted, nil)
+			eventProducer := eventmocks.NewMockProducer[domain.InteractiveEvent](ctrl)
+			if tc.deleted {
+				eventProducer.EXPECT().Produce(gomock.Any(), gomock.Cond(func(evt domain.InteractiveEvent) bool {
+					return evt.Type == domain.InteractiveEventUncollect && evt.BizId == 2
+				})).Return(nil)
+			}
+			svc := NewInteractiveService(repo, nil, nil, nil, nil, nil, eventProducer, nil)
+			assert.NoError(t, svc.Uncollect(context.Background(), "article", 2, 1))
+		})
+	}
+}
+
+func TestCollectionService_ListArticles(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CollectionRepository, article.ArticleRepository)
+
+		userId   int64
+		wantArts []domain.Article
+		wantErr  error
+	}{
+		{
+			name: "查询自己的收藏夹",
+			mock: func(ctrl *gomock.Controller) (repository.CollectionRepository, article.ArticleRepository) {
+				cRepo := repomocks.NewMockCollectionRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				cRepo.EXPECT().FindById(gomock.Any(), int64(3)).Return(domain.Collection{Id: 3, Uid: 1}, nil)
+				cRepo.EXPECT().FindItemIds(gomock.Any(), int64(3), 0, 10).Return([]int64{5, 4}, nil)
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{5, 4}).
+					Return([]domain.Article{{Id: 5}, {Id: 4}}, nil)
+				return cRepo, artRepo
+			},
+			userId:   1,
+			wantArts: []domain.Article{{Id: 5}, {Id: 4}},
+		},
+		{
+			name: "不能查询别人的收藏夹",
+			mock: func(ctrl *gomock.Controller) (repository.CollectionRepository, article.ArticleRepository) {
+				cRepo := repomocks.NewMockCollectionRepository(ctrl)
+				cRepo.EXPECT().FindById(gomock.Any(), int64(3)).Return(domain.Collection{Id: 3, Uid: 2}, nil)
+				return cRepo, artrepomocks.NewMockArticleRepository(ctrl)
+			},
+			userId:  1,
+			wantErr: ErrCollectionNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cRepo, artRepo := tc.mock(ctrl)
+			svc := NewCollectionService(cRepo, artRepo, nil, nil)
+			arts, err := svc.ListArticles(context.Background(), 3, tc.userId, 0, 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArts, arts)
+		})
+	}
+}
+
+func TestCollectionService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cRepo := repomocks.NewMockCollectionRepository(ctrl)
+	cRepo.EXPECT().Delete(gomock.Any(), int64(3), int64(1)).
+		Return([]domain.CollectionItem{{Biz: "article", BizId: 2}, {Biz: "article", BizId: 5}}, nil)
+	producer := eventmocks.NewMockProducer[domain.InteractiveEvent](ctrl)
+	// 被取消的收藏和取消收藏一样发送事件
+	for _, bizId := range []int64{2, 5} {
+		producer.EXPECT().Produce(gomock.Any(), gomock.Cond(func(evt domain.InteractiveEvent) bool {
+			return evt.Type == domain.InteractiveEventUncollect && evt.Uid == 1 && evt.BizId == bizId
+		})).Return(nil)
+	}
+
+	svc := NewCollectionService(cRepo, nil, producer, nil)
+	assert.NoError(t, svc.Delete(context.Background(), 3, 1))
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type CommentService interface {
+	// Create 发表评论，ParentId 不为 0 时是回复
+	Create(ctx context.Context, c domain.Comment) (int64, error)
+	// Delete 删除评论，评论者本人和文章作者都可以删除，返回删除的条数（包括回复）
+	Delete(ctx context.Context, userId int64, id int64) (int64, error)
+	ListRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]domain.Comment, error)
+	ListReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]domain.Comment, error)
+}
+
+var (
+	ErrCommentNotFound      = repository.ErrCommentNotFound
+	ErrCommentEmpty         = errors.New("评论内容不能为空")
+	ErrCommentTooLong       = errors.New("评论内容超过长度限制")
+	ErrCommentBizNotFound   = errors.New("评论的对象不存在")
+	ErrCommentNoPermission  = errors.New("您无权限删除该评论")
+	ErrCommentBizNotSupport = errors.New("不支持评论该业务")
+)
+
+const (
+	commentBizArticle = "article"
+	maxCommentLength  = 1000
+)
+
+type commentService struct {
+	repo    repository.CommentRepository
+	artRepo article.ArticleRepository
+	// 评论后通知文章作者，回复后通知被回复的人
+	producer events.Producer[domain.NotificationEvent]
+	// 评论、删除评论的事件，用于更新榜单
+	eventProducer events.Producer[domain.InteractiveEvent]
+	logger        logger.Logger
+}
+
+func NewCommentService(repo repository.CommentRepository, artRepo article.ArticleRepository,
+	producer events.Producer[domain.NotificationEvent], eventProducer events.Producer[domain.InteractiveEvent],
+	l logger.Logger) CommentService {
+	return &commentService{
+		repo:          repo,
+		artRepo:       artRepo,
+		producer:      producer,
+		eventProducer: eventProducer,
+		logger:        l,
+	}
+}
+
+func (s *commentService) Create(ctx context.Context, c domain.Comment) (int64, error) {
+	c.Content = strings.TrimSpace(c.Content)
+	if c.Content == "" {
+		return 0, ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(c.Content) > maxCommentLength {
+		return 0, ErrCommentTooLong
+	}
+
+	// 只能评论已发表的文章
+	art, err := s.findBiz(ctx, c.Biz, c.BizId)
+	if err != nil {
+		return 0, err
+	}
+	if art.Status != domain.ArticleStatusPublished {
+		return 0, ErrCommentBizNotFound
+	}
+
+	evt := domain.NotificationEvent{
+		Type:     domain.NotificationTypeComment,
+		Actor:    c.Commentator.Id,
+		Receiver: art.Author.Id,
+		Biz:      c.Biz,
+		BizId:    c.BizId,
+		BizTitle: art.Title,
+		Content:  c.Content,
+	}
+
+	// 回复：挂到被回复评论所在的根评论下
+	c.RootId = 0
+	if c.ParentId > 0 {
+		parent, err := s.repo.FindById(ctx, c.ParentId)
+		if err != nil {
+			return 0, err
+		}
+		if parent.Biz != c.Biz || parent.BizId != c.BizId {
+			return 0, ErrCommentNotFound
+		}
+		c.RootId = parent.RootId
+		if parent.IsRoot() {
+			c.RootId = parent.Id
+		}
+		evt.Type = domain.NotificationTypeReply
+		evt.Receiver = parent.Commentator.Id
+	}
+
+	id, err := s.repo.Create(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+	produceNotification(ctx, s.producer, s.logger, evt)
+	s.produceEvent(ctx, domain.InteractiveEventComment, c, 1)
+	return id, nil
+}
+
+func (s *commentService) Delete(ctx context.Context, userId int64, id int64) (int64, error) {
+	c, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	// 不是评论者本人，检查是否是文章作者
+	if c.Commentator.Id != userId {
+		art, err := s.findBiz(ctx, c.Biz, c.BizId)
+		if err != nil {
+			return 0, err
+		}
+		if art.Author.Id != userId {
+			return 0, ErrCommentNoPermission
+		}
+	}
+	cnt, err := s.repo.Delete(ctx, c)
+	if err != nil || cnt == 0 {
+		return cnt, err
+	}
+	s.produceEvent(ctx, domain.InteractiveEventUncomment, c, cnt)
+	return cnt, nil
+}
+
+// produceEvent 发送评论事件，失败不影响业务，只记录日志
+func (s *commentService) produceEvent(ctx context.Context, typ domain.InteractiveEventType, c domain.Comment, cnt int64) {
+	err := s.eventProducer.Produce(ctx, domain.InteractiveEvent{
+		Type:  typ,
+		Uid:   c.Commentator.Id,
+		Biz:   c.Biz,
+		BizId: c.BizId,
+		Cnt:   cnt,
+		Time:  time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("发送评论事件失败",
+			logger.String("type", string(typ)),
+			logger.String("biz", c.Biz),
+			logger.Int64("bizId", c.BizId),
+			logger.Error(err),
+		)
+	}
+}
+
+func (s *commentService) ListRoots(ctx context.Context, biz string, bizId int64, maxId int64, limit int) ([]domain.Comment, error) {
+	return s.repo.FindRoots(ctx, biz, bizId, maxId, limit)
+}
+
+func (s *commentService) ListReplies(ctx context.Context, rootId int64, minId int64, limit int) ([]domain.Comment, error) {
+	return s.repo.FindReplies(ctx, rootId, minId, limit)
+}
+
+// findBiz 查询被评论的对象，目前只支持文章
+func (s *commentService) findBiz(ctx context.Context, biz string, bizId int64) (domain.Article, error) {
+	if biz != commentBizArticle {
+		return domain.Article{}, ErrCommentBizNotSupport
+	}
+	art, err := s.artRepo.FindById(ctx, bizId)
+	if err == article.ErrArticleNotFound {
+		return domain.Article{}, ErrCommentBizNotFound
+	}
+	return art, err
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	artrepomocks "Webook/webook/internal/repository/article/mocks"
+	repomocks "Webook/webook/internal/repository/mocks"
+	eventmocks "Webook/webook/pkg/events/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCommentService_Create(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository)
+
+		comment   domain.Comment
+		wantId    int64
+		wantErr   error
+		wantEvent *domain.NotificationEvent
+	}{
+		{
+			name: "回复的回复挂在根评论下",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Title:  "标题",
+					Author: domain.Author{Id: 5},
+					Status: domain.ArticleStatusPublished,
+				}, nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).Return(domain.Comment{
+					Id:          11,
+					Biz:         "article",
+					BizId:       1,
+					RootId:      10,
+					Commentator: domain.Commentator{Id: 3},
+				}, nil)
+				repo.EXPECT().Create(gomock.Any(), domain.Comment{
+					Biz:         "article",
+					BizId:       1,
+					Content:     "回复",
+					RootId:      10,
+					ParentId:    11,
+					Commentator: domain.Commentator{Id: 2},
+				}).Return(int64(12), nil)
+				return repo, artRepo
+			},
+			comment: domain.Comment{
+				Biz:         "article",
+				BizId:       1,
+				Content:     " 回复 ",
+				ParentId:    11,
+				Commentator: domain.Commentator{Id: 2},
+			},
+			wantId: 12,
+			// 通知被回复的人，而不是文章作者
+			wantEvent: &domain.NotificationEvent{
+				Type:     domain.NotificationTypeReply,
+				Actor:    2,
+				Receiver: 3,
+				Biz:      "article",
+				BizId:    1,
+				BizTitle: "标题",
+				Content:  "回复",
+			},
+		},
+		{
+			name: "被回复的评论不属于同一篇文章",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Status: domain.ArticleStatusPublished,
+				}, nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).Return(domain.Comment{
+					Id:    11,
+					Biz:   "article",
+					BizId: 2,
+				}, nil)
+				return repo, artRepo
+			},
+			comment: domain.Comment{
+				Biz:      "article",
+				BizId:    1,
+				Content:  "回复",
+				ParentId: 11,
+			},
+			wantErr: ErrCommentNotFound,
+		},
+		{
+			name: "文章未发表",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Status: domain.ArticleStatusUnpublished,
+				}, nil)
+				return repo, artRepo
+			},
+			comment: domain.Comment{
+				Biz:     "article",
+				BizId:   1,
+				Content: "评论",
+			},
+			wantErr: ErrCommentBizNotFound,
+		},
+		{
+			name: "评论内容为空",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository) {
+				return repomocks.NewMockCommentRepository(ctrl), artrepomocks.NewMockArticleRepository(ctrl)
+			},
+			comment: domain.Comment{
+				Biz:     "article",
+				BizId:   1,
+				Content: "  ",
+			},
+			wantErr: ErrCommentEmpty,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo := tc.mock(ctrl)
+			producer := eventmocks.NewMockProducer[domain.NotificationEvent](ctrl)
+			eventProducer := eventmocks.NewMockProducer[domain.InteractiveEvent](ctrl)
+			if tc.wantEvent != nil {
+				producer.EXPECT().Produce(gomock.Any(), *tc.wantEvent).Return(nil)
+				// 评论计入榜单
+				eventProducer.EXPECT().Produce(gomock.Any(), gomock.Cond(func(evt domain.InteractiveEvent) bool {
+					return evt.Type == domain.InteractiveEventComment && evt.BizId == tc.comment.BizId && evt.Cnt == 1
+				})).Return(nil)
+			}
+			svc := NewCommentService(repo, artRepo, producer, eventProducer, nil)
+			id, err := svc.Create(context.Background(), tc.comment)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func TestCommentService_Delete(t *testing.T) {
+	comment := domain.Comment{
+		Id:          10,
+		Biz:         "article",
+		BizId:       1,
+		Commentator: domain.Commentator{Id: 2},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository)
+
+		userId  int64
+		wantCnt int64
+		wantErr error
+	}{
+		{
+			name: "评论者本人删除",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(comment, nil)
+				repo.EXPECT().Delete(gomock.Any(), comment).Return(int64(3), nil)
+				return repo, artrepomocks.NewMockArticleRepository(ctrl)
+			},
+			userId:  2,
+			wantCnt: 3,
+		},
+		{
+			name: "文章作者删除",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(comment, nil)
+				artRepo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Author: domain.Author{Id: 3},
+				}, nil)
+				repo.EXPECT().Delete(gomock.Any(), comment).Return(int64(1), nil)
+				return repo, artRepo
+			},
+			userId:  3,
+			wantCnt: 1,
+		},
+		{
+			name: "其他用户无权删除",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(comment, nil)
+				artRepo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Author: domain.Author{Id: 3},
+				}, nil)
+				return repo, artRepo
+			},
+			userId:  4,
+			wantErr: ErrCommentNoPermission,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo := tc.mock(ctrl)
+			eventProducer := eventmocks.NewMockProducer[domain.InteractiveEvent](ctrl)
+			if tc.wantCnt > 0 {
+				// 连同回复一起从榜单中减分
+				eventProducer.EXPECT().Produce(gomock.Any(), gomock.Cond(func(evt domain.InteractiveEvent) bool {
+					return evt.Type == domain.InteractiveEventUncomment && evt.BizId == 1 && evt.Cnt == tc.wantCnt
+				})).Return(nil)
+			}
+			svc := NewCommentService(repo, artRepo, nil, eventProducer, nil)
+			cnt, err := svc.Delete(context.Background(), tc.userId, 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoCronJob             = repository.ErrNoCronJob
+	ErrCronJobNotHeld        = repository.ErrCronJobNotHeld
+	ErrCronJobNotFound       = repository.ErrCronJobNotFound
+	ErrCronJobStatusConflict = repository.ErrCronJobStatusConflict
+	ErrCronExpressionInvalid = errors.New("cron 表达式不合法")
+)
+
+type CronJobService interface {
+	// Register 注册任务，已经存在时更新执行器、配置和 cron 表达式
+	Register(ctx context.Context, j domain.CronJob) error
+	// Preempt 抢占一个可以执行的任务，没有时返回 ErrNoCronJob。
+	// 抢到后定时续约，续约时发现任务已经不归本节点所有会取消 Ctx，执行完调用 CancelFunc 停止续约并释放任务
+	Preempt(ctx context.Context) (domain.CronJob, error)
+
+	// StartExecution 记录一次执行的开始，返回执行记录的 id
+	StartExecution(ctx context.Context, j domain.CronJob) (int64, error)
+	// FinishExecution 记录执行结果，execErr 为 nil 表示执行成功
+	FinishExecution(ctx context.Context, id int64, execErr error) error
+
+	List(ctx context.Context) ([]domain.CronJob, error)
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64) error
+	// Trigger 让等待中的任务马上执行一次，之后按原来的调度执行
+	Trigger(ctx context.Context, id int64) error
+	UpdateExpression(ctx context.Context, id int64, expression string) error
+	// Executions 最近的执行记录
+	Executions(ctx context.Context, jobId int64, offset int, limit int) ([]domain.CronJobExecution, error)
+	// CleanExecutions 分批删除开始时间早于 before 的执行记录，返回删除的条数
+	CleanExecutions(ctx context.Context, before time.Time) (int64, error)
+}
+
+type cronJobService struct {
+	repo   repository.CronJobRepository
+	logger logger.Logger
+	// 本节点的标识
+	owner string
+
+	refreshInterval time.Duration
+	// 超过这个时间没有续约，任务可以被其他节点接手
+	heartbeatTimeout time.Duration
+	timeout          time.Duration
+	// 执行失败原因的最大长度
+	maxErrLen int
+	// 释放时 cron 表达式被并发修改，最多重新计算几次
+	releaseRetries int
+	// 清理执行记录时每批删除的条数
+	cleanBatchSize int
+}
+
+func NewCronJobService(repo repository.CronJobRepository, owner string, l logger.Logger) CronJobService {
+	return &cronJobService{
+		repo:             repo,
+		logger:           l,
+		owner:            owner,
+		refreshInterval:  time.Second * 10,
+		heartbeatTimeout: time.Minute,
+		timeout:          time.Second,
+		maxErrLen:        1024,
+		releaseRetries:   3,
+		cleanBatchSize:   1000,
+	}
+}
+
+func (s *cronJobService) Register(ctx context.Context, j domain.CronJob) error {
+	next, err := j.Next(time.Now())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCronExpressionInvalid, err)
+	}
+	j.Status = domain.CronJobStatusWaiting
+	j.NextTime = next
+	return s.repo.Upsert(ctx, j)
+}
+
+func (s *cronJobService) Preempt(ctx context.Context) (domain.CronJob, error) {
+	j, err := s.repo.Preempt(ctx, s.owner, s.heartbeatTimeout)
+	if err != nil {
+		return domain.CronJob{}, err
+	}
+
+	// 执行完之前不随 ctx 取消，只在失去任务时取消
+	jobCtx, cancelJob := context.WithCancel(context.Background())
+	j.Ctx = jobCtx
+	ticker := time.NewTicker(s.refreshInterval)
+	stop := make(chan struct{})
+	go func(j domain.CronJob) {
+		// 最近一次续约成功的时间，抢占也算一次
+		last := time.Now()
+		for {
+			select {
+			case <-ticker.C:
+				var held bool
+				last, held = s.heartbeat(j, last)
+				if !held {
+					s.logger.Error("定时任务已经不归本节点所有，取消执行",
+						logger.String("name", j.Name),
+						logger.Int64("version", j.Version),
+					)
+					cancelJob()
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}(j)
+
+	var once sync.Once
+	j.CancelFunc = func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(stop)
+			cancelJob()
+			s.release(j)
+		})
+	}
+	return j, nil
+}
+
+// heartbeat 续约，返回最近一次续约成功的时间和任务是否还归本节点所有。
+// 任务已经被其他节点接手，或者超过 heartbeatTimeout 没有续约成功（其他节点随时可以接手），都视为失去任务
+func (s *cronJobService) heartbeat(j domain.CronJob, last time.Time) (time.Time, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	err := s.repo.Heartbeat(ctx, j.Id, j.Version)
+	if err == nil {
+		return time.Now(), true
+	}
+	held := !errors.Is(err, ErrCronJobNotHeld) && time.Since(last) < s.heartbeatTimeout
+	s.logger.Error("定时任务续约失败",
+		logger.String("name", j.Name),
+		logger.Int64("version", j.Version),
+		logger.Error(err),
+	)
+	return last, held
+}
+
+func (s *cronJobService) release(j domain.CronJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := s.doRelease(ctx, j); err != nil {
+		s.logger.Error("释放定时任务失败",
+			logger.String("name", j.Name),
+			logger.Int64("version", j.Version),
+			logger.Error(err),
+		)
+	}
+}
+
+// doRelease 执行期间 cron 表达式可能被修改过，按数据库中的表达式计算下一次执行时间。
+// 释放前表达式又被修改了就重新读一次
+func (s *cronJobService) doRelease(ctx context.Context, j domain.CronJob) error {
+	for i := 0; i < s.releaseRetries; i++ {
+		cur, err := s.repo.FindById(ctx, j.Id)
+		if err != nil {
+			return err
+		}
+		if cur.Version != j.Version || cur.Status != domain.CronJobStatusRunning {
+			return ErrCronJobNotHeld
+		}
+		next, err := cur.Next(time.Now())
+		if err != nil {
+			return fmt.Errorf("计算下次执行时间失败 %s: %w", cur.Expression, err)
+		}
+		err = s.repo.Release(ctx, j.Id, j.Version, cur.Expression, next)
+		if !errors.Is(err, ErrCronJobNotHeld) {
+			return err
+		}
+	}
+	return ErrCronJobNotHeld
+}
+
+func (s *cronJobService) StartExecution(ctx context.Context, j domain.CronJob) (int64, error) {
+	return s.repo.AddExecution(ctx, domain.CronJobExecution{
+		JobId:     j.Id,
+		Name:      j.Name,
+		Owner:     j.Owner,
+		Version:   j.Version,
+		Status:    domain.CronJobExecutionStatusRunning,
+		StartTime: time.Now(),
+	})
+}
+
+func (s *cronJobService) FinishExecution(ctx context.Context, id int64, execErr error) error {
+	if execErr == nil {
+		return s.repo.FinishExecution(ctx, id, domain.CronJobExecutionStatusSucceeded, "")
+	}
+	msg := []rune(execErr.Error())
+	if len(msg) > s.maxErrLen {
+		msg = msg[:s.maxErrLen]
+	}
+	return s.repo.FinishExecution(ctx, id, domain.CronJobExecutionStatusFailed, string(msg))
+}
+
+func (s *cronJobService) List(ctx context.Context) ([]domain.CronJob, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *cronJobService) Pause(ctx context.Context, id int64) error {
+	return s.repo.Pause(ctx, id)
+}
+
+func (s *cronJobService) Resume(ctx context.Context, id int64) error {
+	j, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 暂停期间错过的执行不补
+	next, err := j.Next(time.Now())
+	if err != nil {
+		return err
+	}
+	return s.repo.Resume(ctx, id, next)
+}
+
+func (s *cronJobService) Trigger(ctx context.Context, id int64) error {
+	return s.repo.Trigger(ctx, id)
+}
+
+func (s *cronJobService) UpdateExpression(ctx context.Context, id int64, expression string) error {
+	next, err := domain.CronJob{Expression: expression}.Next(time.Now())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCronExpressionInvalid, err)
+	}
+	return s.repo.UpdateExpression(ctx, id, expression, next)
+}
+
+func (s *cronJobService) Executions(ctx context.Context, jobId int64, offset int, limit int) ([]domain.CronJobExecution, error) {
+	return s.repo.ListExecutions(ctx, jobId, offset, limit)
+}
+
+func (s *cronJobService) CleanExecutions(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	// 分批删除，避免一次删除太多行长时间锁表
+	for ctx.Err() == nil {
+		cnt, err := s.repo.DeleteExecutionsBefore(ctx, before, s.cleanBatchSize)
+		total += cnt
+		if err != nil || cnt < int64(s.cleanBatchSize) {
+			return total, err
+		}
+	}
+	return total, ctx.Err()
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	repomocks "Webook/webook/internal/repository/mocks"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestCronJobService_Register(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+
+		expression string
+		wantErr    bool
+	}{
+		{
+			name: "注册成功",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, j domain.CronJob) error {
+						assert.Equal(t, domain.CronJobStatusWaiting, j.Status)
+						assert.WithinDuration(t, time.Now().Add(time.Minute), j.NextTime, time.Second)
+						return nil
+					})
+				return repo
+			},
+			expression: "@every 1m",
+		},
+		{
+			name: "cron 表达式不合法",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				return repomocks.NewMockCronJobRepository(ctrl)
+			},
+			expression: "abc",
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := NewCronJobService(tc.mock(ctrl), "node", logger.NewZapLogger(zap.NewNop()))
+			err := svc.Register(context.Background(), domain.CronJob{
+				Name:       "mock",
+				Executor:   "local",
+				Expression: tc.expression,
+			})
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestCronJobService_Preempt(t *testing.T) {
+	t.Run("执行期间续约，结束后释放", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := repomocks.NewMockCronJobRepository(ctrl)
+		repo.EXPECT().Preempt(gomock.Any(), "node", time.Minute).
+			Return(domain.CronJob{Id: 1, Name: "mock", Expression: "@every 1m", Version: 2}, nil)
+		repo.EXPECT().Heartbeat(gomock.Any(), int64(1), int64(2)).Return(nil).MinTimes(1)
+		// 调用多次只释放一次
+		repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.CronJob{Id: 1, Name: "mock", Expression: "@every 1m", Version: 2, Status: domain.CronJobStatusRunning}, nil)
+		repo.EXPECT().Release(gomock.Any(), int64(1), int64(2), "@every 1m", gomock.Any()).Return(nil)
+
+		svc := NewCronJobService(repo, "node", logger.NewZapLogger(zap.NewNop())).(*cronJobService)
+		svc.refreshInterval = time.Millisecond * 10
+		j, err := svc.Preempt(context.Background())
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond * 50)
+		assert.NoError(t, j.Ctx.Err())
+		j.CancelFunc()
+		j.CancelFunc()
+		assert.Error(t, j.Ctx.Err())
+	})
+
+	t.Run("任务被其他节点接手，取消执行", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := repomocks.NewMockCronJobRepository(ctrl)
+		repo.EXPECT().Preempt(gomock.Any(), "node", time.Minute).
+			Return(domain.CronJob{Id: 1, Name: "mock", Expression: "@every 1m", Version: 2}, nil)
+		// 接手后不再续约
+		repo.EXPECT().Heartbeat(gomock.Any(), int64(1), int64(2)).Return(repository.ErrCronJobNotHeld)
+		// 版本号变了，不再释放
+		repo.EXPECT().FindById(gomock.Any(), int64(1)).
+			Return(domain.CronJob{Id: 1, Name: "mock", Expression: "@every 1m", Version: 3, Status: domain.CronJobStatusRunning}, nil)
+
+		svc := NewCronJobService(repo, "node", logger.NewZapLogger(zap.NewNop())).(*cronJobService)
+		svc.refreshInterval = time.Millisecond * 10
+		j, err := svc.Preempt(context.Background())
+		require.NoError(t, err)
+		select {
+		case <-j.Ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("没有取消执行")
+		}
+		j.CancelFunc()
+	})
+
+	t.Run("超过心跳超时没有续约成功，取消执行", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := repomocks.NewMockCronJobRepository(ctrl)
+		repo.EXPECT().Preempt(gomock.Any(), "node", time.Millisecond*30).
+			Return(domain.CronJob{Id: 1, Name: "mock", Expression: "@every 1m", Version: 2}, nil)
+		// 数据库超时，不知道任务还在不在，超过心跳超时后其他节点就可以接手了
+		repo.EXPECT().Heartbeat(gomock.Any(), int64(1), int64(2)).
+			Return(context.DeadlineExceeded).MinTimes(2)
+		repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.CronJob{Id: 1, Name: "mock", Expression: "@every 1m", Version: 2, Status: domain.CronJobStatusRunning}, nil)
+		repo.EXPECT().Release(gomock.Any(), int64(1), int64(2), "@every 1m", gomock.Any()).Return(nil)
+
+		svc := NewCronJobService(repo, "node", logger.NewZapLogger(zap.NewNop())).(*cronJobService)
+		svc.refreshInterval = time.Millisecond * 10
+		svc.heartbeatTimeout = time.Millisecond * 30
+		j, err := svc.Preempt(context.Background())
+		require.NoError(t, err)
+		select {
+		case <-j.Ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("没有取消执行")
+		}
+		j.CancelFunc()
+	})
+
+	t.Run("执行期间修改了表达式，按新的表达式释放", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := repomocks.NewMockCronJobRepository(ctrl)
+		repo.EXPECT().Preempt(gomock.Any(), "node", time.Minute).
+			Return(domain.CronJob{Id: 1, Name: "mock", Expression: "@every 1m", Version: 2}, nil)
+		gomock.InOrder(
+			repo.EXPECT().FindById(gomock.Any(), int64(1)).
+				Return(domain.CronJob{Id: 1, Expression: "@every 1h", Version: 2, Status: domain.CronJobStatusRunning}, nil),
+			// 释放前又改了一次
+			repo.EXPECT().Release(gomock.Any(), int64(1), int64(2), "@every 1h", gomock.Any()).
+				Return(repository.ErrCronJobNotHeld),
+			repo.EXPECT().FindById(gomock.Any(), int64(1)).
+				Return(domain.CronJob{Id: 1, Expression: "@every 2h", Version: 2, Status: domain.CronJobStatusRunning}, nil),
+			repo.EXPECT().Release(gomock.Any(), int64(1), int64(2), "@every 2h", gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id int64, version int64, expression string, next time.Time) error {
+					assert.WithinDuration(t, time.Now().Add(time.Hour*2), next, time.Second)
+					return nil
+				}),
+		)
+
+		svc := NewCronJobService(repo, "node", logger.NewZapLogger(zap.NewNop()))
+		j, err := svc.Preempt(context.Background())
+		require.NoError(t, err)
+		j.CancelFunc()
+	})
+
+	t.Run("没有可以执行的任务", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := repomocks.NewMockCronJobRepository(ctrl)
+		repo.EXPECT().Preempt(gomock.Any(), "node", time.Minute).
+			Return(domain.CronJob{}, repository.ErrNoCronJob)
+
+		svc := NewCronJobService(repo, "node", logger.NewZapLogger(zap.NewNop()))
+		_, err := svc.Preempt(context.Background())
+		assert.Equal(t, ErrNoCronJob, err)
+	})
+}
+
+func TestCronJobService_Resume(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+
+		wantErr error
+	}{
+		{
+			name: "按表达式重新计算下一次执行时间",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.CronJob{Id: 1, Expression: "@every 1h", Status: domain.CronJobStatusPaused}, nil)
+				repo.EXPECT().Resume(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, nextTime time.Time) error {
+						assert.WithinDuration(t, time.Now().Add(time.Hour), nextTime, time.Second)
+						return nil
+					})
+				return repo
+			},
+		},
+		{
+			name: "任务不是暂停状态",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.CronJob{Id: 1, Expression: "@every 1h", Status: domain.CronJobStatusWaiting}, nil)
+				repo.EXPECT().Resume(gomock.Any(), int64(1), gomock.Any()).
+					Return(repository.ErrCronJobStatusConflict)
+				return repo
+			},
+			wantErr: ErrCronJobStatusConflict,
+		},
+		{
+			name: "任务不存在",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.CronJob{}, repository.ErrCronJobNotFound)
+				return repo
+			},
+			wantErr: ErrCronJobNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := NewCronJobService(tc.mock(ctrl), "node", logger.NewZapLogger(zap.NewNop()))
+			err := svc.Resume(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestCronJobService_UpdateExpression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repomocks.NewMockCronJobRepository(ctrl)
+	repo.EXPECT().UpdateExpression(gomock.Any(), int64(1), "0 */5 * * * *", gomock.Any()).Return(nil)
+
+	svc := NewCronJobService(repo, "node", logger.NewZapLogger(zap.NewNop()))
+	assert.NoError(t, svc.UpdateExpression(context.Background(), 1, "0 */5 * * * *"))
+	err := svc.UpdateExpression(context.Background(), 1, "abc")
+	assert.ErrorIs(t, err, ErrCronExpressionInvalid)
+}
+
+func TestCronJobService_FinishExecution(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repomocks.NewMockCronJobRepository(ctrl)
+	repo.EXPECT().FinishExecution(gomock.Any(), int64(1), domain.CronJobExecutionStatusSucceeded, "").Return(nil)
+	// 失败原因过长时截断
+	repo.EXPECT().FinishExecution(gomock.Any(), int64(2), domain.CronJobExecutionStatusFailed, strings.Repeat("错", 1024)).Return(nil)
+
+	svc := NewCronJobService(repo, "node", logger.NewZapLogger(zap.NewNop()))
+	assert.NoError(t, svc.FinishExecution(context.Background(), 1, nil))
+	assert.NoError(t, svc.FinishExecution(context.Background(), 2, errors.New(strings.Repeat("错", 2000))))
+}
+
+func TestCronJobService_CleanExecutions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	before := time.Now().Add(-time.Hour * 24 * 7)
+	repo := repomocks.NewMockCronJobRepository(ctrl)
+	// 删满一批就继续删，直到不满一批
+	gomock.InOrder(
+		repo.EXPECT().DeleteExecutionsBefore(gomock.Any(), before, 2).Return(int64(2), nil),
+		repo.EXPECT().DeleteExecutionsBefore(gomock.Any(), before, 2).Return(int64(1), nil),
+	)
+	svc := NewCronJobService(repo, "node", logger.NewZapLogger(zap.NewNop())).(*cronJobService)
+	svc.cleanBatchSize = 2
+	cnt, err := svc.CleanExecutions(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), cnt)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	"context"
+	"sort"
+	"time"
+)
+
+// FeedService 关注的作者发表的文章组成的动态流，推拉结合：
+// 普通作者发表时写入粉丝的收件箱（推），粉丝很多的作者发表时只标记为拉模式，
+// 读的时候再从他们的发件箱（线上库）拉取，最后和收件箱合并
+type FeedService interface {
+	// Push 文章发表后分发给作者的粉丝
+	Push(ctx context.Context, art domain.Article) error
+	// Feed 按发表时间倒序的动态流，返回当前页的文章和下一页的游标，没有更多时游标为零值
+	// 撤回、删除的文章和已经取消关注的作者会被过滤掉，所以一页可能不足 limit 条
+	Feed(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.Article, domain.FeedCursor, error)
+}
+
+const (
+	// 粉丝数超过这个值的作者使用拉模式
+	feedPushThreshold = 1000
+	// 推模式下每批写入的粉丝数
+	feedPushBatchSize = 500
+)
+
+type feedService struct {
+	repo       repository.FeedRepository
+	followRepo repository.FollowRepository
+	artRepo    article.ArticleRepository
+}
+
+func NewFeedService(repo repository.FeedRepository, followRepo repository.FollowRepository,
+	artRepo article.ArticleRepository) FeedService {
+	return &feedService{
+		repo:       repo,
+		followRepo: followRepo,
+		artRepo:    artRepo,
+	}
+}
+
+func (s *feedService) Push(ctx context.Context, art domain.Article) error {
+	authorId := art.Author.Id
+	statics, err := s.followRepo.GetStatics(ctx, authorId)
+	if err != nil {
+		return err
+	}
+	if statics.Followers > feedPushThreshold {
+		return s.repo.AddPullAuthor(ctx, authorId)
+	}
+
+	now := time.Now()
+	for offset := 0; ; offset += feedPushBatchSize {
+		rs, err := s.followRepo.FindFollowers(ctx, authorId, offset, feedPushBatchSize)
+		if err != nil {
+			return err
+		}
+		uids := make([]int64, 0, len(rs))
+		for _, r := range rs {
+			uids = append(uids, r.Follower)
+		}
+		if len(uids) > 0 {
+			if err = s.repo.Push(ctx, art.Id, authorId, uids, now); err != nil {
+				return err
+			}
+		}
+		if len(rs) < feedPushBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *feedService) Feed(ctx context.Context, uid int64, cursor domain.FeedCursor, limit int) ([]domain.Article, domain.FeedCursor, error) {
+	inbox, err := s.repo.FindInbox(ctx, uid, cursor, limit)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+
+	// 关注了的拉模式作者
+	pullAuthors, err := s.repo.FindPullAuthors(ctx)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+	followed, err := s.followRepo.FollowedMap(ctx, uid, pullAuthors)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+	authorIds := make([]int64, 0, len(followed))
+	for _, id := range pullAuthors {
+		if followed[id] {
+			authorIds = append(authorIds, id)
+		}
+	}
+	outbox, err := s.artRepo.FindFeedOutbox(ctx, authorIds, cursor, limit)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+
+	items := mergeFeed(inbox, outbox, limit)
+	var next domain.FeedCursor
+	if len(items) == limit {
+		last := items[len(items)-1]
+		next = domain.FeedCursor{Time: last.Time, ArticleId: last.ArticleId}
+	}
+
+	// 收件箱里可能还有已经取消关注的作者的文章
+	authorIds = authorIds[:0]
+	for _, item := range items {
+		authorIds = append(authorIds, item.AuthorId)
+	}
+	followed, err = s.followRepo.FollowedMap(ctx, uid, authorIds)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+	artIds := make([]int64, 0, len(items))
+	for _, item := range items {
+		if followed[item.AuthorId] {
+			artIds = append(artIds, item.ArticleId)
+		}
+	}
+	arts, err := s.artRepo.FindPublishedArticlesByIds(ctx, artIds)
+	if err != nil {
+		return nil, domain.FeedCursor{}, err
+	}
+	return arts, next, nil
+}
+
+// mergeFeed 合并收件箱和发件箱，按 (Time, ArticleId) 倒序，同一篇文章只保留较新的一条
+func mergeFeed(inbox []domain.FeedItem, outbox []domain.FeedItem, limit int) []domain.FeedItem {
+	items := make([]domain.FeedItem, 0, len(inbox)+len(outbox))
+	items = append(items, inbox...)
+	items = append(items, outbox...)
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Time.Equal(items[j].Time) {
+			return items[i].Time.After(items[j].Time)
+		}
+		return items[i].ArticleId > items[j].ArticleId
+	})
+
+	res := make([]domain.FeedItem, 0, limit)
+	seen := make(map[int64]struct{}, len(items))
+	for _, item := range items {
+		if len(res) == limit {
+			break
+		}
+		if _, ok := seen[item.ArticleId]; ok {
+			continue
+		}
+		seen[item.ArticleId] = struct{}{}
+		res = append(res, item)
+	}
+	return res
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	artrepomocks "Webook/webook/internal/repository/article/mocks"
+	repomocks "Webook/webook/internal/repository/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFeedService_Push(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository)
+
+		wantErr error
+	}{
+		{
+			name: "普通作者写入粉丝收件箱",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().GetStatics(gomock.Any(), int64(1)).Return(domain.FollowStatics{Followers: 2}, nil)
+				followRepo.EXPECT().FindFollowers(gomock.Any(), int64(1), 0, feedPushBatchSize).
+					Return([]domain.FollowRelation{{Follower: 3, Followee: 1}, {Follower: 4, Followee: 1}}, nil)
+				repo.EXPECT().Push(gomock.Any(), int64(10), int64(1), []int64{3, 4}, gomock.Any()).Return(nil)
+				return repo, followRepo
+			},
+		},
+		{
+			name: "没有粉丝",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository) {
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().GetStatics(gomock.Any(), int64(1)).Return(domain.FollowStatics{}, nil)
+				followRepo.EXPECT().FindFollowers(gomock.Any(), int64(1), 0, feedPushBatchSize).
+					Return([]domain.FollowRelation{}, nil)
+				return repomocks.NewMockFeedRepository(ctrl), followRepo
+			},
+		},
+		{
+			name: "粉丝很多的作者使用拉模式",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().GetStatics(gomock.Any(), int64(1)).
+					Return(domain.FollowStatics{Followers: feedPushThreshold + 1}, nil)
+				repo.EXPECT().AddPullAuthor(gomock.Any(), int64(1)).Return(nil)
+				return repo, followRepo
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, followRepo := tc.mock(ctrl)
+			svc := NewFeedService(repo, followRepo, artrepomocks.NewMockArticleRepository(ctrl))
+			err := svc.Push(context.Background(), domain.Article{Id: 10, Author: domain.Author{Id: 1}})
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestFeedService_Feed(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository, article.ArticleRepository)
+
+		limit    int
+		wantArts []domain.Article
+		wantNext domain.FeedCursor
+		wantErr  error
+	}{
+		{
+			name: "合并收件箱和发件箱",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindInbox(gomock.Any(), int64(1), domain.FeedCursor{}, 3).Return([]domain.FeedItem{
+					{ArticleId: 5, AuthorId: 2, Time: now.Add(-time.Minute)},
+					{ArticleId: 3, AuthorId: 4, Time: now.Add(-3 * time.Minute)},
+				}, nil)
+				repo.EXPECT().FindPullAuthors(gomock.Any()).Return([]int64{7, 8}, nil)
+				followRepo.EXPECT().FollowedMap(gomock.Any(), int64(1), []int64{7, 8}).
+					Return(map[int64]bool{7: true}, nil)
+				// 作者 7 从普通作者变成了拉模式，文章 5 在收件箱和发件箱中都有
+				artRepo.EXPECT().FindFeedOutbox(gomock.Any(), []int64{7}, domain.FeedCursor{}, 3).Return([]domain.FeedItem{
+					{ArticleId: 6, AuthorId: 7, Time: now},
+					{ArticleId: 5, AuthorId: 2, Time: now.Add(-2 * time.Minute)},
+				}, nil)
+				// 已经取消关注了作者 2
+				followRepo.EXPECT().FollowedMap(gomock.Any(), int64(1), []int64{7, 2, 4}).
+					Return(map[int64]bool{7: true, 4: true}, nil)
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{6, 3}).
+					Return([]domain.Article{{Id: 6}, {Id: 3}}, nil)
+				return repo, followRepo, artRepo
+			},
+			limit:    3,
+			wantArts: []domain.Article{{Id: 6}, {Id: 3}},
+			wantNext: domain.FeedCursor{Time: now.Add(-3 * time.Minute), ArticleId: 3},
+		},
+		{
+			name: "最后一页",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				repo.EXPECT().FindInbox(gomock.Any(), int64(1), domain.FeedCursor{}, 3).Return([]domain.FeedItem{
+					{ArticleId: 5, AuthorId: 2, Time: now},
+				}, nil)
+				repo.EXPECT().FindPullAuthors(gomock.Any()).Return([]int64{}, nil)
+				followRepo.EXPECT().FollowedMap(gomock.Any(), int64(1), []int64{}).Return(map[int64]bool{}, nil)
+				artRepo.EXPECT().FindFeedOutbox(gomock.Any(), []int64{}, domain.FeedCursor{}, 3).Return([]domain.FeedItem{}, nil)
+				followRepo.EXPECT().FollowedMap(gomock.Any(), int64(1), []int64{2}).Return(map[int64]bool{2: true}, nil)
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{5}).
+					Return([]domain.Article{{Id: 5}}, nil)
+				return repo, followRepo, artRepo
+			},
+			limit:    3,
+			wantArts: []domain.Article{{Id: 5}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewFeedService(tc.mock(ctrl))
+			arts, next, err := svc.Feed(context.Background(), 1, domain.FeedCursor{}, tc.limit)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArts, arts)
+			assert.Equal(t, tc.wantNext, next)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+)
+
+type FollowService interface {
+	Follow(ctx context.Context, follower int64, followee int64) error
+	Unfollow(ctx context.Context, follower int64, followee int64) error
+	// Followers 粉丝列表
+	Followers(ctx context.Context, uid int64, offset int, limit int) ([]domain.FollowRelation, error)
+	// Followees 关注列表
+	Followees(ctx context.Context, uid int64, offset int, limit int) ([]domain.FollowRelation, error)
+	// Info uid 和 target 之间的关注状态，包括是否互相关注
+	Info(ctx context.Context, uid int64, target int64) (domain.FollowInfo, error)
+	// FollowedMap 批量查询 uid 是否关注了 targets 中的用户，用于文章列表
+	FollowedMap(ctx context.Context, uid int64, targets []int64) (map[int64]bool, error)
+	Statics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+var (
+	ErrFollowSelf       = errors.New("不能关注自己")
+	ErrFolloweeNotFound = errors.New("关注的用户不存在")
+)
+
+type followService struct {
+	repo     repository.FollowRepository
+	userRepo repository.UserRepository
+	// 关注后通知被关注的人
+	producer events.Producer[domain.NotificationEvent]
+	logger   logger.Logger
+}
+
+func NewFollowService(repo repository.FollowRepository, userRepo repository.UserRepository,
+	producer events.Producer[domain.NotificationEvent], l logger.Logger) FollowService {
+	return &followService{
+		repo:     repo,
+		userRepo: userRepo,
+		producer: producer,
+		logger:   l,
+	}
+}
+
+func (s *followService) Follow(ctx context.Context, follower int64, followee int64) error {
+	if follower == followee {
+		return ErrFollowSelf
+	}
+	// 被关注的用户必须存在
+	_, err := s.userRepo.FindById(ctx, followee)
+	if err == repository.ErrUserNotFound {
+		return ErrFolloweeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	changed, err := s.repo.Follow(ctx, follower, followee)
+	if err != nil {
+		return err
+	}
+	// 已经关注过，不重复通知
+	if !changed {
+		return nil
+	}
+	// 所有关注聚合成一条 "N 个人关注了你"
+	produceNotification(ctx, s.producer, s.logger, domain.NotificationEvent{
+		Type:     domain.NotificationTypeFollow,
+		Actor:    follower,
+		Receiver: followee,
+		Biz:      "user",
+		BizId:    followee,
+	})
+	return nil
+}
+
+func (s *followService) Unfollow(ctx context.Context, follower int64, followee int64) error {
+	return s.repo.Unfollow(ctx, follower, followee)
+}
+
+func (s *followService) Followers(ctx context.Context, uid int64, offset int, limit int) ([]domain.FollowRelation, error) {
+	return s.repo.FindFollowers(ctx, uid, offset, limit)
+}
+
+func (s *followService) Followees(ctx context.Context, uid int64, offset int, limit int) ([]domain.FollowRelation, error) {
+	return s.repo.FindFollowees(ctx, uid, offset, limit)
+}
+
+func (s *followService) Info(ctx context.Context, uid int64, target int64) (domain.FollowInfo, error) {
+	if uid == target {
+		return domain.FollowInfo{}, nil
+	}
+	followed, err := s.repo.FollowedMap(ctx, uid, []int64{target})
+	if err != nil {
+		return domain.FollowInfo{}, err
+	}
+	followedBy, err := s.repo.FollowedMap(ctx, target, []int64{uid})
+	if err != nil {
+		return domain.FollowInfo{}, err
+	}
+	return domain.FollowInfo{
+		Followed:   followed[target],
+		FollowedBy: followedBy[uid],
+	}, nil
+}
+
+func (s *followService) FollowedMap(ctx context.Context, uid int64, targets []int64) (map[int64]bool, error) {
+	return s.repo.FollowedMap(ctx, uid, targets)
+}
+
+func (s *followService) Statics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	return s.repo.GetStatics(ctx, uid)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	repomocks "Webook/webook/internal/repository/mocks"
+	eventmocks "Webook/webook/pkg/events/mocks"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFollowService_Follow(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository)
+
+		follower   int64
+		followee   int64
+		wantNotify bool
+		wantErr    error
+	}{
+		{
+			name: "关注成功",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2}, nil)
+				repo.EXPECT().Follow(gomock.Any(), int64(1), int64(2)).Return(true, nil)
+				return repo, userRepo
+			},
+			follower:   1,
+			followee:   2,
+			wantNotify: true,
+		},
+		{
+			name: "已经关注过，不重复通知",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{Id: 2}, nil)
+				repo.EXPECT().Follow(gomock.Any(), int64(1), int64(2)).Return(false, nil)
+				return repo, userRepo
+			},
+			follower: 1,
+			followee: 2,
+		},
+		{
+			name: "不能关注自己",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				return repomocks.NewMockFollowRepository(ctrl), repomocks.NewMockUserRepository(ctrl)
+			},
+			follower: 1,
+			followee: 1,
+			wantErr:  ErrFollowSelf,
+		},
+		{
+			name: "被关注的用户不存在",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{}, repository.ErrUserNotFound)
+				return repomocks.NewMockFollowRepository(ctrl), userRepo
+			},
+			follower: 1,
+			followee: 2,
+			wantErr:  ErrFolloweeNotFound,
+		},
+		{
+			name: "查询用户出错",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{}, errors.New("mock db error"))
+				return repomocks.NewMockFollowRepository(ctrl), userRepo
+			},
+			follower: 1,
+			followee: 2,
+			wantErr:  errors.New("mock db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, userRepo := tc.mock(ctrl)
+			producer := eventmocks.NewMockProducer[domain.NotificationEvent](ctrl)
+			if tc.wantNotify {
+				producer.EXPECT().Produce(gomock.Any(), domain.NotificationEvent{
+					Type:     domain.NotificationTypeFollow,
+					Actor:    tc.follower,
+					Receiver: tc.followee,
+					Biz:      "user",
+					BizId:    tc.followee,
+				}).Return(nil)
+			}
+			svc := NewFollowService(repo, userRepo, producer, nil)
+			err := svc.Follow(context.Background(), tc.follower, tc.followee)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestFollowService_Info(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.FollowRepository
+
+		target   int64
+		wantInfo domain.FollowInfo
+		wantErr  error
+	}{
+		{
+			name: "互相关注",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FollowedMap(gomock.Any(), int64(1), []int64{2}).Return(map[int64]bool{2: true}, nil)
+				repo.EXPECT().FollowedMap(gomock.Any(), int64(2), []int64{1}).Return(map[int64]bool{1: true}, nil)
+				return repo
+			},
+			target:   2,
+			wantInfo: domain.FollowInfo{Followed: true, FollowedBy: true},
+		},
+		{
+			name: "只被对方关注",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().FollowedMap(gomock.Any(), int64(1), []int64{2}).Return(map[int64]bool{}, nil)
+				repo.EXPECT().FollowedMap(gomock.Any(), int64(2), []int64{1}).Return(map[int64]bool{1: true}, nil)
+				return repo
+			},
+			target:   2,
+			wantInfo: domain.FollowInfo{FollowedBy: true},
+		},
+		{
+			name: "查询自己",
+			mock: func(ctrl *gomock.Controller) repository.FollowRepository {
+				return repomocks.NewMockFollowRepository(ctrl)
+			},
+			target: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewFollowService(tc.mock(ctrl), repomocks.NewMockUserRepository(ctrl), nil, nil)
+			info, err := svc.Info(context.Background(), 1, tc.target)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantInfo, info)
+			assert.Equal(t, tc.wantInfo.Followed && tc.wantInfo.FollowedBy, info.Mutual())
+		})
+	}
+}
@@ -3,43 +3,188 @@ package service
 import (
 	"Webook/webook/internal/domain"
 	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
 	"context"
+	"time"
 )
 
 //go:generate mockgen -source=./interactive.go -package=svcmocks -destination=./mocks/interactive.mock.go
 type InteractiveService interface {
+	// Read 记录一次阅读：发送阅读事件，由消费者异步增加阅读计数、记录阅读历史，ip 用于阅读去重
+	Read(ctx context.Context, biz string, bizId int64, userId int64, ip string) error
+	// IncreaseReadCnt 直接增加阅读计数
 	IncreaseReadCnt(ctx context.Context, biz string, bizId int64) error
+	// BatchIncreaseReadCnt 批量增加阅读计数，bizs、bizIds、cnts 一一对应
+	BatchIncreaseReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error
 	IncreaseLike(ctx context.Context, biz string, bizId int64, userId int64) error
 	DecreaseLike(ctx context.Context, biz string, bizId int64, userId int64) error
+	// Collect 收藏到用户自己的收藏夹，已经收藏过的会移动到新的收藏夹
 	Collect(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) error
+	Uncollect(ctx context.Context, biz string, bizId int64, userId int64) error
 	Get(ctx context.Context, biz string, bizId int64, userId int64) (domain.Interactive, error)
 	GetInterMapByBizIds(ctx context.Context, biz string, bizIds []int64, userId int64) (map[int64]domain.Interactive, error)
 }
 
+var ErrInteractiveCacheNotUpdated = repository.ErrInteractiveCacheNotUpdated
+
 type interactiveService struct {
-	repo repository.InteractiveRepository
+	repo           repository.InteractiveRepository
+	collectionRepo repository.CollectionRepository
+	// 查询被点赞的文章的作者，webhook 只投递给作者
+	artRepo article.ArticleRepository
+	// 点赞、收藏后通知作者
+	producer events.Producer[domain.NotificationEvent]
+	// 点赞后通知订阅了事件的 webhook
+	webhookSvc WebhookService
+	// 阅读、点赞、收藏事件
+	readProducer  events.Producer[domain.ReadEvent]
+	eventProducer events.Producer[domain.InteractiveEvent]
+	logger        logger.Logger
 }
 
-func NewInteractiveService(repo repository.InteractiveRepository) InteractiveService {
+func NewInteractiveService(repo repository.InteractiveRepository, collectionRepo repository.CollectionRepository,
+	artRepo article.ArticleRepository, producer events.Producer[domain.NotificationEvent], webhookSvc WebhookService,
+	readProducer events.Producer[domain.ReadEvent], eventProducer events.Producer[domain.InteractiveEvent],
+	l logger.Logger) InteractiveService {
 	return &interactiveService{
-		repo: repo,
+		repo:           repo,
+		collectionRepo: collectionRepo,
+		artRepo:        artRepo,
+		producer:       producer,
+		webhookSvc:     webhookSvc,
+		readProducer:   readProducer,
+		eventProducer:  eventProducer,
+		logger:         l,
+	}
+}
+
+func (s *interactiveService) Read(ctx context.Context, biz string, bizId int64, userId int64, ip string) error {
+	err := s.readProducer.Produce(ctx, domain.ReadEvent{
+		Uid:   userId,
+		Biz:   biz,
+		BizId: bizId,
+		Ip:    ip,
+		Time:  time.Now(),
+	})
+	if err == nil {
+		return nil
 	}
+	// 发送失败时退化成同步增加，阅读计数不能丢
+	s.logger.Error("发送阅读事件失败",
+		logger.String("biz", biz),
+		logger.Int64("bizId", bizId),
+		logger.Error(err),
+	)
+	return s.IncreaseReadCnt(ctx, biz, bizId)
 }
 
 func (s *interactiveService) IncreaseReadCnt(ctx context.Context, biz string, bizId int64) error {
 	return s.repo.IncreaseReadCnt(ctx, biz, bizId)
 }
 
+func (s *interactiveService) BatchIncreaseReadCnt(ctx context.Context, bizs []string, bizIds []int64, cnts []int64) error {
+	return s.repo.BatchIncreaseReadCnt(ctx, bizs, bizIds, cnts)
+}
+
 func (s *interactiveService) IncreaseLike(ctx context.Context, biz string, bizId int64, userId int64) error {
-	return s.repo.IncreaseLikeCnt(ctx, biz, bizId, userId)
+	err := s.repo.IncreaseLikeCnt(ctx, biz, bizId, userId)
+	if err != nil {
+		return err
+	}
+	produceNotification(ctx, s.producer, s.logger, domain.NotificationEvent{
+		Type:  domain.NotificationTypeLike,
+		Actor: userId,
+		Biz:   biz,
+		BizId: bizId,
+	})
+	s.likeWebhook(ctx, biz, bizId, userId)
+	s.produceEvent(ctx, domain.InteractiveEventLike, biz, bizId, userId)
+	return nil
+}
+
+// likeWebhook 通知被点赞的文章的作者注册的 webhook，目前只支持文章
+func (s *interactiveService) likeWebhook(ctx context.Context, biz string, bizId int64, userId int64) {
+	if biz != notificationBizArticle {
+		return
+	}
+	art, err := s.artRepo.FindPublishedArticleById(ctx, bizId)
+	if err != nil {
+		s.logger.Error("查询被点赞的文章失败，无法通知 webhook",
+			logger.Int64("bizId", bizId),
+			logger.Error(err),
+		)
+		return
+	}
+	dispatchWebhook(ctx, s.webhookSvc, s.logger, domain.WebhookEventInteractiveLiked, art.Author.Id, webhookLikeData{
+		Biz:    biz,
+		BizId:  bizId,
+		UserId: userId,
+	})
 }
 
 func (s *interactiveService) DecreaseLike(ctx context.Context, biz string, bizId int64, userId int64) error {
-	return s.repo.DecreaseLikeCnt(ctx, biz, bizId, userId)
+	err := s.repo.DecreaseLikeCnt(ctx, biz, bizId, userId)
+	if err != nil {
+		return err
+	}
+	s.produceEvent(ctx, domain.InteractiveEventUnlike, biz, bizId, userId)
+	return nil
 }
 
 func (s *interactiveService) Collect(ctx context.Context, biz string, bizId int64, collectionId int64, userId int64) error {
-	return s.repo.InsertCollection(ctx, biz, bizId, collectionId, userId)
+	c, err := s.collectionRepo.FindById(ctx, collectionId)
+	if err != nil {
+		return err
+	}
+	if c.Uid != userId {
+		return ErrCollectionNotFound
+	}
+	created, err := s.repo.InsertCollection(ctx, biz, bizId, collectionId, userId)
+	if err != nil {
+		return err
+	}
+	// 已经收藏过，只是换了收藏夹，收藏数没变
+	if !created {
+		return nil
+	}
+	produceNotification(ctx, s.producer, s.logger, domain.NotificationEvent{
+		Type:  domain.NotificationTypeCollect,
+		Actor: userId,
+		Biz:   biz,
+		BizId: bizId,
+	})
+	s.produceEvent(ctx, domain.InteractiveEventCollect, biz, bizId, userId)
+	return nil
+}
+
+func (s *interactiveService) Uncollect(ctx context.Context, biz string, bizId int64, userId int64) error {
+	deleted, err := s.repo.DeleteCollection(ctx, biz, bizId, userId)
+	if err != nil || !deleted {
+		return err
+	}
+	s.produceEvent(ctx, domain.InteractiveEventUncollect, biz, bizId, userId)
+	return nil
+}
+
+// produceEvent 发送点赞、收藏事件，失败不影响业务，只记录日志
+func (s *interactiveService) produceEvent(ctx context.Context, typ domain.InteractiveEventType, biz string, bizId int64, userId int64) {
+	err := s.eventProducer.Produce(ctx, domain.InteractiveEvent{
+		Type:  typ,
+		Uid:   userId,
+		Biz:   biz,
+		BizId: bizId,
+		Time:  time.Now(),
+	})
+	if err != nil {
+		s.logger.Error("发送互动事件失败",
+			logger.String("type", string(typ)),
+			logger.String("biz", biz),
+			logger.Int64("bizId", bizId),
+			logger.Error(err),
+		)
+	}
 }
 
 func (s *interactiveService) Get(ctx context.Context, biz string, bizId int64, userId int64) (domain.Interactive, error) {
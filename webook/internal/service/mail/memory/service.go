@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+)
+
+// Service 不真正发送，只打印出来，本地开发和测试用。
+// 正文里可能有重置 token 之类的凭证，只打印收件人和主题
+type Service struct {
+}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Send(ctx context.Context, to string, subject string, body string) error {
+	fmt.Println(to, subject)
+	return nil
+}
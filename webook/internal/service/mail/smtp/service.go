@@ -0,0 +1,138 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Config SMTP 服务器的配置。465 端口直接走 TLS，其他端口要求服务器支持 STARTTLS
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// 发件人，例如 "Webook <no-reply@example.com>"
+	From string
+}
+
+// Service 通过 SMTP 发送纯文本邮件
+type Service struct {
+	cfg  Config
+	from *netmail.Address
+}
+
+func NewService(cfg Config) (*Service, error) {
+	from, err := netmail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("发件人地址不对: %w", err)
+	}
+	if cfg.Host == "" || cfg.Port == 0 {
+		return nil, fmt.Errorf("没有配置 SMTP 服务器")
+	}
+	return &Service{
+		cfg:  cfg,
+		from: from,
+	}, nil
+}
+
+func (s *Service) Send(ctx context.Context, to string, subject string, body string) error {
+	// 只接受一个干净的地址，避免在邮件头里注入换行
+	rcpt, err := netmail.ParseAddress(to)
+	if err != nil {
+		return err
+	}
+	msg := s.message(rcpt, subject, body, time.Now())
+
+	c, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if s.cfg.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err = c.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err = c.Rcpt(rcpt.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// dial 连接服务器并加密，整个会话受 ctx 的过期时间限制
+func (s *Service) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	tlsCfg := &tls.Config{ServerName: s.cfg.Host}
+	if s.cfg.Port == 465 {
+		conn = tls.Client(conn, tlsCfg)
+	}
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if s.cfg.Port != 465 {
+		// 不允许明文发送密码和重置 token
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			c.Close()
+			return nil, fmt.Errorf("SMTP 服务器不支持 STARTTLS")
+		}
+		if err = c.StartTLS(tlsCfg); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// message 构造 UTF-8 纯文本邮件，主题和正文都编码，不会出现裸的换行
+func (s *Service) message(to *netmail.Address, subject string, body string, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	// 每行最多 76 个字符
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
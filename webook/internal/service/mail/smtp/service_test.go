@@ -0,0 +1,56 @@
+package smtp
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Message(t *testing.T) {
+	svc, err := NewService(Config{Host: "smtp.example.com", Port: 465, From: "Webook <no-reply@example.com>"})
+	require.NoError(t, err)
+	to := &netmail.Address{Address: "123@qq.com"}
+	body := strings.Repeat("重置 token 为：abc\n", 10)
+	msg, err := netmail.ReadMessage(strings.NewReader(
+		string(svc.message(to, "重置 Webook 密码", body, time.Now()))))
+	require.NoError(t, err)
+
+	assert.Equal(t, `"Webook" <no-reply@example.com>`, msg.Header.Get("From"))
+	assert.Equal(t, "<123@qq.com>", msg.Header.Get("To"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "重置 Webook 密码", subject)
+
+	raw, err := io.ReadAll(msg.Body)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\r\n")
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+func TestService_Send(t *testing.T) {
+	svc, err := NewService(Config{Host: "smtp.example.com", Port: 465, From: "no-reply@example.com"})
+	require.NoError(t, err)
+	// 收件人里有换行，不连接服务器直接拒绝
+	err = svc.Send(context.Background(), "a@example.com\r\nBcc: b@example.com", "subject", "body")
+	assert.Error(t, err)
+}
+
+func TestNewService(t *testing.T) {
+	_, err := NewService(Config{Host: "smtp.example.com", Port: 465})
+	assert.Error(t, err)
+	_, err = NewService(Config{From: "no-reply@example.com"})
+	assert.Error(t, err)
+}
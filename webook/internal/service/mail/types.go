@@ -0,0 +1,8 @@
+package mail
+
+import "context"
+
+// Service 发送邮件，具体用哪个服务商在 ioc 里决定
+type Service interface {
+	Send(ctx context.Context, to string, subject string, body string) error
+}
@@ -11,6 +11,7 @@ package svcmocks
 
 import (
 	domain "Webook/webook/internal/domain"
+	diff "Webook/webook/pkg/diff"
 	context "context"
 	reflect "reflect"
 	time "time"
@@ -42,6 +43,21 @@ func (m *MockArticleService) EXPECT() *MockArticleServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelSchedule mocks base method.
+func (m *MockArticleService) CancelSchedule(ctx context.Context, art domain.Article) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, art)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockArticleServiceMockRecorder) CancelSchedule(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockArticleService)(nil).CancelSchedule), ctx, art)
+}
+
 // Delete mocks base method.
 func (m *MockArticleService) Delete(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +88,36 @@ func (mr *MockArticleServiceMockRecorder) Detail(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detail", reflect.TypeOf((*MockArticleService)(nil).Detail), ctx, id)
 }
 
+// DiffRevisions mocks base method.
+func (m *MockArticleService) DiffRevisions(ctx context.Context, authorId, fromId, toId int64) ([]diff.Line, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", ctx, authorId, fromId, toId)
+	ret0, _ := ret[0].([]diff.Line)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockArticleServiceMockRecorder) DiffRevisions(ctx, authorId, fromId, toId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockArticleService)(nil).DiffRevisions), ctx, authorId, fromId, toId)
+}
+
+// HotTags mocks base method.
+func (m *MockArticleService) HotTags(ctx context.Context, limit int) ([]domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HotTags", ctx, limit)
+	ret0, _ := ret[0].([]domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HotTags indicates an expected call of HotTags.
+func (mr *MockArticleServiceMockRecorder) HotTags(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HotTags", reflect.TypeOf((*MockArticleService)(nil).HotTags), ctx, limit)
+}
+
 // List mocks base method.
 func (m *MockArticleService) List(ctx context.Context, userId int64, limit, offset int) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +133,21 @@ func (mr *MockArticleServiceMockRecorder) List(ctx, userId, limit, offset any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArticleService)(nil).List), ctx, userId, limit, offset)
 }
 
+// ListRevisions mocks base method.
+func (m *MockArticleService) ListRevisions(ctx context.Context, artId, authorId int64, limit, offset int) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, artId, authorId, limit, offset)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockArticleServiceMockRecorder) ListRevisions(ctx, artId, authorId, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockArticleService)(nil).ListRevisions), ctx, artId, authorId, limit, offset)
+}
+
 // PublicDetail mocks base method.
 func (m *MockArticleService) PublicDetail(ctx context.Context, id int64) (domain.Article, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +178,21 @@ func (mr *MockArticleServiceMockRecorder) PublicList(ctx, end, offset, limit any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicList", reflect.TypeOf((*MockArticleService)(nil).PublicList), ctx, end, offset, limit)
 }
 
+// PublicListByFilter mocks base method.
+func (m *MockArticleService) PublicListByFilter(ctx context.Context, end time.Time, offset, limit int, filter domain.ArticleFilter) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicListByFilter", ctx, end, offset, limit, filter)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublicListByFilter indicates an expected call of PublicListByFilter.
+func (mr *MockArticleServiceMockRecorder) PublicListByFilter(ctx, end, offset, limit, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicListByFilter", reflect.TypeOf((*MockArticleService)(nil).PublicListByFilter), ctx, end, offset, limit, filter)
+}
+
 // Publish mocks base method.
 func (m *MockArticleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -132,6 +208,21 @@ func (mr *MockArticleServiceMockRecorder) Publish(ctx, art any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockArticleService)(nil).Publish), ctx, art)
 }
 
+// PublishDue mocks base method.
+func (m *MockArticleService) PublishDue(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", ctx, now, batchSize)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDue indicates an expected call of PublishDue.
+func (mr *MockArticleServiceMockRecorder) PublishDue(ctx, now, batchSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockArticleService)(nil).PublishDue), ctx, now, batchSize)
+}
+
 // PublishWithTwoRepo mocks base method.
 func (m *MockArticleService) PublishWithTwoRepo(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -147,6 +238,21 @@ func (mr *MockArticleServiceMockRecorder) PublishWithTwoRepo(ctx, art any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishWithTwoRepo", reflect.TypeOf((*MockArticleService)(nil).PublishWithTwoRepo), ctx, art)
 }
 
+// RestoreRevision mocks base method.
+func (m *MockArticleService) RestoreRevision(ctx context.Context, authorId, revisionId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", ctx, authorId, revisionId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockArticleServiceMockRecorder) RestoreRevision(ctx, authorId, revisionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockArticleService)(nil).RestoreRevision), ctx, authorId, revisionId)
+}
+
 // Save mocks base method.
 func (m *MockArticleService) Save(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -177,6 +283,36 @@ func (mr *MockArticleServiceMockRecorder) SaveWithTwoRepo(ctx, art any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWithTwoRepo", reflect.TypeOf((*MockArticleService)(nil).SaveWithTwoRepo), ctx, art)
 }
 
+// Schedule mocks base method.
+func (m *MockArticleService) Schedule(ctx context.Context, art domain.Article) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", ctx, art)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockArticleServiceMockRecorder) Schedule(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockArticleService)(nil).Schedule), ctx, art)
+}
+
+// SuggestTags mocks base method.
+func (m *MockArticleService) SuggestTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestTags", ctx, prefix, limit)
+	ret0, _ := ret[0].([]domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestTags indicates an expected call of SuggestTags.
+func (mr *MockArticleServiceMockRecorder) SuggestTags(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestTags", reflect.TypeOf((*MockArticleService)(nil).SuggestTags), ctx, prefix, limit)
+}
+
 // Withdraw mocks base method.
 func (m *MockArticleService) Withdraw(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
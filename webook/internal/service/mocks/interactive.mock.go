@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./interactive.go
+// Source: ./webook/internal/service/interactive.go
 //
 // Generated by this command:
 //
-//	mockgen -source=./interactive.go -package=svcmocks -destination=./mocks/interactive.mock.go
+//	mockgen -source=./webook/internal/service/interactive.go -package=svcmocks -destination=./webook/internal/service/mocks/interactive.mock.go
 //
 
 // Package svcmocks is a generated GoMock package.
@@ -41,6 +41,20 @@ func (m *MockInteractiveService) EXPECT() *MockInteractiveServiceMockRecorder {
 	return m.recorder
 }
 
+// BatchIncreaseReadCnt mocks base method.
+func (m *MockInteractiveService) BatchIncreaseReadCnt(ctx context.Context, bizs []string, bizIds, cnts []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchIncreaseReadCnt", ctx, bizs, bizIds, cnts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchIncreaseReadCnt indicates an expected call of BatchIncreaseReadCnt.
+func (mr *MockInteractiveServiceMockRecorder) BatchIncreaseReadCnt(ctx, bizs, bizIds, cnts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchIncreaseReadCnt", reflect.TypeOf((*MockInteractiveService)(nil).BatchIncreaseReadCnt), ctx, bizs, bizIds, cnts)
+}
+
 // Collect mocks base method.
 func (m *MockInteractiveService) Collect(ctx context.Context, biz string, bizId, collectionId, userId int64) error {
 	m.ctrl.T.Helper()
@@ -85,18 +99,18 @@ func (mr *MockInteractiveServiceMockRecorder) Get(ctx, biz, bizId, userId any) *
 }
 
 // GetInterMapByBizIds mocks base method.
-func (m *MockInteractiveService) GetInterMapByBizIds(ctx context.Context, biz string, bizIds []int64) (map[int64]domain.Interactive, error) {
+func (m *MockInteractiveService) GetInterMapByBizIds(ctx context.Context, biz string, bizIds []int64, userId int64) (map[int64]domain.Interactive, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetInterMapByBizIds", ctx, biz, bizIds)
+	ret := m.ctrl.Call(m, "GetInterMapByBizIds", ctx, biz, bizIds, userId)
 	ret0, _ := ret[0].(map[int64]domain.Interactive)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetInterMapByBizIds indicates an expected call of GetInterMapByBizIds.
-func (mr *MockInteractiveServiceMockRecorder) GetInterMapByBizIds(ctx, biz, bizIds any) *gomock.Call {
+func (mr *MockInteractiveServiceMockRecorder) GetInterMapByBizIds(ctx, biz, bizIds, userId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterMapByBizIds", reflect.TypeOf((*MockInteractiveService)(nil).GetInterMapByBizIds), ctx, biz, bizIds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterMapByBizIds", reflect.TypeOf((*MockInteractiveService)(nil).GetInterMapByBizIds), ctx, biz, bizIds, userId)
 }
 
 // IncreaseLike mocks base method.
@@ -126,3 +140,31 @@ func (mr *MockInteractiveServiceMockRecorder) IncreaseReadCnt(ctx, biz, bizId an
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseReadCnt", reflect.TypeOf((*MockInteractiveService)(nil).IncreaseReadCnt), ctx, biz, bizId)
 }
+
+// Read mocks base method.
+func (m *MockInteractiveService) Read(ctx context.Context, biz string, bizId, userId int64, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", ctx, biz, bizId, userId, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockInteractiveServiceMockRecorder) Read(ctx, biz, bizId, userId, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockInteractiveService)(nil).Read), ctx, biz, bizId, userId, ip)
+}
+
+// Uncollect mocks base method.
+func (m *MockInteractiveService) Uncollect(ctx context.Context, biz string, bizId, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Uncollect", ctx, biz, bizId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Uncollect indicates an expected call of Uncollect.
+func (mr *MockInteractiveServiceMockRecorder) Uncollect(ctx, biz, bizId, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Uncollect", reflect.TypeOf((*MockInteractiveService)(nil).Uncollect), ctx, biz, bizId, userId)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+)
+
+type NotificationService interface {
+	// Notify 处理通知事件：确定接收者，能聚合的事件聚合到未读的同类通知上
+	Notify(ctx context.Context, evt domain.NotificationEvent) error
+	// List typ 为 NotificationTypeUnknown 表示所有类型
+	List(ctx context.Context, uid int64, typ domain.NotificationType, offset int, limit int) ([]domain.Notification, error)
+	// UnreadCnt 各类型的未读数
+	UnreadCnt(ctx context.Context, uid int64) (map[domain.NotificationType]int64, error)
+	MarkRead(ctx context.Context, uid int64, ids []int64) error
+	// MarkAllRead typ 为 NotificationTypeUnknown 表示所有类型
+	MarkAllRead(ctx context.Context, uid int64, typ domain.NotificationType) error
+}
+
+var ErrNotificationBizNotSupport = errors.New("不支持该业务的通知")
+
+const (
+	notificationBizArticle = "article"
+	// 通知中保留的评论内容长度
+	notificationContentLength = 100
+)
+
+type notificationService struct {
+	repo    repository.NotificationRepository
+	artRepo article.ArticleRepository
+}
+
+func NewNotificationService(repo repository.NotificationRepository, artRepo article.ArticleRepository) NotificationService {
+	return &notificationService{
+		repo:    repo,
+		artRepo: artRepo,
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, evt domain.NotificationEvent) error {
+	if evt.Receiver == 0 {
+		if evt.Biz != notificationBizArticle {
+			return ErrNotificationBizNotSupport
+		}
+		art, err := s.artRepo.FindById(ctx, evt.BizId)
+		if err != nil {
+			return err
+		}
+		evt.Receiver = art.Author.Id
+		evt.BizTitle = art.Title
+	}
+	// 自己给自己点赞、回复自己等不需要通知
+	if evt.Receiver == evt.Actor {
+		return nil
+	}
+
+	content := []rune(evt.Content)
+	if len(content) > notificationContentLength {
+		content = content[:notificationContentLength]
+	}
+	n := domain.Notification{
+		Receiver: evt.Receiver,
+		Type:     evt.Type,
+		Biz:      evt.Biz,
+		BizId:    evt.BizId,
+		BizTitle: evt.BizTitle,
+		Actors:   []int64{evt.Actor},
+		ActorCnt: 1,
+		Content:  string(content),
+	}
+	if evt.Type.Aggregatable() {
+		return s.repo.Aggregate(ctx, n, evt.Actor)
+	}
+	_, err := s.repo.Create(ctx, n)
+	return err
+}
+
+func (s *notificationService) List(ctx context.Context, uid int64, typ domain.NotificationType, offset int, limit int) ([]domain.Notification, error) {
+	return s.repo.FindByReceiver(ctx, uid, typ, offset, limit)
+}
+
+func (s *notificationService) UnreadCnt(ctx context.Context, uid int64) (map[domain.NotificationType]int64, error) {
+	return s.repo.CntUnread(ctx, uid)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, uid int64, ids []int64) error {
+	return s.repo.MarkRead(ctx, uid, ids)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, uid int64, typ domain.NotificationType) error {
+	return s.repo.MarkAllRead(ctx, uid, typ)
+}
+
+// produceNotification 业务代码里发送通知事件，失败不影响业务，只记录日志
+func produceNotification(ctx context.Context, producer events.Producer[domain.NotificationEvent], l logger.Logger,
+	evt domain.NotificationEvent) {
+	if err := producer.Produce(ctx, evt); err != nil {
+		l.Error("发送通知事件失败",
+			logger.String("type", evt.Type.String()),
+			logger.Int64("actor", evt.Actor),
+			logger.String("biz", evt.Biz),
+			logger.Int64("bizId", evt.BizId),
+			logger.Error(err),
+		)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	artrepomocks "Webook/webook/internal/repository/article/mocks"
+	repomocks "Webook/webook/internal/repository/mocks"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNotificationService_Notify(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.NotificationRepository, article.ArticleRepository)
+
+		evt     domain.NotificationEvent
+		wantErr error
+	}{
+		{
+			name: "点赞聚合到文章作者的通知上",
+			mock: func(ctrl *gomock.Controller) (repository.NotificationRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockNotificationRepository(ctrl)
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Title:  "标题",
+					Author: domain.Author{Id: 5},
+				}, nil)
+				repo.EXPECT().Aggregate(gomock.Any(), domain.Notification{
+					Receiver: 5,
+					Type:     domain.NotificationTypeLike,
+					Biz:      "article",
+					BizId:    1,
+					BizTitle: "标题",
+					Actors:   []int64{2},
+					ActorCnt: 1,
+				}, int64(2)).Return(nil)
+				return repo, artRepo
+			},
+			evt: domain.NotificationEvent{
+				Type:  domain.NotificationTypeLike,
+				Actor: 2,
+				Biz:   "article",
+				BizId: 1,
+			},
+		},
+		{
+			name: "给自己的文章点赞不通知",
+			mock: func(ctrl *gomock.Controller) (repository.NotificationRepository, article.ArticleRepository) {
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id:     1,
+					Author: domain.Author{Id: 2},
+				}, nil)
+				return repomocks.NewMockNotificationRepository(ctrl), artRepo
+			},
+			evt: domain.NotificationEvent{
+				Type:  domain.NotificationTypeLike,
+				Actor: 2,
+				Biz:   "article",
+				BizId: 1,
+			},
+		},
+		{
+			name: "回复单独通知，内容截断",
+			mock: func(ctrl *gomock.Controller) (repository.NotificationRepository, article.ArticleRepository) {
+				repo := repomocks.NewMockNotificationRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Notification{
+					Receiver: 3,
+					Type:     domain.NotificationTypeReply,
+					Biz:      "article",
+					BizId:    1,
+					BizTitle: "标题",
+					Actors:   []int64{2},
+					ActorCnt: 1,
+					Content:  strings.Repeat("回", notificationContentLength),
+				}).Return(int64(10), nil)
+				return repo, artrepomocks.NewMockArticleRepository(ctrl)
+			},
+			evt: domain.NotificationEvent{
+				Type:     domain.NotificationTypeReply,
+				Actor:    2,
+				Receiver: 3,
+				Biz:      "article",
+				BizId:    1,
+				BizTitle: "标题",
+				Content:  strings.Repeat("回", notificationContentLength+1),
+			},
+		},
+		{
+			name: "不支持的业务",
+			mock: func(ctrl *gomock.Controller) (repository.NotificationRepository, article.ArticleRepository) {
+				return repomocks.NewMockNotificationRepository(ctrl), artrepomocks.NewMockArticleRepository(ctrl)
+			},
+			evt: domain.NotificationEvent{
+				Type:  domain.NotificationTypeLike,
+				Actor: 2,
+				Biz:   "video",
+				BizId: 1,
+			},
+			wantErr: ErrNotificationBizNotSupport,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewNotificationService(tc.mock(ctrl))
+			err := svc.Notify(context.Background(), tc.evt)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -3,7 +3,9 @@ package service
 import (
 	"Webook/webook/internal/domain"
 	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
 	"context"
+	"errors"
 	"math"
 	"time"
 
@@ -11,62 +13,375 @@ import (
 	"github.com/ecodeclub/ekit/slice"
 )
 
+var ErrRankingBoardNotFound = errors.New("榜单不存在")
+
+// RankingStrategy 根据文章和它的互动数据打分，分数越高排名越靠前
+type RankingStrategy interface {
+	Score(art domain.Article, inter domain.Interactive, now time.Time) float64
+}
+
+// HackerNewsStrategy (P - 1) / (T + 2) ^ G
+// P 是阅读、点赞、收藏、评论的加权和，T 是文章发表至今的小时数，G 是重力，越大越偏向新文章
+type HackerNewsStrategy struct {
+	ReadWeight    float64
+	LikeWeight    float64
+	CollectWeight float64
+	CommentWeight float64
+	Gravity       float64
+}
+
+func NewHackerNewsStrategy() *HackerNewsStrategy {
+	return &HackerNewsStrategy{
+		ReadWeight:    0.1,
+		LikeWeight:    1,
+		CollectWeight: 2,
+		CommentWeight: 1.5,
+		Gravity:       1.5,
+	}
+}
+
+func (s *HackerNewsStrategy) Score(art domain.Article, inter domain.Interactive, now time.Time) float64 {
+	points := s.ReadWeight*float64(inter.ReadCnt) +
+		s.LikeWeight*float64(inter.LikeCnt) +
+		s.CollectWeight*float64(inter.CollectCnt) +
+		s.CommentWeight*float64(inter.CommentCnt)
+	hours := math.Max(now.Sub(art.Utime).Hours(), 0)
+	return (points - 1) / math.Pow(hours+2, s.Gravity)
+}
+
+// IncrementalRankingStrategy 支持增量更新的打分策略，使用这种策略的榜单是增量榜单：
+// 互动发生时实时加分，定时任务只做时间衰减
+type IncrementalRankingStrategy interface {
+	RankingStrategy
+	// Delta 一次互动带来的分数变化
+	Delta(action domain.RankingAction) float64
+	// DecayHalfLife 分数衰减一半需要的时间
+	DecayHalfLife() time.Duration
+}
+
+// DecayStrategy 每次互动按权重加分，分数按半衰期随时间衰减
+type DecayStrategy struct {
+	ReadWeight    float64
+	LikeWeight    float64
+	CollectWeight float64
+	CommentWeight float64
+	HalfLife      time.Duration
+}
+
+func NewDecayStrategy() *DecayStrategy {
+	return &DecayStrategy{
+		ReadWeight:    0.1,
+		LikeWeight:    1,
+		CollectWeight: 2,
+		CommentWeight: 1.5,
+		HalfLife:      time.Hour * 24,
+	}
+}
+
+// Score 重建时使用。不知道每次互动发生的时间，近似认为都发生在文章更新时
+func (s *DecayStrategy) Score(art domain.Article, inter domain.Interactive, now time.Time) float64 {
+	points := s.ReadWeight*float64(inter.ReadCnt) +
+		s.LikeWeight*float64(inter.LikeCnt) +
+		s.CollectWeight*float64(inter.CollectCnt) +
+		s.CommentWeight*float64(inter.CommentCnt)
+	age := math.Max(float64(now.Sub(art.Utime)), 0)
+	return points * math.Pow(0.5, age/float64(s.HalfLife))
+}
+
+func (s *DecayStrategy) Delta(action domain.RankingAction) float64 {
+	switch action {
+	case domain.RankingActionRead:
+		return s.ReadWeight
+	case domain.RankingActionLike:
+		return s.LikeWeight
+	case domain.RankingActionUnlike:
+		return -s.LikeWeight
+	case domain.RankingActionCollect:
+		return s.CollectWeight
+	case domain.RankingActionUncollect:
+		return -s.CollectWeight
+	case domain.RankingActionComment:
+		return s.CommentWeight
+	case domain.RankingActionUncomment:
+		return -s.CommentWeight
+	default:
+		return 0
+	}
+}
+
+func (s *DecayStrategy) DecayHalfLife() time.Duration {
+	return s.HalfLife
+}
+
+// RankingBoard 一个榜单：统计哪些文章，用什么策略打分，取前几名
+type RankingBoard struct {
+	Name string
+	// Window 只统计这段时间内更新过的文章，0 表示不限
+	Window time.Duration
+	// Filter 按标签、分类统计，为空表示全部文章
+	Filter   domain.ArticleFilter
+	N        int
+	Strategy RankingStrategy
+}
+
+func (b RankingBoard) incremental() (IncrementalRankingStrategy, bool) {
+	s, ok := b.Strategy.(IncrementalRankingStrategy)
+	return s, ok
+}
+
+// match 增量榜单判断文章是否计入榜单
+func (b RankingBoard) match(art domain.Article, now time.Time) bool {
+	if b.Window > 0 && now.Sub(art.Utime) > b.Window {
+		return false
+	}
+	if b.Filter.Category != "" && art.Category != b.Filter.Category {
+		return false
+	}
+	if b.Filter.Tag != "" && !slice.Contains(art.Tags, b.Filter.Tag) {
+		return false
+	}
+	return true
+}
+
+// keep 增量榜单的有序集合保留 N 的若干倍，让榜单外的文章也能积累分数
+func (b RankingBoard) keep() int {
+	return b.N * 10
+}
+
 type RankingService interface {
-	SetTop100(ctx context.Context) error
-	GetTop100(ctx context.Context) ([]domain.Article, error)
-	GetFromCache(ctx context.Context) ([]domain.Article, error)
+	// SetTopN 计算榜单并写入缓存
+	SetTopN(ctx context.Context, board string) error
+	GetTopN(ctx context.Context, board string) ([]domain.Article, error)
+	// GetFromCache 榜单不存在时返回 ErrRankingBoardNotFound，增量榜单直接读有序集合
+	GetFromCache(ctx context.Context, board string) ([]domain.Article, error)
+
+	// IncrScores 增量榜单记录互动，artIds 和 cnts 一一对应，cnts 是合并后的次数
+	IncrScores(ctx context.Context, action domain.RankingAction, artIds []int64, cnts []int64) error
+	// Remove 文章撤回、删除后从增量榜单中移除
+	Remove(ctx context.Context, artId int64) error
+	// Decay 增量榜单的时间衰减和截断，同时移除超出时间窗口的文章
+	Decay(ctx context.Context, board string) error
+	// Rebuild 用全量计算的分数重建增量榜单，修正增量更新累积的误差
+	Rebuild(ctx context.Context, board string) error
 }
 
 type BatchRankingService struct {
 	artSvc   ArticleService
 	interSvc InteractiveService
+	artRepo  article.ArticleRepository
 
-	n         int
 	batchSize int
-	scoreFunc func(likeCnt int64, utime time.Time) float64
+	boards    map[string]RankingBoard
+	// 增量榜单，互动发生时要更新
+	incrementalBoards []RankingBoard
 
 	repo repository.RankingRepository
 }
 
-func NewRankingService(artSvc ArticleService, interSvc InteractiveService, repo repository.RankingRepository) RankingService {
+func NewRankingService(artSvc ArticleService, interSvc InteractiveService, artRepo article.ArticleRepository,
+	repo repository.RankingRepository, boards []RankingBoard) RankingService {
+	boardMap := make(map[string]RankingBoard, len(boards))
+	var incrementalBoards []RankingBoard
+	for _, b := range boards {
+		boardMap[b.Name] = b
+		if _, ok := b.incremental(); ok {
+			incrementalBoards = append(incrementalBoards, b)
+		}
+	}
 	return &BatchRankingService{
-		artSvc:    artSvc,
-		interSvc:  interSvc,
-		n:         100,
-		batchSize: 100,
-		scoreFunc: func(likeCnt int64, utime time.Time) float64 {
-			dur := time.Since(utime).Seconds()
-			return float64(likeCnt-1) / math.Pow(dur+2, 1.5)
-		},
-		repo: repo,
-	}
-}
-func (svc *BatchRankingService) SetTop100(ctx context.Context) error {
-	articles, err := svc.GetTop100(ctx)
+		artSvc:            artSvc,
+		interSvc:          interSvc,
+		artRepo:           artRepo,
+		batchSize:         100,
+		boards:            boardMap,
+		incrementalBoards: incrementalBoards,
+		repo:              repo,
+	}
+}
+
+func (svc *BatchRankingService) IncrScores(ctx context.Context, action domain.RankingAction, artIds []int64, cnts []int64) error {
+	if len(svc.incrementalBoards) == 0 || len(artIds) == 0 {
+		return nil
+	}
+	// 按标签、分类、时间窗口判断需要文章信息，只查询一次
+	var artMap map[int64]domain.Article
+	for _, b := range svc.incrementalBoards {
+		if b.Window > 0 || b.Filter != (domain.ArticleFilter{}) {
+			arts, err := svc.artRepo.FindPublishedArticlesByIds(ctx, artIds)
+			if err != nil {
+				return err
+			}
+			artMap = make(map[int64]domain.Article, len(arts))
+			for _, art := range arts {
+				artMap[art.Id] = art
+			}
+			break
+		}
+	}
+
+	now := time.Now()
+	for _, b := range svc.incrementalBoards {
+		strategy, _ := b.incremental()
+		delta := strategy.Delta(action)
+		if delta == 0 {
+			continue
+		}
+		deltas := make(map[int64]float64, len(artIds))
+		for i, id := range artIds {
+			if artMap != nil {
+				art, ok := artMap[id]
+				// 已经下线的文章
+				if !ok || !b.match(art, now) {
+					continue
+				}
+			}
+			deltas[id] += delta * float64(cnts[i])
+		}
+		if err := svc.repo.IncrScores(ctx, b.Name, deltas); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc *BatchRankingService) Remove(ctx context.Context, artId int64) error {
+	boards := slice.Map(svc.incrementalBoards, func(idx int, b RankingBoard) string {
+		return b.Name
+	})
+	return svc.repo.RemoveArticle(ctx, boards, artId)
+}
+
+func (svc *BatchRankingService) Decay(ctx context.Context, board string) error {
+	b, ok := svc.boards[board]
+	if !ok {
+		return ErrRankingBoardNotFound
+	}
+	strategy, ok := b.incremental()
+	if !ok {
+		return nil
+	}
+	err := svc.repo.DecayScores(ctx, board, strategy.DecayHalfLife(), b.keep())
+	if errors.Is(err, repository.ErrRankingScoresNotBuilt) {
+		// 刚上线的榜单，先全量算一次
+		return svc.Rebuild(ctx, board)
+	}
+	if err != nil {
+		return err
+	}
+	return svc.removeExpired(ctx, b)
+}
+
+// removeExpired 移除超出时间窗口的文章，没有时间窗口的榜单不需要
+// 文章只在互动时判断是否在窗口内，之后没有互动的文章要在这里移除，不能等到下次重建
+func (svc *BatchRankingService) removeExpired(ctx context.Context, b RankingBoard) error {
+	if b.Window <= 0 {
+		return nil
+	}
+	ids, err := svc.repo.GetTopIds(ctx, b.Name, b.keep())
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	arts, err := svc.artRepo.FindPublishedArticlesByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	alive := make(map[int64]bool, len(arts))
+	for _, art := range arts {
+		alive[art.Id] = b.match(art, now)
+	}
+	// 已经下线的文章同样移除
+	expired := slice.FilterMap(ids, func(idx int, id int64) (int64, bool) {
+		return id, !alive[id]
+	})
+	if len(expired) == 0 {
+		return nil
+	}
+	return svc.repo.RemoveArticles(ctx, b.Name, expired)
+}
+
+func (svc *BatchRankingService) Rebuild(ctx context.Context, board string) error {
+	b, ok := svc.boards[board]
+	if !ok {
+		return ErrRankingBoardNotFound
+	}
+	if _, ok = b.incremental(); !ok {
+		return nil
+	}
+	scored, err := svc.topN(ctx, b, b.keep())
+	if err != nil {
+		return err
+	}
+	scores := make(map[int64]float64, len(scored))
+	for _, v := range scored {
+		scores[v.art.Id] = v.score
+	}
+	return svc.repo.ReplaceScores(ctx, board, scores)
+}
+
+func (svc *BatchRankingService) SetTopN(ctx context.Context, board string) error {
+	articles, err := svc.GetTopN(ctx, board)
 	if err != nil {
 		return err
 	}
 
 	// 存到缓存中
-	return svc.repo.ReplaceTop100(ctx, articles)
+	return svc.repo.ReplaceTopN(ctx, board, articles)
+}
+
+func (svc *BatchRankingService) GetFromCache(ctx context.Context, board string) ([]domain.Article, error) {
+	b, ok := svc.boards[board]
+	if !ok {
+		return nil, ErrRankingBoardNotFound
+	}
+	if _, ok = b.incremental(); !ok {
+		return svc.repo.GetTopN(ctx, board)
+	}
+
+	ids, err := svc.repo.GetTopIds(ctx, board, b.N)
+	if err != nil {
+		return nil, err
+	}
+	arts, err := svc.artRepo.FindPublishedArticlesByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	// 和缓存的榜单一样只返回摘要
+	for i := range arts {
+		arts[i].Content = arts[i].Abstract()
+	}
+	return arts, nil
 }
 
-func (svc *BatchRankingService) GetFromCache(ctx context.Context) ([]domain.Article, error) {
-	return svc.repo.GetTop100(ctx)
+// GetTopN 计算榜单前 N 的文章
+func (svc *BatchRankingService) GetTopN(ctx context.Context, board string) ([]domain.Article, error) {
+	b, ok := svc.boards[board]
+	if !ok {
+		return nil, ErrRankingBoardNotFound
+	}
+	scored, err := svc.topN(ctx, b, b.N)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(scored, func(idx int, v rankingScore) domain.Article {
+		return v.art
+	}), nil
+}
+
+type rankingScore struct {
+	score float64
+	art   domain.Article
 }
 
-// GetTop100 获取排行榜前 100 的文章
-func (svc *BatchRankingService) GetTop100(ctx context.Context) ([]domain.Article, error) {
+// topN 分批取出所有文章打分，分数从高到低
+func (svc *BatchRankingService) topN(ctx context.Context, b RankingBoard, n int) ([]rankingScore, error) {
 	offset := 0
 	now := time.Now()
 
-	type Score struct {
-		score float64
-		art   domain.Article
-	}
+	type Score = rankingScore
 
-	// 用 小根堆 来维护 Score 前 100 的文章。
-	pq := queue.NewPriorityQueue[Score](svc.n, func(a, b Score) int {
+	// 用 小根堆 来维护 Score 前 n 的文章。
+	pq := queue.NewPriorityQueue[Score](n, func(a, b Score) int {
 		if a.score > b.score {
 			return 1
 		} else if a.score == b.score {
@@ -78,25 +393,30 @@ func (svc *BatchRankingService) GetTop100(ctx context.Context) ([]domain.Article
 
 	// 批次取数据
 	for {
-		arts, err := svc.artSvc.PublicList(ctx, now, offset, svc.batchSize)
+		arts, err := svc.artSvc.PublicListByFilter(ctx, now, offset, svc.batchSize, b.Filter)
 		if err != nil {
 			return nil, err
 		}
+		// 文章按更新时间倒序，超出时间窗口的后面都不用看了
+		inWindow := arts
+		if b.Window > 0 {
+			inWindow = slice.FilterMap(arts, func(idx int, art domain.Article) (domain.Article, bool) {
+				return art, now.Sub(art.Utime) <= b.Window
+			})
+		}
 
-		bizIds := slice.Map(arts, func(idx int, art domain.Article) int64 {
+		bizIds := slice.Map(inWindow, func(idx int, art domain.Article) int64 {
 			return art.Id
 		})
 
-		// 获取文章点赞数
+		// 获取文章的互动数据，不需要用户的点赞收藏状态
 		interMap, err := svc.interSvc.GetInterMapByBizIds(ctx, "article", bizIds, -1)
 		if err != nil {
 			return nil, err
 		}
-		for _, art := range arts {
-			inter := interMap[art.Id]
-			score := svc.scoreFunc(inter.LikeCnt, art.Utime)
+		for _, art := range inWindow {
 			v := Score{
-				score: score,
+				score: b.Strategy.Score(art, interMap[art.Id], now),
 				art:   art,
 			}
 
@@ -113,16 +433,15 @@ func (svc *BatchRankingService) GetTop100(ctx context.Context) ([]domain.Article
 
 		// 有可能 len(arts) < svc.batchSize，说明已经取完了
 		offset = offset + len(arts)
-		if len(arts) < svc.batchSize {
+		if len(arts) < svc.batchSize || len(inWindow) < len(arts) {
 			break
 		}
 	}
 
 	// 封装结果
-	res := make([]domain.Article, pq.Len())
+	res := make([]rankingScore, pq.Len())
 	for i := pq.Len() - 1; i >= 0; i-- {
-		v, _ := pq.Dequeue()
-		res[i] = v.art
+		res[i], _ = pq.Dequeue()
 	}
 	return res, nil
 }
@@ -2,8 +2,12 @@ package service
 
 import (
 	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	artrepomocks "Webook/webook/internal/repository/article/mocks"
+	repomocks "Webook/webook/internal/repository/mocks"
 	svcmocks "Webook/webook/internal/service/mocks"
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -16,6 +20,7 @@ func TestRankingTopN(t *testing.T) {
 	testCases := []struct {
 		name     string
 		mock     func(ctrl *gomock.Controller) (ArticleService, InteractiveService)
+		board    RankingBoard
 		wantErr  error
 		wantArts []domain.Article
 	}{
@@ -24,35 +29,63 @@ func TestRankingTopN(t *testing.T) {
 			mock: func(ctrl *gomock.Controller) (ArticleService, InteractiveService) {
 				artSvc := svcmocks.NewMockArticleService(ctrl)
 				intrSvc := svcmocks.NewMockInteractiveService(ctrl)
-				artSvc.EXPECT().PublicList(gomock.Any(), gomock.Any(), 0, 2).Return([]domain.Article{
+				artSvc.EXPECT().PublicListByFilter(gomock.Any(), gomock.Any(), 0, 2, domain.ArticleFilter{}).Return([]domain.Article{
 					{Id: 1, Ctime: now, Utime: now},
 					{Id: 2, Ctime: now, Utime: now},
 				}, nil)
-				intrSvc.EXPECT().GetInterMapByBizIds(gomock.Any(), "article", []int64{1, 2}).
+				intrSvc.EXPECT().GetInterMapByBizIds(gomock.Any(), "article", []int64{1, 2}, int64(-1)).
 					Return(map[int64]domain.Interactive{
 						1: {BizId: 1, LikeCnt: 1},
 						2: {BizId: 2, LikeCnt: 2},
 					}, nil)
-				artSvc.EXPECT().PublicList(gomock.Any(), gomock.Any(), 2, 2).Return([]domain.Article{
+				artSvc.EXPECT().PublicListByFilter(gomock.Any(), gomock.Any(), 2, 2, domain.ArticleFilter{}).Return([]domain.Article{
 					{Id: 3, Ctime: now, Utime: now},
 					{Id: 4, Ctime: now, Utime: now},
 				}, nil)
-				intrSvc.EXPECT().GetInterMapByBizIds(gomock.Any(), "article", []int64{3, 4}).
+				intrSvc.EXPECT().GetInterMapByBizIds(gomock.Any(), "article", []int64{3, 4}, int64(-1)).
 					Return(map[int64]domain.Interactive{
 						3: {BizId: 3, LikeCnt: 3},
 						4: {BizId: 4, LikeCnt: 4},
 					}, nil)
 
-				artSvc.EXPECT().PublicList(gomock.Any(), gomock.Any(), 4, 2).Return([]domain.Article{}, nil)
-				intrSvc.EXPECT().GetInterMapByBizIds(gomock.Any(), "article", []int64{}).Return(map[int64]domain.Interactive{}, nil)
+				artSvc.EXPECT().PublicListByFilter(gomock.Any(), gomock.Any(), 4, 2, domain.ArticleFilter{}).Return([]domain.Article{}, nil)
+				intrSvc.EXPECT().GetInterMapByBizIds(gomock.Any(), "article", []int64{}, int64(-1)).Return(map[int64]domain.Interactive{}, nil)
 				return artSvc, intrSvc
 			},
+			board: RankingBoard{Name: "all_time", N: 3, Strategy: likeStrategy{}},
 			wantArts: []domain.Article{
 				{Id: 4, Ctime: now, Utime: now},
 				{Id: 3, Ctime: now, Utime: now},
 				{Id: 2, Ctime: now, Utime: now},
 			},
 		},
+		{
+			name: "超出时间窗口后不再往后取",
+			mock: func(ctrl *gomock.Controller) (ArticleService, InteractiveService) {
+				artSvc := svcmocks.NewMockArticleService(ctrl)
+				intrSvc := svcmocks.NewMockInteractiveService(ctrl)
+				artSvc.EXPECT().PublicListByFilter(gomock.Any(), gomock.Any(), 0, 2, domain.ArticleFilter{Tag: "go"}).
+					Return([]domain.Article{
+						{Id: 1, Utime: now},
+						{Id: 2, Utime: now.Add(-time.Hour * 48)},
+					}, nil)
+				intrSvc.EXPECT().GetInterMapByBizIds(gomock.Any(), "article", []int64{1}, int64(-1)).
+					Return(map[int64]domain.Interactive{
+						1: {BizId: 1, LikeCnt: 1},
+					}, nil)
+				return artSvc, intrSvc
+			},
+			board: RankingBoard{
+				Name:     "daily_go",
+				Window:   time.Hour * 24,
+				Filter:   domain.ArticleFilter{Tag: "go"},
+				N:        3,
+				Strategy: likeStrategy{},
+			},
+			wantArts: []domain.Article{
+				{Id: 1, Utime: now},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -62,17 +95,180 @@ func TestRankingTopN(t *testing.T) {
 			svc := &BatchRankingService{
 				artSvc:    artSvc,
 				interSvc:  interSvc,
-				n:         3,
 				batchSize: 2,
-				scoreFunc: func(likeCnt int64, utime time.Time) float64 {
-					return float64(likeCnt + 2)
-				},
+				boards:    map[string]RankingBoard{tc.board.Name: tc.board},
 			}
 
-			arts, err := svc.GetTop100(context.Background())
+			arts, err := svc.GetTopN(context.Background(), tc.board.Name)
 			t.Log("arts: ", arts)
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.wantArts, arts)
 		})
 	}
 }
+
+func TestDecayStrategy(t *testing.T) {
+	now := time.Now()
+	s := NewDecayStrategy()
+	inter := domain.Interactive{LikeCnt: 4}
+	// 经过一个半衰期，分数减半
+	assert.InDelta(t, 4, s.Score(domain.Article{Utime: now}, inter, now), 0.001)
+	assert.InDelta(t, 2, s.Score(domain.Article{Utime: now.Add(-s.HalfLife)}, inter, now), 0.001)
+	assert.Equal(t, -s.LikeWeight, s.Delta(domain.RankingActionUnlike))
+}
+
+func TestHackerNewsStrategy(t *testing.T) {
+	now := time.Now()
+	s := NewHackerNewsStrategy()
+	art := domain.Article{Utime: now}
+	// 收藏的权重比点赞高
+	assert.Greater(t,
+		s.Score(art, domain.Interactive{CollectCnt: 10}, now),
+		s.Score(art, domain.Interactive{LikeCnt: 10}, now))
+	// 同样的互动，新文章排在前面
+	inter := domain.Interactive{ReadCnt: 100, LikeCnt: 10}
+	assert.Greater(t,
+		s.Score(art, inter, now),
+		s.Score(domain.Article{Utime: now.Add(-time.Hour * 24)}, inter, now))
+}
+
+// likeStrategy 只看点赞数
+type likeStrategy struct{}
+
+func (likeStrategy) Score(art domain.Article, inter domain.Interactive, now time.Time) float64 {
+	return float64(inter.LikeCnt + 2)
+}
+
+func TestRankingIncrScores(t *testing.T) {
+	now := time.Now()
+	boards := []RankingBoard{
+		{Name: "daily", Window: time.Hour * 24, N: 10, Strategy: NewDecayStrategy()},
+		{Name: "go", Filter: domain.ArticleFilter{Tag: "go"}, N: 10, Strategy: NewDecayStrategy()},
+		// 全量榜单不受影响
+		{Name: "all_time", N: 10, Strategy: NewHackerNewsStrategy()},
+	}
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (*artrepomocks.MockArticleRepository, *repomocks.MockRankingRepository)
+		action  domain.RankingAction
+		wantErr error
+	}{
+		{
+			name: "按时间窗口和标签计入各个榜单",
+			mock: func(ctrl *gomock.Controller) (*artrepomocks.MockArticleRepository, *repomocks.MockRankingRepository) {
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				// 3 已经下线
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1, 2, 3}).
+					Return([]domain.Article{
+						{Id: 1, Utime: now, Tags: []string{"go"}},
+						{Id: 2, Utime: now.Add(-time.Hour * 48)},
+					}, nil)
+				repo.EXPECT().IncrScores(gomock.Any(), "daily", map[int64]float64{1: 2}).Return(nil)
+				repo.EXPECT().IncrScores(gomock.Any(), "go", map[int64]float64{1: 2}).Return(nil)
+				return artRepo, repo
+			},
+			action: domain.RankingActionCollect,
+		},
+		{
+			name: "查询文章失败",
+			mock: func(ctrl *gomock.Controller) (*artrepomocks.MockArticleRepository, *repomocks.MockRankingRepository) {
+				artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1, 2, 3}).
+					Return(nil, errors.New("db error"))
+				return artRepo, repo
+			},
+			action:  domain.RankingActionLike,
+			wantErr: errors.New("db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			artRepo, repo := tc.mock(ctrl)
+			svc := NewRankingService(nil, nil, artRepo, repo, boards)
+			// 收藏的权重是 2
+			err := svc.IncrScores(context.Background(), tc.action, []int64{1, 2, 3}, []int64{1, 1, 1})
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRankingIncremental(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+	repo := repomocks.NewMockRankingRepository(ctrl)
+	board := RankingBoard{Name: "all", N: 2, Strategy: NewDecayStrategy()}
+	svc := NewRankingService(nil, nil, artRepo, repo, []RankingBoard{board})
+
+	// 增量榜单直接读有序集合
+	repo.EXPECT().GetTopIds(gomock.Any(), "all", 2).Return([]int64{2, 1}, nil)
+	artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{2, 1}).
+		Return([]domain.Article{{Id: 2, Content: "abc"}, {Id: 1}}, nil)
+	arts, err := svc.GetFromCache(context.Background(), "all")
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Article{{Id: 2, Content: "abc"}, {Id: 1}}, arts)
+
+	repo.EXPECT().RemoveArticle(gomock.Any(), []string{"all"}, int64(1)).Return(nil)
+	assert.NoError(t, svc.Remove(context.Background(), 1))
+
+	_, err = svc.GetFromCache(context.Background(), "unknown")
+	assert.Equal(t, ErrRankingBoardNotFound, err)
+}
+
+func TestRankingDecay(t *testing.T) {
+	now := time.Now()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	artSvc := svcmocks.NewMockArticleService(ctrl)
+	interSvc := svcmocks.NewMockInteractiveService(ctrl)
+	repo := repomocks.NewMockRankingRepository(ctrl)
+	strategy := NewDecayStrategy()
+	board := RankingBoard{Name: "all", N: 1, Strategy: strategy}
+	svc := NewRankingService(artSvc, interSvc, nil, repo, []RankingBoard{board})
+
+	// 还没有重建过，先全量重建
+	repo.EXPECT().DecayScores(gomock.Any(), "all", strategy.HalfLife, 10).
+		Return(repository.ErrRankingScoresNotBuilt)
+	artSvc.EXPECT().PublicListByFilter(gomock.Any(), gomock.Any(), 0, 100, domain.ArticleFilter{}).
+		Return([]domain.Article{{Id: 1, Utime: now}}, nil)
+	interSvc.EXPECT().GetInterMapByBizIds(gomock.Any(), "article", []int64{1}, int64(-1)).
+		Return(map[int64]domain.Interactive{1: {LikeCnt: 2}}, nil)
+	repo.EXPECT().ReplaceScores(gomock.Any(), "all", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, board string, scores map[int64]float64) error {
+			assert.InDelta(t, 2, scores[1], 0.01)
+			return nil
+		})
+	assert.NoError(t, svc.Decay(context.Background(), "all"))
+
+	repo.EXPECT().DecayScores(gomock.Any(), "all", strategy.HalfLife, 10).Return(nil)
+	assert.NoError(t, svc.Decay(context.Background(), "all"))
+}
+
+func TestRankingDecay_RemoveExpired(t *testing.T) {
+	now := time.Now()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+	repo := repomocks.NewMockRankingRepository(ctrl)
+	strategy := NewDecayStrategy()
+	board := RankingBoard{Name: "daily", Window: time.Hour * 24, N: 1, Strategy: strategy}
+	svc := NewRankingService(nil, nil, artRepo, repo, []RankingBoard{board})
+
+	repo.EXPECT().DecayScores(gomock.Any(), "daily", strategy.HalfLife, 10).Return(nil)
+	repo.EXPECT().GetTopIds(gomock.Any(), "daily", 10).Return([]int64{1, 2, 3}, nil)
+	// 2 超出了时间窗口，3 已经下线
+	artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1, 2, 3}).
+		Return([]domain.Article{
+			{Id: 1, Utime: now},
+			{Id: 2, Utime: now.Add(-time.Hour * 25)},
+		}, nil)
+	repo.EXPECT().RemoveArticles(gomock.Any(), "daily", []int64{2, 3}).Return(nil)
+	assert.NoError(t, svc.Decay(context.Background(), "daily"))
+}
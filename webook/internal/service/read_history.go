@@ -0,0 +1,98 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	"context"
+	"errors"
+	"time"
+)
+
+type ReadHistoryService interface {
+	// Record 记录阅读，由阅读事件的消费者调用
+	Record(ctx context.Context, evt domain.ReadEvent) error
+	// ShouldCount 是否计入阅读数：去重窗口内同一个用户或同一个 IP 的重复阅读不计入
+	ShouldCount(ctx context.Context, evt domain.ReadEvent) (bool, error)
+	UpdateProgress(ctx context.Context, uid int64, biz string, bizId int64, progress int) error
+	// List 最近阅读，文章会带上线上的内容
+	List(ctx context.Context, uid int64, offset int, limit int) ([]domain.ReadHistory, error)
+	Delete(ctx context.Context, uid int64, biz string, bizIds []int64) error
+	Clear(ctx context.Context, uid int64) error
+}
+
+var ErrReadProgressInvalid = errors.New("阅读进度必须在 0 到 100 之间")
+
+type readHistoryService struct {
+	repo    repository.ReadHistoryRepository
+	artRepo article.ArticleRepository
+	// 去重窗口，为 0 时不去重
+	dedupWindow time.Duration
+}
+
+func NewReadHistoryService(repo repository.ReadHistoryRepository, artRepo article.ArticleRepository,
+	dedupWindow time.Duration) ReadHistoryService {
+	return &readHistoryService{
+		repo:        repo,
+		artRepo:     artRepo,
+		dedupWindow: dedupWindow,
+	}
+}
+
+func (s *readHistoryService) Record(ctx context.Context, evt domain.ReadEvent) error {
+	return s.repo.Record(ctx, evt)
+}
+
+func (s *readHistoryService) ShouldCount(ctx context.Context, evt domain.ReadEvent) (bool, error) {
+	if s.dedupWindow <= 0 {
+		return true, nil
+	}
+	return s.repo.FirstReadInWindow(ctx, evt, s.dedupWindow)
+}
+
+func (s *readHistoryService) UpdateProgress(ctx context.Context, uid int64, biz string, bizId int64, progress int) error {
+	if progress < 0 || progress > 100 {
+		return ErrReadProgressInvalid
+	}
+	return s.repo.UpdateProgress(ctx, uid, biz, bizId, progress)
+}
+
+func (s *readHistoryService) List(ctx context.Context, uid int64, offset int, limit int) ([]domain.ReadHistory, error) {
+	hs, err := s.repo.FindByUid(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var artIds []int64
+	for _, h := range hs {
+		if h.Biz == "article" {
+			artIds = append(artIds, h.BizId)
+		}
+	}
+	if len(artIds) == 0 {
+		return hs, nil
+	}
+	// 已经撤回或删除的文章查不到，阅读记录仍然保留
+	arts, err := s.artRepo.FindPublishedArticlesByIds(ctx, artIds)
+	if err != nil {
+		return nil, err
+	}
+	artMap := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		artMap[art.Id] = art
+	}
+	for i, h := range hs {
+		if h.Biz == "article" {
+			hs[i].Article = artMap[h.BizId]
+		}
+	}
+	return hs, nil
+}
+
+func (s *readHistoryService) Delete(ctx context.Context, uid int64, biz string, bizIds []int64) error {
+	return s.repo.Delete(ctx, uid, biz, bizIds)
+}
+
+func (s *readHistoryService) Clear(ctx context.Context, uid int64) error {
+	return s.repo.DeleteAll(ctx, uid)
+}
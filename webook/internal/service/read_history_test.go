@@ -0,0 +1,87 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	artrepomocks "Webook/webook/internal/repository/article/mocks"
+	repomocks "Webook/webook/internal/repository/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReadHistoryService_ShouldCount(t *testing.T) {
+	evt := domain.ReadEvent{Uid: 1, Biz: "article", BizId: 2, Ip: "127.0.0.1"}
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) repository.ReadHistoryRepository
+		window time.Duration
+
+		wantOk bool
+	}{
+		{
+			name: "窗口内第一次阅读",
+			mock: func(ctrl *gomock.Controller) repository.ReadHistoryRepository {
+				repo := repomocks.NewMockReadHistoryRepository(ctrl)
+				repo.EXPECT().FirstReadInWindow(gomock.Any(), evt, time.Minute).Return(true, nil)
+				return repo
+			},
+			window: time.Minute,
+			wantOk: true,
+		},
+		{
+			name: "窗口内重复阅读",
+			mock: func(ctrl *gomock.Controller) repository.ReadHistoryRepository {
+				repo := repomocks.NewMockReadHistoryRepository(ctrl)
+				repo.EXPECT().FirstReadInWindow(gomock.Any(), evt, time.Minute).Return(false, nil)
+				return repo
+			},
+			window: time.Minute,
+			wantOk: false,
+		},
+		{
+			name: "不去重",
+			mock: func(ctrl *gomock.Controller) repository.ReadHistoryRepository {
+				return repomocks.NewMockReadHistoryRepository(ctrl)
+			},
+			wantOk: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewReadHistoryService(tc.mock(ctrl), nil, tc.window)
+			ok, err := svc.ShouldCount(context.Background(), evt)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantOk, ok)
+		})
+	}
+}
+
+func TestReadHistoryService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := repomocks.NewMockReadHistoryRepository(ctrl)
+	artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+	repo.EXPECT().FindByUid(gomock.Any(), int64(1), 0, 10).Return([]domain.ReadHistory{
+		{Uid: 1, Biz: "article", BizId: 3, Progress: 50},
+		{Uid: 1, Biz: "article", BizId: 2},
+	}, nil)
+	// 文章 2 已经撤回
+	artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{3, 2}).Return([]domain.Article{
+		{Id: 3, Title: "标题"},
+	}, nil)
+
+	svc := NewReadHistoryService(repo, artRepo, time.Minute)
+	hs, err := svc.List(context.Background(), 1, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.ReadHistory{
+		{Uid: 1, Biz: "article", BizId: 3, Progress: 50, Article: domain.Article{Id: 3, Title: "标题"}},
+		{Uid: 1, Biz: "article", BizId: 2},
+	}, hs)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/article"
+	"context"
+	"strings"
+)
+
+type SearchService interface {
+	// SearchArticle 按关键词搜索线上文章，返回当前页的结果和命中总数
+	SearchArticle(ctx context.Context, keyword string, offset int, limit int) ([]domain.ArticleSearchHit, int, error)
+	// RebuildIndex 从线上库重建索引。索引是每个实例本地的，
+	// 启动时用它补上没写进日志的文章，之后由 SyncArticle 增量更新
+	RebuildIndex(ctx context.Context) error
+	// SyncArticle 按线上库中文章的当前状态更新索引：已发表的写入，否则删除。
+	// 不看事件类型，所以事件重复投递、乱序到达都不会把索引改错
+	SyncArticle(ctx context.Context, id int64) error
+}
+
+type searchService struct {
+	repo    article.ArticleSearchRepository
+	artRepo article.ArticleRepository
+}
+
+func NewSearchService(repo article.ArticleSearchRepository, artRepo article.ArticleRepository) SearchService {
+	return &searchService{
+		repo:    repo,
+		artRepo: artRepo,
+	}
+}
+
+const rebuildBatchSize = 500
+
+func (s *searchService) RebuildIndex(ctx context.Context) error {
+	var (
+		arts   []domain.Article
+		lastId int64
+	)
+	for {
+		batch, err := s.artRepo.FindPublishedAfter(ctx, lastId, rebuildBatchSize)
+		if err != nil {
+			return err
+		}
+		arts = append(arts, batch...)
+		if len(batch) < rebuildBatchSize {
+			break
+		}
+		lastId = batch[len(batch)-1].Id
+	}
+	return s.repo.Rebuild(ctx, arts)
+}
+
+func (s *searchService) SyncArticle(ctx context.Context, id int64) error {
+	// 不走缓存，缓存里可能还是发表前的版本
+	arts, err := s.artRepo.FindPublishedArticlesByIds(ctx, []int64{id})
+	if err != nil {
+		return err
+	}
+	if len(arts) == 0 {
+		return s.repo.DeleteArticle(ctx, id)
+	}
+	return s.repo.InputArticle(ctx, arts[0])
+}
+
+func (s *searchService) SearchArticle(ctx context.Context, keyword string, offset int, limit int) ([]domain.ArticleSearchHit, int, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []domain.ArticleSearchHit{}, 0, nil
+	}
+	return s.repo.SearchArticle(ctx, keyword, offset, limit)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository/article"
+	artrepomocks "Webook/webook/internal/repository/article/mocks"
+	"Webook/webook/pkg/search"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchService_SyncArticle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	index, err := search.Open(t.TempDir(), search.Options{})
+	require.NoError(t, err)
+	defer index.Close()
+
+	artRepo := artrepomocks.NewMockArticleRepository(ctrl)
+	svc := NewSearchService(article.NewLocalArticleSearchRepository(index), artRepo)
+
+	// 已发表，写入索引
+	artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1}).
+		Return([]domain.Article{{Id: 1, Title: "缓存一致性", Author: domain.Author{Id: 10}}}, nil)
+	require.NoError(t, svc.SyncArticle(context.Background(), 1))
+	_, total, err := svc.SearchArticle(context.Background(), "缓存", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	// 重复投递的发表事件到达时文章已经撤回，从索引中删除
+	artRepo.EXPECT().FindPublishedArticlesByIds(gomock.Any(), []int64{1}).
+		Return([]domain.Article{}, nil)
+	require.NoError(t, svc.SyncArticle(context.Background(), 1))
+	_, total, err = svc.SearchArticle(context.Background(), "缓存", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
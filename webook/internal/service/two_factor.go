@@ -0,0 +1,266 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/pkg/totp"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("二次验证已经开启")
+	ErrTwoFactorNotEnrolled = errors.New("请先生成二次验证密钥")
+	ErrTwoFactorNotEnabled  = errors.New("二次验证没有开启")
+	ErrInvalidTwoFactorCode = errors.New("验证码不对")
+	ErrTwoFactorLocked      = errors.New("验证码错误次数太多，请稍后再试")
+)
+
+const (
+	twoFactorIssuer = "Webook"
+	// 允许前后各一个时间窗口的时钟偏差
+	totpSkew          = 1
+	recoveryCodeCnt   = 10
+	recoveryCodeBytes = 5
+	// 每个用户在一段时间内最多输错的次数。登录时每次密码校验都会生成新的 challenge，
+	// 只限制 challenge 的次数挡不住暴力破解
+	twoFactorMaxFailures = 10
+)
+
+// TwoFactorService TOTP 二次验证，开启后密码登录需要再输入验证码或者恢复码
+type TwoFactorService interface {
+	Status(ctx context.Context, uid int64) (domain.TwoFactorStatus, error)
+	// Enroll 生成新的密钥，确认之前二次验证不生效
+	Enroll(ctx context.Context, uid int64) (domain.TwoFactorEnrollment, error)
+	// Confirm 校验第一个验证码并开启二次验证，返回一次性的恢复码明文，只有这一次能看到
+	Confirm(ctx context.Context, uid int64, code string) ([]string, error)
+	Enabled(ctx context.Context, uid int64) (bool, error)
+	// Verify 校验验证码或者恢复码，每个验证码和恢复码都只能用一次
+	Verify(ctx context.Context, uid int64, code string) error
+	// Disable 关闭二次验证，需要一个有效的验证码或者恢复码
+	Disable(ctx context.Context, uid int64, code string) error
+}
+
+type twoFactorService struct {
+	repo     repository.TwoFactorRepository
+	userRepo repository.UserRepository
+}
+
+func NewTwoFactorService(repo repository.TwoFactorRepository, userRepo repository.UserRepository) TwoFactorService {
+	return &twoFactorService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (svc *twoFactorService) Status(ctx context.Context, uid int64) (domain.TwoFactorStatus, error) {
+	enabled, err := svc.Enabled(ctx, uid)
+	if err != nil || !enabled {
+		return domain.TwoFactorStatus{}, err
+	}
+	cnt, err := svc.repo.CountRecoveryCodes(ctx, uid)
+	if err != nil {
+		return domain.TwoFactorStatus{}, err
+	}
+	return domain.TwoFactorStatus{
+		Enabled:           true,
+		RecoveryCodesLeft: cnt,
+	}, nil
+}
+
+func (svc *twoFactorService) Enroll(ctx context.Context, uid int64) (domain.TwoFactorEnrollment, error) {
+	user, err := svc.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+	err = svc.repo.SavePending(ctx, uid, secret)
+	if errors.Is(err, repository.ErrTwoFactorConflict) {
+		return domain.TwoFactorEnrollment{}, ErrTwoFactorEnabled
+	}
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+	return domain.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.URI(twoFactorIssuer, svc.account(user), secret),
+	}, nil
+}
+
+// account 验证器里显示的账号
+func (svc *twoFactorService) account(user domain.User) string {
+	switch {
+	case user.Email != "":
+		return user.Email
+	case user.Phone != "":
+		return user.Phone
+	default:
+		return fmt.Sprintf("uid-%d", user.Id)
+	}
+}
+
+func (svc *twoFactorService) Confirm(ctx context.Context, uid int64, code string) ([]string, error) {
+	tf, err := svc.repo.FindByUid(ctx, uid)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	if err = svc.checkLocked(ctx, uid); err != nil {
+		return nil, err
+	}
+	counter, err := totp.Validate(tf.Secret, code, time.Now(), totpSkew)
+	if err != nil {
+		return nil, svc.failed(ctx, uid)
+	}
+
+	codes := make([]string, 0, recoveryCodeCnt)
+	hashes := make([]string, 0, recoveryCodeCnt)
+	for i := 0; i < recoveryCodeCnt; i++ {
+		c, err := svc.newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, svc.hashRecoveryCode(c))
+	}
+	err = svc.repo.Enable(ctx, uid, counter, hashes)
+	if errors.Is(err, repository.ErrTwoFactorConflict) {
+		// 并发确认，另一个请求已经开启了
+		return nil, ErrTwoFactorEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = svc.repo.ResetFailures(ctx, uid)
+	return codes, nil
+}
+
+func (svc *twoFactorService) Enabled(ctx context.Context, uid int64) (bool, error) {
+	tf, err := svc.repo.FindByUid(ctx, uid)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return tf.Enabled, nil
+}
+
+func (svc *twoFactorService) Verify(ctx context.Context, uid int64, code string) error {
+	tf, err := svc.repo.FindByUid(ctx, uid)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if !tf.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if err = svc.checkLocked(ctx, uid); err != nil {
+		return err
+	}
+
+	err = svc.verify(ctx, tf, code)
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		return svc.failed(ctx, uid)
+	}
+	if err == nil {
+		// 失败次数会自动过期，清除失败只是让用户不用等
+		_ = svc.repo.ResetFailures(ctx, uid)
+	}
+	return err
+}
+
+func (svc *twoFactorService) verify(ctx context.Context, tf domain.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		counter, err := totp.Validate(tf.Secret, code, time.Now(), totpSkew)
+		if err != nil {
+			return ErrInvalidTwoFactorCode
+		}
+		err = svc.repo.UseCounter(ctx, tf.Uid, counter)
+		if errors.Is(err, repository.ErrTwoFactorConflict) {
+			// 验证码已经用过了
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+
+	err := svc.repo.UseRecoveryCode(ctx, tf.Uid, svc.hashRecoveryCode(code))
+	if errors.Is(err, repository.ErrTwoFactorConflict) {
+		return ErrInvalidTwoFactorCode
+	}
+	return err
+}
+
+// checkLocked 错误次数太多时锁定一段时间，锁定期间不再校验验证码
+func (svc *twoFactorService) checkLocked(ctx context.Context, uid int64) error {
+	cnt, err := svc.repo.Failures(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if cnt >= twoFactorMaxFailures {
+		return ErrTwoFactorLocked
+	}
+	return nil
+}
+
+// failed 记录一次验证码错误，记录失败时不能当作普通的错误，否则计数失效后可以无限尝试
+func (svc *twoFactorService) failed(ctx context.Context, uid int64) error {
+	cnt, err := svc.repo.IncrFailures(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if cnt >= twoFactorMaxFailures {
+		return ErrTwoFactorLocked
+	}
+	return ErrInvalidTwoFactorCode
+}
+
+func (svc *twoFactorService) Disable(ctx context.Context, uid int64, code string) error {
+	if err := svc.Verify(ctx, uid, code); err != nil {
+		return err
+	}
+	return svc.repo.Delete(ctx, uid)
+}
+
+// newRecoveryCode 形如 abcde-fghij 的随机恢复码
+func (svc *twoFactorService) newRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeBytes*2)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	// 32 个字符，取低 5 位没有偏差
+	const alphabet = "abcdefghijklmnopqrstuvwxyz234567"
+	code := make([]byte, 0, len(buf)+1)
+	for i, b := range buf {
+		if i == recoveryCodeBytes {
+			code = append(code, '-')
+		}
+		code = append(code, alphabet[b&31])
+	}
+	return string(code), nil
+}
+
+// hashRecoveryCode 恢复码是随机生成的，sha256 就足够了。忽略大小写和分隔符
+func (svc *twoFactorService) hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
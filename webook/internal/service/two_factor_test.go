@@ -0,0 +1,239 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	repomocks "Webook/webook/internal/repository/mocks"
+	"Webook/webook/pkg/totp"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	now := time.Now()
+	code, err := totp.Generate(secret, totp.Counter(now))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.TwoFactorRepository
+		code string
+
+		wantCodes int
+		wantErr   error
+	}{
+		{
+			name: "开启成功",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).
+					Return(domain.TwoFactor{Uid: 1, Secret: secret}, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(0), nil)
+				repo.EXPECT().Enable(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, uid int64, counter int64, hashes []string) error {
+						assert.InDelta(t, totp.Counter(now), counter, 1)
+						assert.Len(t, hashes, recoveryCodeCnt)
+						return nil
+					})
+				repo.EXPECT().ResetFailures(gomock.Any(), int64(1)).Return(nil)
+				return repo
+			},
+			code:      code,
+			wantCodes: recoveryCodeCnt,
+		},
+		{
+			name: "没有生成密钥",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).
+					Return(domain.TwoFactor{}, repository.ErrTwoFactorNotFound)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrTwoFactorNotEnrolled,
+		},
+		{
+			name: "已经开启",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).
+					Return(domain.TwoFactor{Uid: 1, Secret: secret, Enabled: true}, nil)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrTwoFactorEnabled,
+		},
+		{
+			name: "验证码不对",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).
+					Return(domain.TwoFactor{Uid: 1, Secret: secret}, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(0), nil)
+				repo.EXPECT().IncrFailures(gomock.Any(), int64(1)).Return(int64(1), nil)
+				return repo
+			},
+			code:    "abcdef",
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "错误次数太多，不再校验",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).
+					Return(domain.TwoFactor{Uid: 1, Secret: secret}, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(twoFactorMaxFailures), nil)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrTwoFactorLocked,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := NewTwoFactorService(tc.mock(ctrl), nil)
+			codes, err := svc.Confirm(context.Background(), 1, tc.code)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Len(t, codes, tc.wantCodes)
+		})
+	}
+}
+
+func TestTwoFactorService_Verify(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	code, err := totp.Generate(secret, totp.Counter(time.Now()))
+	require.NoError(t, err)
+	enabled := domain.TwoFactor{Uid: 1, Secret: secret, Enabled: true}
+	svc := &twoFactorService{}
+
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.TwoFactorRepository
+		code string
+
+		wantErr error
+	}{
+		{
+			name: "验证码正确",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).Return(enabled, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(0), nil)
+				repo.EXPECT().UseCounter(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				repo.EXPECT().ResetFailures(gomock.Any(), int64(1)).Return(nil)
+				return repo
+			},
+			code: code,
+		},
+		{
+			name: "验证码已经用过",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).Return(enabled, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(0), nil)
+				repo.EXPECT().UseCounter(gomock.Any(), int64(1), gomock.Any()).
+					Return(repository.ErrTwoFactorConflict)
+				repo.EXPECT().IncrFailures(gomock.Any(), int64(1)).Return(int64(1), nil)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "恢复码，忽略大小写",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).Return(enabled, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(0), nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), int64(1), svc.hashRecoveryCode("abcde-fghij")).
+					Return(nil)
+				repo.EXPECT().ResetFailures(gomock.Any(), int64(1)).Return(nil)
+				return repo
+			},
+			code: "ABCDE-FGHIJ",
+		},
+		{
+			name: "恢复码已经用过",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).Return(enabled, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(0), nil)
+				repo.EXPECT().UseRecoveryCode(gomock.Any(), int64(1), gomock.Any()).
+					Return(repository.ErrTwoFactorConflict)
+				repo.EXPECT().IncrFailures(gomock.Any(), int64(1)).Return(int64(1), nil)
+				return repo
+			},
+			code:    "abcde-fghij",
+			wantErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "错误次数达到上限后锁定",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).Return(enabled, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(twoFactorMaxFailures-1), nil)
+				repo.EXPECT().IncrFailures(gomock.Any(), int64(1)).Return(int64(twoFactorMaxFailures), nil)
+				return repo
+			},
+			code:    "000000",
+			wantErr: ErrTwoFactorLocked,
+		},
+		{
+			name: "锁定期间正确的验证码也不行",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).Return(enabled, nil)
+				repo.EXPECT().Failures(gomock.Any(), int64(1)).Return(int64(twoFactorMaxFailures), nil)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrTwoFactorLocked,
+		},
+		{
+			name: "没有开启",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).
+					Return(domain.TwoFactor{Uid: 1, Secret: secret}, nil)
+				return repo
+			},
+			code:    code,
+			wantErr: ErrTwoFactorNotEnabled,
+		},
+		{
+			name: "查询失败",
+			mock: func(ctrl *gomock.Controller) repository.TwoFactorRepository {
+				repo := repomocks.NewMockTwoFactorRepository(ctrl)
+				repo.EXPECT().FindByUid(gomock.Any(), int64(1)).
+					Return(domain.TwoFactor{}, errors.New("db error"))
+				return repo
+			},
+			code:    code,
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := NewTwoFactorService(tc.mock(ctrl), nil)
+			err := svc.Verify(context.Background(), 1, tc.code)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
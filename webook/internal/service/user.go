@@ -3,9 +3,15 @@ package service
 import (
 	"Webook/webook/internal/domain"
 	"Webook/webook/internal/repository"
+	"Webook/webook/internal/service/mail"
 	"Webook/webook/pkg/logger"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -18,17 +24,33 @@ type UserService interface {
 	FindOrCreateByWechat(ctx context.Context, wechatInfo domain.WechatInfo) (domain.User, error)
 	Edit(ctx context.Context, user domain.User) error
 	GetNameMapByIds(ctx context.Context, ids []int64) (map[int64]string, error)
+	// ChangePassword 登录状态下修改密码，需要原密码
+	ChangePassword(ctx context.Context, uid int64, oldPassword, newPassword string) error
+	// SendPasswordReset 给邮箱发送一次性的重置 token，邮箱没有注册时什么也不做。
+	// 频率限制和返回值都和邮箱是否注册无关
+	SendPasswordReset(ctx context.Context, email string) error
+	// ResetPassword 用重置 token 设置新密码，返回用户 id
+	ResetPassword(ctx context.Context, token string, newPassword string) (int64, error)
 }
 
 type UserServiceStruct struct {
-	repo   repository.UserRepository
-	logger logger.Logger
+	repo repository.UserRepository
+	// 忘记密码
+	resetRepo repository.PasswordResetRepository
+	mailSvc   mail.Service
+	// 注册后通知订阅了事件的 webhook
+	webhookSvc WebhookService
+	logger     logger.Logger
 }
 
-func NewUserService(repo repository.UserRepository, l logger.Logger) UserService {
+func NewUserService(repo repository.UserRepository, resetRepo repository.PasswordResetRepository, mailSvc mail.Service,
+	webhookSvc WebhookService, l logger.Logger) UserService {
 	return &UserServiceStruct{
-		repo:   repo,
-		logger: l,
+		repo:       repo,
+		resetRepo:  resetRepo,
+		mailSvc:    mailSvc,
+		webhookSvc: webhookSvc,
+		logger:     l,
 	}
 }
 
@@ -43,7 +65,29 @@ func (svc *UserServiceStruct) SignUp(ctx context.Context, user domain.User) erro
 	user.Password = string(hashedPassword)
 
 	// 调用 repository 层进行注册
-	return svc.repo.Create(ctx, user)
+	err = svc.repo.Create(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	// Create 不返回 id，查询刚创建的用户
+	u, err := svc.repo.FindByEmail(ctx, user.Email)
+	if err != nil {
+		svc.logger.Error("注册后查询用户失败，无法通知 webhook",
+			logger.String("email", user.Email),
+			logger.Error(err),
+		)
+		return nil
+	}
+	svc.userCreated(ctx, u)
+	return nil
+}
+
+// userCreated 通知 webhook 有新用户注册
+func (svc *UserServiceStruct) userCreated(ctx context.Context, user domain.User) {
+	dispatchWebhook(ctx, svc.webhookSvc, svc.logger, domain.WebhookEventUserCreated, user.Id, webhookUserData{
+		Id: user.Id,
+	})
 }
 
 var ErrInvalidUserOrPassword = errors.New("邮箱或密码不对")
@@ -95,10 +139,15 @@ func (svc *UserServiceStruct) FindOrCreate(ctx context.Context, phone string) (d
 	if err != nil && err != repository.ErrUserDuplicate {
 		return domain.User{}, err
 	}
+	// 并发创建时冲突的一方不是新用户
+	created := err == nil
 
 	// 根据 phone 查询刚创建的用户
 	// 这里会碰到主从延迟的问题，可能查询不到（
 	user, err = svc.repo.FindByPhone(ctx, phone)
+	if err == nil && created {
+		svc.userCreated(ctx, user)
+	}
 	return user, err
 }
 
@@ -119,10 +168,107 @@ func (svc *UserServiceStruct) FindOrCreateByWechat(ctx context.Context, wechatIn
 	if err != nil && err != repository.ErrUserDuplicate {
 		return domain.User{}, err
 	}
+	created := err == nil
 
-	return svc.repo.FindByWechat(ctx, wechatInfo.OpenId)
+	user, err = svc.repo.FindByWechat(ctx, wechatInfo.OpenId)
+	if err == nil && created {
+		svc.userCreated(ctx, user)
+	}
+	return user, err
 }
 
 func (svc *UserServiceStruct) GetNameMapByIds(ctx context.Context, ids []int64) (map[int64]string, error) {
 	return svc.repo.GetNameMapByIds(ctx, ids)
 }
+
+var (
+	ErrWrongPassword            = errors.New("原密码不对")
+	ErrPasswordResetTooFrequent = repository.ErrPasswordResetTooFrequent
+	ErrPasswordResetInvalid     = repository.ErrPasswordResetNotFound
+)
+
+func (svc *UserServiceStruct) ChangePassword(ctx context.Context, uid int64, oldPassword, newPassword string) error {
+	user, err := svc.repo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// 手机号和微信注册的用户没有密码，也走不到这里
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword))
+	if err != nil {
+		return ErrWrongPassword
+	}
+	return svc.updatePassword(ctx, uid, newPassword)
+}
+
+func (svc *UserServiceStruct) SendPasswordReset(ctx context.Context, email string) error {
+	// 先限制频率，否则没注册的邮箱永远不会太频繁，可以用来判断邮箱是否注册过
+	if err := svc.resetRepo.Limit(ctx, email); err != nil {
+		return err
+	}
+	user, err := svc.repo.FindByEmail(ctx, email)
+	if err == repository.ErrUserNotFound {
+		// 不告诉调用方邮箱是否注册过
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	err = svc.resetRepo.Store(ctx, svc.hashResetToken(token), domain.PasswordReset{
+		Uid:         user.Id,
+		Fingerprint: svc.passwordFingerprint(user.Password),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("你正在重置 Webook 的密码，重置 token 为：%s\n15 分钟内有效，只能使用一次。如果不是你本人操作，请忽略这封邮件。", token)
+	return svc.mailSvc.Send(ctx, email, "重置 Webook 密码", body)
+}
+
+func (svc *UserServiceStruct) ResetPassword(ctx context.Context, token string, newPassword string) (int64, error) {
+	if token == "" {
+		return 0, ErrPasswordResetInvalid
+	}
+	reset, err := svc.resetRepo.Take(ctx, svc.hashResetToken(token))
+	if err != nil {
+		return 0, err
+	}
+	user, err := svc.repo.FindById(ctx, reset.Uid)
+	if err != nil {
+		return 0, err
+	}
+	// 发出 token 之后密码已经改过了
+	if svc.passwordFingerprint(user.Password) != reset.Fingerprint {
+		return 0, ErrPasswordResetInvalid
+	}
+	if err = svc.updatePassword(ctx, user.Id, newPassword); err != nil {
+		return 0, err
+	}
+	svc.logger.Info("用户重置了密码", logger.Int64("uid", user.Id))
+	return user.Id, nil
+}
+
+func (svc *UserServiceStruct) updatePassword(ctx context.Context, uid int64, password string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return svc.repo.UpdatePassword(ctx, uid, string(hashed))
+}
+
+// hashResetToken 只保存 token 的哈希，Redis 泄露也拿不到能用的 token
+func (svc *UserServiceStruct) hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (svc *UserServiceStruct) passwordFingerprint(hashed string) string {
+	sum := sha256.Sum256([]byte(hashed))
+	return hex.EncodeToString(sum[:8])
+}
@@ -4,6 +4,7 @@ import (
 	"Webook/webook/internal/domain"
 	"Webook/webook/internal/repository"
 	repomocks "Webook/webook/internal/repository/mocks"
+	"Webook/webook/pkg/logger"
 	"context"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/go-playground/assert/v2"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -135,7 +137,7 @@ func Test_UserServiceStruct_Login(t *testing.T) {
 			defer ctrl.Finish()
 
 			repo := tc.mock(ctrl)
-			svc := NewUserService(repo, nil)
+			svc := NewUserService(repo, nil, nil, nil, nil)
 			user, err := svc.Login(tc.ctx, tc.email, tc.password)
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.wantUser, user)
@@ -143,6 +145,190 @@ func Test_UserServiceStruct_Login(t *testing.T) {
 	}
 }
 
+func Test_UserServiceStruct_ChangePassword(t *testing.T) {
+	// 123456#qwer 的加密结果
+	const hashed = "$2a$10$teTdyp4lF/nxYQT506m.cu7z9XylX61m6Sg0zLoWdhcBIa0cGY0em"
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.UserRepository
+
+		oldPassword string
+		wantErr     error
+	}{
+		{
+			name: "修改成功",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Password: hashed}, nil)
+				repo.EXPECT().UpdatePassword(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, password string) error {
+						// 保存的是新密码的加密结果
+						return bcrypt.CompareHashAndPassword([]byte(password), []byte("654321#qwer"))
+					})
+				return repo
+			},
+			oldPassword: "123456#qwer",
+		},
+		{
+			name: "原密码不对",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Password: hashed}, nil)
+				return repo
+			},
+			oldPassword: "123456#qwe",
+			wantErr:     ErrWrongPassword,
+		},
+		{
+			name: "没有设置过密码",
+			mock: func(ctrl *gomock.Controller) repository.UserRepository {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Phone: "13512345678"}, nil)
+				return repo
+			},
+			oldPassword: "",
+			wantErr:     ErrWrongPassword,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := NewUserService(tc.mock(ctrl), nil, nil, nil, nil)
+			err := svc.ChangePassword(context.Background(), 1, tc.oldPassword, "654321#qwer")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_UserServiceStruct_ResetPassword(t *testing.T) {
+	const hashed = "$2a$10$teTdyp4lF/nxYQT506m.cu7z9XylX61m6Sg0zLoWdhcBIa0cGY0em"
+	helper := &UserServiceStruct{}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository)
+
+		token   string
+		wantUid int64
+		wantErr error
+	}{
+		{
+			name: "重置成功",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				resetRepo := repomocks.NewMockPasswordResetRepository(ctrl)
+				resetRepo.EXPECT().Take(gomock.Any(), helper.hashResetToken("token")).
+					Return(domain.PasswordReset{Uid: 1, Fingerprint: helper.passwordFingerprint(hashed)}, nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Password: hashed}, nil)
+				repo.EXPECT().UpdatePassword(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				return repo, resetRepo
+			},
+			token:   "token",
+			wantUid: 1,
+		},
+		{
+			name: "token 无效",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				resetRepo := repomocks.NewMockPasswordResetRepository(ctrl)
+				resetRepo.EXPECT().Take(gomock.Any(), helper.hashResetToken("token")).
+					Return(domain.PasswordReset{}, repository.ErrPasswordResetNotFound)
+				return repo, resetRepo
+			},
+			token:   "token",
+			wantErr: ErrPasswordResetInvalid,
+		},
+		{
+			name: "发出 token 之后改过密码",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				resetRepo := repomocks.NewMockPasswordResetRepository(ctrl)
+				resetRepo.EXPECT().Take(gomock.Any(), helper.hashResetToken("token")).
+					Return(domain.PasswordReset{Uid: 1, Fingerprint: helper.passwordFingerprint("old")}, nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Password: hashed}, nil)
+				return repo, resetRepo
+			},
+			token:   "token",
+			wantErr: ErrPasswordResetInvalid,
+		},
+		{
+			name: "没有 token",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository) {
+				return repomocks.NewMockUserRepository(ctrl), repomocks.NewMockPasswordResetRepository(ctrl)
+			},
+			wantErr: ErrPasswordResetInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo, resetRepo := tc.mock(ctrl)
+			svc := NewUserService(repo, resetRepo, nil, nil, logger.NewZapLogger(zap.NewNop()))
+			uid, err := svc.ResetPassword(context.Background(), tc.token, "654321#qwer")
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantUid, uid)
+		})
+	}
+}
+
+func Test_UserServiceStruct_SendPasswordReset(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository)
+
+		wantErr error
+	}{
+		{
+			name: "邮箱没有注册，和发送成功的返回一样",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				resetRepo := repomocks.NewMockPasswordResetRepository(ctrl)
+				resetRepo.EXPECT().Limit(gomock.Any(), "123@qq.com").Return(nil)
+				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").Return(domain.User{}, repository.ErrUserNotFound)
+				return repo, resetRepo
+			},
+		},
+		{
+			name: "发送太频繁，不查询邮箱是否注册",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				resetRepo := repomocks.NewMockPasswordResetRepository(ctrl)
+				resetRepo.EXPECT().Limit(gomock.Any(), "123@qq.com").Return(repository.ErrPasswordResetTooFrequent)
+				return repo, resetRepo
+			},
+			wantErr: ErrPasswordResetTooFrequent,
+		},
+		{
+			name: "查询用户失败",
+			mock: func(ctrl *gomock.Controller) (repository.UserRepository, repository.PasswordResetRepository) {
+				repo := repomocks.NewMockUserRepository(ctrl)
+				resetRepo := repomocks.NewMockPasswordResetRepository(ctrl)
+				resetRepo.EXPECT().Limit(gomock.Any(), "123@qq.com").Return(nil)
+				repo.EXPECT().FindByEmail(gomock.Any(), "123@qq.com").Return(domain.User{}, errors.New("db error"))
+				return repo, resetRepo
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo, resetRepo := tc.mock(ctrl)
+			svc := NewUserService(repo, resetRepo, nil, nil, logger.NewZapLogger(zap.NewNop()))
+			err := svc.SendPasswordReset(context.Background(), "123@qq.com")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
 func TestXXX(t *testing.T) {
 	password := "123456#qwer"
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	"Webook/webook/pkg/logger"
+	"Webook/webook/pkg/webhook"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type WebhookService interface {
+	// Create 注册 webhook，Secret 为空时自动生成，返回的 Secret 之后不会再展示
+	Create(ctx context.Context, w domain.Webhook) (domain.Webhook, error)
+	Delete(ctx context.Context, uid int64, id int64) error
+	List(ctx context.Context, uid int64) ([]domain.Webhook, error)
+	// Deliveries 投递日志，只有 webhook 的所有者可以查看
+	Deliveries(ctx context.Context, uid int64, webhookId int64, offset int, limit int) ([]domain.WebhookDelivery, error)
+	// Redeliver 原样重新发送一次投递：生成一条新的投递记录并立即发送，返回发送结果
+	Redeliver(ctx context.Context, uid int64, deliveryId int64) (domain.WebhookDelivery, error)
+
+	// Dispatch 为订阅了事件的 webhook 生成待发送的投递，由定时任务发送
+	// owner 是事件涉及的资源的所有者，只投递给所有者注册的 webhook；全局事件投递给管理员注册的 webhook，忽略 owner
+	Dispatch(ctx context.Context, evt domain.WebhookEventType, owner int64, data any) error
+	// DeliverDue 发送到期的投递，失败的按指数退避安排重试，返回发送成功的数量
+	DeliverDue(ctx context.Context, now time.Time, batchSize int) (int, error)
+}
+
+var (
+	ErrWebhookNotFound         = repository.ErrWebhookNotFound
+	ErrWebhookDeliveryNotFound = repository.ErrWebhookDeliveryNotFound
+	ErrWebhookUrlInvalid       = errors.New("webhook 地址必须是公网的 http 或 https 地址")
+	ErrWebhookEventInvalid     = errors.New("不支持的 webhook 事件")
+	ErrWebhookEventForbidden   = errors.New("只有管理员可以订阅该事件")
+)
+
+const (
+	// 最多发送的次数，超过后不再重试
+	webhookMaxAttempts = 8
+	// 第 n 次失败后等待 webhookRetryBase * 2^(n-1) 再重试，最长 webhookRetryMax
+	webhookRetryBase = time.Second * 30
+	webhookRetryMax  = time.Hour * 6
+	// 抢到投递后的租约，发送超时的投递会在租约过期后被重新发送
+	webhookDeliveryLease = time.Minute
+	// 投递日志中保留的错误信息长度
+	webhookErrorLength = 1000
+)
+
+// webhookPayload 请求体
+type webhookPayload struct {
+	Event domain.WebhookEventType `json:"event"`
+	// 事件发生的时间，毫秒时间戳
+	Timestamp int64 `json:"timestamp"`
+	Data      any   `json:"data"`
+}
+
+// 各事件的 data
+type webhookArticleData struct {
+	Id       int64  `json:"id"`
+	AuthorId int64  `json:"author_id"`
+	Title    string `json:"title,omitempty"`
+	Abstract string `json:"abstract,omitempty"`
+}
+
+type webhookLikeData struct {
+	Biz    string `json:"biz"`
+	BizId  int64  `json:"biz_id"`
+	UserId int64  `json:"user_id"`
+}
+
+type webhookUserData struct {
+	Id int64 `json:"id"`
+}
+
+type webhookService struct {
+	repo repository.WebhookRepository
+	// 生产环境的客户端只连接公网地址，见 webhook.NewClient
+	client *http.Client
+	// 可以订阅全局事件的管理员
+	admins []int64
+	logger logger.Logger
+}
+
+func NewWebhookService(repo repository.WebhookRepository, client *http.Client, admins []int64, l logger.Logger) WebhookService {
+	return &webhookService{
+		repo:   repo,
+		client: client,
+		admins: admins,
+		logger: l,
+	}
+}
+
+func (s *webhookService) Create(ctx context.Context, w domain.Webhook) (domain.Webhook, error) {
+	u, err := url.Parse(w.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return domain.Webhook{}, ErrWebhookUrlInvalid
+	}
+	// 域名要到发送时解析后才能检查，这里只提前拒绝写死的内网 IP
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !webhook.AllowedAddr(addr) {
+		return domain.Webhook{}, ErrWebhookUrlInvalid
+	}
+	if len(w.Events) == 0 {
+		return domain.Webhook{}, ErrWebhookEventInvalid
+	}
+	events := make([]domain.WebhookEventType, 0, len(w.Events))
+	for _, evt := range w.Events {
+		if !evt.Valid() {
+			return domain.Webhook{}, ErrWebhookEventInvalid
+		}
+		if evt.Global() && !slices.Contains(s.admins, w.Uid) {
+			return domain.Webhook{}, ErrWebhookEventForbidden
+		}
+		if !(domain.Webhook{Events: events}).Subscribed(evt) {
+			events = append(events, evt)
+		}
+	}
+	w.Events = events
+
+	if w.Secret == "" {
+		secret := make([]byte, 16)
+		if _, err = rand.Read(secret); err != nil {
+			return domain.Webhook{}, err
+		}
+		w.Secret = hex.EncodeToString(secret)
+	}
+	w.Id, err = s.repo.Create(ctx, w)
+	return w, err
+}
+
+func (s *webhookService) Delete(ctx context.Context, uid int64, id int64) error {
+	return s.repo.Delete(ctx, id, uid)
+}
+
+func (s *webhookService) List(ctx context.Context, uid int64) ([]domain.Webhook, error) {
+	return s.repo.FindByUid(ctx, uid)
+}
+
+func (s *webhookService) Deliveries(ctx context.Context, uid int64, webhookId int64, offset int, limit int) ([]domain.WebhookDelivery, error) {
+	w, err := s.repo.FindById(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+	if w.Uid != uid {
+		return nil, ErrWebhookNotFound
+	}
+	return s.repo.FindDeliveries(ctx, webhookId, offset, limit)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, uid int64, deliveryId int64) (domain.WebhookDelivery, error) {
+	old, err := s.repo.FindDeliveryById(ctx, deliveryId)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	w, err := s.repo.FindById(ctx, old.WebhookId)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if w.Uid != uid {
+		return domain.WebhookDelivery{}, ErrWebhookDeliveryNotFound
+	}
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	d := domain.WebhookDelivery{
+		WebhookId:   w.Id,
+		Event:       old.Event,
+		Payload:     old.Payload,
+		Status:      domain.WebhookDeliveryStatusPending,
+		NextRetryAt: now,
+	}
+	ids, err := s.repo.CreateDeliveries(ctx, []domain.WebhookDelivery{d})
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	d.Id, d.Ctime, d.Utime = ids[0], now, now
+	// 先抢占，避免定时任务同时发送
+	ok, err := s.repo.ClaimDelivery(ctx, d, now.Add(webhookDeliveryLease))
+	if err != nil || !ok {
+		return d, err
+	}
+	d = s.send(ctx, w, d)
+	return d, s.repo.UpdateDeliveryResult(ctx, d)
+}
+
+func (s *webhookService) Dispatch(ctx context.Context, evt domain.WebhookEventType, owner int64, data any) error {
+	uids := []int64{owner}
+	if evt.Global() {
+		uids = s.admins
+	}
+	hooks, err := s.repo.FindSubscribers(ctx, evt, uids)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+	now := time.Now()
+	payload, err := json.Marshal(webhookPayload{
+		Event:     evt,
+		Timestamp: now.UnixMilli(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+	ds := make([]domain.WebhookDelivery, 0, len(hooks))
+	for _, w := range hooks {
+		ds = append(ds, domain.WebhookDelivery{
+			WebhookId:   w.Id,
+			Event:       evt,
+			Payload:     string(payload),
+			Status:      domain.WebhookDeliveryStatusPending,
+			NextRetryAt: now,
+		})
+	}
+	_, err = s.repo.CreateDeliveries(ctx, ds)
+	return err
+}
+
+func (s *webhookService) DeliverDue(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	ds, err := s.repo.FindDueDeliveries(ctx, now, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	cnt := 0
+	hooks := make(map[int64]domain.Webhook)
+	for _, d := range ds {
+		// 超时了就留给下一轮任务
+		if ctx.Err() != nil {
+			return cnt, ctx.Err()
+		}
+		ok, err := s.repo.ClaimDelivery(ctx, d, now.Add(webhookDeliveryLease))
+		if err != nil {
+			return cnt, err
+		}
+		// 被其他实例抢走了
+		if !ok {
+			continue
+		}
+		w, ok := hooks[d.WebhookId]
+		if !ok {
+			w, err = s.repo.FindById(ctx, d.WebhookId)
+			if err != nil {
+				return cnt, err
+			}
+			hooks[w.Id] = w
+		}
+
+		d = s.send(ctx, w, d)
+		if err = s.repo.UpdateDeliveryResult(ctx, d); err != nil {
+			return cnt, err
+		}
+		if d.Status == domain.WebhookDeliveryStatusSucceeded {
+			cnt++
+		}
+	}
+	return cnt, nil
+}
+
+// send 发送一次，返回更新了结果的投递：2xx 视为成功，否则按指数退避安排重试
+func (s *webhookService) send(ctx context.Context, w domain.Webhook, d domain.WebhookDelivery) domain.WebhookDelivery {
+	d.Attempts++
+	d.StatusCode, d.Error = s.post(ctx, w, d)
+	if d.StatusCode >= 200 && d.StatusCode < 300 {
+		d.Status = domain.WebhookDeliveryStatusSucceeded
+		return d
+	}
+	if d.Attempts >= webhookMaxAttempts {
+		d.Status = domain.WebhookDeliveryStatusFailed
+		return d
+	}
+	d.Status = domain.WebhookDeliveryStatusPending
+	d.NextRetryAt = time.Now().Add(webhookBackoff(d.Attempts))
+	return d
+}
+
+// post 发送签名后的请求，返回响应码，请求失败时响应码为 0，同时返回错误信息
+// 响应内容不读取也不保存，避免投递日志变成读取内网服务的途径
+func (s *webhookService) post(ctx context.Context, w domain.Webhook, d domain.WebhookDelivery) (int, string) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.HeaderEvent, string(d.Event))
+	req.Header.Set(webhook.HeaderDelivery, strconv.FormatInt(d.Id, 10))
+	req.Header.Set(webhook.HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(w.Secret, ts, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, truncateError(err.Error())
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, ""
+}
+
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookRetryBase << (attempts - 1)
+	if backoff <= 0 || backoff > webhookRetryMax {
+		return webhookRetryMax
+	}
+	return backoff
+}
+
+func truncateError(s string) string {
+	s = strings.ToValidUTF8(s, "")
+	runes := []rune(s)
+	if len(runes) > webhookErrorLength {
+		runes = runes[:webhookErrorLength]
+	}
+	return string(runes)
+}
+
+// dispatchWebhook 业务代码里触发 webhook，失败不影响业务，只记录日志
+func dispatchWebhook(ctx context.Context, svc WebhookService, l logger.Logger, evt domain.WebhookEventType, owner int64, data any) {
+	if err := svc.Dispatch(ctx, evt, owner, data); err != nil {
+		l.Error("生成 webhook 投递失败",
+			logger.String("event", string(evt)),
+			logger.Error(err),
+		)
+	}
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/repository"
+	repomocks "Webook/webook/internal/repository/mocks"
+	"Webook/webook/pkg/webhook"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhookService_DeliverDue(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	payload := `{"event":"article.published","timestamp":1,"data":{"id":1,"author_id":2}}`
+	due := domain.WebhookDelivery{
+		Id:          10,
+		WebhookId:   1,
+		Event:       domain.WebhookEventArticlePublished,
+		Payload:     payload,
+		Status:      domain.WebhookDeliveryStatusPending,
+		NextRetryAt: now,
+	}
+
+	testCases := []struct {
+		name string
+		// 接收方的响应码
+		statusCode int
+		mock       func(ctrl *gomock.Controller, url string) repository.WebhookRepository
+
+		wantCnt int
+		wantErr error
+		// 校验写回的发送结果
+		wantDelivery func(t *testing.T, d domain.WebhookDelivery)
+	}{
+		{
+			name:       "发送成功",
+			statusCode: http.StatusOK,
+			mock: func(ctrl *gomock.Controller, url string) repository.WebhookRepository {
+				repo := repomocks.NewMockWebhookRepository(ctrl)
+				repo.EXPECT().FindDueDeliveries(gomock.Any(), now, 10).Return([]domain.WebhookDelivery{due}, nil)
+				repo.EXPECT().ClaimDelivery(gomock.Any(), due, now.Add(webhookDeliveryLease)).Return(true, nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Webhook{
+					Id:     1,
+					Url:    url,
+					Secret: "secret",
+				}, nil)
+				return repo
+			},
+			wantCnt: 1,
+			wantDelivery: func(t *testing.T, d domain.WebhookDelivery) {
+				assert.Equal(t, domain.WebhookDeliveryStatusSucceeded, d.Status)
+				assert.Equal(t, 1, d.Attempts)
+				assert.Equal(t, http.StatusOK, d.StatusCode)
+				assert.Empty(t, d.Error)
+			},
+		},
+		{
+			name:       "发送失败，按指数退避重试",
+			statusCode: http.StatusInternalServerError,
+			mock: func(ctrl *gomock.Controller, url string) repository.WebhookRepository {
+				repo := repomocks.NewMockWebhookRepository(ctrl)
+				d := due
+				d.Attempts = 2
+				repo.EXPECT().FindDueDeliveries(gomock.Any(), now, 10).Return([]domain.WebhookDelivery{d}, nil)
+				repo.EXPECT().ClaimDelivery(gomock.Any(), d, now.Add(webhookDeliveryLease)).Return(true, nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Webhook{
+					Id:     1,
+					Url:    url,
+					Secret: "secret",
+				}, nil)
+				return repo
+			},
+			wantCnt: 0,
+			wantDelivery: func(t *testing.T, d domain.WebhookDelivery) {
+				assert.Equal(t, domain.WebhookDeliveryStatusPending, d.Status)
+				assert.Equal(t, 3, d.Attempts)
+				assert.Equal(t, http.StatusInternalServerError, d.StatusCode)
+				// 第 3 次失败后等待 2 分钟
+				assert.WithinDuration(t, time.Now().Add(time.Minute*2), d.NextRetryAt, time.Second*5)
+			},
+		},
+		{
+			name:       "超过最大次数，不再重试",
+			statusCode: http.StatusBadGateway,
+			mock: func(ctrl *gomock.Controller, url string) repository.WebhookRepository {
+				repo := repomocks.NewMockWebhookRepository(ctrl)
+				d := due
+				d.Attempts = webhookMaxAttempts - 1
+				repo.EXPECT().FindDueDeliveries(gomock.Any(), now, 10).Return([]domain.WebhookDelivery{d}, nil)
+				repo.EXPECT().ClaimDelivery(gomock.Any(), d, now.Add(webhookDeliveryLease)).Return(true, nil)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Webhook{
+					Id:     1,
+					Url:    url,
+					Secret: "secret",
+				}, nil)
+				return repo
+			},
+			wantCnt: 0,
+			wantDelivery: func(t *testing.T, d domain.WebhookDelivery) {
+				assert.Equal(t, domain.WebhookDeliveryStatusFailed, d.Status)
+				assert.Equal(t, webhookMaxAttempts, d.Attempts)
+			},
+		},
+		{
+			name: "被其他实例抢走，跳过",
+			mock: func(ctrl *gomock.Controller, url string) repository.WebhookRepository {
+				repo := repomocks.NewMockWebhookRepository(ctrl)
+				repo.EXPECT().FindDueDeliveries(gomock.Any(), now, 10).Return([]domain.WebhookDelivery{due}, nil)
+				repo.EXPECT().ClaimDelivery(gomock.Any(), due, now.Add(webhookDeliveryLease)).Return(false, nil)
+				return repo
+			},
+			wantCnt: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				// 接收方按文档校验签名
+				require.NoError(t, webhook.Verify("secret", r.Header, body, time.Now(), time.Minute))
+				assert.Equal(t, payload, string(body))
+				assert.Equal(t, string(domain.WebhookEventArticlePublished), r.Header.Get(webhook.HeaderEvent))
+				assert.Equal(t, "10", r.Header.Get(webhook.HeaderDelivery))
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			repo := tc.mock(ctrl, server.URL).(*repomocks.MockWebhookRepository)
+			if tc.wantDelivery != nil {
+				repo.EXPECT().UpdateDeliveryResult(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, d domain.WebhookDelivery) error {
+						tc.wantDelivery(t, d)
+						return nil
+					})
+			}
+			svc := NewWebhookService(repo, server.Client(), nil, nil)
+			cnt, err := svc.DeliverDue(context.Background(), now, 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+		})
+	}
+}
+
+func TestWebhookService_Create(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.WebhookRepository
+
+		webhook domain.Webhook
+		wantErr error
+	}{
+		{
+			name: "创建成功，去掉重复的事件并生成密钥",
+			mock: func(ctrl *gomock.Controller) repository.WebhookRepository {
+				repo := repomocks.NewMockWebhookRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, w domain.Webhook) (int64, error) {
+						assert.Equal(t, []domain.WebhookEventType{
+							domain.WebhookEventArticlePublished,
+							domain.WebhookEventUserCreated,
+						}, w.Events)
+						assert.Len(t, w.Secret, 32)
+						return 1, nil
+					})
+				return repo
+			},
+			webhook: domain.Webhook{
+				Uid: 2,
+				Url: "https://example.com/hook",
+				Events: []domain.WebhookEventType{
+					domain.WebhookEventArticlePublished,
+					domain.WebhookEventUserCreated,
+					domain.WebhookEventArticlePublished,
+				},
+			},
+		},
+		{
+			name: "地址不是 http",
+			mock: func(ctrl *gomock.Controller) repository.WebhookRepository {
+				return repomocks.NewMockWebhookRepository(ctrl)
+			},
+			webhook: domain.Webhook{
+				Url:    "ftp://example.com",
+				Events: []domain.WebhookEventType{domain.WebhookEventArticlePublished},
+			},
+			wantErr: ErrWebhookUrlInvalid,
+		},
+		{
+			name: "内网地址",
+			mock: func(ctrl *gomock.Controller) repository.WebhookRepository {
+				return repomocks.NewMockWebhookRepository(ctrl)
+			},
+			webhook: domain.Webhook{
+				Url:    "http://169.254.169.254/latest/meta-data",
+				Events: []domain.WebhookEventType{domain.WebhookEventArticlePublished},
+			},
+			wantErr: ErrWebhookUrlInvalid,
+		},
+		{
+			name: "普通用户订阅全局事件",
+			mock: func(ctrl *gomock.Controller) repository.WebhookRepository {
+				return repomocks.NewMockWebhookRepository(ctrl)
+			},
+			webhook: domain.Webhook{
+				Uid:    3,
+				Url:    "https://example.com/hook",
+				Events: []domain.WebhookEventType{domain.WebhookEventUserCreated},
+			},
+			wantErr: ErrWebhookEventForbidden,
+		},
+		{
+			name: "事件不支持",
+			mock: func(ctrl *gomock.Controller) repository.WebhookRepository {
+				return repomocks.NewMockWebhookRepository(ctrl)
+			},
+			webhook: domain.Webhook{
+				Url:    "https://example.com/hook",
+				Events: []domain.WebhookEventType{"article.liked"},
+			},
+			wantErr: ErrWebhookEventInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			// 用户 2 是管理员
+			svc := NewWebhookService(tc.mock(ctrl), http.DefaultClient, []int64{2}, nil)
+			w, err := svc.Create(context.Background(), tc.webhook)
+			assert.Equal(t, tc.wantErr, err)
+			if err == nil {
+				assert.Equal(t, int64(1), w.Id)
+				assert.NotEmpty(t, w.Secret)
+			}
+		})
+	}
+}
+
+func TestWebhookService_Dispatch(t *testing.T) {
+	testCases := []struct {
+		name  string
+		evt   domain.WebhookEventType
+		owner int64
+		// 查询订阅者时的用户
+		wantUids []int64
+	}{
+		{
+			name:     "只投递给资源所有者",
+			evt:      domain.WebhookEventArticlePublished,
+			owner:    5,
+			wantUids: []int64{5},
+		},
+		{
+			name:     "全局事件只投递给管理员",
+			evt:      domain.WebhookEventUserCreated,
+			owner:    5,
+			wantUids: []int64{1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := repomocks.NewMockWebhookRepository(ctrl)
+			repo.EXPECT().FindSubscribers(gomock.Any(), tc.evt, tc.wantUids).
+				Return([]domain.Webhook{{Id: 7}}, nil)
+			repo.EXPECT().CreateDeliveries(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, ds []domain.WebhookDelivery) ([]int64, error) {
+					require.Len(t, ds, 1)
+					assert.Equal(t, int64(7), ds[0].WebhookId)
+					assert.Equal(t, tc.evt, ds[0].Event)
+					return []int64{1}, nil
+				})
+			svc := NewWebhookService(repo, http.DefaultClient, []int64{1, 2}, nil)
+			err := svc.Dispatch(context.Background(), tc.evt, tc.owner, webhookUserData{Id: 5})
+			assert.NoError(t, err)
+		})
+	}
+}
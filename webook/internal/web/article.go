@@ -4,6 +4,7 @@ import (
 	"Webook/webook/internal/domain"
 	"Webook/webook/internal/service"
 	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/diff"
 	"Webook/webook/pkg/logger"
 	"net/http"
 	"strconv"
@@ -27,10 +28,11 @@ type ArticleReaderHandler struct {
 	logger logger.Logger
 
 	// 阅读，点赞，收藏
-	biz      string
-	interSvc service.InteractiveService
-	rankSvc  service.RankingService
-	userSvc  service.UserService
+	biz       string
+	interSvc  service.InteractiveService
+	rankSvc   service.RankingService
+	userSvc   service.UserService
+	followSvc service.FollowService
 }
 
 func NewArticleHandler(svc service.ArticleService, interSvc service.InteractiveService, logger logger.Logger) *ArticleHandler {
@@ -42,14 +44,16 @@ func NewArticleHandler(svc service.ArticleService, interSvc service.InteractiveS
 	}
 }
 
-func NewArticleReaderHandler(svc service.ArticleService, interSvc service.InteractiveService, rankSvc service.RankingService, userSvc service.UserService, logger logger.Logger) *ArticleReaderHandler {
+func NewArticleReaderHandler(svc service.ArticleService, interSvc service.InteractiveService, rankSvc service.RankingService,
+	userSvc service.UserService, followSvc service.FollowService, logger logger.Logger) *ArticleReaderHandler {
 	return &ArticleReaderHandler{
-		svc:      svc,
-		logger:   logger,
-		biz:      "article",
-		interSvc: interSvc,
-		rankSvc:  rankSvc,
-		userSvc:  userSvc,
+		svc:       svc,
+		logger:    logger,
+		biz:       "article",
+		interSvc:  interSvc,
+		rankSvc:   rankSvc,
+		userSvc:   userSvc,
+		followSvc: followSvc,
 	}
 }
 
@@ -58,27 +62,52 @@ func (a *ArticleHandler) RegisterRoutes(ug *gin.RouterGroup) {
 	ug.POST("/publish", a.Publish)
 	ug.POST("/withdraw", a.Withdraw)
 	ug.POST("/delete", a.Delete)
+	// 定时发布
+	ug.POST("/schedule", a.Schedule)
+	ug.POST("/schedule/cancel", a.CancelSchedule)
 
 	// 文章列表
 	ug.POST("/list", a.List)
 	// 文章详情
 	ug.GET("/detail/:id", a.Detail)
 
+	// 历史版本
+	ug.POST("/revisions/list", a.ListRevisions)
+	ug.POST("/revisions/diff", a.DiffRevisions)
+	ug.POST("/revisions/restore", a.RestoreRevision)
 }
 
 func (a *ArticleReaderHandler) RegisterRoutes(ug *gin.RouterGroup) {
 	ug.GET("/:id", a.PublicDetail)
 	ug.POST("/like", a.Like)
 	ug.POST("/collect", a.Collect)
+	ug.POST("/uncollect", a.Uncollect)
+	// 兼容旧接口，等同于总榜
 	ug.POST("/rank/list", a.RankingList)
+	// 日榜、周榜、总榜，以及按标签、分类配置的榜单
+	ug.POST("/rank/:board", a.Ranking)
 	ug.POST("/list", a.PublicList)
+	// 标签联想和热门标签
+	ug.POST("/tags/suggest", a.SuggestTags)
+	ug.POST("/tags/hot", a.HotTags)
 }
 
 // Edit 编辑文章
 type ArticleRequest struct {
-	Id      int64  `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Id       int64    `json:"id"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+}
+
+// isArticleInputErr 标签、分类不合法，属于用户输入错误
+func isArticleInputErr(err error) bool {
+	switch err {
+	case service.ErrTooManyTags, service.ErrTagTooLong, service.ErrCategoryTooLong:
+		return true
+	}
+	return false
 }
 
 func (a *ArticleHandler) Edit(ctx *gin.Context) {
@@ -101,13 +130,22 @@ func (a *ArticleHandler) Edit(ctx *gin.Context) {
 	userId := userClaims.UserId
 
 	id, err := a.svc.Save(ctx, domain.Article{
-		Id:      req.Id,
-		Title:   req.Title,
-		Content: req.Content,
+		Id:       req.Id,
+		Title:    req.Title,
+		Content:  req.Content,
+		Category: req.Category,
+		Tags:     req.Tags,
 		Author: domain.Author{
 			Id: userId,
 		},
 	})
+	if isArticleInputErr(err) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -144,11 +182,20 @@ func (a *ArticleHandler) Publish(ctx *gin.Context) {
 	userId := userClaims.UserId
 
 	id, err := a.svc.Publish(ctx, domain.Article{
-		Id:      req.Id,
-		Title:   req.Title,
-		Content: req.Content,
-		Author:  domain.Author{Id: userId},
+		Id:       req.Id,
+		Title:    req.Title,
+		Content:  req.Content,
+		Category: req.Category,
+		Tags:     req.Tags,
+		Author:   domain.Author{Id: userId},
 	})
+	if isArticleInputErr(err) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -244,6 +291,100 @@ func (a *ArticleHandler) Delete(ctx *gin.Context) {
 	})
 }
 
+// Schedule 定时发布文章
+func (a *ArticleHandler) Schedule(ctx *gin.Context) {
+	type Req struct {
+		ArticleRequest
+		// 发布时间，格式：2006-01-02 15:04:05
+		PublishAt string `json:"publish_at"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	publishAt, err := time.ParseInLocation(time.DateTime, req.PublishAt, time.Local)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "发布时间格式错误",
+		})
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	id, err := a.svc.Schedule(ctx, domain.Article{
+		Id:        req.Id,
+		Title:     req.Title,
+		Content:   req.Content,
+		Category:  req.Category,
+		Tags:      req.Tags,
+		Author:    domain.Author{Id: userClaims.UserId},
+		PublishAt: publishAt,
+	})
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "定时发布设置成功",
+			Data: id,
+		})
+	case service.ErrPublishAtInvalid, service.ErrTooManyTags, service.ErrTagTooLong, service.ErrCategoryTooLong:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("定时发布文章失败",
+			logger.Int64("articleId", req.Id),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+	}
+}
+
+// CancelSchedule 取消定时发布
+func (a *ArticleHandler) CancelSchedule(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	id, err := a.svc.CancelSchedule(ctx, domain.Article{
+		Id:     req.Id,
+		Author: domain.Author{Id: userClaims.UserId},
+	})
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "取消定时发布成功",
+			Data: id,
+		})
+	case service.ErrNotScheduled:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("取消定时发布失败",
+			logger.Int64("articleId", req.Id),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+	}
+}
+
 // ------------------------------------------------------------
 // 查询部分
 // ------------------------------------------------------------
@@ -255,22 +396,29 @@ type ArticlePage struct {
 }
 
 type ArticleVO struct {
-	Id         int64  `json:"id"`
-	Title      string `json:"title"`
-	Abstract   string `json:"abstract"`
-	Content    string `json:"content"`
-	AuthorId   int64  `json:"author_id"`
-	AuthorName string `json:"author_name"`
-	Status     uint8  `json:"status"`
-	Ctime      string `json:"ctime"`
-	Utime      string `json:"utime"`
+	Id         int64    `json:"id"`
+	Title      string   `json:"title"`
+	Abstract   string   `json:"abstract"`
+	Content    string   `json:"content"`
+	AuthorId   int64    `json:"author_id"`
+	AuthorName string   `json:"author_name"`
+	Status     uint8    `json:"status"`
+	Ctime      string   `json:"ctime"`
+	Utime      string   `json:"utime"`
+	PublishAt  string   `json:"publish_at,omitempty"`
+	Category   string   `json:"category"`
+	Tags       []string `json:"tags"`
 
 	// 阅读量，点赞数，收藏数
 	ReadCnt    int64 `json:"readCnt"`
 	LikeCnt    int64 `json:"likeCnt"`
 	CollectCnt int64 `json:"collectCnt"`
+	CommentCnt int64 `json:"commentCnt"`
 	Liked      bool  `json:"liked"`
 	Collected  bool  `json:"collected"`
+
+	// 当前用户是否关注了作者
+	Followed bool `json:"followed"`
 }
 
 // List 获取文章列表
@@ -327,11 +475,11 @@ func (a *ArticleHandler) List(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, Result{
 		Code: 0,
 		Msg:  "获取文章列表成功",
-		Data: toArticleVOs(articles, interMap, nil),
+		Data: toArticleVOs(articles, interMap, nil, nil),
 	})
 }
 
-func toArticleVOs(arts []domain.Article, interMap map[int64]domain.Interactive, authorMap map[int64]string) []ArticleVO {
+func toArticleVOs(arts []domain.Article, interMap map[int64]domain.Interactive, authorMap map[int64]string, followedMap map[int64]bool) []ArticleVO {
 	result := make([]ArticleVO, 0)
 	for _, art := range arts {
 		result = append(result, ArticleVO{
@@ -341,13 +489,18 @@ func toArticleVOs(arts []domain.Article, interMap map[int64]domain.Interactive,
 			Status:     art.Status.ToUint8(),
 			Ctime:      art.Ctime.Format(time.DateTime),
 			Utime:      art.Utime.Format(time.DateTime),
+			PublishAt:  formatPublishAt(art),
+			Category:   art.Category,
+			Tags:       art.Tags,
 			AuthorId:   art.Author.Id,
 			AuthorName: authorMap[art.Author.Id],
 			ReadCnt:    interMap[art.Id].ReadCnt,
 			LikeCnt:    interMap[art.Id].LikeCnt,
 			CollectCnt: interMap[art.Id].CollectCnt,
+			CommentCnt: interMap[art.Id].CommentCnt,
 			Liked:      interMap[art.Id].Liked,
 			Collected:  interMap[art.Id].Collected,
+			Followed:   followedMap[art.Author.Id],
 		})
 	}
 	return result
@@ -419,10 +572,164 @@ func (a *ArticleHandler) Detail(ctx *gin.Context) {
 			Status:     article.Status.ToUint8(),
 			Ctime:      article.Ctime.Format(time.DateTime),
 			Utime:      article.Utime.Format(time.DateTime),
+			PublishAt:  formatPublishAt(article),
+			Category:   article.Category,
+			Tags:       article.Tags,
 		},
 	})
 }
 
+// formatPublishAt 只有定时发布的文章才返回发布时间
+func formatPublishAt(art domain.Article) string {
+	if art.Status != domain.ArticleStatusScheduled {
+		return ""
+	}
+	return art.PublishAt.Format(time.DateTime)
+}
+
+// ------------------------------------------------------------
+// 历史版本
+// ------------------------------------------------------------
+
+type ArticleRevisionVO struct {
+	Id        int64  `json:"id"`
+	ArticleId int64  `json:"article_id"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Ctime     string `json:"ctime"`
+}
+
+type DiffLineVO struct {
+	// equal, insert, delete
+	Op      string `json:"op"`
+	Content string `json:"content"`
+}
+
+// ListRevisions 获取文章的历史版本列表
+func (a *ArticleHandler) ListRevisions(ctx *gin.Context) {
+	type Req struct {
+		Id     int64 `json:"id"`
+		Limit  int   `json:"limit"`
+		Offset int   `json:"offset"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 10
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	revs, err := a.svc.ListRevisions(ctx, req.Id, userClaims.UserId, req.Limit, req.Offset)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("获取历史版本失败",
+			logger.Int64("articleId", req.Id),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取历史版本成功",
+		Data: slice.Map(revs, func(idx int, rev domain.ArticleRevision) ArticleRevisionVO {
+			return ArticleRevisionVO{
+				Id:        rev.Id,
+				ArticleId: rev.ArticleId,
+				Title:     rev.Title,
+				Content:   rev.Content,
+				Ctime:     rev.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// DiffRevisions 比较两个历史版本
+func (a *ArticleHandler) DiffRevisions(ctx *gin.Context) {
+	type Req struct {
+		From int64 `json:"from"`
+		To   int64 `json:"to"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	lines, err := a.svc.DiffRevisions(ctx, userClaims.UserId, req.From, req.To)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "比较成功",
+			Data: slice.Map(lines, func(idx int, line diff.Line) DiffLineVO {
+				return DiffLineVO{
+					Op:      line.Op.String(),
+					Content: line.Text,
+				}
+			}),
+		})
+	case service.ErrRevisionNotFound, service.ErrNotArticleAuthor, service.ErrRevisionNotSameArt:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("比较历史版本失败",
+			logger.Int64("from", req.From),
+			logger.Int64("to", req.To),
+			logger.Error(err),
+		)
+	}
+}
+
+// RestoreRevision 将历史版本恢复为当前草稿
+func (a *ArticleHandler) RestoreRevision(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	id, err := a.svc.RestoreRevision(ctx, userClaims.UserId, req.Id)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "恢复成功",
+			Data: id,
+		})
+	case service.ErrRevisionNotFound, service.ErrNotArticleAuthor:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("恢复历史版本失败",
+			logger.Int64("revisionId", req.Id),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+	}
+}
+
 func (a *ArticleReaderHandler) PublicDetail(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -451,9 +758,9 @@ func (a *ArticleReaderHandler) PublicDetail(ctx *gin.Context) {
 		return
 	}
 
-	// 增加阅读计数，保证 Interactive 中存在 (biz,bizId) 的记录
-	// IncreaseReadCnt 采用 Upsert 的写法，如果记录不存在，则创建，如果记录存在，则更新
-	err = a.interSvc.IncreaseReadCnt(ctx, a.biz, article.Id)
+	// 记录阅读，阅读计数和阅读历史由阅读事件的消费者异步处理
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err = a.interSvc.Read(ctx, a.biz, article.Id, userClaims.UserId, ctx.ClientIP())
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -466,7 +773,6 @@ func (a *ArticleReaderHandler) PublicDetail(ctx *gin.Context) {
 		return
 	}
 
-	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
 	interactive, err := a.interSvc.Get(ctx, a.biz, article.Id, userClaims.UserId)
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
@@ -480,6 +786,11 @@ func (a *ArticleReaderHandler) PublicDetail(ctx *gin.Context) {
 		return
 	}
 
+	followedMap, ok := a.followedMap(ctx, userClaims.UserId, []int64{article.Author.Id})
+	if !ok {
+		return
+	}
+
 	ctx.JSON(http.StatusOK, Result{
 		Code: 0,
 		Msg:  "获取Public文章详情成功",
@@ -492,12 +803,16 @@ func (a *ArticleReaderHandler) PublicDetail(ctx *gin.Context) {
 			Status:     article.Status.ToUint8(),
 			Ctime:      article.Ctime.Format(time.DateTime),
 			Utime:      article.Utime.Format(time.DateTime),
+			Category:   article.Category,
+			Tags:       article.Tags,
 
 			ReadCnt:    interactive.ReadCnt,
 			LikeCnt:    interactive.LikeCnt,
 			CollectCnt: interactive.CollectCnt,
+			CommentCnt: interactive.CommentCnt,
 			Liked:      interactive.Liked,
 			Collected:  interactive.Collected,
+			Followed:   followedMap[article.Author.Id],
 		},
 	})
 }
@@ -576,6 +891,13 @@ func (a *ArticleReaderHandler) Collect(ctx *gin.Context) {
 	userId := userClaims.UserId
 
 	err := a.interSvc.Collect(ctx, a.biz, req.Id, req.Cid, userId)
+	if err == service.ErrCollectionNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -596,7 +918,45 @@ func (a *ArticleReaderHandler) Collect(ctx *gin.Context) {
 	})
 }
 
+// Uncollect 取消收藏
+func (a *ArticleReaderHandler) Uncollect(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err := a.interSvc.Uncollect(ctx, a.biz, req.Id, userClaims.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("取消收藏失败",
+			logger.Int64("articleId", req.Id),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "取消收藏成功",
+	})
+}
+
 func (a *ArticleReaderHandler) RankingList(ctx *gin.Context) {
+	a.ranking(ctx, "all_time")
+}
+
+func (a *ArticleReaderHandler) Ranking(ctx *gin.Context) {
+	a.ranking(ctx, ctx.Param("board"))
+}
+
+func (a *ArticleReaderHandler) ranking(ctx *gin.Context, board string) {
 	var page ArticlePage
 	if err := ctx.BindJSON(&page); err != nil {
 		return
@@ -615,13 +975,21 @@ func (a *ArticleReaderHandler) RankingList(ctx *gin.Context) {
 	userClaims := claims.(*myjwt.UserClaims)
 	userId := userClaims.UserId
 
-	articles, err := a.rankSvc.GetFromCache(ctx)
+	articles, err := a.rankSvc.GetFromCache(ctx, board)
+	if err == service.ErrRankingBoardNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
 			Msg:  "系统错误",
 		})
 		a.logger.Error("获取榜单列表失败",
+			logger.String("board", board),
 			logger.Int64("limit", int64(page.Limit)),
 			logger.Int64("offset", int64(page.Offset)),
 			logger.Int64("userId", userId),
@@ -662,15 +1030,25 @@ func (a *ArticleReaderHandler) RankingList(ctx *gin.Context) {
 		)
 		return
 	}
+	followedMap, ok := a.followedMap(ctx, userId, userIds)
+	if !ok {
+		return
+	}
 	ctx.JSON(http.StatusOK, Result{
 		Code: 0,
 		Msg:  "获取文章列表成功",
-		Data: toArticleVOs(articles, interMap, authorMap),
+		Data: toArticleVOs(articles, interMap, authorMap, followedMap),
 	})
 }
 
+// PublicList 线上文章列表，可以按标签和分类过滤
 func (a *ArticleReaderHandler) PublicList(ctx *gin.Context) {
-	var page ArticlePage
+	type Req struct {
+		ArticlePage
+		Tag      string `json:"tag"`
+		Category string `json:"category"`
+	}
+	var page Req
 	if err := ctx.BindJSON(&page); err != nil {
 		return
 	}
@@ -688,7 +1066,10 @@ func (a *ArticleReaderHandler) PublicList(ctx *gin.Context) {
 	userClaims := claims.(*myjwt.UserClaims)
 	userId := userClaims.UserId
 	now := time.Now()
-	articles, err := a.svc.PublicList(ctx, now, page.Offset, page.Limit)
+	articles, err := a.svc.PublicListByFilter(ctx, now, page.Offset, page.Limit, domain.ArticleFilter{
+		Tag:      page.Tag,
+		Category: page.Category,
+	})
 	if err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
@@ -697,6 +1078,8 @@ func (a *ArticleReaderHandler) PublicList(ctx *gin.Context) {
 		a.logger.Error("获取榜单列表失败",
 			logger.Int64("limit", int64(page.Limit)),
 			logger.Int64("offset", int64(page.Offset)),
+			logger.String("tag", page.Tag),
+			logger.String("category", page.Category),
 			logger.Int64("userId", userId),
 			logger.Error(err),
 		)
@@ -734,10 +1117,108 @@ func (a *ArticleReaderHandler) PublicList(ctx *gin.Context) {
 		)
 		return
 	}
+	followedMap, ok := a.followedMap(ctx, userId, userIds)
+	if !ok {
+		return
+	}
 
 	ctx.JSON(http.StatusOK, Result{
 		Code: 0,
 		Msg:  "获取文章列表成功",
-		Data: toArticleVOs(articles, interMap, authorMap),
+		Data: toArticleVOs(articles, interMap, authorMap, followedMap),
 	})
 }
+
+type TagVO struct {
+	Name string `json:"name"`
+	// 使用该标签的已发表文章数
+	Cnt int64 `json:"cnt"`
+}
+
+func toTagVOs(tags []domain.Tag) []TagVO {
+	return slice.Map(tags, func(idx int, tag domain.Tag) TagVO {
+		return TagVO{
+			Name: tag.Name,
+			Cnt:  tag.Cnt,
+		}
+	})
+}
+
+// SuggestTags 标签联想，输入前缀返回匹配的标签
+func (a *ArticleReaderHandler) SuggestTags(ctx *gin.Context) {
+	type Req struct {
+		Prefix string `json:"prefix"`
+		Limit  int    `json:"limit"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 20 {
+		req.Limit = 10
+	}
+
+	tags, err := a.svc.SuggestTags(ctx, req.Prefix, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("标签联想失败",
+			logger.String("prefix", req.Prefix),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "获取标签成功",
+		Data: toTagVOs(tags),
+	})
+}
+
+// HotTags 热门标签
+func (a *ArticleReaderHandler) HotTags(ctx *gin.Context) {
+	type Req struct {
+		Limit int `json:"limit"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+
+	tags, err := a.svc.HotTags(ctx, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("获取热门标签失败", logger.Error(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "获取热门标签成功",
+		Data: toTagVOs(tags),
+	})
+}
+
+// followedMap 查询当前用户是否关注了这些作者，失败时已经写回了响应
+func (a *ArticleReaderHandler) followedMap(ctx *gin.Context, userId int64, authorIds []int64) (map[int64]bool, bool) {
+	followedMap, err := a.followSvc.FollowedMap(ctx, userId, authorIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		a.logger.Error("获取关注状态失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return nil, false
+	}
+	return followedMap, true
+}
@@ -5,6 +5,7 @@ import (
 	"Webook/webook/internal/service"
 	svcmocks "Webook/webook/internal/service/mocks"
 	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
 )
 
 func TestArticleHandler_Publish(t *testing.T) {
@@ -108,7 +110,7 @@ func TestArticleHandler_Publish(t *testing.T) {
 				})
 			})
 
-			articleHandler := NewArticleHandler(tc.mock(ctrl), nil)
+			articleHandler := NewArticleHandler(tc.mock(ctrl), nil, logger.NewZapLogger(zap.NewNop()))
 			articleHandler.RegisterRoutes(server.Group("/articles"))
 
 			// 创建请求
@@ -129,3 +131,59 @@ func TestArticleHandler_Publish(t *testing.T) {
 		})
 	}
 }
+
+func TestArticleHandler_ListRevisions(t *testing.T) {
+	testCases := []struct {
+		name       string
+		reqBody    string
+		wantLimit  int
+		wantOffset int
+	}{
+		{
+			name:       "正常分页",
+			reqBody:    `{"id":1,"limit":20,"offset":40}`,
+			wantLimit:  20,
+			wantOffset: 40,
+		},
+		{
+			name:       "limit 过大，offset 为负数",
+			reqBody:    `{"id":1,"limit":100000,"offset":-1}`,
+			wantLimit:  10,
+			wantOffset: 0,
+		},
+		{
+			name:       "limit 为 0",
+			reqBody:    `{"id":1}`,
+			wantLimit:  10,
+			wantOffset: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			svc := svcmocks.NewMockArticleService(ctrl)
+			svc.EXPECT().ListRevisions(gomock.Any(), int64(1), int64(123), tc.wantLimit, tc.wantOffset).
+				Return([]domain.ArticleRevision{}, nil)
+
+			server := gin.Default()
+			server.Use(func(c *gin.Context) {
+				c.Set("claims", &myjwt.UserClaims{
+					UserId: 123,
+				})
+			})
+			articleHandler := NewArticleHandler(svc, nil, logger.NewZapLogger(zap.NewNop()))
+			articleHandler.RegisterRoutes(server.Group("/articles"))
+
+			req, err := http.NewRequest(http.MethodPost, "/articles/revisions/list", bytes.NewBuffer([]byte(tc.reqBody)))
+			req.Header.Set("Content-Type", "application/json")
+			assert.Nil(t, err)
+			resp := httptest.NewRecorder()
+			server.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+		})
+	}
+}
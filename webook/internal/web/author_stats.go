@@ -0,0 +1,175 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+type AuthorStatsHandler struct {
+	svc     service.AuthorStatsService
+	userSvc service.UserService
+	logger  logger.Logger
+}
+
+func NewAuthorStatsHandler(svc service.AuthorStatsService, userSvc service.UserService, logger logger.Logger) *AuthorStatsHandler {
+	return &AuthorStatsHandler{
+		svc:     svc,
+		userSvc: userSvc,
+		logger:  logger,
+	}
+}
+
+func (h *AuthorStatsHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	// 登录作者自己的数据看板
+	ug.POST("/dashboard", h.Dashboard)
+	// 作者榜单
+	ug.POST("/top", h.Top)
+}
+
+type AuthorStatsVO struct {
+	Day        string `json:"day,omitempty"`
+	ReadCnt    int64  `json:"read_cnt"`
+	LikeCnt    int64  `json:"like_cnt"`
+	CollectCnt int64  `json:"collect_cnt"`
+	PublishCnt int64  `json:"publish_cnt"`
+}
+
+type AuthorDashboardVO struct {
+	// 这段时间的合计
+	Total  AuthorStatsVO   `json:"total"`
+	Series []AuthorStatsVO `json:"series"`
+}
+
+type AuthorRankItemVO struct {
+	AuthorStatsVO
+	AuthorId   int64   `json:"author_id"`
+	AuthorName string  `json:"author_name"`
+	Score      float64 `json:"score"`
+}
+
+type AuthorStatsReq struct {
+	// 最近多少天，包括今天
+	Days int `json:"days"`
+}
+
+func (h *AuthorStatsHandler) Dashboard(ctx *gin.Context) {
+	var req AuthorStatsReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Days == 0 {
+		req.Days = 7
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	stats, err := h.svc.Dashboard(ctx, userId, req.Days)
+	switch {
+	case errors.Is(err, service.ErrAuthorStatsDaysInvalid):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取作者数据看板失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	var vo AuthorDashboardVO
+	vo.Series = make([]AuthorStatsVO, 0, len(stats))
+	for _, s := range stats {
+		vo.Series = append(vo.Series, h.toVO(s))
+		vo.Total.ReadCnt += s.ReadCnt
+		vo.Total.LikeCnt += s.LikeCnt
+		vo.Total.CollectCnt += s.CollectCnt
+		vo.Total.PublishCnt += s.PublishCnt
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "获取作者数据看板成功",
+		Data: vo,
+	})
+}
+
+func (h *AuthorStatsHandler) Top(ctx *gin.Context) {
+	var req AuthorStatsReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Days == 0 {
+		req.Days = 7
+	}
+
+	items, err := h.svc.TopAuthors(ctx, req.Days)
+	switch {
+	case errors.Is(err, service.ErrAuthorStatsDaysInvalid):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取作者榜单失败",
+			logger.Int64("days", int64(req.Days)),
+			logger.Error(err),
+		)
+		return
+	}
+
+	authorIds := slice.Map(items, func(idx int, src domain.AuthorRankItem) int64 {
+		return src.AuthorId
+	})
+	nameMap, err := h.userSvc.GetNameMapByIds(ctx, authorIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取作者信息失败",
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取作者榜单成功",
+		Data: slice.Map(items, func(idx int, src domain.AuthorRankItem) AuthorRankItemVO {
+			return AuthorRankItemVO{
+				AuthorStatsVO: h.toVO(src.AuthorStats),
+				AuthorId:      src.AuthorId,
+				AuthorName:    nameMap[src.AuthorId],
+				Score:         src.Score,
+			}
+		}),
+	})
+}
+
+func (h *AuthorStatsHandler) toVO(s domain.AuthorStats) AuthorStatsVO {
+	vo := AuthorStatsVO{
+		ReadCnt:    s.ReadCnt,
+		LikeCnt:    s.LikeCnt,
+		CollectCnt: s.CollectCnt,
+		PublishCnt: s.PublishCnt,
+	}
+	if !s.Day.IsZero() {
+		vo.Day = s.Day.Format(time.DateOnly)
+	}
+	return vo
+}
@@ -0,0 +1,284 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+type CollectionHandler struct {
+	svc       service.CollectionService
+	interSvc  service.InteractiveService
+	userSvc   service.UserService
+	followSvc service.FollowService
+	logger    logger.Logger
+	biz       string
+}
+
+func NewCollectionHandler(svc service.CollectionService, interSvc service.InteractiveService,
+	userSvc service.UserService, followSvc service.FollowService, logger logger.Logger) *CollectionHandler {
+	return &CollectionHandler{
+		svc:       svc,
+		interSvc:  interSvc,
+		userSvc:   userSvc,
+		followSvc: followSvc,
+		logger:    logger,
+		biz:       "article",
+	}
+}
+
+func (h *CollectionHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	ug.POST("/create", h.Create)
+	ug.POST("/rename", h.Rename)
+	ug.POST("/delete", h.Delete)
+	ug.POST("/list", h.List)
+	// 收藏夹中的文章
+	ug.POST("/articles", h.Articles)
+}
+
+type CollectionVO struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Cnt   int64  `json:"cnt"`
+	Ctime string `json:"ctime"`
+	Utime string `json:"utime"`
+}
+
+// Create 新建收藏夹
+func (h *CollectionHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		Name string `json:"name"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	id, err := h.svc.Create(ctx, domain.Collection{
+		Uid:  userClaims.UserId,
+		Name: req.Name,
+	})
+	if h.isUserErr(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("新建收藏夹失败",
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "新建收藏夹成功",
+		Data: id,
+	})
+}
+
+// Rename 重命名收藏夹
+func (h *CollectionHandler) Rename(ctx *gin.Context) {
+	type Req struct {
+		Id   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err := h.svc.Rename(ctx, domain.Collection{
+		Id:   req.Id,
+		Uid:  userClaims.UserId,
+		Name: req.Name,
+	})
+	if h.isUserErr(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("重命名收藏夹失败",
+			logger.Int64("collectionId", req.Id),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "重命名收藏夹成功",
+	})
+}
+
+// Delete 删除收藏夹，收藏夹中的收藏会被全部取消
+func (h *CollectionHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err := h.svc.Delete(ctx, req.Id, userClaims.UserId)
+	if h.isUserErr(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("删除收藏夹失败",
+			logger.Int64("collectionId", req.Id),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "删除收藏夹成功",
+	})
+}
+
+// List 当前用户的收藏夹列表
+func (h *CollectionHandler) List(ctx *gin.Context) {
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	cs, err := h.svc.List(ctx, userClaims.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取收藏夹列表失败",
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取收藏夹列表成功",
+		Data: slice.Map(cs, func(idx int, c domain.Collection) CollectionVO {
+			return CollectionVO{
+				Id:    c.Id,
+				Name:  c.Name,
+				Cnt:   c.Cnt,
+				Ctime: c.Ctime.Format(time.DateTime),
+				Utime: c.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// Articles 分页获取收藏夹中的文章
+func (h *CollectionHandler) Articles(ctx *gin.Context) {
+	type Req struct {
+		ArticlePage
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 10
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	userId := userClaims.UserId
+	articles, err := h.svc.ListArticles(ctx, req.Id, userId, req.Offset, req.Limit)
+	if h.isUserErr(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取收藏夹文章失败",
+			logger.Int64("collectionId", req.Id),
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	// 获取文章交互信息
+	bizIds := slice.Map(articles, func(idx int, art domain.Article) int64 {
+		return art.Id
+	})
+	interMap, err := h.interSvc.GetInterMapByBizIds(ctx, h.biz, bizIds, userId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取文章交互信息失败",
+			logger.Error(err),
+		)
+		return
+	}
+
+	// 获取作者信息
+	userIds := slice.Map(articles, func(idx int, art domain.Article) int64 {
+		return art.Author.Id
+	})
+	authorMap, err := h.userSvc.GetNameMapByIds(ctx, userIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取作者信息失败",
+			logger.Error(err),
+		)
+		return
+	}
+	followedMap, err := h.followSvc.FollowedMap(ctx, userId, userIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取关注状态失败",
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "获取收藏夹文章成功",
+		Data: toArticleVOs(articles, interMap, authorMap, followedMap),
+	})
+}
+
+// isUserErr 用户输入错误时直接返回错误信息
+func (h *CollectionHandler) isUserErr(ctx *gin.Context, err error) bool {
+	switch err {
+	case service.ErrCollectionNotFound, service.ErrCollectionDuplicate,
+		service.ErrCollectionNameEmpty, service.ErrCollectionNameLong:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return true
+	}
+	return false
+}
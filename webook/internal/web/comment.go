@@ -0,0 +1,243 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	svc     service.CommentService
+	userSvc service.UserService
+	logger  logger.Logger
+}
+
+func NewCommentHandler(svc service.CommentService, userSvc service.UserService, logger logger.Logger) *CommentHandler {
+	return &CommentHandler{
+		svc:     svc,
+		userSvc: userSvc,
+		logger:  logger,
+	}
+}
+
+func (h *CommentHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	ug.POST("/create", h.Create)
+	ug.POST("/delete", h.Delete)
+	// 根评论列表和某个根评论下的回复列表，都是游标分页
+	ug.POST("/list", h.List)
+	ug.POST("/replies", h.Replies)
+}
+
+type CommentVO struct {
+	Id        int64  `json:"id"`
+	Biz       string `json:"biz"`
+	BizId     int64  `json:"biz_id"`
+	Content   string `json:"content"`
+	UserId    int64  `json:"user_id"`
+	UserName  string `json:"user_name"`
+	RootId    int64  `json:"root_id"`
+	ParentId  int64  `json:"parent_id"`
+	ReplyToId int64  `json:"reply_to_id,omitempty"`
+	ReplyTo   string `json:"reply_to,omitempty"`
+	ReplyCnt  int64  `json:"reply_cnt"`
+	Ctime     string `json:"ctime"`
+}
+
+// CommentPage 游标分页，Cursor 为上一页最后一条评论的 id，第一页传 0
+type CommentPage struct {
+	Cursor int64 `json:"cursor"`
+	Limit  int   `json:"limit"`
+}
+
+func (p CommentPage) limit() int {
+	if p.Limit <= 0 || p.Limit > 50 {
+		return 10
+	}
+	return p.Limit
+}
+
+// Create 发表评论或回复
+func (h *CommentHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		Biz     string `json:"biz"`
+		BizId   int64  `json:"biz_id"`
+		Content string `json:"content"`
+		// 回复的评论 id，发表根评论时为 0
+		ParentId int64 `json:"parent_id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	id, err := h.svc.Create(ctx, domain.Comment{
+		Biz:         req.Biz,
+		BizId:       req.BizId,
+		Content:     req.Content,
+		ParentId:    req.ParentId,
+		Commentator: domain.Commentator{Id: userClaims.UserId},
+	})
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "评论成功",
+			Data: id,
+		})
+	case service.ErrCommentEmpty, service.ErrCommentTooLong, service.ErrCommentNotFound,
+		service.ErrCommentBizNotFound, service.ErrCommentBizNotSupport:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("发表评论失败",
+			logger.String("biz", req.Biz),
+			logger.Int64("bizId", req.BizId),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+	}
+}
+
+// Delete 删除评论，根评论会连带删除所有回复
+func (h *CommentHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	cnt, err := h.svc.Delete(ctx, userClaims.UserId, req.Id)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "删除成功",
+			Data: cnt,
+		})
+	case service.ErrCommentNotFound, service.ErrCommentNoPermission:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("删除评论失败",
+			logger.Int64("commentId", req.Id),
+			logger.Int64("userId", userClaims.UserId),
+			logger.Error(err),
+		)
+	}
+}
+
+// List 根评论列表，最新的在前
+func (h *CommentHandler) List(ctx *gin.Context) {
+	type Req struct {
+		CommentPage
+		Biz   string `json:"biz"`
+		BizId int64  `json:"biz_id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	comments, err := h.svc.ListRoots(ctx, req.Biz, req.BizId, req.Cursor, req.limit())
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取评论列表失败",
+			logger.String("biz", req.Biz),
+			logger.Int64("bizId", req.BizId),
+			logger.Int64("cursor", req.Cursor),
+			logger.Error(err),
+		)
+		return
+	}
+	h.respond(ctx, comments)
+}
+
+// Replies 根评论下的回复列表，按时间正序
+func (h *CommentHandler) Replies(ctx *gin.Context) {
+	type Req struct {
+		CommentPage
+		RootId int64 `json:"root_id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	comments, err := h.svc.ListReplies(ctx, req.RootId, req.Cursor, req.limit())
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取回复列表失败",
+			logger.Int64("rootId", req.RootId),
+			logger.Int64("cursor", req.Cursor),
+			logger.Error(err),
+		)
+		return
+	}
+	h.respond(ctx, comments)
+}
+
+// respond 补充评论者和被回复者的昵称
+func (h *CommentHandler) respond(ctx *gin.Context, comments []domain.Comment) {
+	userIds := make([]int64, 0, len(comments)*2)
+	for _, c := range comments {
+		userIds = append(userIds, c.Commentator.Id)
+		if c.ReplyTo.Id > 0 {
+			userIds = append(userIds, c.ReplyTo.Id)
+		}
+	}
+	nameMap, err := h.userSvc.GetNameMapByIds(ctx, userIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取评论者信息失败", logger.Error(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取评论成功",
+		Data: slice.Map(comments, func(idx int, c domain.Comment) CommentVO {
+			return CommentVO{
+				Id:        c.Id,
+				Biz:       c.Biz,
+				BizId:     c.BizId,
+				Content:   c.Content,
+				UserId:    c.Commentator.Id,
+				UserName:  nameMap[c.Commentator.Id],
+				RootId:    c.RootId,
+				ParentId:  c.ParentId,
+				ReplyToId: c.ReplyTo.Id,
+				ReplyTo:   nameMap[c.ReplyTo.Id],
+				ReplyCnt:  c.ReplyCnt,
+				Ctime:     c.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
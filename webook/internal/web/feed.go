@@ -0,0 +1,138 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	svc      service.FeedService
+	interSvc service.InteractiveService
+	userSvc  service.UserService
+	logger   logger.Logger
+}
+
+func NewFeedHandler(svc service.FeedService, interSvc service.InteractiveService,
+	userSvc service.UserService, logger logger.Logger) *FeedHandler {
+	return &FeedHandler{
+		svc:      svc,
+		interSvc: interSvc,
+		userSvc:  userSvc,
+		logger:   logger,
+	}
+}
+
+func (h *FeedHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	// 关注的作者发表的文章，游标分页
+	ug.POST("", h.Feed)
+}
+
+// FeedCursorVO 动态流的游标，第一页传零值，之后传上一页返回的 next
+type FeedCursorVO struct {
+	// 毫秒时间戳
+	Time      int64 `json:"time"`
+	ArticleId int64 `json:"article_id"`
+}
+
+type FeedVO struct {
+	Articles []ArticleVO  `json:"articles"`
+	Next     FeedCursorVO `json:"next"`
+	// 为 false 时不需要再请求下一页
+	HasMore bool `json:"has_more"`
+}
+
+// Feed 关注的作者发表的文章，按发表时间倒序
+func (h *FeedHandler) Feed(ctx *gin.Context) {
+	type Req struct {
+		Cursor FeedCursorVO `json:"cursor"`
+		Limit  int          `json:"limit"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 10
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	var cursor domain.FeedCursor
+	if req.Cursor.Time > 0 {
+		cursor = domain.FeedCursor{
+			Time:      time.UnixMilli(req.Cursor.Time),
+			ArticleId: req.Cursor.ArticleId,
+		}
+	}
+	articles, next, err := h.svc.Feed(ctx, userId, cursor, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取动态流失败",
+			logger.Int64("userId", userId),
+			logger.Int64("cursorTime", req.Cursor.Time),
+			logger.Int64("cursorId", req.Cursor.ArticleId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	// 获取文章互动信息
+	bizIds := slice.Map(articles, func(idx int, art domain.Article) int64 {
+		return art.Id
+	})
+	interMap, err := h.interSvc.GetInterMapByBizIds(ctx, "article", bizIds, userId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取文章交互信息失败",
+			logger.Error(err),
+		)
+		return
+	}
+
+	// 获取作者信息，动态流里的作者都是已经关注了的
+	userIds := slice.Map(articles, func(idx int, art domain.Article) int64 {
+		return art.Author.Id
+	})
+	authorMap, err := h.userSvc.GetNameMapByIds(ctx, userIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取作者信息失败",
+			logger.Error(err),
+		)
+		return
+	}
+	followedMap := make(map[int64]bool, len(userIds))
+	for _, id := range userIds {
+		followedMap[id] = true
+	}
+
+	vo := FeedVO{
+		Articles: toArticleVOs(articles, interMap, authorMap, followedMap),
+		HasMore:  !next.IsZero(),
+	}
+	if vo.HasMore {
+		vo.Next = FeedCursorVO{
+			Time:      next.Time.UnixMilli(),
+			ArticleId: next.ArticleId,
+		}
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "获取动态流成功",
+		Data: vo,
+	})
+}
@@ -0,0 +1,251 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	svc     service.FollowService
+	userSvc service.UserService
+	logger  logger.Logger
+}
+
+func NewFollowHandler(svc service.FollowService, userSvc service.UserService, logger logger.Logger) *FollowHandler {
+	return &FollowHandler{
+		svc:     svc,
+		userSvc: userSvc,
+		logger:  logger,
+	}
+}
+
+func (h *FollowHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	ug.POST("/follow", h.Follow)
+	ug.POST("/unfollow", h.Unfollow)
+	// 粉丝列表和关注列表
+	ug.POST("/followers", h.Followers)
+	ug.POST("/followees", h.Followees)
+	// 和某个用户之间的关注状态，以及该用户的粉丝数和关注数
+	ug.POST("/info", h.Info)
+}
+
+type FollowUserVO struct {
+	Id       int64  `json:"id"`
+	Nickname string `json:"nickname"`
+	// 当前用户是否关注了该用户
+	Followed bool   `json:"followed"`
+	Ctime    string `json:"ctime"`
+}
+
+type FollowInfoVO struct {
+	Followed    bool  `json:"followed"`
+	FollowedBy  bool  `json:"followed_by"`
+	Mutual      bool  `json:"mutual"`
+	FollowerCnt int64 `json:"follower_cnt"`
+	FolloweeCnt int64 `json:"followee_cnt"`
+}
+
+// FollowListReq Uid 为 0 时查询当前用户
+type FollowListReq struct {
+	ArticlePage
+	Uid int64 `json:"uid"`
+}
+
+func (h *FollowHandler) Follow(ctx *gin.Context) {
+	type Req struct {
+		Followee int64 `json:"followee"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err := h.svc.Follow(ctx, userClaims.UserId, req.Followee)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "关注成功",
+		})
+	case service.ErrFollowSelf, service.ErrFolloweeNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("关注失败",
+			logger.Int64("follower", userClaims.UserId),
+			logger.Int64("followee", req.Followee),
+			logger.Error(err),
+		)
+	}
+}
+
+func (h *FollowHandler) Unfollow(ctx *gin.Context) {
+	type Req struct {
+		Followee int64 `json:"followee"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err := h.svc.Unfollow(ctx, userClaims.UserId, req.Followee)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("取消关注失败",
+			logger.Int64("follower", userClaims.UserId),
+			logger.Int64("followee", req.Followee),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "取消关注成功",
+	})
+}
+
+func (h *FollowHandler) Followers(ctx *gin.Context) {
+	h.list(ctx, "粉丝", h.svc.Followers, func(r domain.FollowRelation) int64 {
+		return r.Follower
+	})
+}
+
+func (h *FollowHandler) Followees(ctx *gin.Context) {
+	h.list(ctx, "关注", h.svc.Followees, func(r domain.FollowRelation) int64 {
+		return r.Followee
+	})
+}
+
+// list 粉丝列表和关注列表的公共部分，userOf 取出列表中展示的用户
+func (h *FollowHandler) list(ctx *gin.Context, name string,
+	find func(ctx context.Context, uid int64, offset int, limit int) ([]domain.FollowRelation, error),
+	userOf func(r domain.FollowRelation) int64) {
+	var req FollowListReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	userId := userClaims.UserId
+	uid := req.Uid
+	if uid == 0 {
+		uid = userId
+	}
+
+	relations, err := find(ctx, uid, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取"+name+"列表失败",
+			logger.Int64("uid", uid),
+			logger.Error(err),
+		)
+		return
+	}
+
+	userIds := slice.Map(relations, func(idx int, r domain.FollowRelation) int64 {
+		return userOf(r)
+	})
+	nameMap, err := h.userSvc.GetNameMapByIds(ctx, userIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取用户信息失败", logger.Error(err))
+		return
+	}
+	followedMap, err := h.svc.FollowedMap(ctx, userId, userIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取关注状态失败", logger.Error(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取" + name + "列表成功",
+		Data: slice.Map(relations, func(idx int, r domain.FollowRelation) FollowUserVO {
+			id := userOf(r)
+			return FollowUserVO{
+				Id:       id,
+				Nickname: nameMap[id],
+				Followed: followedMap[id],
+				Ctime:    r.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+func (h *FollowHandler) Info(ctx *gin.Context) {
+	type Req struct {
+		Uid int64 `json:"uid"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userClaims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	info, err := h.svc.Info(ctx, userClaims.UserId, req.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取关注状态失败",
+			logger.Int64("uid", userClaims.UserId),
+			logger.Int64("target", req.Uid),
+			logger.Error(err),
+		)
+		return
+	}
+	statics, err := h.svc.Statics(ctx, req.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取关注数失败",
+			logger.Int64("uid", req.Uid),
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取关注状态成功",
+		Data: FollowInfoVO{
+			Followed:    info.Followed,
+			FollowedBy:  info.FollowedBy,
+			Mutual:      info.Mutual(),
+			FollowerCnt: statics.Followers,
+			FolloweeCnt: statics.Followees,
+		},
+	})
+}
@@ -0,0 +1,234 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/logger"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler 定时任务的管理接口，只有管理员可以访问
+type JobHandler struct {
+	svc    service.CronJobService
+	logger logger.Logger
+}
+
+func NewJobHandler(svc service.CronJobService, logger logger.Logger) *JobHandler {
+	return &JobHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+func (h *JobHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	ug.GET("/list", h.List)
+	ug.POST("/pause", h.Pause)
+	ug.POST("/resume", h.Resume)
+	// 马上执行一次
+	ug.POST("/trigger", h.Trigger)
+	// 修改 cron 表达式
+	ug.POST("/schedule", h.Schedule)
+	// 最近的执行记录
+	ug.POST("/executions", h.Executions)
+}
+
+type CronJobVO struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Executor   string `json:"executor"`
+	Expression string `json:"expression"`
+	Status     string `json:"status"`
+	NextTime   string `json:"next_time"`
+	// 正在执行的节点和最近一次续约的时间
+	Owner     string `json:"owner,omitempty"`
+	Heartbeat string `json:"heartbeat,omitempty"`
+	Utime     string `json:"utime"`
+}
+
+type CronJobExecutionVO struct {
+	Id        int64  `json:"id"`
+	Owner     string `json:"owner"`
+	Version   int64  `json:"version"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time,omitempty"`
+	// 执行耗时，单位毫秒
+	Duration int64 `json:"duration"`
+}
+
+type JobReq struct {
+	Id int64 `json:"id"`
+}
+
+func (h *JobHandler) List(ctx *gin.Context) {
+	js, err := h.svc.List(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取定时任务列表失败", logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取定时任务列表成功",
+		Data: slice.Map(js, func(idx int, src domain.CronJob) CronJobVO {
+			return h.toVO(src)
+		}),
+	})
+}
+
+func (h *JobHandler) Pause(ctx *gin.Context) {
+	var req JobReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	err := h.svc.Pause(ctx, req.Id)
+	h.respond(ctx, err, "暂停定时任务成功", "暂停定时任务失败", req.Id)
+}
+
+func (h *JobHandler) Resume(ctx *gin.Context) {
+	var req JobReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	err := h.svc.Resume(ctx, req.Id)
+	h.respond(ctx, err, "恢复定时任务成功", "恢复定时任务失败", req.Id)
+}
+
+func (h *JobHandler) Trigger(ctx *gin.Context) {
+	var req JobReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	err := h.svc.Trigger(ctx, req.Id)
+	h.respond(ctx, err, "触发定时任务成功", "触发定时任务失败", req.Id)
+}
+
+func (h *JobHandler) Schedule(ctx *gin.Context) {
+	type Req struct {
+		Id         int64  `json:"id"`
+		Expression string `json:"expression"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	err := h.svc.UpdateExpression(ctx, req.Id, req.Expression)
+	h.respond(ctx, err, "修改定时任务调度成功", "修改定时任务调度失败", req.Id)
+}
+
+func (h *JobHandler) Executions(ctx *gin.Context) {
+	type Req struct {
+		ArticlePage
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 10
+	}
+
+	es, err := h.svc.Executions(ctx, req.Id, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取定时任务执行记录失败",
+			logger.Int64("id", req.Id),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取执行记录成功",
+		Data: slice.Map(es, func(idx int, src domain.CronJobExecution) CronJobExecutionVO {
+			return h.toExecutionVO(src)
+		}),
+	})
+}
+
+// respond 管理操作的结果，任务不存在、状态不对和表达式不合法是用户错误
+func (h *JobHandler) respond(ctx *gin.Context, err error, okMsg string, errMsg string, id int64) {
+	switch {
+	case errors.Is(err, service.ErrCronJobNotFound),
+		errors.Is(err, service.ErrCronJobStatusConflict),
+		errors.Is(err, service.ErrCronExpressionInvalid):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error(errMsg,
+			logger.Int64("id", id),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: okMsg,
+	})
+}
+
+func (h *JobHandler) toVO(j domain.CronJob) CronJobVO {
+	vo := CronJobVO{
+		Id:         j.Id,
+		Name:       j.Name,
+		Executor:   j.Executor,
+		Expression: j.Expression,
+		NextTime:   j.NextTime.Format(time.DateTime),
+		Utime:      j.Utime.Format(time.DateTime),
+	}
+	switch j.Status {
+	case domain.CronJobStatusWaiting:
+		vo.Status = "waiting"
+	case domain.CronJobStatusRunning:
+		vo.Status = "running"
+		vo.Owner = j.Owner
+		vo.Heartbeat = j.Heartbeat.Format(time.DateTime)
+	case domain.CronJobStatusPaused:
+		vo.Status = "paused"
+	default:
+		vo.Status = "unknown"
+	}
+	return vo
+}
+
+func (h *JobHandler) toExecutionVO(e domain.CronJobExecution) CronJobExecutionVO {
+	vo := CronJobExecutionVO{
+		Id:        e.Id,
+		Owner:     e.Owner,
+		Version:   e.Version,
+		Error:     e.Error,
+		StartTime: e.StartTime.Format(time.DateTime),
+	}
+	if !e.EndTime.IsZero() {
+		vo.EndTime = e.EndTime.Format(time.DateTime)
+		vo.Duration = e.EndTime.Sub(e.StartTime).Milliseconds()
+	}
+	switch e.Status {
+	case domain.CronJobExecutionStatusRunning:
+		vo.Status = "running"
+	case domain.CronJobExecutionStatusSucceeded:
+		vo.Status = "succeeded"
+	case domain.CronJobExecutionStatusFailed:
+		vo.Status = "failed"
+	default:
+		vo.Status = "unknown"
+	}
+	return vo
+}
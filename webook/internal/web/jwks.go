@@ -0,0 +1,29 @@
+package web
+
+import (
+	myjwt "Webook/webook/internal/web/jwt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler 公开 access token 的公钥，其他服务按 token 头部的 kid 取公钥验证
+type JWKSHandler struct {
+	keys *myjwt.KeyManager
+}
+
+func NewJWKSHandler(keys myjwt.Keys) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys.Access,
+	}
+}
+
+func (h *JWKSHandler) RegisterRoutes(server *gin.Engine) {
+	server.GET("/.well-known/jwks.json", h.JWKS)
+}
+
+func (h *JWKSHandler) JWKS(ctx *gin.Context) {
+	// 允许其他服务缓存一会儿，轮换时新公钥要提前加进来
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.keys.JWKS())
+}
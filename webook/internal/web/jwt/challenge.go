@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	_ "embed"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+//go:embed lua/challenge_failed.lua
+var luaChallengeFailed string
+
+var ErrChallengeNotFound = errors.New("二次验证已过期，请重新登录")
+
+const (
+	challengeExpire      = time.Minute * 5
+	challengeMaxAttempts = 5
+)
+
+// Challenge 密码校验通过、还差二次验证的登录
+// challenge token 是一个随机字符串，只能在二次验证接口换成真正的 token
+type Challenge struct {
+	Uid         int64
+	LoginMethod string
+}
+
+func (h *RedisJWTHandler) challengeKey(token string) string {
+	return "users:2fa_challenge:" + token
+}
+
+// SetChallengeToken 生成 challenge token，放在 x-2fa-token 中
+func (h *RedisJWTHandler) SetChallengeToken(ctx *gin.Context, uid int64, method string) error {
+	token := uuid.New().String()
+	key := h.challengeKey(token)
+	pipe := h.cmd.TxPipeline()
+	pipe.HSet(ctx, key,
+		"uid", uid,
+		"login_method", method,
+		"user_agent", ctx.Request.UserAgent(),
+		"attempts", 0,
+	)
+	pipe.Expire(ctx, key, challengeExpire)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	ctx.Header("x-2fa-token", token)
+	return nil
+}
+
+// CheckChallenge 查询 challenge，过期、错误次数太多或者换了设备都返回 ErrChallengeNotFound
+func (h *RedisJWTHandler) CheckChallenge(ctx *gin.Context, token string) (Challenge, error) {
+	if token == "" {
+		return Challenge{}, ErrChallengeNotFound
+	}
+	data, err := h.cmd.HGetAll(ctx, h.challengeKey(token)).Result()
+	if err != nil {
+		return Challenge{}, err
+	}
+	if len(data) == 0 || data["user_agent"] != ctx.Request.UserAgent() {
+		return Challenge{}, ErrChallengeNotFound
+	}
+	uid, err := strconv.ParseInt(data["uid"], 10, 64)
+	if err != nil {
+		return Challenge{}, ErrChallengeNotFound
+	}
+	return Challenge{
+		Uid:         uid,
+		LoginMethod: data["login_method"],
+	}, nil
+}
+
+// ChallengeFailed 记录一次验证码错误，错误太多次 challenge 直接失效
+func (h *RedisJWTHandler) ChallengeFailed(ctx *gin.Context, token string) error {
+	return h.cmd.Eval(ctx, luaChallengeFailed, []string{h.challengeKey(token)}, challengeMaxAttempts).Err()
+}
+
+// ConsumeChallenge 验证通过后删除 challenge，并发请求只有一个能删除成功
+func (h *RedisJWTHandler) ConsumeChallenge(ctx *gin.Context, token string) error {
+	n, err := h.cmd.Del(ctx, h.challengeKey(token)).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrChallengeNotFound
+	}
+	return nil
+}
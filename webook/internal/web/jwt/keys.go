@@ -0,0 +1,259 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrKeyNotFound        = errors.New("找不到 JWT 密钥")
+	ErrAlgorithmNotMatch  = errors.New("JWT 算法和密钥不匹配")
+	ErrSigningKeyNotFound = errors.New("找不到签名密钥")
+)
+
+// KeyConfig 一个密钥的配置，PEM 可以直接写在配置里，也可以放在文件中由调用方读出来。
+// 只配置公钥的密钥只用来验证，轮换时留着旧的公钥，直到旧密钥签发的 token 都过期
+type KeyConfig struct {
+	Kid string `yaml:"Kid"`
+	// RS256、ES256 或者 EdDSA
+	Alg        string `yaml:"Alg"`
+	PrivateKey string `yaml:"PrivateKey"`
+	PublicKey  string `yaml:"PublicKey"`
+}
+
+type Key struct {
+	Kid    string
+	Method jwt.SigningMethod
+	// 只用来验证的密钥为 nil
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// ParseKey 解析 PEM 格式的密钥，有私钥时公钥从私钥中取
+func ParseKey(cfg KeyConfig) (Key, error) {
+	method, ok := supportedMethods[cfg.Alg]
+	if !ok {
+		return Key{}, fmt.Errorf("不支持的 JWT 算法 %s", cfg.Alg)
+	}
+	key := Key{
+		Kid:    cfg.Kid,
+		Method: method,
+	}
+	switch {
+	case cfg.PrivateKey != "":
+		priv, err := parsePrivateKey(cfg.PrivateKey)
+		if err != nil {
+			return Key{}, fmt.Errorf("解析密钥 %s 的私钥失败: %w", cfg.Kid, err)
+		}
+		key.PrivateKey = priv
+		key.PublicKey = priv.Public()
+	case cfg.PublicKey != "":
+		pub, err := parsePublicKey(cfg.PublicKey)
+		if err != nil {
+			return Key{}, fmt.Errorf("解析密钥 %s 的公钥失败: %w", cfg.Kid, err)
+		}
+		key.PublicKey = pub
+	default:
+		return Key{}, fmt.Errorf("密钥 %s 没有配置私钥或者公钥", cfg.Kid)
+	}
+	if err := checkAlgorithm(method, key.PublicKey); err != nil {
+		return Key{}, fmt.Errorf("密钥 %s: %w", cfg.Kid, err)
+	}
+	return key, nil
+}
+
+var supportedMethods = map[string]jwt.SigningMethod{
+	jwt.SigningMethodRS256.Alg(): jwt.SigningMethodRS256,
+	jwt.SigningMethodES256.Alg(): jwt.SigningMethodES256,
+	jwt.SigningMethodEdDSA.Alg(): jwt.SigningMethodEdDSA,
+}
+
+func checkAlgorithm(method jwt.SigningMethod, pub crypto.PublicKey) error {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if method == jwt.SigningMethodRS256 {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		if method == jwt.SigningMethodES256 && k.Curve == elliptic.P256() {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if method == jwt.SigningMethodEdDSA {
+			return nil
+		}
+	}
+	return ErrAlgorithmNotMatch
+}
+
+func parsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("不是 PEM 格式")
+	}
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("不支持的私钥类型")
+	}
+	return signer, nil
+}
+
+func parsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("不是 PEM 格式")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// KeyManager 用一个密钥签名，用所有密钥验证，按 token 头部的 kid 找验证的密钥
+type KeyManager struct {
+	signing *Key
+	keys    map[string]*Key
+	// 按配置的顺序，用于 JWKS
+	ordered []*Key
+	methods []string
+}
+
+func NewKeyManager(keys []Key, signingKid string) (*KeyManager, error) {
+	m := &KeyManager{
+		keys: make(map[string]*Key, len(keys)),
+	}
+	methods := make(map[string]struct{})
+	for i := range keys {
+		k := &keys[i]
+		if k.Kid == "" {
+			return nil, errors.New("JWT 密钥缺少 kid")
+		}
+		if _, ok := m.keys[k.Kid]; ok {
+			return nil, fmt.Errorf("JWT 密钥 kid %s 重复", k.Kid)
+		}
+		m.keys[k.Kid] = k
+		m.ordered = append(m.ordered, k)
+		if _, ok := methods[k.Method.Alg()]; !ok {
+			methods[k.Method.Alg()] = struct{}{}
+			m.methods = append(m.methods, k.Method.Alg())
+		}
+	}
+	signing, ok := m.keys[signingKid]
+	if !ok || signing.PrivateKey == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSigningKeyNotFound, signingKid)
+	}
+	m.signing = signing
+	return m, nil
+}
+
+// Sign 用当前的签名密钥签名，头部带上 kid
+func (m *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(m.signing.Method, claims)
+	token.Header["kid"] = m.signing.Kid
+	return token.SignedString(m.signing.PrivateKey)
+}
+
+// Parse 解析并验证 token，只接受配置过的算法，算法必须和 kid 对应的密钥一致
+func (m *KeyManager) Parse(tokenStr string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenStr, claims, m.keyFunc, jwt.WithValidMethods(m.methods))
+}
+
+func (m *KeyManager) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrAlgorithmNotMatch
+	}
+	return key.PublicKey, nil
+}
+
+// JWK 公钥的 JSON Web Key 表示
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC 和 OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS 所有验证用的公钥，其他服务按 kid 取公钥验证 token
+func (m *KeyManager) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(m.ordered))}
+	for _, k := range m.ordered {
+		jwk := JWK{
+			Kid: k.Kid,
+			Alg: k.Method.Alg(),
+			Use: "sig",
+		}
+		switch pub := k.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case *ecdsa.PublicKey:
+			ecdhPub, err := pub.ECDH()
+			if err != nil {
+				continue
+			}
+			// 非压缩格式：0x04 || X || Y
+			raw := ecdhPub.Bytes()[1:]
+			size := len(raw) / 2
+			jwk.Kty = "EC"
+			jwk.Crv = pub.Curve.Params().Name
+			jwk.X = base64.RawURLEncoding.EncodeToString(raw[:size])
+			jwk.Y = base64.RawURLEncoding.EncodeToString(raw[size:])
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// Keys 签发 access token 和 refresh token 的两组密钥。
+// 两组分开，refresh token 不能当作 access token 用；只有 access token 的公钥对外公开
+type Keys struct {
+	Access  *KeyManager
+	Refresh *KeyManager
+}
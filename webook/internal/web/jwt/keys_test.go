@@ -0,0 +1,202 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		cfg  KeyConfig
+
+		wantPrivate bool
+		wantErr     bool
+	}{
+		{
+			name:        "RS256 PKCS1 私钥",
+			cfg:         KeyConfig{Kid: "rsa", Alg: "RS256", PrivateKey: pemEncode(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))},
+			wantPrivate: true,
+		},
+		{
+			name:        "ES256 PKCS8 私钥",
+			cfg:         KeyConfig{Kid: "ec", Alg: "ES256", PrivateKey: pkcs8(t, ecKey)},
+			wantPrivate: true,
+		},
+		{
+			name:        "EdDSA PKCS8 私钥",
+			cfg:         KeyConfig{Kid: "ed", Alg: "EdDSA", PrivateKey: pkcs8(t, edKey)},
+			wantPrivate: true,
+		},
+		{
+			name: "只有公钥",
+			cfg:  KeyConfig{Kid: "rsa", Alg: "RS256", PublicKey: pkix(t, &rsaKey.PublicKey)},
+		},
+		{
+			name:    "算法和密钥不匹配",
+			cfg:     KeyConfig{Kid: "ec", Alg: "RS256", PrivateKey: pkcs8(t, ecKey)},
+			wantErr: true,
+		},
+		{
+			name:    "不支持对称算法",
+			cfg:     KeyConfig{Kid: "hs", Alg: "HS512", PrivateKey: pkcs8(t, ecKey)},
+			wantErr: true,
+		},
+		{
+			name:    "没有密钥",
+			cfg:     KeyConfig{Kid: "ed", Alg: "EdDSA"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParseKey(tc.cfg)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.cfg.Kid, key.Kid)
+			assert.Equal(t, tc.wantPrivate, key.PrivateKey != nil)
+			assert.NotNil(t, key.PublicKey)
+		})
+	}
+}
+
+func TestKeyManager_Rotation(t *testing.T) {
+	oldKey := newEd25519Key(t, "old")
+	newKey := newEd25519Key(t, "new")
+	claims := &UserClaims{
+		UserId: 123,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+
+	before, err := NewKeyManager([]Key{oldKey}, "old")
+	require.NoError(t, err)
+	oldToken, err := before.Sign(claims)
+	require.NoError(t, err)
+
+	// 轮换：用新密钥签名，旧密钥只保留公钥
+	oldKey.PrivateKey = nil
+	after, err := NewKeyManager([]Key{newKey, oldKey}, "new")
+	require.NoError(t, err)
+	newToken, err := after.Sign(claims)
+	require.NoError(t, err)
+
+	for _, tokenStr := range []string{oldToken, newToken} {
+		var got UserClaims
+		token, err := after.Parse(tokenStr, &got)
+		require.NoError(t, err)
+		assert.True(t, token.Valid)
+		assert.Equal(t, int64(123), got.UserId)
+	}
+	token, _, err := jwt.NewParser().ParseUnverified(newToken, &UserClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", token.Header["kid"])
+
+	// 旧密钥下线后，旧 token 不再有效
+	removed, err := NewKeyManager([]Key{newKey}, "new")
+	require.NoError(t, err)
+	_, err = removed.Parse(oldToken, &UserClaims{})
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	// 不能用只有公钥的密钥签名
+	_, err = NewKeyManager([]Key{oldKey}, "old")
+	assert.ErrorIs(t, err, ErrSigningKeyNotFound)
+}
+
+func TestKeyManager_Parse_RejectOtherAlgorithms(t *testing.T) {
+	m, err := NewKeyManager([]Key{newEd25519Key(t, "k1")}, "k1")
+	require.NoError(t, err)
+
+	// 对称算法伪造的 token
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &UserClaims{UserId: 1})
+	token.Header["kid"] = "k1"
+	tokenStr, err := token.SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = m.Parse(tokenStr, &UserClaims{})
+	assert.Error(t, err)
+
+	// 其他密钥签名
+	other, err := NewKeyManager([]Key{newEd25519Key(t, "k1")}, "k1")
+	require.NoError(t, err)
+	tokenStr, err = other.Sign(&UserClaims{UserId: 1})
+	require.NoError(t, err)
+	_, err = m.Parse(tokenStr, &UserClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeyManager_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edKey := newEd25519Key(t, "ed")
+
+	m, err := NewKeyManager([]Key{
+		edKey,
+		{Kid: "rsa", Method: jwt.SigningMethodRS256, PublicKey: &rsaKey.PublicKey},
+		{Kid: "ec", Method: jwt.SigningMethodES256, PublicKey: &ecKey.PublicKey},
+	}, "ed")
+	require.NoError(t, err)
+
+	set := m.JWKS()
+	require.Len(t, set.Keys, 3)
+	assert.Equal(t, JWK{Kty: "OKP", Kid: "ed", Alg: "EdDSA", Use: "sig", Crv: "Ed25519",
+		X: b64(edKey.PublicKey.(ed25519.PublicKey))}, set.Keys[0])
+	assert.Equal(t, "RSA", set.Keys[1].Kty)
+	assert.Equal(t, "AQAB", set.Keys[1].E)
+	assert.Equal(t, b64(rsaKey.N.Bytes()), set.Keys[1].N)
+	assert.Equal(t, "EC", set.Keys[2].Kty)
+	assert.Equal(t, "P-256", set.Keys[2].Crv)
+	assert.Equal(t, b64(ecKey.X.FillBytes(make([]byte, 32))), set.Keys[2].X)
+	assert.Equal(t, b64(ecKey.Y.FillBytes(make([]byte, 32))), set.Keys[2].Y)
+}
+
+func newEd25519Key(t *testing.T, kid string) Key {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return Key{Kid: kid, Method: jwt.SigningMethodEdDSA, PrivateKey: priv, PublicKey: pub}
+}
+
+func pemEncode(t *testing.T, typ string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}))
+}
+
+func pkcs8(t *testing.T, key any) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pemEncode(t, "PRIVATE KEY", der)
+}
+
+func pkix(t *testing.T, key any) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pemEncode(t, "PUBLIC KEY", der)
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
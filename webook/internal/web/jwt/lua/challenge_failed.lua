@@ -0,0 +1,14 @@
+-- 二次验证的 challenge
+local key = KEYS[1]
+local maxAttempts = tonumber(ARGV[1])
+
+if redis.call("EXISTS", key) == 0 then
+    return 0
+end
+local attempts = redis.call("HINCRBY", key, "attempts", 1)
+if attempts >= maxAttempts then
+    -- 错误次数太多，只能重新用密码登录
+    redis.call("DEL", key)
+    return 0
+end
+return maxAttempts - attempts
@@ -0,0 +1,16 @@
+-- 登录会话
+local key = KEYS[1]
+local uid = ARGV[1]
+local now = tonumber(ARGV[2])
+-- 最近活跃时间最多多久更新一次
+local interval = tonumber(ARGV[3])
+
+-- 会话不存在说明已经退出登录或者被踢下线
+if redis.call("HGET", key, "uid") ~= uid then
+    return 0
+end
+local lastSeen = tonumber(redis.call("HGET", key, "last_seen") or "0")
+if now - lastSeen >= interval then
+    redis.call("HSET", key, "last_seen", now)
+end
+return 1
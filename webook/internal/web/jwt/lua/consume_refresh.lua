@@ -0,0 +1,19 @@
+-- 这个 ssid 下已经用过的 refresh token
+local consumed = KEYS[1]
+-- 登录会话，不存在时整个 ssid 失效
+local session = KEYS[2]
+-- 用户的所有会话
+local sessions = KEYS[3]
+local jti = ARGV[1]
+local expiration = ARGV[2]
+
+if redis.call("SADD", consumed, jti) == 0 then
+    -- 用过的 refresh token 又出现了，可能已经泄露，整个 ssid 失效
+    redis.call("DEL", session)
+    return 0
+end
+-- 换了新的 refresh token，会话跟着续期
+redis.call("PEXPIRE", consumed, expiration)
+redis.call("PEXPIRE", session, expiration)
+redis.call("PEXPIRE", sessions, expiration)
+return 1
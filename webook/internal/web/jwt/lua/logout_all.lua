@@ -0,0 +1,17 @@
+-- 用户的所有会话
+local sessions = KEYS[1]
+-- 会话 key 的前缀，后面拼上 ssid
+local prefix = ARGV[1]
+-- 保留的会话，为空表示全部退出
+local keep = ARGV[2]
+
+local ssids = redis.call("ZRANGE", sessions, 0, -1)
+local cnt = 0
+for _, ssid in ipairs(ssids) do
+    if ssid ~= keep then
+        redis.call("DEL", prefix .. ssid)
+        redis.call("ZREM", sessions, ssid)
+        cnt = cnt + 1
+    end
+end
+return cnt
@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	_ "embed"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,17 +13,30 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+//go:embed lua/consume_refresh.lua
+var luaConsumeRefresh string
+
+var (
+	errInvalidToken = errors.New("token 无效")
+	// ErrRefreshTokenReused 用过的 refresh token 又被使用，整个 ssid 已经失效
+	ErrRefreshTokenReused = errors.New("refresh token 被重复使用")
+)
+
+// RedisJWTHandler 登录会话保存在 Redis 中，会话被删除后这个 ssid 下的 token 全部失效
 type RedisJWTHandler struct {
 	cmd                redis.Cmdable
-	signingMethod      jwt.SigningMethod
+	keys               Keys
 	refreshTokenExpire time.Duration
+	// 最近活跃时间的更新间隔
+	lastSeenInterval time.Duration
 }
 
-func NewRedisJWTHandler(cmd redis.Cmdable) Handler {
+func NewRedisJWTHandler(cmd redis.Cmdable, keys Keys) Handler {
 	return &RedisJWTHandler{
 		cmd:                cmd,
-		signingMethod:      jwt.SigningMethodHS512,
+		keys:               keys,
 		refreshTokenExpire: time.Hour * 24 * 7,
+		lastSeenInterval:   time.Minute,
 	}
 }
 
@@ -32,9 +47,6 @@ type UserClaims struct {
 	UserAgent string
 }
 
-var AccessTokenKey = []byte("sUwYXfLAdddhd1hyWJkWMd4gqQiFznp6")
-var RefreshTokenKey = []byte("sUwYXfLAdddhd1hyWJkWMd4gqQiFznv2")
-
 // SetJWTToken 生成 JWT token
 func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string) error {
 	// claims 中存储用户的信息
@@ -48,8 +60,7 @@ func (h *RedisJWTHandler) SetJWTToken(ctx *gin.Context, uid int64, ssid string)
 		UserAgent: ctx.Request.UserAgent(),
 	}
 
-	token := jwt.NewWithClaims(h.signingMethod, claims)
-	tokenStr, err := token.SignedString(AccessTokenKey)
+	tokenStr, err := h.keys.Access.Sign(claims)
 	if err != nil {
 		return err
 	}
@@ -69,12 +80,13 @@ func (h *RedisJWTHandler) SetRefreshToken(ctx *gin.Context, uid int64, ssid stri
 		Uid:  uid,
 		Ssid: ssid,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// 每个 refresh token 都不一样，用过之后记下来
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(h.refreshTokenExpire)),
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(h.signingMethod, claims)
-	refreshTokenStr, err := refreshToken.SignedString(RefreshTokenKey)
+	refreshTokenStr, err := h.keys.Refresh.Sign(claims)
 	if err != nil {
 		return err
 	}
@@ -82,15 +94,54 @@ func (h *RedisJWTHandler) SetRefreshToken(ctx *gin.Context, uid int64, ssid stri
 	return nil
 }
 
-// CheckSession 检查 Redis 中是否存在 ssid，存在说明已经退出登录
-func (h *RedisJWTHandler) CheckSession(ctx *gin.Context, ssid string) error {
-	cnt, err := h.cmd.Exists(ctx, fmt.Sprintf("users:ssid:%s", ssid)).Result()
-	if err != nil || cnt > 0 {
-		return fmt.Errorf("您已退出登录")
+// ParseAccessToken 解析并验证 access token
+func (h *RedisJWTHandler) ParseAccessToken(tokenStr string) (*UserClaims, error) {
+	claims := &UserClaims{}
+	token, err := h.keys.Access.Parse(tokenStr, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errInvalidToken
+	}
+	return claims, nil
+}
+
+// ParseRefreshToken 解析并验证 refresh token
+func (h *RedisJWTHandler) ParseRefreshToken(tokenStr string) (*RefreshTokenClaims, error) {
+	claims := &RefreshTokenClaims{}
+	token, err := h.keys.Refresh.Parse(tokenStr, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errInvalidToken
+	}
+	return claims, nil
+}
+
+// ConsumeRefreshToken 每个 refresh token 只能用一次。
+// 用过的 token 再次出现说明可能已经泄露，整个 ssid 下的 token 全部失效，返回 ErrRefreshTokenReused
+func (h *RedisJWTHandler) ConsumeRefreshToken(ctx *gin.Context, claims *RefreshTokenClaims) error {
+	if claims.ID == "" {
+		return errInvalidToken
+	}
+	res, err := h.cmd.Eval(ctx, luaConsumeRefresh,
+		[]string{h.consumedKey(claims.Ssid), h.sessionKey(claims.Ssid), h.sessionsKey(claims.Uid)},
+		claims.ID, h.refreshTokenExpire.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if res == 0 {
+		return ErrRefreshTokenReused
 	}
 	return nil
 }
 
+func (h *RedisJWTHandler) consumedKey(ssid string) string {
+	return fmt.Sprintf("users:ssid:%s:refresh_consumed", ssid)
+}
+
 func (h *RedisJWTHandler) ClearToken(ctx *gin.Context) error {
 	// 设置 JWT token 和 refresh token 为空
 	ctx.Header("x-jwt-token", "")
@@ -103,8 +154,12 @@ func (h *RedisJWTHandler) ClearToken(ctx *gin.Context) error {
 	}
 	userClaims := claims.(*UserClaims)
 
-	// 设置 Ssid 为有效，表示退出登录
-	return h.cmd.Set(ctx, fmt.Sprintf("users:ssid:%s", userClaims.Ssid), "", time.Hour*24*7).Err()
+	// 删除当前会话，表示退出登录。会话已经不在了也算退出成功
+	err := h.RevokeSession(ctx, userClaims.UserId, userClaims.Ssid)
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil
+	}
+	return err
 }
 
 // ExtractToken: 从 Authorization 中提取 token
@@ -125,10 +180,15 @@ func (h *RedisJWTHandler) ExtractToken(ctx *gin.Context) string {
 	return segs[1]
 }
 
-// SetLoginToken: 生成 JWT token 和 refresh token
-func (h *RedisJWTHandler) SetLoginToken(ctx *gin.Context, uid int64) error {
+// SetLoginToken: 记录登录会话，生成 JWT token 和 refresh token
+func (h *RedisJWTHandler) SetLoginToken(ctx *gin.Context, uid int64, method string) error {
 	ssid := uuid.New().String()
-	err := h.SetJWTToken(ctx, uid, ssid)
+	err := h.createSession(ctx, uid, ssid, method)
+	if err != nil {
+		return err
+	}
+
+	err = h.SetJWTToken(ctx, uid, ssid)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,227 @@
+package jwt
+
+import (
+	"Webook/webook/internal/repository/cache/redismocks"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRedisJWTHandler_ConsumeRefreshToken(t *testing.T) {
+	keys := []string{"users:ssid:s1:refresh_consumed", "users:session:s1", "users:sessions:1"}
+	args := []any{"jti-1", (time.Hour * 24 * 7).Milliseconds()}
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) redis.Cmdable
+		claims *RefreshTokenClaims
+
+		wantErr error
+	}{
+		{
+			name: "第一次使用",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal(int64(1))
+				cmd.EXPECT().Eval(gomock.Any(), luaConsumeRefresh, keys, args...).Return(res)
+				return cmd
+			},
+			claims: refreshClaims("s1", "jti-1"),
+		},
+		{
+			name: "重复使用，整个 ssid 失效",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal(int64(0))
+				cmd.EXPECT().Eval(gomock.Any(), luaConsumeRefresh, keys, args...).Return(res)
+				return cmd
+			},
+			claims:  refreshClaims("s1", "jti-1"),
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "Redis 出错",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetErr(errors.New("redis error"))
+				cmd.EXPECT().Eval(gomock.Any(), luaConsumeRefresh, keys, args...).Return(res)
+				return cmd
+			},
+			claims:  refreshClaims("s1", "jti-1"),
+			wantErr: errors.New("redis error"),
+		},
+		{
+			name: "没有 jti",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				return redismocks.NewMockCmdable(ctrl)
+			},
+			claims:  refreshClaims("s1", ""),
+			wantErr: errInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := NewRedisJWTHandler(tc.mock(ctrl), Keys{})
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			err := h.ConsumeRefreshToken(ctx, tc.claims)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisJWTHandler_CheckSession(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantErr error
+	}{
+		{
+			name: "会话有效",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal(int64(1))
+				cmd.EXPECT().Eval(gomock.Any(), luaCheckSession, []string{"users:session:s1"},
+					int64(1), gomock.Any(), time.Minute.Milliseconds()).Return(res)
+				return cmd
+			},
+		},
+		{
+			name: "会话已失效",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal(int64(0))
+				cmd.EXPECT().Eval(gomock.Any(), luaCheckSession, []string{"users:session:s1"},
+					int64(1), gomock.Any(), time.Minute.Milliseconds()).Return(res)
+				return cmd
+			},
+			wantErr: errSessionInvalid,
+		},
+		{
+			name: "Redis 出错",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetErr(errors.New("redis error"))
+				cmd.EXPECT().Eval(gomock.Any(), luaCheckSession, []string{"users:session:s1"},
+					int64(1), gomock.Any(), time.Minute.Milliseconds()).Return(res)
+				return cmd
+			},
+			wantErr: errors.New("redis error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := NewRedisJWTHandler(tc.mock(ctrl), Keys{})
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			err := h.CheckSession(ctx, 1, "s1")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisJWTHandler_RevokeSession(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantErr error
+	}{
+		{
+			name: "踢掉自己的会话",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				zrem := redis.NewIntCmd(context.Background())
+				zrem.SetVal(1)
+				cmd.EXPECT().ZRem(gomock.Any(), "users:sessions:1", "s1").Return(zrem)
+				del := redis.NewIntCmd(context.Background())
+				del.SetVal(1)
+				cmd.EXPECT().Del(gomock.Any(), "users:session:s1").Return(del)
+				return cmd
+			},
+		},
+		{
+			name: "不是自己的会话",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				zrem := redis.NewIntCmd(context.Background())
+				zrem.SetVal(0)
+				cmd.EXPECT().ZRem(gomock.Any(), "users:sessions:1", "s1").Return(zrem)
+				return cmd
+			},
+			wantErr: ErrSessionNotFound,
+		},
+		{
+			name: "Redis 出错",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				zrem := redis.NewIntCmd(context.Background())
+				zrem.SetErr(errors.New("redis error"))
+				cmd.EXPECT().ZRem(gomock.Any(), "users:sessions:1", "s1").Return(zrem)
+				return cmd
+			},
+			wantErr: errors.New("redis error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := NewRedisJWTHandler(tc.mock(ctrl), Keys{})
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			err := h.RevokeSession(ctx, 1, "s1")
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisJWTHandler_SetRefreshToken(t *testing.T) {
+	m, err := NewKeyManager([]Key{newEd25519Key(t, "k1")}, "k1")
+	require.NoError(t, err)
+	h := NewRedisJWTHandler(nil, Keys{Access: m, Refresh: m})
+
+	// 每次签发的 jti 都不一样
+	var jtis []string
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/users/refresh_token", nil)
+		require.NoError(t, h.SetRefreshToken(ctx, 1, "s1"))
+
+		claims, err := h.ParseRefreshToken(recorder.Header().Get("x-refresh-token"))
+		require.NoError(t, err)
+		assert.Equal(t, "s1", claims.Ssid)
+		assert.NotEmpty(t, claims.ID)
+		jtis = append(jtis, claims.ID)
+	}
+	assert.NotEqual(t, jtis[0], jtis[1])
+}
+
+func refreshClaims(ssid string, jti string) *RefreshTokenClaims {
+	c := &RefreshTokenClaims{Uid: 1, Ssid: ssid}
+	c.ID = jti
+	return c
+}
@@ -0,0 +1,164 @@
+package jwt
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/check_session.lua
+var luaCheckSession string
+
+//go:embed lua/logout_all.lua
+var luaLogoutAll string
+
+var (
+	ErrSessionNotFound = errors.New("登录会话不存在")
+	errSessionInvalid  = errors.New("您已退出登录")
+)
+
+// 登录方式
+const (
+	LoginMethodPassword = "password"
+	LoginMethodSMS      = "sms"
+	LoginMethodWechat   = "wechat"
+)
+
+// Session 一次登录，对应一个 ssid 下的所有 token
+type Session struct {
+	Ssid        string
+	Uid         int64
+	UserAgent   string
+	Ip          string
+	LoginMethod string
+	Ctime       time.Time
+	// 最近活跃的时间，不是每次请求都更新
+	LastSeen time.Time
+}
+
+const sessionKeyPrefix = "users:session:"
+
+func (h *RedisJWTHandler) sessionKey(ssid string) string {
+	return sessionKeyPrefix + ssid
+}
+
+// sessionsKey 用户的所有会话，member 是 ssid，score 是登录时间
+func (h *RedisJWTHandler) sessionsKey(uid int64) string {
+	return fmt.Sprintf("users:sessions:%d", uid)
+}
+
+// createSession 记录一次登录，会话和 refresh token 一起过期
+func (h *RedisJWTHandler) createSession(ctx *gin.Context, uid int64, ssid string, method string) error {
+	now := time.Now().UnixMilli()
+	key := h.sessionKey(ssid)
+	pipe := h.cmd.TxPipeline()
+	pipe.HSet(ctx, key,
+		"uid", uid,
+		"user_agent", ctx.Request.UserAgent(),
+		"ip", ctx.ClientIP(),
+		"login_method", method,
+		"ctime", now,
+		"last_seen", now,
+	)
+	pipe.Expire(ctx, key, h.refreshTokenExpire)
+	pipe.ZAdd(ctx, h.sessionsKey(uid), redis.Z{Score: float64(now), Member: ssid})
+	pipe.Expire(ctx, h.sessionsKey(uid), h.refreshTokenExpire)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// CheckSession 会话存在并且属于这个用户才有效，顺便按间隔更新最近活跃时间
+func (h *RedisJWTHandler) CheckSession(ctx *gin.Context, uid int64, ssid string) error {
+	res, err := h.cmd.Eval(ctx, luaCheckSession, []string{h.sessionKey(ssid)},
+		uid, time.Now().UnixMilli(), h.lastSeenInterval.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if res == 0 {
+		return errSessionInvalid
+	}
+	return nil
+}
+
+// ListSessions 用户所有有效的会话，最近登录的在前。已经过期的会话顺便清理掉
+func (h *RedisJWTHandler) ListSessions(ctx *gin.Context, uid int64) ([]Session, error) {
+	ssids, err := h.cmd.ZRevRange(ctx, h.sessionsKey(uid), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ssids) == 0 {
+		return nil, nil
+	}
+
+	pipe := h.cmd.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(ssids))
+	for _, ssid := range ssids {
+		cmds = append(cmds, pipe.HGetAll(ctx, h.sessionKey(ssid)))
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(ssids))
+	var expired []any
+	for i, cmd := range cmds {
+		data := cmd.Val()
+		if len(data) == 0 {
+			expired = append(expired, ssids[i])
+			continue
+		}
+		sessions = append(sessions, h.toSession(ssids[i], data))
+	}
+	if len(expired) > 0 {
+		// 清理失败不影响返回
+		_ = h.cmd.ZRem(ctx, h.sessionsKey(uid), expired...).Err()
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Ctime.After(sessions[j].Ctime)
+	})
+	return sessions, nil
+}
+
+// RevokeSession 踢掉用户的一个会话，会话不属于这个用户时返回 ErrSessionNotFound
+func (h *RedisJWTHandler) RevokeSession(ctx *gin.Context, uid int64, ssid string) error {
+	// 只有自己的会话才在自己的集合里
+	removed, err := h.cmd.ZRem(ctx, h.sessionsKey(uid), ssid).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrSessionNotFound
+	}
+	return h.cmd.Del(ctx, h.sessionKey(ssid)).Err()
+}
+
+// RevokeAllSessions 退出所有设备，包括当前设备
+func (h *RedisJWTHandler) RevokeAllSessions(ctx *gin.Context, uid int64) error {
+	return h.RevokeOtherSessions(ctx, uid, "")
+}
+
+// RevokeOtherSessions 退出除了 ssid 之外的所有设备
+func (h *RedisJWTHandler) RevokeOtherSessions(ctx *gin.Context, uid int64, ssid string) error {
+	return h.cmd.Eval(ctx, luaLogoutAll, []string{h.sessionsKey(uid)}, sessionKeyPrefix, ssid).Err()
+}
+
+func (h *RedisJWTHandler) toSession(ssid string, data map[string]string) Session {
+	uid, _ := strconv.ParseInt(data["uid"], 10, 64)
+	ctime, _ := strconv.ParseInt(data["ctime"], 10, 64)
+	lastSeen, _ := strconv.ParseInt(data["last_seen"], 10, 64)
+	return Session{
+		Ssid:        ssid,
+		Uid:         uid,
+		UserAgent:   data["user_agent"],
+		Ip:          data["ip"],
+		LoginMethod: data["login_method"],
+		Ctime:       time.UnixMilli(ctime),
+		LastSeen:    time.UnixMilli(lastSeen),
+	}
+}
@@ -5,8 +5,22 @@ import "github.com/gin-gonic/gin"
 type Handler interface {
 	SetJWTToken(ctx *gin.Context, uid int64, ssid string) error
 	SetRefreshToken(ctx *gin.Context, uid int64, ssid string) error
-	CheckSession(ctx *gin.Context, ssid string) error
+	CheckSession(ctx *gin.Context, uid int64, ssid string) error
 	ClearToken(ctx *gin.Context) error
 	ExtractToken(ctx *gin.Context) string
-	SetLoginToken(ctx *gin.Context, uid int64) error
+	// SetLoginToken method 是登录方式
+	SetLoginToken(ctx *gin.Context, uid int64, method string) error
+	ParseAccessToken(tokenStr string) (*UserClaims, error)
+	ParseRefreshToken(tokenStr string) (*RefreshTokenClaims, error)
+	ConsumeRefreshToken(ctx *gin.Context, claims *RefreshTokenClaims) error
+	ListSessions(ctx *gin.Context, uid int64) ([]Session, error)
+	RevokeSession(ctx *gin.Context, uid int64, ssid string) error
+	RevokeAllSessions(ctx *gin.Context, uid int64) error
+	RevokeOtherSessions(ctx *gin.Context, uid int64, ssid string) error
+
+	// 二次验证
+	SetChallengeToken(ctx *gin.Context, uid int64, method string) error
+	CheckChallenge(ctx *gin.Context, token string) (Challenge, error)
+	ChallengeFailed(ctx *gin.Context, token string) error
+	ConsumeChallenge(ctx *gin.Context, token string) error
 }
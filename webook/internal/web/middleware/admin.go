@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	myjwt "Webook/webook/internal/web/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddlewareBuilder 只允许配置的管理员访问，需要放在登录校验之后
+type AdminMiddlewareBuilder struct {
+	adminIds map[int64]struct{}
+}
+
+func NewAdminMiddlewareBuilder(adminIds ...int64) *AdminMiddlewareBuilder {
+	ids := make(map[int64]struct{}, len(adminIds))
+	for _, id := range adminIds {
+		ids[id] = struct{}{}
+	}
+	return &AdminMiddlewareBuilder{
+		adminIds: ids,
+	}
+}
+
+func (a *AdminMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		val, _ := ctx.Get("claims")
+		claims, ok := val.(*myjwt.UserClaims)
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if _, ok = a.adminIds[claims.UserId]; !ok {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	myjwt "Webook/webook/internal/web/jwt"
 
 	"github.com/gin-gonic/gin"
-	jwt "github.com/golang-jwt/jwt/v5"
 )
 
 type LoginJWTMiddlewareBuilder struct {
@@ -40,17 +39,13 @@ func (l *LoginJWTMiddlewareBuilder) Build() gin.HandlerFunc {
 			ctx.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
-		claims := &myjwt.UserClaims{}
-		token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-			return myjwt.AccessTokenKey, nil
-		})
+		claims, err := l.ParseAccessToken(tokenStr)
 		if err != nil {
-			// 解析失败
+			// 解析失败，或者签名、过期时间验证不通过
 			ctx.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
-		if token == nil || !token.Valid || claims.UserId == 0 {
-			// 解析成功，但是 token 无效
+		if claims.UserId == 0 {
 			ctx.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
@@ -61,8 +56,8 @@ func (l *LoginJWTMiddlewareBuilder) Build() gin.HandlerFunc {
 			return
 		}
 
-		// 检查 Redis 中是否存在 ssid，存在说明已经退出登录
-		if err := l.CheckSession(ctx, claims.Ssid); err != nil {
+		// 检查登录会话是否还在，不在说明已经退出登录或者被踢下线
+		if err := l.CheckSession(ctx, claims.UserId, claims.Ssid); err != nil {
 			ctx.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
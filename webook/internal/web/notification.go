@@ -0,0 +1,234 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	svc     service.NotificationService
+	userSvc service.UserService
+	logger  logger.Logger
+}
+
+func NewNotificationHandler(svc service.NotificationService, userSvc service.UserService, logger logger.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		svc:     svc,
+		userSvc: userSvc,
+		logger:  logger,
+	}
+}
+
+func (h *NotificationHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	ug.POST("/list", h.List)
+	ug.GET("/unread", h.UnreadCnt)
+	// 标记指定的通知已读，或者标记某个类型（不传则为所有类型）的通知全部已读
+	ug.POST("/read", h.MarkRead)
+	ug.POST("/read_all", h.MarkAllRead)
+}
+
+type NotificationActorVO struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type NotificationVO struct {
+	Id       int64  `json:"id"`
+	Type     string `json:"type"`
+	Biz      string `json:"biz"`
+	BizId    int64  `json:"biz_id"`
+	BizTitle string `json:"biz_title"`
+	// 最近的几个触发者，最新的在前，ActorCnt 是总人数
+	Actors   []NotificationActorVO `json:"actors"`
+	ActorCnt int64                 `json:"actor_cnt"`
+	Content  string                `json:"content,omitempty"`
+	Read     bool                  `json:"read"`
+	Utime    string                `json:"utime"`
+}
+
+// List 通知列表，最近更新的在前
+func (h *NotificationHandler) List(ctx *gin.Context) {
+	type Req struct {
+		ArticlePage
+		// like, collect, follow, comment, reply，不传表示所有类型
+		Type string `json:"type"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 10
+	}
+	typ, ok := h.parseType(ctx, req.Type)
+	if !ok {
+		return
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	ns, err := h.svc.List(ctx, userId, typ, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取通知列表失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	var actorIds []int64
+	for _, n := range ns {
+		actorIds = append(actorIds, n.Actors...)
+	}
+	nameMap, err := h.userSvc.GetNameMapByIds(ctx, actorIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取通知触发者信息失败",
+			logger.Error(err),
+		)
+		return
+	}
+
+	vos := make([]NotificationVO, 0, len(ns))
+	for _, n := range ns {
+		actors := make([]NotificationActorVO, 0, len(n.Actors))
+		for _, id := range n.Actors {
+			actors = append(actors, NotificationActorVO{
+				Id:   id,
+				Name: nameMap[id],
+			})
+		}
+		vos = append(vos, NotificationVO{
+			Id:       n.Id,
+			Type:     n.Type.String(),
+			Biz:      n.Biz,
+			BizId:    n.BizId,
+			BizTitle: n.BizTitle,
+			Actors:   actors,
+			ActorCnt: n.ActorCnt,
+			Content:  n.Content,
+			Read:     n.Read,
+			Utime:    n.Utime.Format(time.DateTime),
+		})
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "获取通知列表成功",
+		Data: vos,
+	})
+}
+
+// UnreadCnt 未读数：总数和各类型的数量
+func (h *NotificationHandler) UnreadCnt(ctx *gin.Context) {
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	cnts, err := h.svc.UnreadCnt(ctx, userId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取未读通知数失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	type UnreadVO struct {
+		Total int64            `json:"total"`
+		Types map[string]int64 `json:"types"`
+	}
+	vo := UnreadVO{Types: make(map[string]int64, len(cnts))}
+	for typ, cnt := range cnts {
+		vo.Total += cnt
+		vo.Types[typ.String()] = cnt
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "获取未读通知数成功",
+		Data: vo,
+	})
+}
+
+func (h *NotificationHandler) MarkRead(ctx *gin.Context) {
+	type Req struct {
+		Ids []int64 `json:"ids"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	if err := h.svc.MarkRead(ctx, userId, req.Ids); err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("标记通知已读失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "标记已读成功",
+	})
+}
+
+func (h *NotificationHandler) MarkAllRead(ctx *gin.Context) {
+	type Req struct {
+		Type string `json:"type"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	typ, ok := h.parseType(ctx, req.Type)
+	if !ok {
+		return
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	if err := h.svc.MarkAllRead(ctx, userId, typ); err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("标记全部通知已读失败",
+			logger.Int64("userId", userId),
+			logger.String("type", req.Type),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "标记已读成功",
+	})
+}
+
+// parseType 空字符串表示所有类型，未知的类型直接返回错误响应
+func (h *NotificationHandler) parseType(ctx *gin.Context, s string) (domain.NotificationType, bool) {
+	if s == "" {
+		return domain.NotificationTypeUnknown, true
+	}
+	typ := domain.NotificationTypeFromString(s)
+	if typ == domain.NotificationTypeUnknown {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "通知类型错误",
+		})
+		return typ, false
+	}
+	return typ, true
+}
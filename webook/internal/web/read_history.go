@@ -0,0 +1,207 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReadHistoryHandler struct {
+	svc     service.ReadHistoryService
+	userSvc service.UserService
+	logger  logger.Logger
+	biz     string
+}
+
+func NewReadHistoryHandler(svc service.ReadHistoryService, userSvc service.UserService, logger logger.Logger) *ReadHistoryHandler {
+	return &ReadHistoryHandler{
+		svc:     svc,
+		userSvc: userSvc,
+		logger:  logger,
+		biz:     "article",
+	}
+}
+
+func (h *ReadHistoryHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	// 最近阅读
+	ug.POST("/list", h.List)
+	// 上报阅读进度
+	ug.POST("/progress", h.Progress)
+	ug.POST("/delete", h.Delete)
+	ug.POST("/clear", h.Clear)
+}
+
+type ReadHistoryVO struct {
+	// 文章 id
+	Id         int64  `json:"id"`
+	Title      string `json:"title"`
+	Abstract   string `json:"abstract"`
+	AuthorId   int64  `json:"author_id"`
+	AuthorName string `json:"author_name"`
+	// 文章已经撤回或删除
+	Unavailable bool   `json:"unavailable"`
+	Progress    int    `json:"progress"`
+	FirstReadAt string `json:"first_read_at"`
+	LastReadAt  string `json:"last_read_at"`
+}
+
+func (h *ReadHistoryHandler) List(ctx *gin.Context) {
+	var req ArticlePage
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 10
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	hs, err := h.svc.List(ctx, userId, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取阅读历史失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+
+	var authorIds []int64
+	for _, hi := range hs {
+		if hi.Article.Id > 0 {
+			authorIds = append(authorIds, hi.Article.Author.Id)
+		}
+	}
+	nameMap, err := h.userSvc.GetNameMapByIds(ctx, authorIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取作者信息失败",
+			logger.Error(err),
+		)
+		return
+	}
+
+	vos := make([]ReadHistoryVO, 0, len(hs))
+	for _, hi := range hs {
+		vos = append(vos, h.toVO(hi, nameMap))
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "获取阅读历史成功",
+		Data: vos,
+	})
+}
+
+func (h *ReadHistoryHandler) Progress(ctx *gin.Context) {
+	type Req struct {
+		Id       int64 `json:"id"`
+		Progress int   `json:"progress"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	err := h.svc.UpdateProgress(ctx, userId, h.biz, req.Id, req.Progress)
+	switch {
+	case errors.Is(err, service.ErrReadProgressInvalid):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("更新阅读进度失败",
+			logger.Int64("userId", userId),
+			logger.Int64("id", req.Id),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "更新阅读进度成功",
+	})
+}
+
+func (h *ReadHistoryHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Ids []int64 `json:"ids"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if len(req.Ids) == 0 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "请选择要删除的阅读记录",
+		})
+		return
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	if err := h.svc.Delete(ctx, userId, h.biz, req.Ids); err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("删除阅读历史失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "删除阅读历史成功",
+	})
+}
+
+func (h *ReadHistoryHandler) Clear(ctx *gin.Context) {
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	if err := h.svc.Clear(ctx, userId); err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("清空阅读历史失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "清空阅读历史成功",
+	})
+}
+
+func (h *ReadHistoryHandler) toVO(hi domain.ReadHistory, nameMap map[int64]string) ReadHistoryVO {
+	vo := ReadHistoryVO{
+		Id:          hi.BizId,
+		Unavailable: hi.Article.Id == 0,
+		Progress:    hi.Progress,
+		FirstReadAt: hi.FirstReadAt.Format(time.DateTime),
+		LastReadAt:  hi.LastReadAt.Format(time.DateTime),
+	}
+	if !vo.Unavailable {
+		vo.Title = hi.Article.Title
+		vo.Abstract = hi.Article.Abstract()
+		vo.AuthorId = hi.Article.Author.Id
+		vo.AuthorName = nameMap[hi.Article.Author.Id]
+	}
+	return vo
+}
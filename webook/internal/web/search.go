@@ -0,0 +1,109 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	svc     service.SearchService
+	userSvc service.UserService
+	logger  logger.Logger
+}
+
+func NewSearchHandler(svc service.SearchService, userSvc service.UserService, logger logger.Logger) *SearchHandler {
+	return &SearchHandler{
+		svc:     svc,
+		userSvc: userSvc,
+		logger:  logger,
+	}
+}
+
+func (h *SearchHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	ug.POST("/search", h.SearchArticle)
+}
+
+// ArticleSearchVO 标题和摘要中命中的关键词用 <em></em> 包裹，其余部分已做 HTML 转义
+type ArticleSearchVO struct {
+	Id         int64  `json:"id"`
+	Title      string `json:"title"`
+	Abstract   string `json:"abstract"`
+	AuthorId   int64  `json:"author_id"`
+	AuthorName string `json:"author_name"`
+	Utime      string `json:"utime"`
+}
+
+type ArticleSearchResultVO struct {
+	Total    int               `json:"total"`
+	Articles []ArticleSearchVO `json:"articles"`
+}
+
+// SearchArticle 搜索线上文章
+func (h *SearchHandler) SearchArticle(ctx *gin.Context) {
+	type Req struct {
+		ArticlePage
+		Keyword string `json:"keyword"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 10
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	hits, total, err := h.svc.SearchArticle(ctx, req.Keyword, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("搜索文章失败",
+			logger.String("keyword", req.Keyword),
+			logger.Error(err),
+		)
+		return
+	}
+
+	// 获取作者信息
+	userIds := slice.Map(hits, func(idx int, hit domain.ArticleSearchHit) int64 {
+		return hit.Article.Author.Id
+	})
+	authorMap, err := h.userSvc.GetNameMapByIds(ctx, userIds)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取作者信息失败",
+			logger.Error(err),
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "搜索成功",
+		Data: ArticleSearchResultVO{
+			Total: total,
+			Articles: slice.Map(hits, func(idx int, hit domain.ArticleSearchHit) ArticleSearchVO {
+				return ArticleSearchVO{
+					Id:         hit.Article.Id,
+					Title:      hit.TitleHighlight,
+					Abstract:   hit.ContentHighlight,
+					AuthorId:   hit.Article.Author.Id,
+					AuthorName: authorMap[hit.Article.Author.Id],
+					Utime:      hit.Article.Utime.Format(time.DateTime),
+				}
+			}),
+		},
+	})
+}
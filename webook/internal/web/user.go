@@ -5,12 +5,12 @@ import (
 	"Webook/webook/internal/service"
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	regexp "github.com/dlclark/regexp2"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	jwt "github.com/golang-jwt/jwt/v5"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
@@ -18,11 +18,14 @@ import (
 )
 
 type UserHandler struct {
-	svc         service.UserService
-	codeSvc     service.CodeService
-	emailExp    *regexp.Regexp
-	passwordExp *regexp.Regexp
-	cmd         redis.Cmdable
+	svc       service.UserService
+	codeSvc   service.CodeService
+	followSvc service.FollowService
+	// 开启了二次验证的用户，密码登录后还要输入验证码
+	twoFactorSvc service.TwoFactorService
+	emailExp     *regexp.Regexp
+	passwordExp  *regexp.Regexp
+	cmd          redis.Cmdable
 	myjwt.Handler
 }
 
@@ -38,22 +41,38 @@ func (u *UserHandler) RegisterRoutes(ug *gin.RouterGroup) {
 	ug.POST("/login_sms/code/send", u.LoginSMSCodeSend)
 	ug.POST("/login_sms", u.LoginSMSCodeVerify)
 	ug.POST("/refresh_token", u.RefreshToken)
+	ug.GET("/sessions", u.Sessions)
+	ug.POST("/sessions/revoke", u.RevokeSession)
+	ug.POST("/logout_all", u.LogoutAll)
+	ug.POST("/login/2fa", u.LoginTwoFactor)
+	ug.GET("/2fa", u.TwoFactorStatus)
+	ug.POST("/2fa/enroll", u.TwoFactorEnroll)
+	ug.POST("/2fa/confirm", u.TwoFactorConfirm)
+	ug.POST("/2fa/disable", u.TwoFactorDisable)
+	ug.POST("/password/change", u.ChangePassword)
+	ug.POST("/password/forgot", u.ForgotPassword)
+	ug.POST("/password/reset", u.ResetPassword)
 }
 
 const (
 	emailRegexPattern    = "^[a-zA-Z0-9_.+-]+@[a-zA-Z0-9-]+\\.[a-zA-Z0-9-.]+$"
 	passwordRegexPattern = "^(?=.*[a-zA-Z])(?=.*[0-9])(?=.*[!@#$%^&*()_+\\-=\\[\\]{};':\"\\\\|,.<>\\/?]).{8,}$"
+
+	passwordFormatMsg = "密码必须包含至少一个字母、数字、特殊字符，长度至少8位"
 )
 
-func NewUserHandler(svc service.UserService, codeSvc service.CodeService, handler myjwt.Handler) *UserHandler {
+func NewUserHandler(svc service.UserService, codeSvc service.CodeService, followSvc service.FollowService,
+	twoFactorSvc service.TwoFactorService, handler myjwt.Handler) *UserHandler {
 	emailExp := regexp.MustCompile(emailRegexPattern, regexp.None)
 	passwordExp := regexp.MustCompile(passwordRegexPattern, regexp.None)
 	return &UserHandler{
-		svc:         svc,
-		emailExp:    emailExp,
-		passwordExp: passwordExp,
-		codeSvc:     codeSvc,
-		Handler:     handler,
+		svc:          svc,
+		emailExp:     emailExp,
+		passwordExp:  passwordExp,
+		codeSvc:      codeSvc,
+		followSvc:    followSvc,
+		twoFactorSvc: twoFactorSvc,
+		Handler:      handler,
 	}
 }
 
@@ -90,7 +109,7 @@ func (u *UserHandler) SignUp(ctx *gin.Context) {
 		return
 	}
 	if !ok {
-		ctx.String(http.StatusOK, "密码必须包含至少一个字母、数字、特殊字符，长度至少8位")
+		ctx.String(http.StatusOK, passwordFormatMsg)
 		return
 	}
 
@@ -165,8 +184,22 @@ func (u *UserHandler) LoginJWT(ctx *gin.Context) {
 	user, err := u.svc.Login(ctx, req.Email, req.Password)
 	switch err {
 	case nil:
+		enabled, err := u.twoFactorSvc.Enabled(ctx, user.Id)
+		if err != nil {
+			ctx.String(http.StatusOK, "系统错误")
+			return
+		}
+		if enabled {
+			// 开启了二次验证，先发 challenge token，验证码通过后才真正登录
+			if err := u.SetChallengeToken(ctx, user.Id, myjwt.LoginMethodPassword); err != nil {
+				ctx.String(http.StatusOK, "系统错误")
+				return
+			}
+			ctx.String(http.StatusOK, "请输入二次验证码")
+			return
+		}
 		// 设置 JWT token，保持登录状态
-		if err := u.SetLoginToken(ctx, user.Id); err != nil {
+		if err := u.SetLoginToken(ctx, user.Id, myjwt.LoginMethodPassword); err != nil {
 			ctx.String(http.StatusOK, "系统错误")
 			return
 		}
@@ -251,6 +284,12 @@ type ProfileJWTResp struct {
 	Nickname string `json:"Nickname"`
 	Birthday string `json:"Birthday"`
 	AboutMe  string `json:"AboutMe"`
+
+	// 粉丝数、关注数，查看其他用户时还会返回当前用户是否关注了对方
+	FollowerCnt int64 `json:"FollowerCnt"`
+	FolloweeCnt int64 `json:"FolloweeCnt"`
+	Followed    bool  `json:"Followed"`
+	FollowedBy  bool  `json:"FollowedBy"`
 }
 
 func (u *UserHandler) ProfileJWT(ctx *gin.Context) {
@@ -262,24 +301,51 @@ func (u *UserHandler) ProfileJWT(ctx *gin.Context) {
 	}
 	userClaims := claims.(*myjwt.UserClaims)
 
-	// 获取用户信息
+	// 获取用户信息，带上 id 参数时查看其他用户
 	userId := userClaims.UserId
-	user, err := u.svc.Profile(ctx, userId)
+	targetId := userId
+	if idStr := ctx.Query("id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			ctx.String(http.StatusBadRequest, "id 格式错误")
+			return
+		}
+		targetId = id
+	}
+	user, err := u.svc.Profile(ctx, targetId)
 	if err != nil {
 		ctx.String(http.StatusBadRequest, "系统错误")
 		return
 	}
 
+	statics, err := u.followSvc.Statics(ctx, targetId)
+	if err != nil {
+		ctx.String(http.StatusOK, "系统错误")
+		return
+	}
+	resp := &ProfileJWTResp{
+		Nickname:    user.Nickname,
+		Birthday:    user.Birthday.Format(time.DateOnly),
+		AboutMe:     user.AboutMe,
+		FollowerCnt: statics.Followers,
+		FolloweeCnt: statics.Followees,
+	}
+	if targetId == userId {
+		// 邮箱和手机号只对本人可见
+		resp.Email = user.Email
+		resp.Phone = user.Phone
+	} else {
+		info, err := u.followSvc.Info(ctx, userId, targetId)
+		if err != nil {
+			ctx.String(http.StatusOK, "系统错误")
+			return
+		}
+		resp.Followed = info.Followed
+		resp.FollowedBy = info.FollowedBy
+	}
+
 	// 返回用户信息
-	ctx.JSON(http.StatusOK,
-		&ProfileJWTResp{
-			Email:    user.Email,
-			Phone:    user.Phone,
-			Nickname: user.Nickname,
-			Birthday: user.Birthday.Format(time.DateOnly),
-			AboutMe:  user.AboutMe,
-		},
-	)
+	ctx.JSON(http.StatusOK, resp)
 }
 
 type LoginSMSCodeSendReq struct {
@@ -367,7 +433,7 @@ func (u *UserHandler) LoginSMSCodeVerify(ctx *gin.Context) {
 	}
 
 	// 配置 JWT token，保持登录状态
-	if err := u.SetLoginToken(ctx, user.Id); err != nil {
+	if err := u.SetLoginToken(ctx, user.Id, myjwt.LoginMethodSMS); err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Code: 5,
 			Msg:  "系统错误",
@@ -381,31 +447,52 @@ func (u *UserHandler) LoginSMSCodeVerify(ctx *gin.Context) {
 	})
 }
 
-// RefreshToken: Authorization 中携带的是 refresh token
+// RefreshToken: Authorization 中携带的是 refresh token。
+// 每次刷新同时换一个新的 refresh token，旧的作废
 func (u *UserHandler) RefreshToken(ctx *gin.Context) {
 	refreshTokenStr := u.ExtractToken(ctx)
 
 	// 解析 refresh token
-	var claims myjwt.RefreshTokenClaims
-	token, err := jwt.ParseWithClaims(refreshTokenStr, &claims, func(token *jwt.Token) (interface{}, error) {
-		return myjwt.RefreshTokenKey, nil
-	})
-	if err != nil || !token.Valid {
+	claims, err := u.ParseRefreshToken(refreshTokenStr)
+	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
 	// 检查 Redis 中是否存在 ssid，存在说明已经退出登录
-	if err := u.CheckSession(ctx, claims.Ssid); err != nil {
+	if err := u.CheckSession(ctx, claims.Uid, claims.Ssid); err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
-	// 生成一个新的 access token
+	// 标记为已使用，用过的 token 再次出现时整个 ssid 失效
+	err = u.ConsumeRefreshToken(ctx, claims)
+	if errors.Is(err, myjwt.ErrRefreshTokenReused) {
+		zap.L().Warn("refresh token 被重复使用，已退出该登录的所有 token",
+			zap.Int64("uid", claims.Uid),
+			zap.String("ssid", claims.Ssid),
+			zap.String("jti", claims.ID),
+			zap.String("ip", ctx.ClientIP()),
+			zap.String("userAgent", ctx.Request.UserAgent()),
+		)
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		zap.L().Error("使用 refresh token 失败", zap.Error(err))
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	// 生成新的 access token 和 refresh token
 	if err := u.SetJWTToken(ctx, claims.Uid, claims.Ssid); err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
+	if err := u.SetRefreshToken(ctx, claims.Uid, claims.Ssid); err != nil {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
 
 	ctx.JSON(http.StatusOK, Result{
 		Msg: "刷新成功",
@@ -425,3 +512,106 @@ func (u *UserHandler) LogoutJWT(ctx *gin.Context) {
 		Msg: "退出登录成功",
 	})
 }
+
+type SessionVO struct {
+	Ssid        string `json:"ssid"`
+	UserAgent   string `json:"userAgent"`
+	Ip          string `json:"ip"`
+	LoginMethod string `json:"loginMethod"`
+	Ctime       int64  `json:"ctime"`
+	LastSeen    int64  `json:"lastSeen"`
+	// 是不是当前正在使用的会话
+	Current bool `json:"current"`
+}
+
+// Sessions 当前用户所有登录中的设备
+func (u *UserHandler) Sessions(ctx *gin.Context) {
+	claims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	sessions, err := u.ListSessions(ctx, claims.UserId)
+	if err != nil {
+		zap.L().Error("查询登录会话失败", zap.Int64("uid", claims.UserId), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+
+	vos := make([]SessionVO, 0, len(sessions))
+	for _, s := range sessions {
+		vos = append(vos, SessionVO{
+			Ssid:        s.Ssid,
+			UserAgent:   s.UserAgent,
+			Ip:          s.Ip,
+			LoginMethod: s.LoginMethod,
+			Ctime:       s.Ctime.UnixMilli(),
+			LastSeen:    s.LastSeen.UnixMilli(),
+			Current:     s.Ssid == claims.Ssid,
+		})
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: vos,
+	})
+}
+
+type RevokeSessionReq struct {
+	Ssid string `json:"ssid"`
+}
+
+// RevokeSession 踢掉某个设备，踢掉当前设备等同于退出登录
+func (u *UserHandler) RevokeSession(ctx *gin.Context) {
+	var req RevokeSessionReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Ssid == "" {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "会话不存在",
+		})
+		return
+	}
+
+	claims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err := u.Handler.RevokeSession(ctx, claims.UserId, req.Ssid)
+	switch {
+	case errors.Is(err, myjwt.ErrSessionNotFound):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "会话不存在",
+		})
+	case err != nil:
+		zap.L().Error("踢掉登录会话失败", zap.Int64("uid", claims.UserId),
+			zap.String("ssid", req.Ssid), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	default:
+		if req.Ssid == claims.Ssid {
+			ctx.Header("x-jwt-token", "")
+			ctx.Header("x-refresh-token", "")
+		}
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	}
+}
+
+// LogoutAll 退出所有设备
+func (u *UserHandler) LogoutAll(ctx *gin.Context) {
+	claims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	if err := u.RevokeAllSessions(ctx, claims.UserId); err != nil {
+		zap.L().Error("退出所有设备失败", zap.Int64("uid", claims.UserId), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.Header("x-jwt-token", "")
+	ctx.Header("x-refresh-token", "")
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "已退出所有设备",
+	})
+}
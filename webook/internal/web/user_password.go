@@ -0,0 +1,172 @@
+package web
+
+import (
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// checkNewPassword 新密码和注册时一样要满足 passwordExp，不满足时直接写回响应
+func (u *UserHandler) checkNewPassword(ctx *gin.Context, password, confirmPassword string) bool {
+	ok, err := u.passwordExp.MatchString(password)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return false
+	}
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  passwordFormatMsg,
+		})
+		return false
+	}
+	if password != confirmPassword {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "两次输入的密码不一致",
+		})
+		return false
+	}
+	return true
+}
+
+type ChangePasswordReq struct {
+	OldPassword     string `json:"oldPassword"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+// ChangePassword 修改密码，当前设备保持登录，其他设备全部退出
+func (u *UserHandler) ChangePassword(ctx *gin.Context) {
+	var req ChangePasswordReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if !u.checkNewPassword(ctx, req.Password, req.ConfirmPassword) {
+		return
+	}
+
+	claims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err := u.svc.ChangePassword(ctx, claims.UserId, req.OldPassword, req.Password)
+	if errors.Is(err, service.ErrWrongPassword) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		zap.L().Error("修改密码失败", zap.Int64("uid", claims.UserId), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+
+	if err = u.RevokeOtherSessions(ctx, claims.UserId, claims.Ssid); err != nil {
+		// 密码已经改好了，只是其他设备没退出，提示用户手动退出
+		zap.L().Error("修改密码后退出其他设备失败", zap.Int64("uid", claims.UserId), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "密码已修改，但其他设备退出失败，请在登录设备中手动退出",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "密码已修改，其他设备已退出登录",
+	})
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email"`
+}
+
+// ForgotPassword 发送重置邮件。不管邮箱是否注册都返回一样的结果
+func (u *UserHandler) ForgotPassword(ctx *gin.Context) {
+	var req ForgotPasswordReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	ok, err := u.emailExp.MatchString(req.Email)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "你的邮箱格式不对",
+		})
+		return
+	}
+
+	err = u.svc.SendPasswordReset(ctx, req.Email)
+	switch {
+	case errors.Is(err, service.ErrPasswordResetTooFrequent):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "发送太频繁，请稍后再试",
+		})
+	case err != nil:
+		zap.L().Error("发送重置密码邮件失败", zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "如果邮箱已注册，重置邮件已发送",
+		})
+	}
+}
+
+type ResetPasswordReq struct {
+	Token           string `json:"token"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+// ResetPassword 用邮件中的 token 重置密码，所有设备都需要重新登录
+func (u *UserHandler) ResetPassword(ctx *gin.Context) {
+	var req ResetPasswordReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if !u.checkNewPassword(ctx, req.Password, req.ConfirmPassword) {
+		return
+	}
+
+	uid, err := u.svc.ResetPassword(ctx, req.Token, req.Password)
+	if errors.Is(err, service.ErrPasswordResetInvalid) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		zap.L().Error("重置密码失败", zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+
+	if err = u.RevokeAllSessions(ctx, uid); err != nil {
+		zap.L().Error("重置密码后退出所有设备失败", zap.Int64("uid", uid), zap.Error(err))
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "密码已重置，请重新登录",
+	})
+}
@@ -0,0 +1,212 @@
+package web
+
+import (
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type LoginTwoFactorReq struct {
+	// 密码登录时 x-2fa-token 中的 challenge token
+	Token string `json:"token"`
+	// 验证器上的 6 位验证码，或者一个恢复码
+	Code string `json:"code"`
+}
+
+// LoginTwoFactor 用 challenge token 和验证码换真正的登录 token
+func (u *UserHandler) LoginTwoFactor(ctx *gin.Context) {
+	var req LoginTwoFactorReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+
+	challenge, err := u.CheckChallenge(ctx, req.Token)
+	if errors.Is(err, myjwt.ErrChallengeNotFound) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  myjwt.ErrChallengeNotFound.Error(),
+		})
+		return
+	}
+	if err != nil {
+		zap.L().Error("查询二次验证 challenge 失败", zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+
+	err = u.twoFactorSvc.Verify(ctx, challenge.Uid, req.Code)
+	switch {
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		if err := u.ChallengeFailed(ctx, req.Token); err != nil {
+			zap.L().Error("记录二次验证失败次数失败", zap.Int64("uid", challenge.Uid), zap.Error(err))
+		}
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "验证码不对",
+		})
+		return
+	case errors.Is(err, service.ErrTwoFactorLocked):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	case err != nil:
+		zap.L().Error("二次验证失败", zap.Int64("uid", challenge.Uid), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+
+	// challenge 只能用一次
+	err = u.ConsumeChallenge(ctx, req.Token)
+	if errors.Is(err, myjwt.ErrChallengeNotFound) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  myjwt.ErrChallengeNotFound.Error(),
+		})
+		return
+	}
+	if err == nil {
+		err = u.SetLoginToken(ctx, challenge.Uid, challenge.LoginMethod)
+	}
+	if err != nil {
+		zap.L().Error("二次验证后登录失败", zap.Int64("uid", challenge.Uid), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "登录成功",
+	})
+}
+
+type TwoFactorStatusVO struct {
+	Enabled           bool  `json:"enabled"`
+	RecoveryCodesLeft int64 `json:"recoveryCodesLeft"`
+}
+
+func (u *UserHandler) TwoFactorStatus(ctx *gin.Context) {
+	claims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	status, err := u.twoFactorSvc.Status(ctx, claims.UserId)
+	if err != nil {
+		zap.L().Error("查询二次验证状态失败", zap.Int64("uid", claims.UserId), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: TwoFactorStatusVO{
+			Enabled:           status.Enabled,
+			RecoveryCodesLeft: status.RecoveryCodesLeft,
+		},
+	})
+}
+
+type TwoFactorEnrollVO struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TwoFactorEnroll 生成密钥，用户用验证器扫描 uri 后再调用 confirm
+func (u *UserHandler) TwoFactorEnroll(ctx *gin.Context) {
+	claims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	enrollment, err := u.twoFactorSvc.Enroll(ctx, claims.UserId)
+	switch {
+	case errors.Is(err, service.ErrTwoFactorEnabled):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	case err != nil:
+		zap.L().Error("生成二次验证密钥失败", zap.Int64("uid", claims.UserId), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Data: TwoFactorEnrollVO{
+				Secret: enrollment.Secret,
+				URI:    enrollment.URI,
+			},
+		})
+	}
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code"`
+}
+
+// TwoFactorConfirm 确认第一个验证码，开启二次验证并返回恢复码
+func (u *UserHandler) TwoFactorConfirm(ctx *gin.Context) {
+	var req TwoFactorCodeReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	codes, err := u.twoFactorSvc.Confirm(ctx, claims.UserId, req.Code)
+	switch {
+	case errors.Is(err, service.ErrTwoFactorEnabled),
+		errors.Is(err, service.ErrTwoFactorNotEnrolled),
+		errors.Is(err, service.ErrInvalidTwoFactorCode),
+		errors.Is(err, service.ErrTwoFactorLocked):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	case err != nil:
+		zap.L().Error("开启二次验证失败", zap.Int64("uid", claims.UserId), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Msg:  "二次验证已开启，请妥善保存恢复码",
+			Data: codes,
+		})
+	}
+}
+
+// TwoFactorDisable 关闭二次验证，需要验证码或者恢复码
+func (u *UserHandler) TwoFactorDisable(ctx *gin.Context) {
+	var req TwoFactorCodeReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims := ctx.MustGet("claims").(*myjwt.UserClaims)
+	err := u.twoFactorSvc.Disable(ctx, claims.UserId, req.Code)
+	switch {
+	case errors.Is(err, service.ErrTwoFactorNotEnabled),
+		errors.Is(err, service.ErrInvalidTwoFactorCode),
+		errors.Is(err, service.ErrTwoFactorLocked):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+	case err != nil:
+		zap.L().Error("关闭二次验证失败", zap.Int64("uid", claims.UserId), zap.Error(err))
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "二次验证已关闭",
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package web
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/service"
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	svc    service.WebhookService
+	logger logger.Logger
+}
+
+func NewWebhookHandler(svc service.WebhookService, logger logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+func (h *WebhookHandler) RegisterRoutes(ug *gin.RouterGroup) {
+	ug.POST("/create", h.Create)
+	ug.POST("/delete", h.Delete)
+	ug.GET("/list", h.List)
+	// 投递日志
+	ug.POST("/deliveries", h.Deliveries)
+	// 手动重新投递
+	ug.POST("/redeliver", h.Redeliver)
+}
+
+type WebhookVO struct {
+	Id  int64  `json:"id"`
+	Url string `json:"url"`
+	// 只在创建时返回
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events"`
+	Ctime  string   `json:"ctime"`
+}
+
+type WebhookDeliveryVO struct {
+	Id          int64  `json:"id"`
+	WebhookId   int64  `json:"webhook_id"`
+	Event       string `json:"event"`
+	Payload     string `json:"payload"`
+	Status      string `json:"status"`
+	Attempts    int    `json:"attempts"`
+	NextRetryAt string `json:"next_retry_at,omitempty"`
+	StatusCode  int    `json:"status_code"`
+	Error       string `json:"error,omitempty"`
+	Ctime       string `json:"ctime"`
+	Utime       string `json:"utime"`
+}
+
+func (h *WebhookHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		Url string `json:"url"`
+		// 不传则自动生成
+		Secret string   `json:"secret"`
+		Events []string `json:"events"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	w, err := h.svc.Create(ctx, domain.Webhook{
+		Uid:    userId,
+		Url:    req.Url,
+		Secret: req.Secret,
+		Events: slice.Map(req.Events, func(idx int, src string) domain.WebhookEventType {
+			return domain.WebhookEventType(src)
+		}),
+	})
+	switch {
+	case errors.Is(err, service.ErrWebhookUrlInvalid), errors.Is(err, service.ErrWebhookEventInvalid),
+		errors.Is(err, service.ErrWebhookEventForbidden):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  err.Error(),
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("创建 webhook 失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+	vo := h.toVO(w)
+	vo.Secret = w.Secret
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "创建 webhook 成功",
+		Data: vo,
+	})
+}
+
+func (h *WebhookHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	err := h.svc.Delete(ctx, userId, req.Id)
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "webhook 不存在",
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("删除 webhook 失败",
+			logger.Int64("userId", userId),
+			logger.Int64("id", req.Id),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "删除 webhook 成功",
+	})
+}
+
+func (h *WebhookHandler) List(ctx *gin.Context) {
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	ws, err := h.svc.List(ctx, userId)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取 webhook 列表失败",
+			logger.Int64("userId", userId),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取 webhook 列表成功",
+		Data: slice.Map(ws, func(idx int, src domain.Webhook) WebhookVO {
+			return h.toVO(src)
+		}),
+	})
+}
+
+func (h *WebhookHandler) Deliveries(ctx *gin.Context) {
+	type Req struct {
+		ArticlePage
+		WebhookId int64 `json:"webhook_id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 50 {
+		req.Limit = 10
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	ds, err := h.svc.Deliveries(ctx, userId, req.WebhookId, req.Offset, req.Limit)
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "webhook 不存在",
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("获取 webhook 投递日志失败",
+			logger.Int64("userId", userId),
+			logger.Int64("webhookId", req.WebhookId),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "获取投递日志成功",
+		Data: slice.Map(ds, func(idx int, src domain.WebhookDelivery) WebhookDeliveryVO {
+			return h.toDeliveryVO(src)
+		}),
+	})
+}
+
+func (h *WebhookHandler) Redeliver(ctx *gin.Context) {
+	type Req struct {
+		DeliveryId int64 `json:"delivery_id"`
+	}
+	var req Req
+	if err := ctx.BindJSON(&req); err != nil {
+		return
+	}
+
+	userId := ctx.MustGet("claims").(*myjwt.UserClaims).UserId
+	d, err := h.svc.Redeliver(ctx, userId, req.DeliveryId)
+	switch {
+	case errors.Is(err, service.ErrWebhookDeliveryNotFound), errors.Is(err, service.ErrWebhookNotFound):
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "投递记录不存在",
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.logger.Error("重新投递 webhook 失败",
+			logger.Int64("userId", userId),
+			logger.Int64("deliveryId", req.DeliveryId),
+			logger.Error(err),
+		)
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg:  "重新投递成功",
+		Data: h.toDeliveryVO(d),
+	})
+}
+
+func (h *WebhookHandler) toVO(w domain.Webhook) WebhookVO {
+	return WebhookVO{
+		Id:  w.Id,
+		Url: w.Url,
+		Events: slice.Map(w.Events, func(idx int, src domain.WebhookEventType) string {
+			return string(src)
+		}),
+		Ctime: w.Ctime.Format(time.DateTime),
+	}
+}
+
+func (h *WebhookHandler) toDeliveryVO(d domain.WebhookDelivery) WebhookDeliveryVO {
+	vo := WebhookDeliveryVO{
+		Id:         d.Id,
+		WebhookId:  d.WebhookId,
+		Event:      string(d.Event),
+		Payload:    d.Payload,
+		Attempts:   d.Attempts,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Ctime:      d.Ctime.Format(time.DateTime),
+		Utime:      d.Utime.Format(time.DateTime),
+	}
+	switch d.Status {
+	case domain.WebhookDeliveryStatusPending:
+		vo.Status = "pending"
+		vo.NextRetryAt = d.NextRetryAt.Format(time.DateTime)
+	case domain.WebhookDeliveryStatusSucceeded:
+		vo.Status = "succeeded"
+	case domain.WebhookDeliveryStatusFailed:
+		vo.Status = "failed"
+	default:
+		vo.Status = "unknown"
+	}
+	return vo
+}
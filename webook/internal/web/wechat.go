@@ -87,7 +87,7 @@ func (o *OAuth2WechatHandler) Callback(ctx *gin.Context) {
 		return
 	}
 
-	if err := o.SetLoginToken(ctx, user.Id); err != nil {
+	if err := o.SetLoginToken(ctx, user.Id, myjwt.LoginMethodWechat); err != nil {
 		ctx.JSON(http.StatusOK, Result{
 			Msg:  "系统错误",
 			Code: 5,
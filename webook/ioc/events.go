@@ -0,0 +1,79 @@
+package ioc
+
+import (
+	"Webook/webook/internal/domain"
+	myevents "Webook/webook/internal/events"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/events"
+	"Webook/webook/pkg/logger"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+)
+
+// InitEventBus 初始化事件总线，默认使用 Redis Streams，单机开发时可以配置成 memory
+func InitEventBus(client redis.Cmdable, l logger.Logger) events.Bus {
+	type EventsConfig struct {
+		// redis 或者 memory
+		Backend     string `yaml:"Backend"`
+		MaxAttempts int    `yaml:"MaxAttempts"`
+	}
+	var eventsConfig = EventsConfig{
+		Backend:     "redis",
+		MaxAttempts: 3,
+	}
+	err := viper.UnmarshalKey("events", &eventsConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	cfg := events.Config{
+		MaxAttempts:  eventsConfig.MaxAttempts,
+		RetryBackoff: time.Millisecond * 200,
+	}
+	if eventsConfig.Backend == "memory" {
+		return events.NewMemoryBus(cfg)
+	}
+	// 消费者名称在消费者组内要唯一
+	return events.NewRedisBus(client, nodeName(), cfg, l)
+}
+
+// nodeName 本进程的标识，主机名加进程号
+func nodeName() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+func InitArticleEventProducer(bus events.Bus) events.Producer[domain.ArticleEvent] {
+	return events.NewJSONProducer[domain.ArticleEvent](bus, myevents.TopicArticleEvents)
+}
+
+func InitInteractiveEventProducer(bus events.Bus) events.Producer[domain.InteractiveEvent] {
+	return events.NewJSONProducer[domain.InteractiveEvent](bus, myevents.TopicInteractiveEvents)
+}
+
+func InitReadEventProducer(bus events.Bus) events.Producer[domain.ReadEvent] {
+	return events.NewJSONProducer[domain.ReadEvent](bus, myevents.TopicReadEvents)
+}
+
+func InitNotificationEventProducer(bus events.Bus) events.Producer[domain.NotificationEvent] {
+	return events.NewJSONProducer[domain.NotificationEvent](bus, myevents.TopicNotificationEvents)
+}
+
+// InitConsumers 所有的后台消费者，由 main 启动
+func InitConsumers(bus events.Bus, interSvc service.InteractiveService,
+	historySvc service.ReadHistoryService, rankSvc service.RankingService,
+	statsSvc service.AuthorStatsService, notificationSvc service.NotificationService,
+	searchSvc service.SearchService, l logger.Logger) []events.Consumer {
+	consumers := []events.Consumer{
+		myevents.NewReadCntConsumer(bus, interSvc, historySvc, rankSvc, statsSvc, l),
+		myevents.NewReadHistoryConsumer(bus, historySvc, l),
+		myevents.NewNotificationConsumer(bus, notificationSvc, l),
+		myevents.NewSearchIndexConsumer(bus, searchSvc, searchIndexGroup(), l),
+	}
+	consumers = append(consumers, myevents.NewRankingConsumers(bus, rankSvc, l)...)
+	return append(consumers, myevents.NewAuthorStatsConsumers(bus, statsSvc, l)...)
+}
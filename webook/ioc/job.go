@@ -2,21 +2,53 @@ package ioc
 
 import (
 	"Webook/webook/internal/job"
+	"Webook/webook/internal/repository"
 	"Webook/webook/internal/service"
 	"Webook/webook/pkg/logger"
-	"github.com/robfig/cron/v3"
+	"context"
 	"time"
 )
 
-func InitRankingJob(svc service.RankingService) *job.RankingJob {
-	return job.NewRankingJob(svc, time.Second*30)
+func InitScheduledPublishJob(svc service.ArticleService) *job.ScheduledPublishJob {
+	return job.NewScheduledPublishJob(svc, time.Second*30)
 }
-func InitJobs(logger logger.Logger, rankJob *job.RankingJob) *cron.Cron {
-	cronJobBuilder := job.NewCronJobBuilder(logger)
-	cornn := cron.New(cron.WithSeconds())
-	_, err := cornn.AddJob("@every 1m", cronJobBuilder.Build(rankJob))
+
+func InitWebhookRetryJob(svc service.WebhookService) *job.WebhookRetryJob {
+	return job.NewWebhookRetryJob(svc, time.Second*30)
+}
+
+// InitCronJobExecutionCleanJob 执行记录保留 7 天
+func InitCronJobExecutionCleanJob(svc service.CronJobService) *job.CronJobExecutionCleanJob {
+	return job.NewCronJobExecutionCleanJob(svc, time.Hour*24*7, time.Minute)
+}
+
+func InitCronJobService(repo repository.CronJobRepository, l logger.Logger) service.CronJobService {
+	return service.NewCronJobService(repo, nodeName(), l)
+}
+
+// InitScheduler 所有定时任务都通过调度器注册，由抢占到任务的节点执行
+func InitScheduler(svc service.CronJobService, l logger.Logger, rankJobs []RankingJob, publishJob *job.ScheduledPublishJob,
+	webhookJob *job.WebhookRetryJob, cleanJob *job.CronJobExecutionCleanJob) *job.Scheduler {
+	scheduler := job.NewScheduler(svc, l)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	for _, rankJob := range rankJobs {
+		err := scheduler.RegisterJob(ctx, rankJob.Job, rankJob.Spec)
+		if err != nil {
+			panic(err)
+		}
+	}
+	err := scheduler.RegisterJob(ctx, publishJob, "@every 1m")
+	if err != nil {
+		panic(err)
+	}
+	err = scheduler.RegisterJob(ctx, webhookJob, "@every 10s")
+	if err != nil {
+		panic(err)
+	}
+	err = scheduler.RegisterJob(ctx, cleanJob, "@every 1h")
 	if err != nil {
 		panic(err)
 	}
-	return cornn
+	return scheduler
 }
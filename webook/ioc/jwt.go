@@ -0,0 +1,121 @@
+package ioc
+
+import (
+	myjwt "Webook/webook/internal/web/jwt"
+	"Webook/webook/pkg/logger"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+// InitJWTKeys 从配置中加载 access token 和 refresh token 的密钥，例如：
+//
+//	jwt:
+//	  Access:
+//	    SigningKid: "2026-10"
+//	    Keys:
+//	      - Kid: "2026-10"
+//	        Alg: "ES256"
+//	        PrivateKeyFile: "/etc/webook/jwt/access-2026-10.pem"
+//	      # 轮换中的旧密钥，只保留公钥用来验证
+//	      - Kid: "2026-07"
+//	        Alg: "RS256"
+//	        PublicKeyFile: "/etc/webook/jwt/access-2026-07.pub.pem"
+//
+// 没有配置密钥时返回错误。本地开发可以设置 jwt.AllowEphemeralKeys: true 临时生成密钥：
+// 重启后已经签发的 token 全部失效，多个实例之间也不能互相验证
+func InitJWTKeys(l logger.Logger) (myjwt.Keys, error) {
+	allowEphemeral := viper.GetBool("jwt.AllowEphemeralKeys")
+	access, err := initKeyManager("jwt.Access", allowEphemeral, l)
+	if err != nil {
+		return myjwt.Keys{}, err
+	}
+	refresh, err := initKeyManager("jwt.Refresh", allowEphemeral, l)
+	if err != nil {
+		return myjwt.Keys{}, err
+	}
+	return myjwt.Keys{
+		Access:  access,
+		Refresh: refresh,
+	}, nil
+}
+
+func initKeyManager(key string, allowEphemeral bool, l logger.Logger) (*myjwt.KeyManager, error) {
+	type KeyConfig struct {
+		Kid string `yaml:"Kid"`
+		Alg string `yaml:"Alg"`
+		// PEM 可以直接写在配置里，也可以指定文件
+		PrivateKey     string `yaml:"PrivateKey"`
+		PublicKey      string `yaml:"PublicKey"`
+		PrivateKeyFile string `yaml:"PrivateKeyFile"`
+		PublicKeyFile  string `yaml:"PublicKeyFile"`
+	}
+	type KeysConfig struct {
+		SigningKid string      `yaml:"SigningKid"`
+		Keys       []KeyConfig `yaml:"Keys"`
+	}
+	var cfg KeysConfig
+	err := viper.UnmarshalKey(key, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Keys) == 0 {
+		if !allowEphemeral {
+			return nil, fmt.Errorf("没有配置 JWT 密钥 %s", key)
+		}
+		l.Warn("没有配置 JWT 密钥，使用临时生成的密钥", logger.String("key", key))
+		return ephemeralKeyManager()
+	}
+
+	keys := make([]myjwt.Key, 0, len(cfg.Keys))
+	for _, kc := range cfg.Keys {
+		if kc.PrivateKeyFile != "" {
+			kc.PrivateKey, err = readKeyFile(kc.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if kc.PublicKeyFile != "" {
+			kc.PublicKey, err = readKeyFile(kc.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		k, err := myjwt.ParseKey(myjwt.KeyConfig{
+			Kid:        kc.Kid,
+			Alg:        kc.Alg,
+			PrivateKey: kc.PrivateKey,
+			PublicKey:  kc.PublicKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return myjwt.NewKeyManager(keys, cfg.SigningKid)
+}
+
+func readKeyFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+func ephemeralKeyManager() (*myjwt.KeyManager, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	kid := uuid.New().String()
+	return myjwt.NewKeyManager([]myjwt.Key{{
+		Kid:        kid,
+		Method:     jwt.SigningMethodEdDSA,
+		PrivateKey: priv,
+		PublicKey:  pub,
+	}}, kid)
+}
@@ -0,0 +1,41 @@
+package ioc
+
+import (
+	"Webook/webook/internal/service/mail"
+	"Webook/webook/internal/service/mail/memory"
+	"Webook/webook/internal/service/mail/smtp"
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// InitMailService 按配置选择邮件服务，例如：
+//
+//	mail:
+//	  Provider: "smtp"
+//	  SMTP:
+//	    Host: "smtp.example.com"
+//	    Port: 465
+//	    Username: "no-reply@example.com"
+//	    Password: "..."
+//	    From: "Webook <no-reply@example.com>"
+//
+// Provider 为 memory 时不真正发送，只能用于本地开发和测试
+func InitMailService() (mail.Service, error) {
+	type Config struct {
+		Provider string      `yaml:"Provider"`
+		SMTP     smtp.Config `yaml:"SMTP"`
+	}
+	var cfg Config
+	if err := viper.UnmarshalKey("mail", &cfg); err != nil {
+		return nil, err
+	}
+	switch cfg.Provider {
+	case "smtp":
+		return smtp.NewService(cfg.SMTP)
+	case "memory":
+		return memory.NewService(), nil
+	default:
+		return nil, fmt.Errorf("不支持的邮件服务 %q", cfg.Provider)
+	}
+}
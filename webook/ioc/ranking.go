@@ -0,0 +1,140 @@
+package ioc
+
+import (
+	"Webook/webook/internal/domain"
+	"Webook/webook/internal/job"
+	"Webook/webook/internal/service"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// RankingBoardConfig 榜单配置，Tag、Category 为空表示全部文章，权重、重力、半衰期为 0 时用默认值。
+// Mode 为 incremental 时是增量榜单：Schedule 是衰减周期，RebuildSchedule 是全量重建周期
+type RankingBoardConfig struct {
+	Name     string        `yaml:"Name"`
+	Window   time.Duration `yaml:"Window"`
+	Tag      string        `yaml:"Tag"`
+	Category string        `yaml:"Category"`
+	N        int           `yaml:"N"`
+	Schedule string        `yaml:"Schedule"`
+
+	Mode            string        `yaml:"Mode"`
+	HalfLife        time.Duration `yaml:"HalfLife"`
+	RebuildSchedule string        `yaml:"RebuildSchedule"`
+
+	ReadWeight    float64 `yaml:"ReadWeight"`
+	LikeWeight    float64 `yaml:"LikeWeight"`
+	CollectWeight float64 `yaml:"CollectWeight"`
+	CommentWeight float64 `yaml:"CommentWeight"`
+	Gravity       float64 `yaml:"Gravity"`
+}
+
+const rankingModeIncremental = "incremental"
+
+// RankingJob 榜单的定时任务和它的调度规则
+type RankingJob struct {
+	Spec string
+	Job  job.Job
+}
+
+// InitRankingBoardConfigs 读取 ranking.Boards，没有配置时使用日榜、周榜、总榜
+func InitRankingBoardConfigs() []RankingBoardConfig {
+	type RankingConfig struct {
+		Boards []RankingBoardConfig `yaml:"Boards"`
+	}
+	var rankingConfig RankingConfig
+	err := viper.UnmarshalKey("ranking", &rankingConfig)
+	if err != nil {
+		panic(err)
+	}
+	if len(rankingConfig.Boards) > 0 {
+		return rankingConfig.Boards
+	}
+	return []RankingBoardConfig{
+		{Name: "daily", Window: time.Hour * 24, Schedule: "@every 1m",
+			Mode: rankingModeIncremental, HalfLife: time.Hour * 12, RebuildSchedule: "@every 1h"},
+		{Name: "weekly", Window: time.Hour * 24 * 7, Schedule: "@every 5m",
+			Mode: rankingModeIncremental, HalfLife: time.Hour * 72, RebuildSchedule: "@every 6h"},
+		{Name: "all_time", Schedule: "@every 10m"},
+	}
+}
+
+func InitRankingBoards(cfgs []RankingBoardConfig) []service.RankingBoard {
+	boards := make([]service.RankingBoard, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		n := cfg.N
+		if n <= 0 {
+			n = 100
+		}
+		boards = append(boards, service.RankingBoard{
+			Name:   cfg.Name,
+			Window: cfg.Window,
+			Filter: domain.ArticleFilter{
+				Tag:      cfg.Tag,
+				Category: cfg.Category,
+			},
+			N:        n,
+			Strategy: rankingStrategy(cfg),
+		})
+	}
+	return boards
+}
+
+func rankingStrategy(cfg RankingBoardConfig) service.RankingStrategy {
+	if cfg.Mode == rankingModeIncremental {
+		strategy := service.NewDecayStrategy()
+		setPositive(&strategy.ReadWeight, cfg.ReadWeight)
+		setPositive(&strategy.LikeWeight, cfg.LikeWeight)
+		setPositive(&strategy.CollectWeight, cfg.CollectWeight)
+		setPositive(&strategy.CommentWeight, cfg.CommentWeight)
+		if cfg.HalfLife > 0 {
+			strategy.HalfLife = cfg.HalfLife
+		}
+		return strategy
+	}
+	strategy := service.NewHackerNewsStrategy()
+	setPositive(&strategy.ReadWeight, cfg.ReadWeight)
+	setPositive(&strategy.LikeWeight, cfg.LikeWeight)
+	setPositive(&strategy.CollectWeight, cfg.CollectWeight)
+	setPositive(&strategy.CommentWeight, cfg.CommentWeight)
+	setPositive(&strategy.Gravity, cfg.Gravity)
+	return strategy
+}
+
+func setPositive(dst *float64, val float64) {
+	if val > 0 {
+		*dst = val
+	}
+}
+
+// InitRankingJobs 每个榜单按各自的周期刷新，增量榜单刷新时只做衰减，另外定期全量重建
+func InitRankingJobs(svc service.RankingService, cfgs []RankingBoardConfig) []RankingJob {
+	jobs := make([]RankingJob, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		spec := cfg.Schedule
+		if spec == "" {
+			spec = "@every 1m"
+		}
+		if cfg.Mode != rankingModeIncremental {
+			jobs = append(jobs, RankingJob{
+				Spec: spec,
+				Job:  job.NewRankingJob(svc, cfg.Name, time.Second*30),
+			})
+			continue
+		}
+
+		rebuildSpec := cfg.RebuildSchedule
+		if rebuildSpec == "" {
+			rebuildSpec = "@every 1h"
+		}
+		jobs = append(jobs, RankingJob{
+			Spec: spec,
+			Job:  job.NewRankingDecayJob(svc, cfg.Name, time.Second*10),
+		}, RankingJob{
+			Spec: rebuildSpec,
+			Job:  job.NewRankingRebuildJob(svc, cfg.Name, time.Minute),
+		})
+	}
+	return jobs
+}
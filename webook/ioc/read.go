@@ -0,0 +1,25 @@
+package ioc
+
+import (
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/repository/article"
+	"Webook/webook/internal/service"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// InitReadHistoryService 阅读历史，去重窗口可以配置，配置成 0 时不去重
+func InitReadHistoryService(repo repository.ReadHistoryRepository, artRepo article.ArticleRepository) service.ReadHistoryService {
+	type ReadConfig struct {
+		DedupWindow time.Duration `yaml:"DedupWindow"`
+	}
+	var readConfig = ReadConfig{
+		DedupWindow: time.Minute * 30,
+	}
+	err := viper.UnmarshalKey("read", &readConfig)
+	if err != nil {
+		panic(err)
+	}
+	return service.NewReadHistoryService(repo, artRepo, readConfig.DedupWindow)
+}
@@ -0,0 +1,53 @@
+package ioc
+
+import (
+	"Webook/webook/pkg/search"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// InitSearchIndex 初始化嵌入式的全文检索索引
+func InitSearchIndex() *search.Index {
+	type SearchConfig struct {
+		// 索引文件所在目录
+		Dir string `yaml:"Dir"`
+		// 摘要的长度（字符数）
+		SnippetLen int `yaml:"SnippetLen"`
+		// 日志超过这个大小（MB）后自动压缩
+		CompactSizeMB int64 `yaml:"CompactSizeMB"`
+	}
+	var searchConfig = SearchConfig{
+		Dir:           "./data/search",
+		SnippetLen:    120,
+		CompactSizeMB: 64,
+	}
+	err := viper.UnmarshalKey("search", &searchConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	index, err := search.Open(searchConfig.Dir, search.Options{
+		// 标题命中比正文命中更相关
+		Boosts: map[string]float64{
+			"title": 3,
+		},
+		SnippetLen:  searchConfig.SnippetLen,
+		CompactSize: searchConfig.CompactSizeMB << 20,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return index
+}
+
+// searchIndexGroup 本实例更新搜索索引的消费者组。索引存在本地磁盘上，每个实例一个组，
+// 组名要在重启后保持不变，重启后才能接着消费停机期间的文章事件。
+// 默认用主机名，同一台机器上部署多个实例时用 search.Node 区分
+func searchIndexGroup() string {
+	node := viper.GetString("search.Node")
+	if node == "" {
+		node, _ = os.Hostname()
+	}
+	return "search:" + node
+}
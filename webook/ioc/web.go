@@ -25,7 +25,7 @@ func newCORSConfig() cors.Config {
 	return cors.Config{
 		AllowHeaders: []string{"Content-Type", "Authorization"},
 		// 暴露给前端，前端可以从 Header 中获取
-		ExposeHeaders: []string{"x-jwt-token", "x-refresh-token"},
+		ExposeHeaders: []string{"x-jwt-token", "x-refresh-token", "x-2fa-token"},
 		// 允许跨域请求携带 cookie
 		AllowCredentials: true,
 		AllowOriginFunc: func(origin string) bool {
@@ -58,28 +58,81 @@ func InitGinMiddleware(redisClient redis.Cmdable, jwthandler myjwt.Handler, l lo
 			IgnorePaths("/users/login", "/users/signup").
 			IgnorePaths("/users/login_sms/code/send", "/users/login_sms").
 			IgnorePaths("/oauth2/wechat/authurl", "/oauth2/wechat/callback").
-			IgnorePaths("/users/refresh_token").
+			IgnorePaths("/users/refresh_token", "/users/login/2fa").
+			IgnorePaths("/users/password/forgot", "/users/password/reset").
+			IgnorePaths("/.well-known/jwks.json").
 			Build(),
 	}
 }
 
+// InitAdminMiddleware 管理员在配置中指定，默认没有管理员
+func InitAdminMiddleware() *middleware.AdminMiddlewareBuilder {
+	return middleware.NewAdminMiddlewareBuilder(adminUserIds()...)
+}
+
+func adminUserIds() []int64 {
+	type AdminConfig struct {
+		UserIds []int64 `yaml:"UserIds"`
+	}
+	var cfg AdminConfig
+	err := viper.UnmarshalKey("admin", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg.UserIds
+}
+
 // InitWebServer 初始化 Web 服务器
 func InitWebServer(middlewares []gin.HandlerFunc,
 	userHdl *web.UserHandler, wechatHdl *web.OAuth2WechatHandler,
 	articleHdl *web.ArticleHandler, articleReaderHdl *web.ArticleReaderHandler,
+	commentHdl *web.CommentHandler, searchHdl *web.SearchHandler,
+	collectionHdl *web.CollectionHandler, followHdl *web.FollowHandler,
+	feedHdl *web.FeedHandler, notificationHdl *web.NotificationHandler,
+	webhookHdl *web.WebhookHandler, historyHdl *web.ReadHistoryHandler,
+	adminMdl *middleware.AdminMiddlewareBuilder, jobHdl *web.JobHandler,
+	authorStatsHdl *web.AuthorStatsHandler, jwksHdl *web.JWKSHandler,
 ) *gin.Engine {
 	server := gin.Default()
 
 	// 使用中间件
 	server.Use(middlewares...)
 
+	// 验证 access token 的公钥
+	jwksHdl.RegisterRoutes(server)
+
 	// 用户模块
 	userHdl.RegisterRoutes(server.Group("/users"))
 	wechatHdl.RegisterRoutes(server.Group("/oauth2/wechat"))
+	// 关注
+	followHdl.RegisterRoutes(server.Group("/follow"))
+	// 关注的作者的动态流
+	feedHdl.RegisterRoutes(server.Group("/feed"))
+	// 站内通知
+	notificationHdl.RegisterRoutes(server.Group("/notifications"))
+	// 用户注册的 webhook
+	webhookHdl.RegisterRoutes(server.Group("/webhooks"))
+	// 阅读历史
+	historyHdl.RegisterRoutes(server.Group("/history"))
+	// 作者数据看板和作者榜单
+	authorStatsHdl.RegisterRoutes(server.Group("/authors"))
 
 	// 文章模块
 	articleHdl.RegisterRoutes(server.Group("/articles"))
 	// 线上库文章
 	articleReaderHdl.RegisterRoutes(server.Group("articles/pub"))
+	// 线上文章搜索
+	searchHdl.RegisterRoutes(server.Group("articles/pub"))
+
+	// 收藏夹
+	collectionHdl.RegisterRoutes(server.Group("/collections"))
+
+	// 评论模块
+	commentHdl.RegisterRoutes(server.Group("/comments"))
+
+	// 管理接口
+	admin := server.Group("/admin", adminMdl.Build())
+	// 定时任务
+	jobHdl.RegisterRoutes(admin.Group("/jobs"))
 	return server
 }
@@ -0,0 +1,20 @@
+package ioc
+
+import (
+	"Webook/webook/internal/repository"
+	"Webook/webook/internal/service"
+	"Webook/webook/pkg/logger"
+	"Webook/webook/pkg/webhook"
+	"net/http"
+	"time"
+)
+
+// InitWebhookClient 发送 webhook 用的 HTTP 客户端，超时不能超过投递的租约
+func InitWebhookClient() *http.Client {
+	return webhook.NewClient(time.Second * 5)
+}
+
+// InitWebhookService 全局事件只投递给管理员注册的 webhook
+func InitWebhookService(repo repository.WebhookRepository, client *http.Client, l logger.Logger) service.WebhookService {
+	return service.NewWebhookService(repo, client, adminUserIds(), l)
+}
@@ -1,6 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/fsnotify/fsnotify"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -15,15 +22,27 @@ func main() {
 	InitViperWithFlags()
 	InitLogger()
 
-	app := InitWebServer()
+	app, err := InitWebServer()
+	if err != nil {
+		panic(err)
+	}
 	server := app.server
-	cronJob := app.cron
+
+	// 重建搜索索引，在接收请求和消费事件之前完成
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	if err := app.searchSvc.RebuildIndex(ctx); err != nil {
+		// 索引还能用，只是可能缺少一部分文章
+		zap.L().Error("重建搜索索引失败", zap.Error(err))
+	}
+	cancel()
+
+	// 启动事件消费者
+	for _, c := range app.consumers {
+		c.Start(context.Background())
+	}
 
 	// 启动定时任务
-	cronJob.Start()
-	defer func() {
-		<-cronJob.Stop().Done()
-	}()
+	app.scheduler.Start(context.Background())
 
 	// 测试
 	server.GET("/hello", func(ctx *gin.Context) {
@@ -31,7 +50,31 @@ func main() {
 	})
 
 	// listen and serve on 8080
-	_ = server.Run(":8080")
+	srv := &http.Server{Addr: ":8080", Handler: server}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+
+	// 优雅退出：先停止接收请求，再停止消费者（会写完缓冲中的数据），最后停止抢占任务并等待正在执行的任务结束
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-sigCtx.Done()
+	stop()
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		zap.L().Error("关闭 HTTP 服务失败", zap.Error(err))
+	}
+	for _, c := range app.consumers {
+		if err := c.Stop(ctx); err != nil {
+			zap.L().Error("停止消费者失败", zap.Error(err))
+		}
+	}
+	if err := app.scheduler.Stop(ctx); err != nil {
+		zap.L().Error("停止定时任务调度器失败", zap.Error(err))
+	}
 }
 
 func InitViper() {
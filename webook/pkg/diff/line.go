@@ -0,0 +1,159 @@
+package diff
+
+import "strings"
+
+type Op uint8
+
+const (
+	OpEqual Op = iota
+	OpInsert
+	OpDelete
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpInsert:
+		return "insert"
+	case OpDelete:
+		return "delete"
+	default:
+		return "equal"
+	}
+}
+
+// Line 行级 diff 的一行结果
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines 按行比较 a 和 b，返回把 a 变成 b 的最短编辑脚本（Myers 算法）
+func Lines(a, b string) []Line {
+	return diff(splitLines(a), splitLines(b))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func diff(a, b []string) []Line {
+	// 先去掉公共前缀和后缀，减少 Myers 的搜索空间
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	res := make([]Line, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		res = append(res, Line{Op: OpEqual, Text: text})
+	}
+	res = append(res, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		res = append(res, Line{Op: OpEqual, Text: text})
+	}
+	return res
+}
+
+// maxEditDistance Myers 回溯需要保存每一步的 V 数组，内存是编辑距离的平方
+// 改动超过这么多行时不再找最短编辑脚本，直接整块替换
+const maxEditDistance = 1000
+
+// myers 记录每一步的 V 数组，找到终点后再回溯出编辑路径
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	maxD := min(n+m, maxEditDistance)
+	offset := maxD + 1
+	v := make([]int, 2*offset+1)
+	trace := make([][]int, 0)
+
+	for d := 0; d <= maxD; d++ {
+		// 第 d 步只会用到 [-d-1, d+1] 范围内的 V
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				// 向下走：插入 b 中的一行
+				x = v[offset+k+1]
+			} else {
+				// 向右走：删除 a 中的一行
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return replace(a, b)
+}
+
+// replace 整块替换：先删掉 a 的所有行，再插入 b 的所有行
+func replace(a, b []string) []Line {
+	res := make([]Line, 0, len(a)+len(b))
+	for _, text := range a {
+		res = append(res, Line{Op: OpDelete, Text: text})
+	}
+	for _, text := range b {
+		res = append(res, Line{Op: OpInsert, Text: text})
+	}
+	return res
+}
+
+func backtrack(a, b []string, trace [][]int) []Line {
+	x, y := len(a), len(b)
+	res := make([]Line, 0, len(a)+len(b))
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		offset := d + 1
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			res = append(res, Line{Op: OpEqual, Text: a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				res = append(res, Line{Op: OpInsert, Text: b[y]})
+			} else {
+				x--
+				res = append(res, Line{Op: OpDelete, Text: a[x]})
+			}
+		}
+	}
+
+	// 回溯得到的是逆序结果
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
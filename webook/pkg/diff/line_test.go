@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+		want []Line
+	}{
+		{
+			name: "内容相同",
+			a:    "a\nb",
+			b:    "a\nb\n",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+			},
+		},
+		{
+			name: "从空内容新增",
+			a:    "",
+			b:    "a\nb",
+			want: []Line{
+				{Op: OpInsert, Text: "a"},
+				{Op: OpInsert, Text: "b"},
+			},
+		},
+		{
+			name: "删除全部内容",
+			a:    "a\nb",
+			b:    "",
+			want: []Line{
+				{Op: OpDelete, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+			},
+		},
+		{
+			name: "修改中间一行",
+			a:    "title\nold line\nfooter",
+			b:    "title\nnew line\nfooter",
+			want: []Line{
+				{Op: OpEqual, Text: "title"},
+				{Op: OpDelete, Text: "old line"},
+				{Op: OpInsert, Text: "new line"},
+				{Op: OpEqual, Text: "footer"},
+			},
+		},
+		{
+			name: "交错的增删",
+			a:    "a\nb\nc\na\nb\nb\na",
+			b:    "c\nb\na\nb\na\nc",
+			want: []Line{
+				{Op: OpDelete, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpEqual, Text: "c"},
+				{Op: OpInsert, Text: "b"},
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpEqual, Text: "a"},
+				{Op: OpInsert, Text: "c"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Lines(tc.a, tc.b))
+		})
+	}
+}
+
+func TestLines_TooManyEdits(t *testing.T) {
+	// 改动超过 maxEditDistance 时整块替换
+	var a, b []string
+	for i := 0; i < maxEditDistance; i++ {
+		a = append(a, fmt.Sprintf("a%d", i))
+		b = append(b, fmt.Sprintf("b%d", i))
+	}
+	res := Lines("head\n"+strings.Join(a, "\n"), "head\n"+strings.Join(b, "\n"))
+	require.Len(t, res, 2*maxEditDistance+1)
+	assert.Equal(t, Line{Op: OpEqual, Text: "head"}, res[0])
+	assert.Equal(t, Line{Op: OpDelete, Text: "a0"}, res[1])
+	assert.Equal(t, Line{Op: OpInsert, Text: "b0"}, res[maxEditDistance+1])
+}
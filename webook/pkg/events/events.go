@@ -0,0 +1,97 @@
+// Package events 事件总线：发送方把事件发到 topic，消费者以消费者组的方式订阅。
+//
+// 同一个消费者组内，每条消息只会交给其中一个消费者；不同的消费者组各自消费全部消息。
+// 投递语义是至少一次：处理失败会按退避重试，超过最大次数后投递到死信 topic，
+// 所以处理逻辑需要是幂等的。
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+var ErrBusClosed = errors.New("事件总线已关闭")
+
+// 死信消息的 Header
+const (
+	HeaderGroup    = "dead_letter_group"
+	HeaderError    = "dead_letter_error"
+	HeaderOriginId = "dead_letter_origin_id"
+	HeaderAttempts = "dead_letter_attempts"
+)
+
+// Message 总线上的一条消息
+type Message struct {
+	// 由总线生成，同一个 topic 内唯一
+	Id     string
+	Topic  string
+	Header map[string]string
+	Value  []byte
+}
+
+// Handler 处理消息，返回 error 表示处理失败，消息会被重新投递
+type Handler func(ctx context.Context, msg Message) error
+
+type Bus interface {
+	Publish(ctx context.Context, topic string, msg Message) error
+	// Subscribe 以消费者组 group 订阅 topic，阻塞到 ctx 取消为止。
+	// 消费者组第一次订阅时从 topic 最早的消息开始消费
+	Subscribe(ctx context.Context, topic string, group string, handler Handler) error
+}
+
+type Config struct {
+	// 处理失败时最多尝试的次数，超过后投递到死信 topic
+	MaxAttempts int
+	// 第 n 次失败后等待 RetryBackoff * n 再重试
+	RetryBackoff time.Duration
+}
+
+func (c Config) withDefault() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = time.Millisecond * 100
+	}
+	return c
+}
+
+// DeadLetterTopic topic 对应的死信 topic
+func DeadLetterTopic(topic string) string {
+	return topic + ".dead_letter"
+}
+
+// process 处理一条消息：失败后退避重试，超过最大次数后投递到死信 topic。
+// 返回 nil 表示消息已经处理完（成功或者进了死信），可以确认
+func process(ctx context.Context, bus Bus, cfg Config, group string, msg Message, handler Handler) error {
+	var err error
+	for attempt := 1; attempt <= cfg.MaxAttempts; attempt++ {
+		if err = handler(ctx, msg); err == nil {
+			return nil
+		}
+		if attempt == cfg.MaxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			// 没有处理完，不确认，下次重新投递
+			return ctx.Err()
+		case <-time.After(cfg.RetryBackoff * time.Duration(attempt)):
+		}
+	}
+
+	header := make(map[string]string, len(msg.Header)+4)
+	for k, v := range msg.Header {
+		header[k] = v
+	}
+	header[HeaderGroup] = group
+	header[HeaderError] = err.Error()
+	header[HeaderOriginId] = msg.Id
+	header[HeaderAttempts] = strconv.Itoa(cfg.MaxAttempts)
+	return bus.Publish(ctx, DeadLetterTopic(msg.Topic), Message{
+		Header: header,
+		Value:  msg.Value,
+	})
+}
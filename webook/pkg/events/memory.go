@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// MemoryBus 进程内的事件总线，消息保存在内存中且不会清理，只用于测试和单机开发
+type MemoryBus struct {
+	cfg Config
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// topic => 全部消息
+	topics map[string][]Message
+	// topic => 消费者组 => 下一条要消费的消息的下标
+	offsets map[string]map[string]int
+}
+
+func NewMemoryBus(cfg Config) *MemoryBus {
+	b := &MemoryBus{
+		cfg:     cfg.withDefault(),
+		topics:  make(map[string][]Message),
+		offsets: make(map[string]map[string]int),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, topic string, msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	msg.Topic = topic
+	msg.Id = strconv.Itoa(len(b.topics[topic]) + 1)
+	b.topics[topic] = append(b.topics[topic], msg)
+	b.cond.Broadcast()
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context, topic string, group string, handler Handler) error {
+	// ctx 取消时唤醒等待中的消费者
+	stop := context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.cond.Broadcast()
+	})
+	defer stop()
+
+	for {
+		msg, ok := b.next(ctx, topic, group)
+		if !ok {
+			return ctx.Err()
+		}
+		// 和 Redis 的实现不同，这里取出即确认，处理到一半进程退出的消息会丢失
+		if err := process(ctx, b, b.cfg, group, msg, handler); err != nil {
+			return err
+		}
+	}
+}
+
+// next 取出消费者组的下一条消息，没有消息时阻塞，ctx 取消时返回 false
+func (b *MemoryBus) next(ctx context.Context, topic string, group string) (Message, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	groups, ok := b.offsets[topic]
+	if !ok {
+		groups = make(map[string]int)
+		b.offsets[topic] = groups
+	}
+	for {
+		if ctx.Err() != nil {
+			return Message{}, false
+		}
+		offset := groups[group]
+		if offset < len(b.topics[topic]) {
+			groups[group] = offset + 1
+			return b.topics[topic][offset], true
+		}
+		b.cond.Wait()
+	}
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	Id int64 `json:"id"`
+}
+
+func TestMemoryBus_Groups(t *testing.T) {
+	bus := NewMemoryBus(Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	// 消费者组 => 收到的事件
+	got := make(map[string][]int64)
+	var wg sync.WaitGroup
+	wg.Add(20)
+	consume := func(group string) Consumer {
+		return NewJSONConsumer[testEvent](bus, "topic", group, func(ctx context.Context, evt testEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got[group] = append(got[group], evt.Id)
+			wg.Done()
+			return nil
+		}, nil)
+	}
+	// a 组两个消费者分摊消息，b 组一个消费者消费全部消息
+	consume("a").Start(ctx)
+	consume("a").Start(ctx)
+	consume("b").Start(ctx)
+
+	producer := NewJSONProducer[testEvent](bus, "topic")
+	for i := int64(1); i <= 10; i++ {
+		require.NoError(t, producer.Produce(ctx, testEvent{Id: i}))
+	}
+	wg.Wait()
+
+	want := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.ElementsMatch(t, want, got["a"])
+	assert.Equal(t, want, got["b"])
+}
+
+func TestMemoryBus_Retry(t *testing.T) {
+	testCases := []struct {
+		name string
+		// 前 failures 次处理失败
+		failures int
+
+		wantCalls      int
+		wantDeadLetter bool
+	}{
+		{
+			name:      "重试后成功",
+			failures:  2,
+			wantCalls: 3,
+		},
+		{
+			name:           "超过最大次数，进死信",
+			failures:       10,
+			wantCalls:      3,
+			wantDeadLetter: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bus := NewMemoryBus(Config{MaxAttempts: 3, RetryBackoff: time.Millisecond})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			calls := 0
+			done := make(chan struct{})
+			go func() {
+				_ = bus.Subscribe(ctx, "topic", "group", func(ctx context.Context, msg Message) error {
+					calls++
+					if calls <= tc.failures {
+						if calls == 3 {
+							close(done)
+						}
+						return errors.New("mock error")
+					}
+					close(done)
+					return nil
+				})
+			}()
+			require.NoError(t, bus.Publish(ctx, "topic", Message{Value: []byte("hello")}))
+			<-done
+
+			// 等死信写入
+			dead := make(chan Message, 1)
+			go func() {
+				_ = bus.Subscribe(ctx, DeadLetterTopic("topic"), "dead", func(ctx context.Context, msg Message) error {
+					dead <- msg
+					return nil
+				})
+			}()
+			select {
+			case msg := <-dead:
+				assert.True(t, tc.wantDeadLetter)
+				assert.Equal(t, "hello", string(msg.Value))
+				assert.Equal(t, "group", msg.Header[HeaderGroup])
+				assert.Equal(t, "mock error", msg.Header[HeaderError])
+				assert.Equal(t, "1", msg.Header[HeaderOriginId])
+			case <-time.After(time.Millisecond * 100):
+				assert.False(t, tc.wantDeadLetter)
+			}
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
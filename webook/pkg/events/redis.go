@@ -0,0 +1,205 @@
+package events
+
+import (
+	"Webook/webook/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisFieldValue     = "value"
+	redisFieldHeaderPfx = "h:"
+)
+
+// RedisBus 基于 Redis Streams 的事件总线：每个 topic 是一个 stream，消费者组就是 stream 的 consumer group。
+// 处理完成（成功或者进了死信）才 XACK，进程退出时没有确认的消息，
+// 会在空闲超过 claimIdle 后被同组的其他消费者通过 XAUTOCLAIM 认领
+type RedisBus struct {
+	client redis.Cmdable
+	cfg    Config
+	logger logger.Logger
+	// 消费者名称，同一个消费者组内唯一
+	consumer string
+
+	// stream 保留的大约长度
+	maxLen    int64
+	batchSize int64
+	// XREADGROUP 阻塞等待的时间
+	block time.Duration
+	// 消息空闲多久后可以被其他消费者认领
+	claimIdle time.Duration
+	// Redis 出错后等待多久再重试
+	errBackoff time.Duration
+	// 停止消费后，最多花多久处理完已经读到的这一批消息
+	drainTimeout time.Duration
+}
+
+func NewRedisBus(client redis.Cmdable, consumer string, cfg Config, l logger.Logger) *RedisBus {
+	return &RedisBus{
+		client:     client,
+		cfg:        cfg.withDefault(),
+		logger:     l,
+		consumer:   consumer,
+		maxLen:     100000,
+		batchSize:  10,
+		block:      time.Second * 2,
+		claimIdle:  time.Minute * 5,
+		errBackoff: time.Second,
+		// 不超过 main 里停止消费者的超时
+		drainTimeout: time.Second * 10,
+	}
+}
+
+func (b *RedisBus) Publish(ctx context.Context, topic string, msg Message) error {
+	values := make(map[string]any, len(msg.Header)+1)
+	values[redisFieldValue] = msg.Value
+	for k, v := range msg.Header {
+		values[redisFieldHeaderPfx+k] = v
+	}
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: topic,
+		MaxLen: b.maxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
+
+func (b *RedisBus) Subscribe(ctx context.Context, topic string, group string, handler Handler) error {
+	for {
+		err := b.client.XGroupCreateMkStream(ctx, topic, group, "0").Err()
+		// 消费者组已经存在
+		if err == nil || strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			break
+		}
+		b.logger.Error("创建消费者组失败",
+			logger.String("topic", topic),
+			logger.String("group", group),
+			logger.Error(err),
+		)
+		if !b.sleep(ctx, b.errBackoff) {
+			return ctx.Err()
+		}
+	}
+
+	claimStart := "0-0"
+	for ctx.Err() == nil {
+		// 先认领其他消费者处理到一半遗留下来的消息
+		msgs, next, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   topic,
+			Group:    group,
+			Consumer: b.consumer,
+			MinIdle:  b.claimIdle,
+			Start:    claimStart,
+			Count:    b.batchSize,
+		}).Result()
+		if err == nil {
+			claimStart = next
+			b.handle(ctx, topic, group, msgs, handler)
+		}
+
+		streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.consumer,
+			Streams:  []string{topic, ">"},
+			Count:    b.batchSize,
+			Block:    b.block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			b.logger.Error("读取消息失败",
+				logger.String("topic", topic),
+				logger.String("group", group),
+				logger.Error(err),
+			)
+			b.sleep(ctx, b.errBackoff)
+			continue
+		}
+		for _, stream := range streams {
+			b.handle(ctx, topic, group, stream.Messages, handler)
+		}
+	}
+	return ctx.Err()
+}
+
+func (b *RedisBus) handle(ctx context.Context, topic string, group string, msgs []redis.XMessage, handler Handler) {
+	// 已经读到的消息挂在当前消费者名下，停止消费时如果直接放弃，要等 claimIdle 之后才会被其他消费者认领。
+	// 所以处理和确认用不随 ctx 取消的 context，把这一批处理完，最多等 drainTimeout
+	ctx, cancel := b.drainContext(ctx)
+	defer cancel()
+	for _, m := range msgs {
+		msg := b.decode(topic, m)
+		if err := process(ctx, b, b.cfg, group, msg, handler); err != nil {
+			// 不确认，等待被重新认领
+			b.logger.Error("处理消息失败",
+				logger.String("topic", topic),
+				logger.String("group", group),
+				logger.String("id", msg.Id),
+				logger.Error(err),
+			)
+			return
+		}
+		if err := b.client.XAck(ctx, topic, group, m.ID).Err(); err != nil {
+			b.logger.Error("确认消息失败",
+				logger.String("topic", topic),
+				logger.String("group", group),
+				logger.String("id", msg.Id),
+				logger.Error(err),
+			)
+		}
+	}
+}
+
+// drainContext 返回的 context 在 ctx 取消后 drainTimeout 才取消
+func (b *RedisBus) drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	dctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(b.drainTimeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-dctx.Done():
+		}
+	})
+	return dctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+func (b *RedisBus) decode(topic string, m redis.XMessage) Message {
+	msg := Message{
+		Id:     m.ID,
+		Topic:  topic,
+		Header: make(map[string]string),
+	}
+	for k, v := range m.Values {
+		s, _ := v.(string)
+		if k == redisFieldValue {
+			msg.Value = []byte(s)
+			continue
+		}
+		if strings.HasPrefix(k, redisFieldHeaderPfx) {
+			msg.Header[strings.TrimPrefix(k, redisFieldHeaderPfx)] = s
+		}
+	}
+	return msg
+}
+
+func (b *RedisBus) sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
@@ -0,0 +1,45 @@
+package events
+
+import (
+	"Webook/webook/internal/repository/cache/redismocks"
+	"Webook/webook/pkg/logger"
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// 停止消费时，已经读到的这一批消息也要处理完并确认
+func TestRedisBus_HandleDrainsOnStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := redismocks.NewMockCmdable(ctrl)
+	for _, id := range []string{"1-0", "2-0"} {
+		client.EXPECT().XAck(gomock.Any(), "topic", "group", id).
+			DoAndReturn(func(ctx context.Context, stream string, group string, ids ...string) *redis.IntCmd {
+				assert.NoError(t, ctx.Err())
+				cmd := redis.NewIntCmd(ctx)
+				cmd.SetVal(1)
+				return cmd
+			})
+	}
+	bus := NewRedisBus(client, "consumer", Config{}, logger.NewZapLogger(zap.NewNop()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var handled []string
+	bus.handle(ctx, "topic", "group", []redis.XMessage{
+		{ID: "1-0", Values: map[string]any{redisFieldValue: "{}"}},
+		{ID: "2-0", Values: map[string]any{redisFieldValue: "{}"}},
+	}, func(hctx context.Context, msg Message) error {
+		// 处理第一条时停止消费
+		cancel()
+		assert.NoError(t, hctx.Err())
+		handled = append(handled, msg.Id)
+		return nil
+	})
+	assert.Equal(t, []string{"1-0", "2-0"}, handled)
+}
@@ -0,0 +1,113 @@
+package events
+
+import (
+	"Webook/webook/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Producer 发送某一种事件
+type Producer[T any] interface {
+	Produce(ctx context.Context, evt T) error
+}
+
+// Consumer 后台运行的消费者
+type Consumer interface {
+	// Start 在后台开始消费，不阻塞，ctx 取消后停止
+	Start(ctx context.Context)
+	// Stop 停止消费，等待正在处理的消息处理完，最多等到 ctx 过期
+	Stop(ctx context.Context) error
+}
+
+// JSONProducer 把事件编码成 JSON 发送到 topic
+type JSONProducer[T any] struct {
+	bus   Bus
+	topic string
+}
+
+func NewJSONProducer[T any](bus Bus, topic string) Producer[T] {
+	return &JSONProducer[T]{
+		bus:   bus,
+		topic: topic,
+	}
+}
+
+func (p *JSONProducer[T]) Produce(ctx context.Context, evt T) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	return p.bus.Publish(ctx, p.topic, Message{Value: val})
+}
+
+// JSONConsumer 以消费者组 group 消费 topic 中的 JSON 事件
+type JSONConsumer[T any] struct {
+	bus     Bus
+	topic   string
+	group   string
+	handler func(ctx context.Context, evt T) error
+	logger  logger.Logger
+
+	cancel context.CancelFunc
+	// 消费协程退出后关闭
+	done chan struct{}
+}
+
+func NewJSONConsumer[T any](bus Bus, topic string, group string,
+	handler func(ctx context.Context, evt T) error, l logger.Logger) Consumer {
+	return &JSONConsumer[T]{
+		bus:     bus,
+		topic:   topic,
+		group:   group,
+		handler: handler,
+		logger:  l,
+	}
+}
+
+func (c *JSONConsumer[T]) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		err := c.bus.Subscribe(ctx, c.topic, c.group, c.handle)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			c.logger.Error("消费者退出",
+				logger.String("topic", c.topic),
+				logger.String("group", c.group),
+				logger.Error(err),
+			)
+		}
+	}()
+}
+
+func (c *JSONConsumer[T]) Stop(ctx context.Context) error {
+	// 没有启动过
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *JSONConsumer[T]) handle(ctx context.Context, msg Message) error {
+	var evt T
+	// 格式错误的消息重试也没用，最终会进死信
+	if err := json.Unmarshal(msg.Value, &evt); err != nil {
+		return err
+	}
+	return c.handler(context.WithValue(ctx, messageIdKey{}, msg.Id), evt)
+}
+
+type messageIdKey struct{}
+
+// MessageIdFromContext JSONConsumer 处理的消息 id，重新投递时不变，可以用来去重
+func MessageIdFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(messageIdKey{}).(string)
+	return id, ok && id != ""
+}
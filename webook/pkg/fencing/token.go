@@ -0,0 +1,25 @@
+// Package fencing fencing token 在 ctx 中的传递。
+// 任务每次被抢占时拿到一个更大的 token，写入时带上，存储端拒绝比见过的更小的 token，
+// 避免任务被其他节点接手后，还在执行的旧持有者覆盖新持有者的结果
+package fencing
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStaleToken 存储端见过更大的 fencing token，写入被拒绝
+var ErrStaleToken = errors.New("fencing token 已过期")
+
+type tokenCtxKey struct{}
+
+// WithToken 把 fencing token 放进 ctx，交给写入的地方检查
+func WithToken(ctx context.Context, token int64) context.Context {
+	return context.WithValue(ctx, tokenCtxKey{}, token)
+}
+
+// TokenFromContext 没有 token 时返回 false
+func TokenFromContext(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(tokenCtxKey{}).(int64)
+	return token, ok
+}
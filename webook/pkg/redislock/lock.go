@@ -0,0 +1,135 @@
+// Package redislock 基于 Redis 的分布式锁，支持自动续约和 fencing token
+package redislock
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	//go:embed lock.lua
+	luaLock string
+	//go:embed unlock.lua
+	luaUnlock string
+	//go:embed refresh.lua
+	luaRefresh string
+)
+
+var (
+	// ErrFailedToPreemptLock 锁被其他人持有
+	ErrFailedToPreemptLock = errors.New("抢锁失败")
+	// ErrLockNotHold 锁已经过期或者被其他人拿走了
+	ErrLockNotHold = errors.New("未持有锁")
+)
+
+type Client struct {
+	client redis.Cmdable
+	valuer func() string
+}
+
+func NewClient(client redis.Cmdable) *Client {
+	return &Client{
+		client: client,
+		valuer: func() string {
+			return uuid.New().String()
+		},
+	}
+}
+
+// TryLock 只尝试一次，锁被其他人持有时返回 ErrFailedToPreemptLock
+func (c *Client) TryLock(ctx context.Context, key string, expiration time.Duration) (*Lock, error) {
+	value := c.valuer()
+	token, err := c.client.Eval(ctx, luaLock, []string{key, tokenKey(key)},
+		value, expiration.Milliseconds()).Int64()
+	if err != nil {
+		return nil, err
+	}
+	if token == 0 {
+		return nil, ErrFailedToPreemptLock
+	}
+	return newLock(c.client, key, value, expiration, token), nil
+}
+
+func tokenKey(key string) string {
+	return key + ":fencing_token"
+}
+
+type Lock struct {
+	client     redis.Cmdable
+	key        string
+	value      string
+	expiration time.Duration
+	token      int64
+
+	unlockOnce sync.Once
+	unlockCh   chan struct{}
+}
+
+func newLock(client redis.Cmdable, key string, value string, expiration time.Duration, token int64) *Lock {
+	return &Lock{
+		client:     client,
+		key:        key,
+		value:      value,
+		expiration: expiration,
+		token:      token,
+		unlockCh:   make(chan struct{}),
+	}
+}
+
+// Token fencing token，每次加锁成功都比上一次大，用 fencing.WithToken 带到写入的地方
+func (l *Lock) Token() int64 {
+	return l.token
+}
+
+func (l *Lock) Refresh(ctx context.Context) error {
+	res, err := l.client.Eval(ctx, luaRefresh, []string{l.key},
+		l.value, l.expiration.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if res != 1 {
+		return ErrLockNotHold
+	}
+	return nil
+}
+
+// AutoRefresh 每隔 interval 续约一次，每次最多等 timeout。
+// 一直阻塞到 Unlock 后返回 nil；续约失败或者超时都立刻返回 error，不再续约：
+// 超时的时候不知道续约有没有成功，锁可能已经过期被其他人拿走了，调用方要停止使用锁保护的资源
+func (l *Lock) AutoRefresh(interval time.Duration, timeout time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-l.unlockCh:
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := l.Refresh(ctx)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (l *Lock) Unlock(ctx context.Context) error {
+	l.unlockOnce.Do(func() {
+		close(l.unlockCh)
+	})
+	res, err := l.client.Eval(ctx, luaUnlock, []string{l.key}, l.value).Int64()
+	if err != nil {
+		return err
+	}
+	if res != 1 {
+		return ErrLockNotHold
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+-- 锁
+local key = KEYS[1]
+-- fencing token 的计数器，不过期，保证 token 一直递增
+local tokenKey = KEYS[2]
+-- 持有者的唯一标识
+local value = ARGV[1]
+-- 过期时间，毫秒
+local expiration = ARGV[2]
+
+if redis.call("SET", key, value, "NX", "PX", expiration) then
+    return redis.call("INCR", tokenKey)
+end
+-- 上次加锁其实成功了，只是没有收到响应
+if redis.call("GET", key) == value then
+    redis.call("PEXPIRE", key, expiration)
+    return tonumber(redis.call("GET", tokenKey))
+end
+return 0
@@ -0,0 +1,169 @@
+package redislock
+
+import (
+	"Webook/webook/internal/repository/cache/redismocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestClient_TryLock(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantToken int64
+		wantErr   error
+	}{
+		{
+			name: "加锁成功",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal(int64(3))
+				client.EXPECT().Eval(gomock.Any(), luaLock,
+					[]string{"job:ranking", "job:ranking:fencing_token"},
+					[]any{"value", int64(30000)}).Return(res)
+				return client
+			},
+			wantToken: 3,
+		},
+		{
+			name: "锁被其他人持有",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal(int64(0))
+				client.EXPECT().Eval(gomock.Any(), luaLock, gomock.Any(), gomock.Any()).Return(res)
+				return client
+			},
+			wantErr: ErrFailedToPreemptLock,
+		},
+		{
+			name: "redis 错误",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				client := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetErr(errors.New("redis error"))
+				client.EXPECT().Eval(gomock.Any(), luaLock, gomock.Any(), gomock.Any()).Return(res)
+				return client
+			},
+			wantErr: errors.New("redis error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			c := NewClient(tc.mock(ctrl))
+			c.valuer = func() string {
+				return "value"
+			}
+			lock, err := c.TryLock(context.Background(), "job:ranking", time.Second*30)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantToken, lock.Token())
+		})
+	}
+}
+
+func TestLock_Unlock(t *testing.T) {
+	testCases := []struct {
+		name    string
+		val     int64
+		wantErr error
+	}{
+		{
+			name: "解锁成功",
+			val:  1,
+		},
+		{
+			name:    "锁已经过期",
+			val:     0,
+			wantErr: ErrLockNotHold,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := redismocks.NewMockCmdable(ctrl)
+			res := redis.NewCmd(context.Background())
+			res.SetVal(tc.val)
+			client.EXPECT().Eval(gomock.Any(), luaUnlock, []string{"job:ranking"}, []any{"value"}).Return(res)
+
+			lock := newLock(client, "job:ranking", "value", time.Second*30, 1)
+			assert.Equal(t, tc.wantErr, lock.Unlock(context.Background()))
+		})
+	}
+}
+
+func TestLock_AutoRefresh(t *testing.T) {
+	t.Run("锁被其他人拿走时返回", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := redismocks.NewMockCmdable(ctrl)
+		ok := redis.NewCmd(context.Background())
+		ok.SetVal(int64(1))
+		lost := redis.NewCmd(context.Background())
+		lost.SetVal(int64(0))
+		gomock.InOrder(
+			client.EXPECT().Eval(gomock.Any(), luaRefresh, []string{"job:ranking"},
+				[]any{"value", int64(30000)}).Return(ok),
+			client.EXPECT().Eval(gomock.Any(), luaRefresh, gomock.Any(), gomock.Any()).Return(lost),
+		)
+
+		lock := newLock(client, "job:ranking", "value", time.Second*30, 1)
+		err := lock.AutoRefresh(time.Millisecond*10, time.Second)
+		assert.Equal(t, ErrLockNotHold, err)
+	})
+
+	t.Run("续约超时不再重试", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := redismocks.NewMockCmdable(ctrl)
+		timeout := redis.NewCmd(context.Background())
+		timeout.SetErr(context.DeadlineExceeded)
+		client.EXPECT().Eval(gomock.Any(), luaRefresh, gomock.Any(), gomock.Any()).Return(timeout)
+
+		lock := newLock(client, "job:ranking", "value", time.Second*30, 1)
+		err := lock.AutoRefresh(time.Millisecond*10, time.Second)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+
+	t.Run("解锁后退出", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := redismocks.NewMockCmdable(ctrl)
+		res := redis.NewCmd(context.Background())
+		res.SetVal(int64(1))
+		client.EXPECT().Eval(gomock.Any(), luaUnlock, gomock.Any(), gomock.Any()).Return(res)
+
+		lock := newLock(client, "job:ranking", "value", time.Second*30, 1)
+		done := make(chan error)
+		go func() {
+			done <- lock.AutoRefresh(time.Hour, time.Second)
+		}()
+		assert.NoError(t, lock.Unlock(context.Background()))
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("解锁后没有退出续约")
+		}
+	})
+}
@@ -0,0 +1,5 @@
+-- 只能续约自己持有的锁
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
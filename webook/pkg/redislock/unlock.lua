@@ -0,0 +1,5 @@
+-- 只能释放自己持有的锁
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("DEL", KEYS[1])
+end
+return 0
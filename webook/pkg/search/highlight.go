@@ -0,0 +1,66 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+)
+
+const ellipsis = "..."
+
+// highlight 用高亮标签包裹命中的关键词，其余部分做 HTML 转义
+// 文本过长时只保留第一个命中位置附近的片段
+func highlight(text string, terms map[string]struct{}, opts Options) string {
+	runes := []rune(text)
+
+	// 命中的区间，合并重叠和相邻的区间
+	var ranges [][2]int
+	for _, token := range tokenizeForIndex(text) {
+		if _, ok := terms[token.Term]; ok {
+			ranges = append(ranges, [2]int{token.Start, token.End})
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+	merged := make([][2]int, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r[0] <= merged[n-1][1] {
+			merged[n-1][1] = max(merged[n-1][1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	// 截取片段：命中位置前面保留四分之一的长度作为上下文
+	start, end := 0, len(runes)
+	if opts.SnippetLen > 0 && len(runes) > opts.SnippetLen {
+		if len(merged) > 0 {
+			start = max(0, merged[0][0]-opts.SnippetLen/4)
+		}
+		end = min(len(runes), start+opts.SnippetLen)
+		start = max(0, end-opts.SnippetLen)
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString(ellipsis)
+	}
+	pos := start
+	for _, r := range merged {
+		s, e := max(r[0], start), min(r[1], end)
+		if s >= e {
+			continue
+		}
+		sb.WriteString(html.EscapeString(string(runes[pos:s])))
+		sb.WriteString(opts.PreTag)
+		sb.WriteString(html.EscapeString(string(runes[s:e])))
+		sb.WriteString(opts.PostTag)
+		pos = e
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		sb.WriteString(ellipsis)
+	}
+	return sb.String()
+}
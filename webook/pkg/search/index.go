@@ -0,0 +1,396 @@
+// Package search 一个嵌入式的全文检索引擎：内存中的倒排索引 + 磁盘上的操作日志
+//
+// 每次写入都追加到日志文件，启动时回放日志重建倒排索引，并压缩日志；
+// 运行中日志超过 Options.CompactSize 后也会压缩。这样不需要额外部署搜索集群
+package search
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const logFileName = "index.log"
+
+// Document 文档，Fields 参与检索和高亮，Extra 只存储，随结果返回
+type Document struct {
+	Id     int64             `json:"id"`
+	Fields map[string]string `json:"fields"`
+	Extra  map[string]string `json:"extra,omitempty"`
+}
+
+// Hit 检索结果，Highlights 为高亮后的字段，已经做过 HTML 转义
+type Hit struct {
+	Document
+	Score      float64
+	Highlights map[string]string
+}
+
+type Options struct {
+	// 字段权重，默认为 1
+	Boosts map[string]float64
+	// 高亮时字段超过这个长度（字符数）就截取命中位置附近的片段，0 表示不截取
+	SnippetLen int
+	// 高亮标签，默认为 <em></em>
+	PreTag  string
+	PostTag string
+	// 日志超过这个大小（字节），并且超过上次压缩后大小的两倍时自动压缩，0 表示只在 Open 时压缩。
+	// 要求两倍是为了文档本身就很多时，不会每次写入都压缩一遍
+	CompactSize int64
+}
+
+// Index 倒排索引，并发安全
+type Index struct {
+	mu   sync.RWMutex
+	opts Options
+
+	docs map[int64]Document
+	// term -> docId -> 加权词频
+	postings map[string]map[int64]float64
+
+	path string
+	log  *os.File
+	// 当前日志的大小和上次压缩后日志的大小
+	logSize       int64
+	compactedSize int64
+}
+
+type logEntry struct {
+	Op  string    `json:"op"`
+	Doc *Document `json:"doc,omitempty"`
+	Id  int64     `json:"id,omitempty"`
+}
+
+const (
+	opPut    = "put"
+	opDelete = "del"
+)
+
+// Open 打开 dir 下的索引，不存在则创建
+func Open(dir string, opts Options) (*Index, error) {
+	if opts.PreTag == "" && opts.PostTag == "" {
+		opts.PreTag, opts.PostTag = "<em>", "</em>"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	idx := &Index{
+		opts:     opts,
+		docs:     make(map[int64]Document),
+		postings: make(map[string]map[int64]float64),
+		path:     filepath.Join(dir, logFileName),
+	}
+	if err := idx.replay(); err != nil {
+		return nil, err
+	}
+	// 回放完成后压缩日志，去掉被覆盖和删除的文档
+	if err := idx.Compact(); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// replay 回放日志，最后一行可能因为进程崩溃只写了一半，忽略即可
+func (idx *Index) replay() error {
+	f, err := os.Open(idx.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var entry logEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		switch entry.Op {
+		case opPut:
+			if entry.Doc != nil {
+				idx.put(*entry.Doc)
+			}
+		case opDelete:
+			idx.delete(entry.Id)
+		}
+	}
+}
+
+// Put 写入文档，已存在则覆盖
+func (idx *Index) Put(doc Document) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if err := idx.append(logEntry{Op: opPut, Doc: &doc}); err != nil {
+		return err
+	}
+	idx.put(doc)
+	return idx.compactIfNeeded()
+}
+
+// Delete 删除文档，不存在也不会报错
+func (idx *Index) Delete(id int64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, ok := idx.docs[id]; !ok {
+		return nil
+	}
+	if err := idx.append(logEntry{Op: opDelete, Id: id}); err != nil {
+		return err
+	}
+	idx.delete(id)
+	return idx.compactIfNeeded()
+}
+
+// Len 文档数量
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Search 检索同时包含所有关键词的文档，按相关度降序，相关度相同时 id 大的在前
+// 返回当前页的结果和命中总数
+func (idx *Index) Search(query string, offset, limit int) ([]Hit, int) {
+	terms := uniqueTerms(Tokenize(query))
+	if len(terms) == 0 {
+		return []Hit{}, 0
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// 从最短的倒排链开始求交集
+	lists := make([]map[int64]float64, 0, len(terms))
+	for _, term := range terms {
+		list, ok := idx.postings[term]
+		if !ok {
+			return []Hit{}, 0
+		}
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+
+	n := float64(len(idx.docs))
+	type scored struct {
+		id    int64
+		score float64
+	}
+	var matched []scored
+	for id := range lists[0] {
+		score := 0.0
+		hit := true
+		for _, list := range lists {
+			weight, ok := list[id]
+			if !ok {
+				hit = false
+				break
+			}
+			score += weight * math.Log(1+n/float64(len(list)))
+		}
+		if hit {
+			matched = append(matched, scored{id: id, score: score})
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].score != matched[j].score {
+			return matched[i].score > matched[j].score
+		}
+		return matched[i].id > matched[j].id
+	})
+
+	total := len(matched)
+	if offset >= total {
+		return []Hit{}, total
+	}
+	end := min(offset+limit, total)
+
+	termSet := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		termSet[term] = struct{}{}
+	}
+	hits := make([]Hit, 0, end-offset)
+	for _, m := range matched[offset:end] {
+		doc := idx.docs[m.id]
+		highlights := make(map[string]string, len(doc.Fields))
+		for field, text := range doc.Fields {
+			highlights[field] = highlight(text, termSet, idx.opts)
+		}
+		hits = append(hits, Hit{
+			Document:   doc,
+			Score:      m.score,
+			Highlights: highlights,
+		})
+	}
+	return hits, total
+}
+
+// Replace 用 docs 替换索引中的所有文档，并重写日志
+func (idx *Index) Replace(docs []Document) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs = make(map[int64]Document, len(docs))
+	idx.postings = make(map[string]map[int64]float64)
+	for _, doc := range docs {
+		idx.put(doc)
+	}
+	return idx.compact()
+}
+
+// Compact 把当前所有文档重写为新的日志，替换旧日志
+func (idx *Index) Compact() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.compact()
+}
+
+// compactIfNeeded 写入后日志超过阈值就压缩。压缩失败时这次写入已经在日志里了，
+// 返回错误让调用方知道，下次写入会再尝试压缩
+func (idx *Index) compactIfNeeded() error {
+	if idx.opts.CompactSize <= 0 ||
+		idx.logSize < idx.opts.CompactSize || idx.logSize < idx.compactedSize*2 {
+		return nil
+	}
+	return idx.compact()
+}
+
+func (idx *Index) compact() error {
+	tmp := idx.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, doc := range idx.docs {
+		doc := doc
+		if err = enc.Encode(logEntry{Op: opPut, Doc: &doc}); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err = w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// 先替换再关闭旧日志，替换失败时旧日志还能继续写
+	if err = os.Rename(tmp, idx.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if idx.log != nil {
+		idx.log.Close()
+	}
+	idx.log, err = os.OpenFile(idx.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := idx.log.Stat()
+	if err != nil {
+		return err
+	}
+	idx.logSize = info.Size()
+	idx.compactedSize = info.Size()
+	return nil
+}
+
+// Close 关闭日志文件
+func (idx *Index) Close() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.log == nil {
+		return nil
+	}
+	err := idx.log.Close()
+	idx.log = nil
+	return err
+}
+
+func (idx *Index) append(entry logEntry) error {
+	if idx.log == nil {
+		return errors.New("search: 索引已关闭")
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	n, err := idx.log.Write(append(data, '\n'))
+	idx.logSize += int64(n)
+	return err
+}
+
+func (idx *Index) put(doc Document) {
+	idx.delete(doc.Id)
+	idx.docs[doc.Id] = doc
+	for field, text := range doc.Fields {
+		boost, ok := idx.opts.Boosts[field]
+		if !ok {
+			boost = 1
+		}
+		for _, token := range tokenizeForIndex(text) {
+			list, ok := idx.postings[token.Term]
+			if !ok {
+				list = make(map[int64]float64)
+				idx.postings[token.Term] = list
+			}
+			list[doc.Id] += boost
+		}
+	}
+}
+
+func (idx *Index) delete(id int64) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	delete(idx.docs, id)
+	for _, text := range doc.Fields {
+		for _, token := range tokenizeForIndex(text) {
+			list, ok := idx.postings[token.Term]
+			if !ok {
+				continue
+			}
+			delete(list, id)
+			if len(list) == 0 {
+				delete(idx.postings, token.Term)
+			}
+		}
+	}
+}
+
+func uniqueTerms(tokens []Token) []string {
+	seen := make(map[string]struct{}, len(tokens))
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if _, ok := seen[token.Term]; ok {
+			continue
+		}
+		seen[token.Term] = struct{}{}
+		terms = append(terms, token.Term)
+	}
+	return terms
+}
@@ -0,0 +1,138 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex_Search(t *testing.T) {
+	idx, err := Open(t.TempDir(), Options{
+		Boosts: map[string]float64{"title": 2},
+	})
+	require.NoError(t, err)
+	defer idx.Close()
+
+	require.NoError(t, idx.Put(Document{Id: 1, Fields: map[string]string{
+		"title":   "Go 语言并发编程",
+		"content": "goroutine 和 channel 是 Go 并发的基础",
+	}}))
+	require.NoError(t, idx.Put(Document{Id: 2, Fields: map[string]string{
+		"title":   "MySQL 索引",
+		"content": "B+ 树索引和 Go 无关",
+	}}))
+	require.NoError(t, idx.Put(Document{Id: 3, Fields: map[string]string{
+		"title":   "Redis <缓存>",
+		"content": "缓存一致性",
+	}}))
+
+	testCases := []struct {
+		name    string
+		query   string
+		wantIds []int64
+	}{
+		{name: "英文大小写不敏感", query: "GO", wantIds: []int64{1, 2}},
+		{name: "中文词组", query: "并发", wantIds: []int64{1}},
+		{name: "中文单字", query: "索", wantIds: []int64{2}},
+		{name: "多个关键词取交集", query: "go 索引", wantIds: []int64{2}},
+		{name: "没有命中", query: "kafka", wantIds: []int64{}},
+		{name: "空查询", query: " ,. ", wantIds: []int64{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hits, total := idx.Search(tc.query, 0, 10)
+			ids := make([]int64, 0, len(hits))
+			for _, hit := range hits {
+				ids = append(ids, hit.Id)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+			assert.Equal(t, len(tc.wantIds), total)
+		})
+	}
+
+	hits, _ := idx.Search("缓存", 0, 10)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "Redis &lt;<em>缓存</em>&gt;", hits[0].Highlights["title"])
+	assert.Equal(t, "<em>缓存</em>一致性", hits[0].Highlights["content"])
+}
+
+func TestIndex_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := Open(dir, Options{})
+	require.NoError(t, err)
+	require.NoError(t, idx.Put(Document{Id: 1, Fields: map[string]string{"title": "旧标题"}}))
+	require.NoError(t, idx.Put(Document{Id: 1, Fields: map[string]string{"title": "新标题"}}))
+	require.NoError(t, idx.Put(Document{Id: 2, Fields: map[string]string{"title": "新文章"},
+		Extra: map[string]string{"author": "1"}}))
+	require.NoError(t, idx.Delete(2))
+	require.NoError(t, idx.Close())
+
+	idx, err = Open(dir, Options{})
+	require.NoError(t, err)
+	defer idx.Close()
+	assert.Equal(t, 1, idx.Len())
+
+	hits, total := idx.Search("标题", 0, 10)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "新<em>标题</em>", hits[0].Highlights["title"])
+	_, total = idx.Search("旧", 0, 10)
+	assert.Equal(t, 0, total)
+}
+
+func TestIndex_Replace(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := Open(dir, Options{})
+	require.NoError(t, err)
+	require.NoError(t, idx.Put(Document{Id: 1, Fields: map[string]string{"title": "已经撤回"}}))
+	require.NoError(t, idx.Replace([]Document{
+		{Id: 2, Fields: map[string]string{"title": "别的实例发表"}},
+	}))
+	_, total := idx.Search("撤回", 0, 10)
+	assert.Equal(t, 0, total)
+	require.NoError(t, idx.Close())
+
+	// 替换之后的结果也写进了日志
+	idx, err = Open(dir, Options{})
+	require.NoError(t, err)
+	defer idx.Close()
+	assert.Equal(t, 1, idx.Len())
+	_, total = idx.Search("发表", 0, 10)
+	assert.Equal(t, 1, total)
+}
+
+func TestIndex_AutoCompact(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := Open(dir, Options{CompactSize: 1024})
+	require.NoError(t, err)
+	// 反复覆盖同一篇文档，压缩后日志只剩一行
+	for i := 0; i < 100; i++ {
+		require.NoError(t, idx.Put(Document{Id: 1, Fields: map[string]string{
+			"title": fmt.Sprintf("第 %d 版标题", i),
+		}}))
+	}
+	info, err := os.Stat(filepath.Join(dir, logFileName))
+	require.NoError(t, err)
+	assert.Less(t, info.Size(), int64(1024))
+	require.NoError(t, idx.Close())
+
+	idx, err = Open(dir, Options{})
+	require.NoError(t, err)
+	defer idx.Close()
+	assert.Equal(t, 1, idx.Len())
+	_, total := idx.Search("第 99 版", 0, 10)
+	assert.Equal(t, 1, total)
+}
+
+func TestHighlight_Snippet(t *testing.T) {
+	text := "0123456789 关键词 0123456789"
+	got := highlight(text, map[string]struct{}{"关键": {}, "键词": {}}, Options{
+		SnippetLen: 10,
+		PreTag:     "[",
+		PostTag:    "]",
+	})
+	assert.Equal(t, "...9 [关键词] 0123...", got)
+}
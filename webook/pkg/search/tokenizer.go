@@ -0,0 +1,78 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token 分词结果，Start 和 End 是在原文 []rune 中的位置，用于高亮
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenize 分词：英文和数字按单词切分并转小写，中日韩文字按二元组（bigram）切分，
+// 单独出现的一个汉字作为一个词。查询时使用
+func Tokenize(text string) []Token {
+	return tokenize(text, false)
+}
+
+// tokenizeForIndex 建索引时额外切出中日韩文字的单字，这样单字查询也能命中
+func tokenizeForIndex(text string) []Token {
+	return tokenize(text, true)
+}
+
+func tokenize(text string, unigram bool) []Token {
+	runes := []rune(text)
+	var tokens []Token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isCJK(r):
+			j := i
+			for j < len(runes) && isCJK(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, cjkTokens(runes, i, j, unigram)...)
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && !isCJK(runes[j]) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, Token{
+				Term:  strings.ToLower(string(runes[i:j])),
+				Start: i,
+				End:   j,
+			})
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+func cjkTokens(runes []rune, start, end int, unigram bool) []Token {
+	if end-start == 1 {
+		return []Token{{Term: string(runes[start:end]), Start: start, End: end}}
+	}
+	tokens := make([]Token, 0, 2*(end-start))
+	for i := start; i < end; i++ {
+		if unigram {
+			tokens = append(tokens, Token{Term: string(runes[i]), Start: i, End: i + 1})
+		}
+		if i+1 < end {
+			tokens = append(tokens, Token{Term: string(runes[i : i+2]), Start: i, End: i + 2})
+		}
+	}
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
@@ -0,0 +1,91 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试向量，取后 6 位
+func TestGenerate(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	testCases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tc := range testCases {
+		code, err := Generate(secret, Counter(time.Unix(tc.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, code)
+	}
+
+	_, err := Generate("not base32!", 1)
+	assert.Equal(t, ErrInvalidSecret, err)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	now := time.Now()
+	code := func(d time.Duration) string {
+		c, err := Generate(secret, Counter(now.Add(d)))
+		require.NoError(t, err)
+		return c
+	}
+
+	testCases := []struct {
+		name        string
+		code        string
+		wantCounter int64
+		wantErr     error
+	}{
+		{
+			name:        "当前窗口",
+			code:        code(0),
+			wantCounter: Counter(now),
+		},
+		{
+			name:        "上一个窗口",
+			code:        code(-Period),
+			wantCounter: Counter(now) - 1,
+		},
+		{
+			name:    "超出允许的偏差",
+			code:    code(-3 * Period),
+			wantErr: ErrInvalidCode,
+		},
+		{
+			name:    "长度不对",
+			code:    "12345",
+			wantErr: ErrInvalidCode,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter, err := Validate(secret, tc.code, now, 1)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCounter, counter)
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("Webook", "a@b.com", "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Webook:a@b.com", u.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	assert.Equal(t, "Webook", u.Query().Get("issuer"))
+}
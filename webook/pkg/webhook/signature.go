@@ -0,0 +1,60 @@
+// Package webhook webhook 请求的签名和校验，发送方和接收方共用，以及发送方用的 HTTP 客户端
+//
+// 签名的内容是 "时间戳.请求体"，算法为 HMAC-SHA256，结果放在请求头中：
+//
+//	X-Webook-Signature: sha256=<hex>
+//
+// 接收方用注册 webhook 时拿到的 secret 重新计算并比较，同时检查时间戳防止重放
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Webook-Event"
+	HeaderDelivery  = "X-Webook-Delivery"
+	HeaderTimestamp = "X-Webook-Timestamp"
+	HeaderSignature = "X-Webook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook: 签名错误")
+	ErrExpiredTimestamp = errors.New("webhook: 时间戳过期")
+)
+
+// Sign 返回 "sha256=<hex>"，timestamp 为秒级时间戳
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验请求头中的签名，时间戳和 now 相差超过 tolerance 视为重放，tolerance 为 0 表示不检查
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		diff := now.Sub(time.Unix(timestamp, 0))
+		if diff > tolerance || diff < -tolerance {
+			return ErrExpiredTimestamp
+		}
+	}
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(HeaderSignature))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
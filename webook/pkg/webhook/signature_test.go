@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"event":"article.published"}`)
+	header := func(secret string, ts time.Time) http.Header {
+		h := http.Header{}
+		h.Set(HeaderTimestamp, strconv.FormatInt(ts.Unix(), 10))
+		h.Set(HeaderSignature, Sign(secret, ts.Unix(), body))
+		return h
+	}
+
+	testCases := []struct {
+		name    string
+		header  http.Header
+		body    []byte
+		wantErr error
+	}{
+		{
+			name:   "签名正确",
+			header: header("secret", now),
+			body:   body,
+		},
+		{
+			name:    "secret 不对",
+			header:  header("other", now),
+			body:    body,
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "请求体被篡改",
+			header:  header("secret", now),
+			body:    []byte(`{"event":"article.deleted"}`),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "时间戳过期",
+			header:  header("secret", now.Add(-time.Hour)),
+			body:    body,
+			wantErr: ErrExpiredTimestamp,
+		},
+		{
+			name:    "没有时间戳",
+			header:  http.Header{},
+			body:    body,
+			wantErr: ErrInvalidSignature,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify("secret", tc.header, tc.body, now, time.Minute*5)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -21,15 +21,20 @@ var rankingSvcSet = wire.NewSet(
 	rankCache.NewRankingLocalCache,
 	rankCache.NewRankingRedisCache,
 	rankCache.NewCompositeRankingCache,
+	rankCache.NewRankingScoreCache,
 	repository.NewRankingRepository,
+	ioc.InitRankingBoardConfigs,
+	ioc.InitRankingBoards,
 	service.NewRankingService,
 )
 
-func InitWebServer() *App {
+func InitWebServer() (*App, error) {
 	wire.Build(
 		// 第三方依赖
 		ioc.InitRedis, ioc.InitDB,
 		ioc.InitLogger,
+		ioc.InitSearchIndex,
+		ioc.InitEventBus,
 
 		// Dao
 		dao.NewUserDAO,
@@ -37,45 +42,106 @@ func InitWebServer() *App {
 		// article2.NewGormArticleAuthorDAO,
 		// article2.NewGormArticleReaderDAO,
 		dao.NewInteractiveDAO,
+		dao.NewCommentDAO,
+		dao.NewCollectionDAO,
+		dao.NewFollowDAO,
+		dao.NewFeedDAO,
+		dao.NewNotificationDAO,
+		dao.NewWebhookDAO,
+		dao.NewReadHistoryDAO,
+		dao.NewCronJobDAO,
+		dao.NewAuthorStatsDAO,
+		dao.NewTwoFactorDAO,
 
 		// Ranking Svc
 		rankingSvcSet,
-		ioc.InitRankingJob,
-		ioc.InitJobs,
+		ioc.InitRankingJobs,
+		ioc.InitScheduledPublishJob,
+		ioc.InitWebhookRetryJob,
+		ioc.InitCronJobExecutionCleanJob,
+		ioc.InitCronJobService,
+		ioc.InitScheduler,
 
 		// Cache
 		cache.NewUserCache,
 		cache.NewCodeCache,
+		cache.NewPasswordResetCache,
 		cache.NewRedisArticleCache,
 		cache.NewInteractiveCache,
+		cache.NewFollowCache,
+		cache.NewReadDedupCache,
+		cache.NewAuthorStatsCache,
+		cache.NewTwoFactorCache,
 
 		// repository
 		repository.NewUserRepository,
 		repository.NewCodeRepository,
+		repository.NewPasswordResetRepository,
 		article.NewArticleRepository,
+		article.NewLocalArticleSearchRepository,
 		// article.NewArticleAuthorRepository,
 		// article.NewArticleReaderRepository,
 		repository.NewInteractiveRepository,
+		repository.NewCommentRepository,
+		repository.NewCollectionRepository,
+		repository.NewFollowRepository,
+		repository.NewFeedRepository,
+		repository.NewNotificationRepository,
+		repository.NewWebhookRepository,
+		repository.NewReadHistoryRepository,
+		repository.NewCronJobRepository,
+		repository.NewAuthorStatsRepository,
+		repository.NewTwoFactorRepository,
 
 		// Service
 		ioc.InitSMSService,
+		ioc.InitMailService,
 		ioc.InitWechatService,
 		service.NewUserService,
 		service.NewCodeService,
 		service.NewArticleService,
 		// service.NewArticleServiceWithTwoRepo,
 		service.NewInteractiveService,
+		service.NewCommentService,
+		service.NewCollectionService,
+		service.NewFollowService,
+		service.NewFeedService,
+		service.NewNotificationService,
+		ioc.InitArticleEventProducer,
+		ioc.InitInteractiveEventProducer,
+		ioc.InitReadEventProducer,
+		ioc.InitNotificationEventProducer,
+		ioc.InitConsumers,
+		service.NewSearchService,
+		ioc.InitWebhookClient,
+		ioc.InitWebhookService,
+		ioc.InitReadHistoryService,
+		service.NewAuthorStatsService,
+		service.NewTwoFactorService,
 
 		// Handler
 		web.NewUserHandler,
+		ioc.InitJWTKeys,
 		myjwt.NewRedisJWTHandler,
 		web.NewOAuth2WechatHandler,
 		web.NewArticleHandler,
 		web.NewArticleReaderHandler,
+		web.NewCommentHandler,
+		web.NewCollectionHandler,
+		web.NewFollowHandler,
+		web.NewFeedHandler,
+		web.NewNotificationHandler,
+		web.NewSearchHandler,
+		web.NewWebhookHandler,
+		web.NewReadHistoryHandler,
+		web.NewJobHandler,
+		web.NewAuthorStatsHandler,
+		web.NewJWKSHandler,
+		ioc.InitAdminMiddleware,
 		ioc.InitGinMiddleware,
 		ioc.InitWebServer,
 
 		wire.Struct(new(App), "*"),
 	)
-	return new(App)
+	return new(App), nil
 }
@@ -26,48 +26,122 @@ import (
 
 // Injectors from wire.go:
 
-func InitWebServer() *App {
+func InitWebServer() (*App, error) {
 	cmdable := ioc.InitRedis()
-	handler := jwt.NewRedisJWTHandler(cmdable)
 	logger := ioc.InitLogger()
+	keys, err := ioc.InitJWTKeys(logger)
+	if err != nil {
+		return nil, err
+	}
+	handler := jwt.NewRedisJWTHandler(cmdable, keys)
 	v := ioc.InitGinMiddleware(cmdable, handler, logger)
 	db := ioc.InitDB(logger)
 	userDAO := dao.NewUserDAO(db)
 	userCache := cache.NewUserCache(cmdable)
 	userRepository := repository.NewUserRepository(userDAO, userCache)
-	userService := service.NewUserService(userRepository, logger)
+	passwordResetCache := cache.NewPasswordResetCache(cmdable)
+	passwordResetRepository := repository.NewPasswordResetRepository(passwordResetCache)
+	mailService, err := ioc.InitMailService()
+	if err != nil {
+		return nil, err
+	}
+	webhookDAO := dao.NewWebhookDAO(db)
+	webhookRepository := repository.NewWebhookRepository(webhookDAO)
+	client := ioc.InitWebhookClient()
+	webhookService := ioc.InitWebhookService(webhookRepository, client, logger)
+	userService := service.NewUserService(userRepository, passwordResetRepository, mailService, webhookService, logger)
 	codeCache := cache.NewCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
 	smsService := ioc.InitSMSService()
 	codeService := service.NewCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, codeService, handler)
+	followDAO := dao.NewFollowDAO(db)
+	followCache := cache.NewFollowCache(cmdable)
+	followRepository := repository.NewFollowRepository(followDAO, followCache, logger)
+	bus := ioc.InitEventBus(cmdable, logger)
+	producer := ioc.InitNotificationEventProducer(bus)
+	followService := service.NewFollowService(followRepository, userRepository, producer, logger)
+	twoFactorDAO := dao.NewTwoFactorDAO(db)
+	twoFactorCache := cache.NewTwoFactorCache(cmdable)
+	twoFactorRepository := repository.NewTwoFactorRepository(twoFactorDAO, twoFactorCache)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepository, userRepository)
+	userHandler := web.NewUserHandler(userService, codeService, followService, twoFactorService, handler)
 	wechatService := ioc.InitWechatService(logger)
 	oAuth2WechatHandler := web.NewOAuth2WechatHandler(wechatService, userService, handler)
 	articleDAO := article.NewArticleDAO(db)
 	articleCache := cache.NewRedisArticleCache(cmdable)
 	articleRepository := article2.NewArticleRepository(articleDAO, articleCache, userRepository, logger)
-	articleService := service.NewArticleService(articleRepository)
+	feedDAO := dao.NewFeedDAO(db)
+	feedRepository := repository.NewFeedRepository(feedDAO)
+	feedService := service.NewFeedService(feedRepository, followRepository, articleRepository)
+	eventsProducer := ioc.InitArticleEventProducer(bus)
+	articleService := service.NewArticleService(articleRepository, feedService, webhookService, eventsProducer, logger)
 	interactiveDAO := dao.NewInteractiveDAO(db)
 	interactiveCache := cache.NewInteractiveCache(cmdable)
-	interactiveRepository := repository.NewInteractiveRepository(interactiveDAO, interactiveCache)
-	interactiveService := service.NewInteractiveService(interactiveRepository)
+	interactiveRepository := repository.NewInteractiveRepository(interactiveDAO, interactiveCache, logger)
+	collectionDAO := dao.NewCollectionDAO(db)
+	collectionRepository := repository.NewCollectionRepository(collectionDAO, interactiveCache)
+	producer2 := ioc.InitReadEventProducer(bus)
+	producer3 := ioc.InitInteractiveEventProducer(bus)
+	interactiveService := service.NewInteractiveService(interactiveRepository, collectionRepository, articleRepository, producer, webhookService, producer2, producer3, logger)
 	articleHandler := web.NewArticleHandler(articleService, interactiveService, logger)
 	rankingLocalCache := cache2.NewRankingLocalCache()
 	rankingRedisCache := cache2.NewRankingRedisCache(cmdable)
 	rankingCache := cache2.NewCompositeRankingCache(rankingLocalCache, rankingRedisCache)
-	rankingRepository := repository.NewRankingRepository(rankingCache)
-	rankingService := service.NewRankingService(articleService, interactiveService, rankingRepository)
-	articleReaderHandler := web.NewArticleReaderHandler(articleService, interactiveService, rankingService, userService, logger)
-	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler, articleReaderHandler)
-	rankingJob := ioc.InitRankingJob(rankingService)
-	cron := ioc.InitJobs(logger, rankingJob)
+	rankingScoreCache := cache2.NewRankingScoreCache(cmdable)
+	rankingRepository := repository.NewRankingRepository(rankingCache, rankingScoreCache)
+	v2 := ioc.InitRankingBoardConfigs()
+	v3 := ioc.InitRankingBoards(v2)
+	rankingService := service.NewRankingService(articleService, interactiveService, articleRepository, rankingRepository, v3)
+	articleReaderHandler := web.NewArticleReaderHandler(articleService, interactiveService, rankingService, userService, followService, logger)
+	commentDAO := dao.NewCommentDAO(db)
+	commentRepository := repository.NewCommentRepository(commentDAO, interactiveCache)
+	commentService := service.NewCommentService(commentRepository, articleRepository, producer, producer3, logger)
+	commentHandler := web.NewCommentHandler(commentService, userService, logger)
+	index := ioc.InitSearchIndex()
+	articleSearchRepository := article2.NewLocalArticleSearchRepository(index)
+	searchService := service.NewSearchService(articleSearchRepository, articleRepository)
+	searchHandler := web.NewSearchHandler(searchService, userService, logger)
+	collectionService := service.NewCollectionService(collectionRepository, articleRepository, producer3, logger)
+	collectionHandler := web.NewCollectionHandler(collectionService, interactiveService, userService, followService, logger)
+	followHandler := web.NewFollowHandler(followService, userService, logger)
+	feedHandler := web.NewFeedHandler(feedService, interactiveService, userService, logger)
+	notificationDAO := dao.NewNotificationDAO(db)
+	notificationRepository := repository.NewNotificationRepository(notificationDAO)
+	notificationService := service.NewNotificationService(notificationRepository, articleRepository)
+	notificationHandler := web.NewNotificationHandler(notificationService, userService, logger)
+	webhookHandler := web.NewWebhookHandler(webhookService, logger)
+	readHistoryDAO := dao.NewReadHistoryDAO(db)
+	readDedupCache := cache.NewReadDedupCache(cmdable)
+	readHistoryRepository := repository.NewReadHistoryRepository(readHistoryDAO, readDedupCache)
+	readHistoryService := ioc.InitReadHistoryService(readHistoryRepository, articleRepository)
+	readHistoryHandler := web.NewReadHistoryHandler(readHistoryService, userService, logger)
+	adminMiddlewareBuilder := ioc.InitAdminMiddleware()
+	cronJobDAO := dao.NewCronJobDAO(db)
+	cronJobRepository := repository.NewCronJobRepository(cronJobDAO)
+	cronJobService := ioc.InitCronJobService(cronJobRepository, logger)
+	jobHandler := web.NewJobHandler(cronJobService, logger)
+	authorStatsDAO := dao.NewAuthorStatsDAO(db)
+	authorStatsCache := cache.NewAuthorStatsCache(cmdable)
+	authorStatsRepository := repository.NewAuthorStatsRepository(authorStatsDAO, authorStatsCache, logger)
+	authorStatsService := service.NewAuthorStatsService(authorStatsRepository, articleRepository)
+	authorStatsHandler := web.NewAuthorStatsHandler(authorStatsService, userService, logger)
+	jwksHandler := web.NewJWKSHandler(keys)
+	engine := ioc.InitWebServer(v, userHandler, oAuth2WechatHandler, articleHandler, articleReaderHandler, commentHandler, searchHandler, collectionHandler, followHandler, feedHandler, notificationHandler, webhookHandler, readHistoryHandler, adminMiddlewareBuilder, jobHandler, authorStatsHandler, jwksHandler)
+	v4 := ioc.InitRankingJobs(rankingService, v2)
+	scheduledPublishJob := ioc.InitScheduledPublishJob(articleService)
+	webhookRetryJob := ioc.InitWebhookRetryJob(webhookService)
+	cronJobExecutionCleanJob := ioc.InitCronJobExecutionCleanJob(cronJobService)
+	scheduler := ioc.InitScheduler(cronJobService, logger, v4, scheduledPublishJob, webhookRetryJob, cronJobExecutionCleanJob)
+	v5 := ioc.InitConsumers(bus, interactiveService, readHistoryService, rankingService, authorStatsService, notificationService, searchService, logger)
 	app := &App{
-		server: engine,
-		cron:   cron,
+		server:    engine,
+		scheduler: scheduler,
+		searchSvc: searchService,
+		consumers: v5,
 	}
-	return app
+	return app, nil
 }
 
 // wire.go:
 
-var rankingSvcSet = wire.NewSet(cache2.NewRankingLocalCache, cache2.NewRankingRedisCache, cache2.NewCompositeRankingCache, repository.NewRankingRepository, service.NewRankingService)
+var rankingSvcSet = wire.NewSet(cache2.NewRankingLocalCache, cache2.NewRankingRedisCache, cache2.NewCompositeRankingCache, cache2.NewRankingScoreCache, repository.NewRankingRepository, ioc.InitRankingBoardConfigs, ioc.InitRankingBoards, service.NewRankingService)